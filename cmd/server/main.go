@@ -2,32 +2,182 @@ package main
 
 import (
 	"backend/config"
+	domain_auth "backend/internal/domain/auth"
 	infrastructure_auth "backend/internal/infrastructure/auth"
+	infrastructure_fetch "backend/internal/infrastructure/fetch"
+	infrastructure_ratelimit "backend/internal/infrastructure/ratelimit"
 	infrastructure_todo "backend/internal/infrastructure/todo"
 	infrastructure_user "backend/internal/infrastructure/user"
+	infrastructure_workspace "backend/internal/infrastructure/workspace"
 	interfaces_auth "backend/internal/interfaces/auth"
 	interfaces_paralell "backend/internal/interfaces/paralell"
+	interfaces_ratelimit "backend/internal/interfaces/ratelimit"
 	interfaces_sample "backend/internal/interfaces/sample"
 	interfaces_search "backend/internal/interfaces/search"
+	interfaces_sort "backend/internal/interfaces/sort"
 	interfaces_todo "backend/internal/interfaces/todo"
 	interfaces_user "backend/internal/interfaces/user"
+	interfaces_workspace "backend/internal/interfaces/workspace"
+	pkg_httpcache "backend/internal/pkg/httpcache"
+	pkg_httpclient "backend/internal/pkg/httpclient"
 	pkg_logger "backend/internal/pkg/logger"
+	pkg_mail "backend/internal/pkg/mail"
+	pkg_oidc "backend/internal/pkg/oidc"
 	pkg_supabase "backend/internal/pkg/supabase"
+	repository_ratelimit "backend/internal/repository/ratelimit"
 	"backend/internal/router"
 	usecase_auth "backend/internal/usecase/auth"
+	usecase_fetch "backend/internal/usecase/fetch"
+	usecase_ratelimit "backend/internal/usecase/ratelimit"
 	usecase_search "backend/internal/usecase/search"
+	usecase_sort "backend/internal/usecase/sort"
 	usecase_todo "backend/internal/usecase/todo"
 	usecase_user "backend/internal/usecase/user"
+	usecase_workspace "backend/internal/usecase/workspace"
+	"context"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
 
+// 上流APIのクライアント
+// 再試行・サーキットブレーカー付きのクライアントの前にキャッシュを置く
+func newUpstreamClient(ap *config.AppConfig, l *pkg_logger.AppLogger) pkg_httpclient.Doer {
+	client := pkg_httpclient.NewClient(l, pkg_httpclient.Options{
+		Timeout:          ap.Fetch.Timeout,
+		MaxRetries:       ap.Fetch.MaxRetries,
+		BaseBackoff:      ap.Fetch.RetryBackoff,
+		MaxBackoff:       ap.Fetch.RetryMaxBackoff,
+		MaxResponseSize:  ap.Fetch.MaxResponseSize,
+		BreakerThreshold: ap.Fetch.BreakerThreshold,
+		BreakerCooldown:  ap.Fetch.BreakerCooldown,
+	})
+	if ap.Fetch.CacheTTL <= 0 {
+		return client
+	}
+	return pkg_httpcache.NewCache(l, client, pkg_httpcache.NewMemoryStore(ap.Fetch.CacheMaxEntries), pkg_httpcache.Options{
+		TTL:                  ap.Fetch.CacheTTL,
+		StaleWhileRevalidate: ap.Fetch.CacheStaleTTL,
+		Retention:            ap.Fetch.CacheRetention,
+	})
+}
+
+// レート制限の状態の保存先
+// postgresの場合はインスタンス間で共有する(migrations/002_rate_limits.sql)
+func newRateLimitRepository(ap *config.AppConfig, l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_ratelimit.IRateLimitRepository {
+	switch ap.RateLimit.Store {
+	case "postgres":
+		return infrastructure_ratelimit.NewRateLimitRepository(l, sc)
+	case "memory":
+	default:
+		l.WarnLog.Printf("Unknown rate limit store %q, using memory", ap.RateLimit.Store)
+	}
+	return infrastructure_ratelimit.NewRateLimitMemoryRepository(l)
+}
+
+// ログインの失敗を制限するポリシー(アカウントごと・IPアドレスごと)
+func newLockoutPolicies(ap *config.AppConfig) (domain_auth.LockoutPolicy, domain_auth.LockoutPolicy) {
+	account := domain_auth.LockoutPolicy{
+		Threshold: ap.Auth.LockoutThreshold,
+		Duration:  ap.Auth.LockoutDuration,
+		Window:    ap.Auth.FailureWindow,
+		BaseDelay: ap.Auth.BackoffBase,
+		MaxDelay:  ap.Auth.BackoffMax,
+	}
+	ip := account
+	ip.Threshold = ap.Auth.IPLockoutThreshold
+	return account, ip
+}
+
+// OIDCのIDプロバイダ
+// ディスカバリ・JWKSの取得は再試行し、トークンの交換(POST)は再試行しない
+func newOIDCProviders(ap *config.AppConfig, l *pkg_logger.AppLogger) []*pkg_oidc.Provider {
+	client := pkg_httpclient.NewClient(l, pkg_httpclient.Options{
+		Timeout:          ap.Fetch.Timeout,
+		MaxRetries:       ap.Fetch.MaxRetries,
+		BaseBackoff:      ap.Fetch.RetryBackoff,
+		MaxBackoff:       ap.Fetch.RetryMaxBackoff,
+		MaxResponseSize:  1 << 20,
+		BreakerThreshold: ap.Fetch.BreakerThreshold,
+		BreakerCooldown:  ap.Fetch.BreakerCooldown,
+	})
+	providers := []*pkg_oidc.Provider{}
+	for _, p := range ap.Auth.OIDCProviders {
+		providers = append(providers, pkg_oidc.NewProvider(l, pkg_oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, client))
+	}
+	return providers
+}
+
+// メールの送信方法
+// smtp以外はローカル環境・テスト用
+func newMailer(ap *config.AppConfig, l *pkg_logger.AppLogger) pkg_mail.Mailer {
+	switch ap.Mail.Driver {
+	case "smtp":
+		return pkg_mail.NewSMTPMailer(l, pkg_mail.SMTPConfig{
+			Host:     ap.Mail.SMTPHost,
+			Port:     ap.Mail.SMTPPort,
+			Username: ap.Mail.SMTPUsername,
+			Password: ap.Mail.SMTPPassword,
+			From:     ap.Mail.From,
+		})
+	case "file":
+		return pkg_mail.NewFileMailer(l, ap.Mail.Dir, ap.Mail.From)
+	case "log":
+	default:
+		l.WarnLog.Printf("Unknown mail driver %q, using log", ap.Mail.Driver)
+	}
+	return pkg_mail.NewLogMailer(l)
+}
+
+// 繰り返しのTodoのスケジューラ
+// 起動時とintervalごとに、先の期間の予定のTodoを作成する。返した関数で止める(実行中の処理の終了を待つ)
+// 複数のインスタンスで動かしても、同じ予定は一意制約で1回だけ作成する
+func startRecurrenceScheduler(l *pkg_logger.AppLogger, uc usecase_todo.ITodoRecurrenceUsecase, interval time.Duration) func() {
+	if interval <= 0 {
+		l.InfoLog.Println("Recurrence scheduler is disabled")
+		return func() {}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if _, err := uc.MaterializeDue(time.Now()); err != nil {
+				l.ErrorLog.Printf("Failed to materialize recurrences: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
 // main関数のセットアップ
-func setUp(e *echo.Echo, ap *config.AppConfig, l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) {
+// バックグラウンドの処理を止める関数を返す
+func setUp(e *echo.Echo, ap *config.AppConfig, l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) func() {
 	// Supabaseの接続
 	err := sc.InitSupabase(l)
 	if err != nil {
@@ -43,23 +193,91 @@ func setUp(e *echo.Echo, ap *config.AppConfig, l *pkg_logger.AppLogger, sc *pkg_
 	// repository
 	userRepository := infrastructure_user.NewUserRepository(l, sc)
 	authRepository := infrastructure_auth.NewAuthRepository(l, sc)
+	loginAttemptRepository := infrastructure_auth.NewLoginAttemptRepository(l, sc)
+	auditLogRepository := infrastructure_auth.NewAuditLogRepository(l, sc)
+	apiKeyRepository := infrastructure_auth.NewAPIKeyRepository(l, sc)
+	identityRepository := infrastructure_auth.NewIdentityRepository(l, sc)
+	mfaRepository := infrastructure_auth.NewMFARepository(l, sc)
+	accountRepository := infrastructure_auth.NewAccountRepository(l, sc)
+	userTokenRepository := infrastructure_auth.NewUserTokenRepository(l, sc)
+	sessionRepository := infrastructure_auth.NewSessionRepository(l, sc)
+	roleRepository := infrastructure_auth.NewRoleRepository(l, sc)
 	todoRepository := infrastructure_todo.NewTodoRepository(l, sc)
+	todoSearchRepository := infrastructure_todo.NewTodoSearchRepository(l, sc)
+	todoRecurrenceRepository := infrastructure_todo.NewTodoRecurrenceRepository(l, sc)
+	workspaceRepository := infrastructure_workspace.NewWorkspaceRepository(l, sc)
+	upstreamRepository := infrastructure_fetch.NewUpstreamRepository(l, ap.TestAPI, newUpstreamClient(ap, l))
+	rateLimitRepository := newRateLimitRepository(ap, l, sc)
 	// usecase
 	userUsecase := usecase_user.NewUserUsecase(l, userRepository)
-	authUsecase := usecase_auth.NewAuthUsecase(l, authRepository)
-	todoUsecase := usecase_todo.NewTodoUsecase(l, todoRepository)
+	accountPolicy, ipPolicy := newLockoutPolicies(ap)
+	authUsecase := usecase_auth.NewAuthUsecase(l, authRepository, loginAttemptRepository, auditLogRepository, accountPolicy, ipPolicy)
+	apiKeyUsecase := usecase_auth.NewAPIKeyUsecase(l, apiKeyRepository, roleRepository)
+	oidcUsecase := usecase_auth.NewOIDCUsecase(l, identityRepository, auditLogRepository, newOIDCProviders(ap, l))
+	mfaUsecase := usecase_auth.NewMFAUsecase(l, mfaRepository, loginAttemptRepository, auditLogRepository, accountPolicy, ap.Auth.MFAIssuer)
+	sessionUsecase := usecase_auth.NewSessionUsecase(l, sessionRepository, roleRepository, ap.Auth.SessionCacheTTL)
+	mailTemplates, err := pkg_mail.NewTemplates(ap.Mail.DefaultLanguage)
+	if err != nil {
+		l.ErrorLog.Fatalf("Failed to load mail templates: %v", err)
+	}
+	mailer := newMailer(ap, l)
+	accountUsecase := usecase_auth.NewAccountUsecase(l, accountRepository, userTokenRepository, sessionRepository, mailer, mailTemplates, usecase_auth.AccountOptions{
+		TokenSecret:          []byte(ap.Auth.TokenSecret),
+		PasswordResetTTL:     ap.Auth.PasswordResetTTL,
+		EmailVerificationTTL: ap.Auth.EmailVerificationTTL,
+		LinkBaseURL:          ap.Mail.LinkBaseURL,
+	})
+	workspaceUsecase := usecase_workspace.NewWorkspaceUsecase(l, workspaceRepository, accountRepository, mailer, mailTemplates, usecase_workspace.WorkspaceOptions{
+		InvitationTTL: ap.Workspace.InvitationTTL,
+		LinkBaseURL:   ap.Mail.LinkBaseURL,
+	})
+	todoUsecase := usecase_todo.NewTodoUsecase(l, todoRepository, todoRecurrenceRepository)
+	todoSearchUsecase := usecase_todo.NewTodoSearchUsecase(l, todoSearchRepository)
+	todoRecurrenceUsecase := usecase_todo.NewTodoRecurrenceUsecase(l, todoRepository, todoRecurrenceRepository, usecase_todo.RecurrenceOptions{
+		Horizon:   ap.Todo.RecurrenceHorizon,
+		BatchSize: ap.Todo.RecurrenceBatchSize,
+	})
+	fetchUsecase := usecase_fetch.NewFetchUsecase(l, upstreamRepository)
+	aggregateUsecase := usecase_fetch.NewAggregateUsecase(l, upstreamRepository)
 	searchUsecase := usecase_search.NewSearchUsecase(l)
+	graphUsecase := usecase_search.NewGraphUsecase(l)
+	benchmarkUsecase := usecase_search.NewBenchmarkUsecase(l)
+	mazeUsecase := usecase_search.NewMazeUsecase(l)
+	sortUsecase := usecase_sort.NewSortUsecase(l)
+	rateLimitUsecase := usecase_ratelimit.NewRateLimitUsecase(l, rateLimitRepository)
 
 	// handler
 	userHandler := interfaces_user.NewUserHandler(l, userUsecase)
-	authHandler := interfaces_auth.NewAuthHandler(l, authUsecase)
+	authHandler := interfaces_auth.NewAuthHandler(l, authUsecase, apiKeyUsecase, mfaUsecase, sessionUsecase)
+	apiKeyHandler := interfaces_auth.NewAPIKeyHandler(ap, l, apiKeyUsecase)
+	oidcHandler := interfaces_auth.NewOIDCHandler(l, oidcUsecase, mfaUsecase, sessionUsecase)
+	mfaHandler := interfaces_auth.NewMFAHandler(l, mfaUsecase, sessionUsecase)
+	sessionHandler := interfaces_auth.NewSessionHandler(l, sessionUsecase)
+	accountHandler := interfaces_auth.NewAccountHandler(l, accountUsecase)
 	todoHandler := interfaces_todo.NewTodoHandler(l, todoUsecase)
+	todoSearchHandler := interfaces_todo.NewTodoSearchHandler(l, todoSearchUsecase)
+	todoRecurrenceHandler := interfaces_todo.NewTodoRecurrenceHandler(l, todoRecurrenceUsecase)
+	workspaceHandler := interfaces_workspace.NewWorkspaceHandler(l, workspaceUsecase, authHandler.WorkspaceToken)
 	sampleHandler := interfaces_sample.NewSampleHandler()
-	paralellHandler := interfaces_paralell.NewParalellHandler(ap, l)
-	searchHandler := interfaces_search.NewSearchHandler(l, searchUsecase)
+	paralellHandler := interfaces_paralell.NewParalellHandler(ap, l, fetchUsecase)
+	aggregateHandler := interfaces_paralell.NewAggregateHandler(ap, l, aggregateUsecase)
+	searchHandler := interfaces_search.NewSearchHandler(ap, l, searchUsecase)
+	graphHandler := interfaces_search.NewGraphHandler(ap, l, graphUsecase)
+	benchmarkHandler := interfaces_search.NewBenchmarkHandler(ap, l, benchmarkUsecase)
+	mazeHandler := interfaces_search.NewMazeHandler(ap, l, mazeUsecase)
+	sortHandler := interfaces_sort.NewSortHandler(ap, l, sortUsecase)
+	rateLimitHandler := interfaces_ratelimit.NewRateLimitHandler(ap, l, rateLimitUsecase, authHandler.UserID)
 
 	// ルーティングの設定
-	router.SetUpRouter(e, sampleHandler, paralellHandler, userHandler, authHandler, todoHandler, searchHandler)
+	router.SetUpRouter(e, ap, sampleHandler, paralellHandler, aggregateHandler, userHandler, authHandler, apiKeyHandler, oidcHandler, mfaHandler, sessionHandler, accountHandler, todoHandler, todoSearchHandler, todoRecurrenceHandler, workspaceHandler, searchHandler, graphHandler, benchmarkHandler, mazeHandler, sortHandler, rateLimitHandler)
+
+	// バックグラウンドの処理
+	stopScheduler := startRecurrenceScheduler(l, todoRecurrenceUsecase, ap.Todo.RecurrenceInterval)
+	return func() {
+		stopScheduler()
+		// 送信中のパスワードの再設定のメールを待つ
+		accountUsecase.Wait()
+	}
 }
 
 // アプリケーションのメイン関数
@@ -77,9 +295,15 @@ func main() {
 
 	// Echoの設定
 	e := echo.New()
+	// クライアントのIPアドレス(レート制限のキー)。プロキシを信頼しない場合はX-Forwarded-Forを無視する
+	if appConfig.RateLimit.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
 
 	// セットアップ
-	setUp(e, appConfig, logger, supabaseClient)
+	stopBackground := setUp(e, appConfig, logger, supabaseClient)
 
 	// シグナルハンドラーの設定
 	quit := make(chan os.Signal, 1)
@@ -95,6 +319,9 @@ func main() {
 			logger.ErrorLog.Printf("Echo shutdown failed: %v", err)
 		}
 
+		// スケジューラの停止・送信中のメールの待機(コネクションプールを閉じる前に止める)
+		stopBackground()
+
 		// Supabaseコネクションプールのクローズ
 		supabaseClient.ClosePool(logger)
 	}()
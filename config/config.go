@@ -4,18 +4,258 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
 // アプリケーションの設定
 type AppConfig struct {
-	TestAPI string
+	TestAPI      string
+	SearchLimits SearchLimits
+	SortLimits   SortLimits
+	Fetch        FetchConfig
+	RateLimit    RateLimitConfig
+	Auth         AuthConfig
+	Mail         MailConfig
+	Workspace    WorkspaceConfig
+	Todo         TodoConfig
+}
+
+// ワークスペースの設定
+type WorkspaceConfig struct {
+	InvitationTTL time.Duration // 招待の有効期間
+}
+
+// Todoの設定
+type TodoConfig struct {
+	RecurrenceInterval  time.Duration // 繰り返しの予定を作成する間隔(0の場合はスケジューラを起動しない)
+	RecurrenceHorizon   time.Duration // 繰り返しの予定を先に作成しておく期間
+	RecurrenceBatchSize int           // 1回に処理する繰り返しの数
+}
+
+// メール送信の設定
+type MailConfig struct {
+	Driver          string // smtp, file(Dirに.emlで書き出す), log(ログに出す)
+	Dir             string // fileの書き出し先
+	From            string
+	SMTPHost        string
+	SMTPPort        int
+	SMTPUsername    string // 空の場合は認証しない
+	SMTPPassword    string
+	DefaultLanguage string // テンプレートの既定の言語(ja, en)
+	LinkBaseURL     string // メールのリンク先(フロントエンド)のURL
+}
+
+// 認証の設定
+type AuthConfig struct {
+	LockoutThreshold   int           // アカウントをロックするまでの失敗回数
+	IPLockoutThreshold int           // IPアドレスをロックするまでの失敗回数
+	LockoutDuration    time.Duration // ロックする時間(経過すると自動で解除する)
+	FailureWindow      time.Duration // 失敗回数を数える期間
+	BackoffBase        time.Duration // 失敗後の待ち時間の基準(失敗ごとに倍にする)
+	BackoffMax         time.Duration // 失敗後の待ち時間の上限
+
+	APIKeyLifetime    time.Duration // APIキーの既定の有効期間
+	APIKeyMaxLifetime time.Duration // APIキーの有効期間の上限
+
+	OIDCProviders []OIDCProviderConfig // OIDCでログインできるIDプロバイダ
+
+	MFAIssuer string // 認証アプリに表示する発行者名
+
+	TokenSecret          string        // パスワードの再設定・メールアドレスの確認のトークンの署名の鍵
+	PasswordResetTTL     time.Duration // パスワードの再設定のトークンの有効期間
+	EmailVerificationTTL time.Duration // メールアドレスの確認のトークンの有効期間
+
+	SessionCacheTTL time.Duration // セッションをキャッシュする時間(失効が反映されるまでの最大の遅れ)
+}
+
+// OIDCのIDプロバイダの設定
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string // ディスカバリ(/.well-known/openid-configuration)の取得元
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // /api/auth/oidc/{name}/callback
+	Scopes       []string // 空の場合は openid email profile
+}
+
+// 一括取得の設定
+type FetchConfig struct {
+	Upstreams      []UpstreamConfig // 既定の上流APIへのリクエスト
+	Concurrency    int              // 既定の同時実行数
+	MaxConcurrency int              // 同時実行数の上限
+	MaxRequests    int              // 1回のリクエスト数の上限
+	Timeout        time.Duration    // 1リクエスト(1回の試行)のタイムアウト
+	Deadline       time.Duration    // 一括取得全体の既定の期限
+	MaxDeadline    time.Duration    // 一括取得全体の期限の上限
+
+	AggregatePerPage    int // 集約の1ページの既定の件数
+	AggregateMaxPerPage int // 集約の1ページの件数の上限
+	AggregateMaxPage    int // 集約のページ番号の上限
+
+	MaxRetries       int           // 再試行の回数(冪等なリクエストのみ)
+	RetryBackoff     time.Duration // 再試行の待ち時間の基準
+	RetryMaxBackoff  time.Duration // 再試行の待ち時間の上限
+	MaxResponseSize  int64         // レスポンスボディの上限(バイト)
+	BreakerThreshold int           // サーキットブレーカーを開くまでの連続失敗回数(0で無効)
+	BreakerCooldown  time.Duration // サーキットブレーカーを開いている時間
+
+	CacheTTL        time.Duration // キャッシュの鮮度(0でキャッシュしない)
+	CacheStaleTTL   time.Duration // 鮮度が切れた後、古いキャッシュを返しながら裏で再検証する時間
+	CacheRetention  time.Duration // さらにその後、条件付きリクエストでの再検証用に残す時間
+	CacheMaxEntries int           // メモリ上のキャッシュの最大件数
+}
+
+// 上流APIへのリクエストの設定
+type UpstreamConfig struct {
+	Name   string
+	Method string
+	Path   string
+}
+
+// レート制限の設定
+type RateLimitConfig struct {
+	Store      string                  // 状態の保存先(memory: インスタンスごと, postgres: インスタンス間で共有)
+	TrustProxy bool                    // X-Forwarded-ForからクライアントのIPアドレスを取得する(リバースプロキシの背後で使う)
+	Policies   []RateLimitPolicyConfig // ルートごとのポリシー
+}
+
+// レート制限のポリシーの設定
+type RateLimitPolicyConfig struct {
+	Name      string
+	Method    string        // 空の場合は全てのメソッド
+	Path      string        // ルートのパス(末尾が"*"の場合は前方一致)
+	Algorithm string        // token_bucket, sliding_window
+	KeyBy     string        // ip, user, api_key
+	Limit     int           // window当たりのリクエスト数
+	Window    time.Duration // 期間
+}
+
+// 探索APIの入力制限
+type SearchLimits struct {
+	MaxArrayLength int    // 配列の最大要素数
+	MaxGridCells   int    // グリッドの最大マス数
+	MaxGraphNodes  int    // グラフの最大ノード数
+	MaxGraphEdges  int    // グラフの最大辺数
+	MaxBodySize    string // リクエストボディの最大サイズ(例: 2M)
+
+	MaxBenchmarkIterations int   // ベンチマークの最大実行回数(ウォームアップを含む)
+	MaxBenchmarkTargets    int   // ベンチマークの最大target数
+	MaxBenchmarkWork       int64 // ベンチマークの最大作業量(要素数 x target数 x 実行回数 x アルゴリズム数)
+
+	MaxTraceEvents int // 途中経過(JSON)の最大イベント数。NDJSONは上限なし
+}
+
+// ソートAPIの入力制限
+type SortLimits struct {
+	MaxValues          int    // 最大要素数
+	MaxQuadraticValues int    // O(n^2)のソート(挿入ソート)の最大要素数
+	MaxTraceValues     int    // 途中経過を記録する場合の最大要素数
+	MaxTraceSteps      int    // 記録する途中経過の最大数
+	MaxBodySize        string // リクエストボディの最大サイズ(例: 64M)
 }
 
 // アプリケーションの設定のインスタンス化
 func NewAppConfig() *AppConfig {
-	return &AppConfig{}
+	return &AppConfig{
+		SearchLimits: SearchLimits{
+			MaxArrayLength: 100000,
+			MaxGridCells:   250000,
+			MaxGraphNodes:  10000,
+			MaxGraphEdges:  100000,
+			MaxBodySize:    "2M",
+
+			MaxBenchmarkIterations: 1000,
+			MaxBenchmarkTargets:    10000,
+			MaxBenchmarkWork:       2000000000,
+
+			MaxTraceEvents: 100000,
+		},
+		SortLimits: SortLimits{
+			MaxValues:          1000000,
+			MaxQuadraticValues: 20000,
+			MaxTraceValues:     1000,
+			MaxTraceSteps:      100000,
+			MaxBodySize:        "64M",
+		},
+		Fetch: FetchConfig{
+			Upstreams: []UpstreamConfig{
+				{Name: "posts", Method: "GET", Path: "/posts"},
+				{Name: "comments", Method: "GET", Path: "/comments"},
+				{Name: "albums", Method: "GET", Path: "/albums"},
+			},
+			Concurrency:    4,
+			MaxConcurrency: 16,
+			MaxRequests:    20,
+			Timeout:        10 * time.Second,
+			Deadline:       15 * time.Second,
+			MaxDeadline:    time.Minute,
+
+			AggregatePerPage:    10,
+			AggregateMaxPerPage: 100,
+			AggregateMaxPage:    10000,
+
+			MaxRetries:       2,
+			RetryBackoff:     100 * time.Millisecond,
+			RetryMaxBackoff:  2 * time.Second,
+			MaxResponseSize:  10 << 20,
+			BreakerThreshold: 5,
+			BreakerCooldown:  30 * time.Second,
+
+			CacheTTL:        30 * time.Second,
+			CacheStaleTTL:   time.Minute,
+			CacheRetention:  10 * time.Minute,
+			CacheMaxEntries: 1000,
+		},
+		Auth: AuthConfig{
+			LockoutThreshold:   5,
+			IPLockoutThreshold: 20,
+			LockoutDuration:    15 * time.Minute,
+			FailureWindow:      15 * time.Minute,
+			BackoffBase:        time.Second,
+			BackoffMax:         30 * time.Second,
+
+			APIKeyLifetime:    90 * 24 * time.Hour,
+			APIKeyMaxLifetime: 365 * 24 * time.Hour,
+
+			MFAIssuer: "backend",
+
+			TokenSecret:          "secret",
+			PasswordResetTTL:     time.Hour,
+			EmailVerificationTTL: 24 * time.Hour,
+
+			SessionCacheTTL: 5 * time.Second,
+		},
+		Mail: MailConfig{
+			Driver:          "log",
+			Dir:             "tmp/mail",
+			From:            "no-reply@example.com",
+			SMTPPort:        587,
+			DefaultLanguage: "ja",
+			LinkBaseURL:     "http://localhost:3000",
+		},
+		Workspace: WorkspaceConfig{
+			InvitationTTL: 7 * 24 * time.Hour,
+		},
+		Todo: TodoConfig{
+			RecurrenceInterval:  time.Hour,
+			RecurrenceHorizon:   14 * 24 * time.Hour,
+			RecurrenceBatchSize: 100,
+		},
+		RateLimit: RateLimitConfig{
+			Store: "memory",
+			Policies: []RateLimitPolicyConfig{
+				{Name: "login", Method: "POST", Path: "/api/auth/login", Algorithm: "sliding_window", KeyBy: "ip", Limit: 5, Window: time.Minute},
+				{Name: "password_reset", Method: "POST", Path: "/api/auth/password/forgot", Algorithm: "sliding_window", KeyBy: "ip", Limit: 5, Window: time.Hour},
+				{Name: "mfa_verify", Method: "POST", Path: "/api/auth/mfa/verify", Algorithm: "sliding_window", KeyBy: "ip", Limit: 5, Window: time.Minute},
+				{Name: "fetch", Method: "POST", Path: "/api/fetch", Algorithm: "token_bucket", KeyBy: "user", Limit: 10, Window: time.Minute},
+				{Name: "search", Path: "/api/search/*", Algorithm: "token_bucket", KeyBy: "user", Limit: 60, Window: time.Minute},
+			},
+		},
+	}
 }
 
 // プロジェクトのルートディレクトリを特定する関数
@@ -60,4 +300,217 @@ func (c *AppConfig) SetUpEnv() {
 	}
 
 	c.TestAPI = os.Getenv("TEST_API")
+
+	// 探索APIの入力制限(未設定の場合は既定値)
+	c.SearchLimits.MaxArrayLength = getEnvInt("SEARCH_MAX_ARRAY_LENGTH", c.SearchLimits.MaxArrayLength)
+	c.SearchLimits.MaxGridCells = getEnvInt("SEARCH_MAX_GRID_CELLS", c.SearchLimits.MaxGridCells)
+	c.SearchLimits.MaxGraphNodes = getEnvInt("SEARCH_MAX_GRAPH_NODES", c.SearchLimits.MaxGraphNodes)
+	c.SearchLimits.MaxGraphEdges = getEnvInt("SEARCH_MAX_GRAPH_EDGES", c.SearchLimits.MaxGraphEdges)
+	c.SearchLimits.MaxBodySize = getEnvString("SEARCH_MAX_BODY_SIZE", c.SearchLimits.MaxBodySize)
+	c.SearchLimits.MaxBenchmarkIterations = getEnvInt("SEARCH_MAX_BENCHMARK_ITERATIONS", c.SearchLimits.MaxBenchmarkIterations)
+	c.SearchLimits.MaxBenchmarkTargets = getEnvInt("SEARCH_MAX_BENCHMARK_TARGETS", c.SearchLimits.MaxBenchmarkTargets)
+	c.SearchLimits.MaxBenchmarkWork = int64(getEnvInt("SEARCH_MAX_BENCHMARK_WORK", int(c.SearchLimits.MaxBenchmarkWork)))
+	c.SearchLimits.MaxTraceEvents = getEnvInt("SEARCH_MAX_TRACE_EVENTS", c.SearchLimits.MaxTraceEvents)
+
+	// ソートAPIの入力制限(未設定の場合は既定値)
+	c.SortLimits.MaxValues = getEnvInt("SORT_MAX_VALUES", c.SortLimits.MaxValues)
+	c.SortLimits.MaxQuadraticValues = getEnvInt("SORT_MAX_QUADRATIC_VALUES", c.SortLimits.MaxQuadraticValues)
+	c.SortLimits.MaxTraceValues = getEnvInt("SORT_MAX_TRACE_VALUES", c.SortLimits.MaxTraceValues)
+	c.SortLimits.MaxTraceSteps = getEnvInt("SORT_MAX_TRACE_STEPS", c.SortLimits.MaxTraceSteps)
+	c.SortLimits.MaxBodySize = getEnvString("SORT_MAX_BODY_SIZE", c.SortLimits.MaxBodySize)
+
+	// 一括取得の設定(未設定の場合は既定値)
+	if v := os.Getenv("FETCH_UPSTREAMS"); v != "" {
+		c.Fetch.Upstreams = parseUpstreams(v)
+	}
+	c.Fetch.Concurrency = getEnvInt("FETCH_CONCURRENCY", c.Fetch.Concurrency)
+	c.Fetch.MaxConcurrency = getEnvInt("FETCH_MAX_CONCURRENCY", c.Fetch.MaxConcurrency)
+	c.Fetch.MaxRequests = getEnvInt("FETCH_MAX_REQUESTS", c.Fetch.MaxRequests)
+	c.Fetch.Timeout = getEnvMillis("FETCH_TIMEOUT_MS", c.Fetch.Timeout)
+	c.Fetch.Deadline = getEnvMillis("FETCH_DEADLINE_MS", c.Fetch.Deadline)
+	c.Fetch.MaxDeadline = getEnvMillis("FETCH_MAX_DEADLINE_MS", c.Fetch.MaxDeadline)
+	c.Fetch.AggregatePerPage = getEnvInt("FETCH_AGGREGATE_PER_PAGE", c.Fetch.AggregatePerPage)
+	c.Fetch.AggregateMaxPerPage = getEnvInt("FETCH_AGGREGATE_MAX_PER_PAGE", c.Fetch.AggregateMaxPerPage)
+	c.Fetch.AggregateMaxPage = getEnvInt("FETCH_AGGREGATE_MAX_PAGE", c.Fetch.AggregateMaxPage)
+	c.Fetch.MaxRetries = getEnvInt("FETCH_MAX_RETRIES", c.Fetch.MaxRetries)
+	c.Fetch.RetryBackoff = getEnvMillis("FETCH_RETRY_BACKOFF_MS", c.Fetch.RetryBackoff)
+	c.Fetch.RetryMaxBackoff = getEnvMillis("FETCH_RETRY_MAX_BACKOFF_MS", c.Fetch.RetryMaxBackoff)
+	c.Fetch.MaxResponseSize = int64(getEnvInt("FETCH_MAX_RESPONSE_SIZE", int(c.Fetch.MaxResponseSize)))
+	c.Fetch.BreakerThreshold = getEnvInt("FETCH_BREAKER_THRESHOLD", c.Fetch.BreakerThreshold)
+	c.Fetch.BreakerCooldown = getEnvMillis("FETCH_BREAKER_COOLDOWN_MS", c.Fetch.BreakerCooldown)
+	c.Fetch.CacheTTL = getEnvMillis("FETCH_CACHE_TTL_MS", c.Fetch.CacheTTL)
+	c.Fetch.CacheStaleTTL = getEnvMillis("FETCH_CACHE_STALE_MS", c.Fetch.CacheStaleTTL)
+	c.Fetch.CacheRetention = getEnvMillis("FETCH_CACHE_RETENTION_MS", c.Fetch.CacheRetention)
+	c.Fetch.CacheMaxEntries = getEnvInt("FETCH_CACHE_MAX_ENTRIES", c.Fetch.CacheMaxEntries)
+
+	// レート制限の設定(未設定の場合は既定値)
+	c.RateLimit.Store = getEnvString("RATE_LIMIT_STORE", c.RateLimit.Store)
+	c.RateLimit.TrustProxy = getEnvBool("RATE_LIMIT_TRUST_PROXY", c.RateLimit.TrustProxy)
+	if v, ok := os.LookupEnv("RATE_LIMIT_POLICIES"); ok {
+		c.RateLimit.Policies = parseRateLimitPolicies(v)
+	}
+
+	// 認証の設定(未設定の場合は既定値)
+	c.Auth.LockoutThreshold = getEnvInt("AUTH_LOCKOUT_THRESHOLD", c.Auth.LockoutThreshold)
+	c.Auth.IPLockoutThreshold = getEnvInt("AUTH_IP_LOCKOUT_THRESHOLD", c.Auth.IPLockoutThreshold)
+	c.Auth.LockoutDuration = getEnvMillis("AUTH_LOCKOUT_DURATION_MS", c.Auth.LockoutDuration)
+	c.Auth.FailureWindow = getEnvMillis("AUTH_FAILURE_WINDOW_MS", c.Auth.FailureWindow)
+	c.Auth.BackoffBase = getEnvMillis("AUTH_BACKOFF_BASE_MS", c.Auth.BackoffBase)
+	c.Auth.BackoffMax = getEnvMillis("AUTH_BACKOFF_MAX_MS", c.Auth.BackoffMax)
+	c.Auth.APIKeyLifetime = getEnvMillis("AUTH_API_KEY_LIFETIME_MS", c.Auth.APIKeyLifetime)
+	c.Auth.APIKeyMaxLifetime = getEnvMillis("AUTH_API_KEY_MAX_LIFETIME_MS", c.Auth.APIKeyMaxLifetime)
+	c.Auth.MFAIssuer = getEnvString("AUTH_MFA_ISSUER", c.Auth.MFAIssuer)
+	c.Auth.TokenSecret = getEnvString("AUTH_TOKEN_SECRET", c.Auth.TokenSecret)
+	c.Auth.PasswordResetTTL = getEnvMillis("AUTH_PASSWORD_RESET_TTL_MS", c.Auth.PasswordResetTTL)
+	c.Auth.EmailVerificationTTL = getEnvMillis("AUTH_EMAIL_VERIFICATION_TTL_MS", c.Auth.EmailVerificationTTL)
+	c.Auth.SessionCacheTTL = getEnvMillis("AUTH_SESSION_CACHE_TTL_MS", c.Auth.SessionCacheTTL)
+	if v := os.Getenv("OIDC_PROVIDERS"); v != "" {
+		c.Auth.OIDCProviders = parseOIDCProviders(v)
+	}
+
+	// メール送信の設定(未設定の場合は既定値)
+	c.Mail.Driver = getEnvString("MAIL_DRIVER", c.Mail.Driver)
+	c.Mail.Dir = getEnvString("MAIL_DIR", c.Mail.Dir)
+	c.Mail.From = getEnvString("MAIL_FROM", c.Mail.From)
+	c.Mail.SMTPHost = getEnvString("MAIL_SMTP_HOST", c.Mail.SMTPHost)
+	c.Mail.SMTPPort = getEnvInt("MAIL_SMTP_PORT", c.Mail.SMTPPort)
+	c.Mail.SMTPUsername = getEnvString("MAIL_SMTP_USERNAME", c.Mail.SMTPUsername)
+	c.Mail.SMTPPassword = getEnvString("MAIL_SMTP_PASSWORD", c.Mail.SMTPPassword)
+	c.Mail.DefaultLanguage = getEnvString("MAIL_DEFAULT_LANGUAGE", c.Mail.DefaultLanguage)
+	c.Mail.LinkBaseURL = getEnvString("MAIL_LINK_BASE_URL", c.Mail.LinkBaseURL)
+
+	// ワークスペースの設定(未設定の場合は既定値)
+	c.Workspace.InvitationTTL = getEnvMillis("WORKSPACE_INVITATION_TTL_MS", c.Workspace.InvitationTTL)
+
+	// Todoの設定(未設定の場合は既定値)
+	c.Todo.RecurrenceInterval = getEnvMillis("TODO_RECURRENCE_INTERVAL_MS", c.Todo.RecurrenceInterval)
+	c.Todo.RecurrenceHorizon = getEnvMillis("TODO_RECURRENCE_HORIZON_MS", c.Todo.RecurrenceHorizon)
+	c.Todo.RecurrenceBatchSize = getEnvInt("TODO_RECURRENCE_BATCH_SIZE", c.Todo.RecurrenceBatchSize)
+}
+
+// 上流APIへのリクエストの設定を読み込む
+// 形式: "name=/path,name=METHOD /path" (メソッド省略時はGET)
+func parseUpstreams(v string) []UpstreamConfig {
+	upstreams := []UpstreamConfig{}
+	for _, item := range strings.Split(v, ",") {
+		name, target, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || name == "" {
+			log.Printf("Invalid FETCH_UPSTREAMS item: %q", item)
+			continue
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(target), " ")
+		if !ok {
+			method, path = "GET", method
+		}
+		upstreams = append(upstreams, UpstreamConfig{Name: name, Method: strings.ToUpper(method), Path: strings.TrimSpace(path)})
+	}
+	return upstreams
+}
+
+// レート制限のポリシーの設定を読み込む
+// 形式: "name=[METHOD ]/path algorithm key limit/window,..." (例: "login=POST /api/auth/login sliding_window ip 5/1m")
+// 空文字の場合はレート制限を行わない
+func parseRateLimitPolicies(v string) []RateLimitPolicyConfig {
+	policies := []RateLimitPolicyConfig{}
+	for _, item := range strings.Split(v, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(strings.TrimSpace(item), "=")
+		fields := strings.Fields(spec)
+		if !ok || name == "" || len(fields) < 4 || len(fields) > 5 {
+			log.Printf("Invalid RATE_LIMIT_POLICIES item: %q", item)
+			continue
+		}
+		policy := RateLimitPolicyConfig{Name: name}
+		if len(fields) == 5 {
+			policy.Method, fields = strings.ToUpper(fields[0]), fields[1:]
+		}
+		policy.Path, policy.Algorithm, policy.KeyBy = fields[0], fields[1], fields[2]
+
+		limit, window, _ := strings.Cut(fields[3], "/")
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			log.Printf("Invalid RATE_LIMIT_POLICIES limit: %q", item)
+			continue
+		}
+		d, err := time.ParseDuration(window)
+		if err != nil {
+			log.Printf("Invalid RATE_LIMIT_POLICIES window: %q", item)
+			continue
+		}
+		policy.Limit, policy.Window = n, d
+		policies = append(policies, policy)
+	}
+	return policies
+}
+
+// OIDCのIDプロバイダの設定を読み込む
+// OIDC_PROVIDERS にプロバイダ名を並べ("google,corp")、プロバイダごとに OIDC_<NAME>_ISSUER などを設定する
+func parseOIDCProviders(v string) []OIDCProviderConfig {
+	providers := []OIDCProviderConfig{}
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+		}
+		if scopes := os.Getenv(prefix + "SCOPES"); scopes != "" {
+			provider.Scopes = strings.Fields(scopes)
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			log.Printf("Invalid OIDC provider %s: %sISSUER, %sCLIENT_ID and %sREDIRECT_URL are required", name, prefix, prefix, prefix)
+			continue
+		}
+		providers = append(providers, provider)
+	}
+	return providers
+}
+
+// 環境変数を文字列で取得する(未設定の場合は既定値)
+func getEnvString(key string, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
+
+// 環境変数を整数で取得する(未設定・不正な場合は既定値)
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Invalid %s: %v", key, err)
+		return def
+	}
+	return n
+}
+
+// 環境変数を真偽値で取得する(未設定・不正な場合は既定値)
+func getEnvBool(key string, def bool) bool {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		log.Printf("Invalid %s: %v", key, err)
+		return def
+	}
+	return b
+}
+
+// 環境変数をミリ秒として取得する(未設定・不正な場合は既定値)
+func getEnvMillis(key string, def time.Duration) time.Duration {
+	return time.Duration(getEnvInt(key, int(def/time.Millisecond))) * time.Millisecond
 }
@@ -0,0 +1,95 @@
+package domain_auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+)
+
+// APIキーの先頭の文字列(キーの種類を見分けるため)
+const APIKeyPrefix = "bk_"
+
+// APIキーの種類
+type APIKeyKind string
+
+const (
+	APIKeyPersonal APIKeyKind = "personal" // 個人のスクリプト用
+	APIKeyService  APIKeyKind = "service"  // CIや外部サービスとの連携用(管理者のみ作成できる)
+)
+
+// APIキーで許可できる権限(ロールと同じ)
+var APIKeyScopes = []string{"user", "admin"}
+
+// APIキー
+// キーそのものは保存せず、識別用のプレフィックスとハッシュのみ保存する
+type APIKey struct {
+	ID         string     `json:"id"           db:"id"`
+	UserID     string     `json:"user_id"      db:"user_id"`
+	Name       string     `json:"name"         db:"name"`
+	Kind       APIKeyKind `json:"kind"         db:"kind"`
+	Prefix     string     `json:"prefix"       db:"prefix"`
+	Hash       string     `json:"-"            db:"hash"`
+	Scopes     []string   `json:"scopes"       db:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"   db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"   db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"   db:"created_at"`
+}
+
+// 有効なキーか(失効・期限切れでない)
+func (k APIKey) Valid(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpiresAt)
+}
+
+// 権限を持つか
+func (k APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// APIキーを生成する
+// 形式は "bk_<プレフィックス>_<秘密の部分>" で、プレフィックスでキーを探し、全体のハッシュで照合する
+func GenerateAPIKey() (key string, prefix string, hash string, err error) {
+	p := make([]byte, 4)
+	if _, err := rand.Read(p); err != nil {
+		return "", "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	prefix = hex.EncodeToString(p)
+	key = APIKeyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return key, prefix, HashAPIKey(key), nil
+}
+
+// APIキーのプレフィックスを取り出す
+func ParseAPIKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, APIKeyPrefix)
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// APIキーのハッシュ
+// キーは十分に長い乱数のため、パスワードのような遅いハッシュは使わない
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIキーの作成リクエスト
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name"`
+	Kind      APIKeyKind `json:"kind"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,26 @@
+package domain_auth
+
+import "time"
+
+// 監査ログの操作
+type AuditAction string
+
+const (
+	AuditAccountLocked   AuditAction = "account_locked"   // 失敗回数の超過によるアカウントのロック
+	AuditIPLocked        AuditAction = "ip_locked"        // 失敗回数の超過によるIPアドレスのロック
+	AuditAccountUnlocked AuditAction = "account_unlocked" // 管理者によるロックの解除
+	AuditIdentityLinked  AuditAction = "identity_linked"  // 外部のIDプロバイダのアカウントの紐付け
+	AuditMFAEnabled      AuditAction = "mfa_enabled"      // 2要素認証の有効化
+	AuditMFAReset        AuditAction = "mfa_reset"        // 管理者による2要素認証の解除
+)
+
+// 監査ログ
+type AuditLog struct {
+	ID        string      `json:"id"         db:"id"`
+	Action    AuditAction `json:"action"     db:"action"`
+	ActorID   string      `json:"actor_id"   db:"actor_id"` // 操作したユーザー(自動の場合は空)
+	Email     string      `json:"email"      db:"email"`
+	IP        string      `json:"ip"         db:"ip"`
+	Detail    string      `json:"detail"     db:"detail"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,20 @@
+package domain_auth
+
+import "time"
+
+// 外部のIDプロバイダのアカウントとユーザーの紐付け
+type Identity struct {
+	Provider  string    `json:"provider"   db:"provider"`
+	Subject   string    `json:"subject"    db:"subject"` // プロバイダ内のユーザーID(sub)
+	UserID    string    `json:"user_id"    db:"user_id"`
+	Email     string    `json:"email"      db:"email"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// 認可リクエストからコールバックまで保持する値
+type OIDCFlow struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"` // PKCEのcode_verifier
+}
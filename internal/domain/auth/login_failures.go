@@ -0,0 +1,111 @@
+package domain_auth
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// 認証情報が一致しない(ユーザーが存在しない場合も含む)
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// ログインの失敗を制限するポリシー
+type LockoutPolicy struct {
+	Threshold int           // ロックするまでの失敗回数
+	Duration  time.Duration // ロックする時間(経過すると自動で解除する)
+	Window    time.Duration // 失敗回数を数える期間(最後の失敗からこの時間が経つと0に戻す)
+	BaseDelay time.Duration // 失敗後、次の試行を受け付けるまでの待ち時間の基準(失敗ごとに倍にする)
+	MaxDelay  time.Duration // 待ち時間の上限
+}
+
+// n回失敗した後の待ち時間
+func (p LockoutPolicy) Delay(n int) time.Duration {
+	if n <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < n && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+// ログインの失敗の記録(アカウントごと・IPアドレスごと)
+type LoginFailures struct {
+	Count        int
+	LastFailedAt time.Time
+	LockedUntil  time.Time
+}
+
+// 失敗を1回記録する
+// ロックの期限が切れている場合、または最後の失敗からWindowが経っている場合は数え直す
+func (f LoginFailures) Fail(now time.Time, p LockoutPolicy) LoginFailures {
+	if (!f.LockedUntil.IsZero() && !now.Before(f.LockedUntil)) || now.Sub(f.LastFailedAt) > p.Window {
+		f = LoginFailures{}
+	}
+	f.Count++
+	f.LastFailedAt = now
+	if f.Count >= p.Threshold && f.LockedUntil.IsZero() {
+		f.LockedUntil = now.Add(p.Duration)
+	}
+	return f
+}
+
+// 試行を1回予約する
+// 待ち時間中・ロック中でなければ、結果を待たずに失敗として数える(同時の試行でもしきい値を超えて検証しない)
+// 受け付けない場合は記録を変えずに、次の試行を受け付ける時刻を返す
+func (f LoginFailures) Reserve(now time.Time, p LockoutPolicy) (LoginFailures, time.Time) {
+	if retryAt := f.RetryAt(p); now.Before(retryAt) {
+		return f, retryAt
+	}
+	return f.Fail(now, p), time.Time{}
+}
+
+// 予約した試行を取り消す(失敗として数えない場合)
+// しきい値を下回る場合はロックも解除する
+func (f LoginFailures) Release(p LockoutPolicy) LoginFailures {
+	if f.Count <= 1 {
+		return LoginFailures{}
+	}
+	f.Count--
+	if f.Count < p.Threshold {
+		f.LockedUntil = time.Time{}
+	}
+	return f
+}
+
+// ロックされているか
+func (f LoginFailures) Locked(now time.Time) bool {
+	return now.Before(f.LockedUntil)
+}
+
+// 次の試行を受け付ける時刻(ロック中はロックの期限、それ以外は失敗回数に応じた待ち時間の後)
+func (f LoginFailures) RetryAt(p LockoutPolicy) time.Time {
+	if !f.LockedUntil.IsZero() {
+		return f.LockedUntil
+	}
+	if f.Count == 0 {
+		return time.Time{}
+	}
+	return f.LastFailedAt.Add(p.Delay(f.Count))
+}
+
+// 試行が多すぎて受け付けない
+// メールアドレスが存在するかどうかに関わらず同じように返す
+type LockedError struct {
+	RetryAt time.Time
+}
+
+func (e *LockedError) Error() string {
+	return "too many failed attempts"
+}
+
+// アカウントの失敗を記録するキー
+func AccountKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// IPアドレスの失敗を記録するキー
+func IPKey(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,57 @@
+package domain_auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// 回復コードの数
+const RecoveryCodeCount = 10
+
+// ユーザーの2要素認証(TOTP)
+// 登録後、コードで確認するまでは有効にしない
+type MFA struct {
+	UserID        string     `json:"user_id"         db:"user_id"`
+	Secret        string     `json:"-"               db:"secret"`
+	ConfirmedAt   *time.Time `json:"confirmed_at"    db:"confirmed_at"`
+	LastUsedStep  int64      `json:"-"               db:"last_used_step"` // 最後に使ったTOTPのステップ(再利用防止)
+	RecoveryCodes []string   `json:"-"               db:"recovery_codes"` // 未使用の回復コードのハッシュ
+	CreatedAt     time.Time  `json:"created_at"      db:"created_at"`
+}
+
+// 有効か(確認済みか)
+func (m MFA) Enabled() bool {
+	return m.ConfirmedAt != nil
+}
+
+// 回復コードを生成し、コードとハッシュを返す
+// 形式は "xxxxx-xxxxx"(16進数)
+func GenerateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		s := hex.EncodeToString(b)
+		codes[i] = s[:5] + "-" + s[5:]
+		hashes[i] = HashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// 回復コードのハッシュ(区切り・大文字小文字・空白は無視する)
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// 2要素認証の失敗を記録するキー
+func MFAKey(userId string) string {
+	return "mfa:" + userId
+}
@@ -0,0 +1,19 @@
+package domain_auth
+
+// ユーザーのロール
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// roleでrequiredの権限を満たすか(管理者は一般ユーザーの権限も持つ)
+func RoleAllows(role string, required string) bool {
+	switch role {
+	case RoleAdmin:
+		return required == RoleAdmin || required == RoleUser
+	case RoleUser:
+		return required == RoleUser
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package domain_auth
+
+import (
+	"strings"
+	"time"
+)
+
+// ログインのセッションの有効期間(アクセストークンの有効期間と同じ)
+const SessionLifetime = 24 * time.Hour
+
+// ログインのセッション
+// アクセストークンを発行するごとに作成し、トークンにIDを含める
+type Session struct {
+	ID         string     `json:"id"           db:"id"`
+	UserID     string     `json:"user_id"      db:"user_id"`
+	Device     string     `json:"device"       db:"device"`
+	IP         string     `json:"ip"           db:"ip"`
+	UserAgent  string     `json:"user_agent"   db:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"   db:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt  *time.Time `json:"revoked_at"   db:"revoked_at"`
+
+	// 作成時のユーザーのロール(アクセストークンに含める。保存はしない)
+	Role string `json:"-" db:"-"`
+}
+
+// 有効か(失効しておらず、期限内)
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.CreatedAt.Add(SessionLifetime))
+}
+
+// User-Agentに含まれる文字列と端末の種類(一覧での表示用)
+// 順に判定するため、より具体的なものを先に並べる
+var devices = []struct {
+	keyword string
+	name    string
+}{
+	{"iPhone", "iPhone"},
+	{"iPad", "iPad"},
+	{"Android", "Android"},
+	{"Windows", "Windows"},
+	{"Macintosh", "Mac"},
+	{"CrOS", "ChromeOS"},
+	{"Linux", "Linux"},
+	{"curl/", "curl"},
+}
+
+// User-Agentから端末の種類を推定する
+func DeviceName(userAgent string) string {
+	for _, d := range devices {
+		if strings.Contains(userAgent, d.keyword) {
+			return d.name
+		}
+	}
+	return "Unknown"
+}
@@ -0,0 +1,80 @@
+package domain_auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// パスワードの最小の長さ
+const MinPasswordLength = 8
+
+// メールで送るトークンの用途
+type TokenPurpose string
+
+const (
+	PurposePasswordReset     TokenPurpose = "password_reset"     // パスワードの再設定
+	PurposeEmailVerification TokenPurpose = "email_verification" // メールアドレスの確認
+)
+
+// メールで送る1回限りのトークン
+// トークンそのものは保存せず、SHA-256のハッシュを保存する
+type UserToken struct {
+	ID        string       `json:"id"         db:"id"`
+	UserID    string       `json:"user_id"    db:"user_id"`
+	Purpose   TokenPurpose `json:"purpose"    db:"purpose"`
+	Hash      string       `json:"-"          db:"hash"`
+	Email     string       `json:"email"      db:"email"` // 発行した時点のメールアドレス
+	ExpiresAt time.Time    `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time   `json:"used_at"    db:"used_at"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+}
+
+// メールを送る対象のアカウント
+type Account struct {
+	ID              string     `json:"id"                db:"id"`
+	Username        string     `json:"username"          db:"username"`
+	Email           string     `json:"email"             db:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at" db:"email_verified_at"`
+}
+
+// トークンを生成し、トークンと保存するハッシュを返す
+// 形式は "{乱数}.{署名}"。署名は用途ごとに異なり、別の用途のトークンとしては使えない
+func GenerateUserToken(secret []byte, purpose TokenPurpose) (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(b)
+	return nonce + "." + signUserToken(secret, purpose, nonce), hashUserToken(nonce), nil
+}
+
+// トークンの署名を確認し、保存されたハッシュと照合する値を返す
+// 署名が一致しない場合は保存先を参照せずに拒否できる
+func VerifyUserToken(secret []byte, purpose TokenPurpose, token string) (string, bool) {
+	nonce, signature, ok := strings.Cut(token, ".")
+	if !ok || nonce == "" {
+		return "", false
+	}
+	if !hmac.Equal([]byte(signature), []byte(signUserToken(secret, purpose, nonce))) {
+		return "", false
+	}
+	return hashUserToken(nonce), true
+}
+
+// トークンの署名(HMAC-SHA256)
+func signUserToken(secret []byte, purpose TokenPurpose, nonce string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(string(purpose) + "." + nonce))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// トークンのハッシュ
+func hashUserToken(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,241 @@
+package domain_fetch
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// 投稿(JSONPlaceholderの/posts)
+type Post struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"userId"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+// コメント(JSONPlaceholderの/comments)
+type Comment struct {
+	ID     int    `json:"id"`
+	PostID int    `json:"postId"`
+	Name   string `json:"name"`
+	Email  string `json:"email"`
+	Body   string `json:"body"`
+}
+
+// アルバム(JSONPlaceholderの/albums)
+type Album struct {
+	ID     int    `json:"id"`
+	UserID int    `json:"userId"`
+	Title  string `json:"title"`
+}
+
+// ユーザー(JSONPlaceholderの/users)
+// 住所・会社などの入れ子の項目は使わないため持たない
+type User struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Phone    string `json:"phone"`
+	Website  string `json:"website"`
+}
+
+// コメント付きの投稿
+type PostWithComments struct {
+	Post
+	Comments []Comment `json:"comments"`
+}
+
+// アルバム付きのユーザー
+type UserWithAlbums struct {
+	User
+	Albums []Album `json:"albums"`
+}
+
+// 投稿にコメントを結合する(投稿の順序を保つ)
+func JoinPosts(posts []Post, comments []Comment) []PostWithComments {
+	byPost := map[int][]Comment{}
+	for _, c := range comments {
+		byPost[c.PostID] = append(byPost[c.PostID], c)
+	}
+	result := make([]PostWithComments, len(posts))
+	for i, p := range posts {
+		result[i] = PostWithComments{Post: p, Comments: byPost[p.ID]}
+		if result[i].Comments == nil {
+			result[i].Comments = []Comment{}
+		}
+	}
+	return result
+}
+
+// ユーザーにアルバムを結合する(ユーザーの順序を保つ)
+func JoinUsers(users []User, albums []Album) []UserWithAlbums {
+	byUser := map[int][]Album{}
+	for _, a := range albums {
+		byUser[a.UserID] = append(byUser[a.UserID], a)
+	}
+	result := make([]UserWithAlbums, len(users))
+	for i, u := range users {
+		result[i] = UserWithAlbums{User: u, Albums: byUser[u.ID]}
+		if result[i].Albums == nil {
+			result[i].Albums = []Album{}
+		}
+	}
+	return result
+}
+
+// 集約するリソース
+type AggregateResource string
+
+const (
+	AggregatePosts AggregateResource = "posts" // コメント付きの投稿
+	AggregateUsers AggregateResource = "users" // アルバム付きのユーザー
+)
+
+// リソースごとの選択できる項目
+// 結合した一覧の項目は "comments.email" のように指定する
+var aggregateFields = map[AggregateResource]map[string][]string{
+	AggregatePosts: {
+		"":         {"id", "userId", "title", "body", "comments"},
+		"comments": {"id", "postId", "name", "email", "body"},
+	},
+	AggregateUsers: {
+		"":       {"id", "name", "username", "email", "phone", "website", "albums"},
+		"albums": {"id", "userId", "title"},
+	},
+}
+
+// 集約の条件
+type AggregateQuery struct {
+	Resource AggregateResource
+	Fields   []string // 空の場合は全ての項目
+	Page     int      // 1始まり
+	PerPage  int
+}
+
+// 集約の結果(1ページ分)
+type AggregateResult struct {
+	Items      []map[string]any `json:"items"`
+	Page       int              `json:"page"`
+	PerPage    int              `json:"per_page"`
+	Total      int              `json:"total"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// 項目の指定を確認する
+// エラーの場合は問題のある項目を返す
+func ValidateFields(resource AggregateResource, fields []string) (string, error) {
+	known, ok := aggregateFields[resource]
+	if !ok {
+		return "", errors.New("unknown resource")
+	}
+	for _, f := range fields {
+		parent, child, nested := strings.Cut(f, ".")
+		if !nested {
+			parent, child = "", f
+		}
+		if !contains(known[parent], child) {
+			return f, errors.New("unknown field")
+		}
+	}
+	return "", nil
+}
+
+// 文字列が一覧に含まれるか
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// 指定した項目だけを残す
+// "comments" のように結合した一覧の名前だけを指定した場合は、その一覧の全ての項目を残す
+func SelectFields(items any, fields []string) ([]map[string]any, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	result := []map[string]any{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []map[string]any{}
+	}
+	if len(fields) == 0 {
+		return result, nil
+	}
+
+	// 親の項目ごとに、残す子の項目をまとめる(nilは全て)
+	selected := map[string][]string{}
+	order := []string{}
+	for _, f := range fields {
+		parent, child, nested := strings.Cut(f, ".")
+		children, seen := selected[parent]
+		if !seen {
+			order = append(order, parent)
+		}
+		switch {
+		case !nested:
+			selected[parent] = nil
+		case !seen:
+			selected[parent] = []string{child}
+		case children != nil:
+			selected[parent] = append(children, child)
+		}
+	}
+
+	for i, item := range result {
+		picked := make(map[string]any, len(order))
+		for _, name := range order {
+			value, ok := item[name]
+			if !ok {
+				continue
+			}
+			if children := selected[name]; children != nil {
+				value = pickChildren(value, children)
+			}
+			picked[name] = value
+		}
+		result[i] = picked
+	}
+	return result, nil
+}
+
+// 一覧の各要素から指定した項目だけを残す
+func pickChildren(value any, children []string) any {
+	list, ok := value.([]any)
+	if !ok {
+		return value
+	}
+	for i, v := range list {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		picked := make(map[string]any, len(children))
+		for _, c := range children {
+			if cv, ok := obj[c]; ok {
+				picked[c] = cv
+			}
+		}
+		list[i] = picked
+	}
+	return list
+}
+
+// ページの範囲[start, end)と総ページ数
+// 総ページ数を超えるページは空の範囲にする(大きなページ番号で掛け算が溢れないよう先に判定する)
+func Paginate(total, page, perPage int) (int, int, int) {
+	totalPages := (total + perPage - 1) / perPage
+	if page > totalPages {
+		return total, total, totalPages
+	}
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+	return start, end, totalPages
+}
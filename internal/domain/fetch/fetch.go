@@ -0,0 +1,225 @@
+package domain_fetch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// 上流APIへのリクエスト
+// PathはTEST_APIからの相対パスで、任意のホストへは送れない
+type UpstreamRequest struct {
+	Name    string            `json:"name"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// 送信できるメソッド
+var allowedMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodHead:   true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+// 副作用が無く、繰り返し送ってよいメソッドかどうか
+func (r UpstreamRequest) IsSafe() bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// 上流APIへ転送しないヘッダ(ホップバイホップ・認証情報)
+var droppedHeaders = map[string]bool{
+	"Connection":          true,
+	"Keep-Alive":          true,
+	"Proxy-Authenticate":  true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+	"Host":                true,
+	"Content-Length":      true,
+	"Authorization":       true,
+	"Cookie":              true,
+}
+
+// 転送するヘッダ(転送しないヘッダと、Connectionで指定されたヘッダを除く)
+func forwardedHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return headers
+	}
+	dropped := map[string]bool{}
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) != "Connection" {
+			continue
+		}
+		for _, name := range strings.Split(v, ",") {
+			dropped[http.CanonicalHeaderKey(strings.TrimSpace(name))] = true
+		}
+	}
+	out := map[string]string{}
+	for k, v := range headers {
+		name := http.CanonicalHeaderKey(k)
+		if droppedHeaders[name] || dropped[name] {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// リクエストの既定値を補い、内容を確認する
+// 転送しないヘッダはここで取り除く
+func (r *UpstreamRequest) Normalize() error {
+	if r.Name == "" {
+		return errors.New("name is empty")
+	}
+	r.Method = strings.ToUpper(r.Method)
+	if r.Method == "" {
+		r.Method = http.MethodGet
+	}
+	if !allowedMethods[r.Method] {
+		return errors.New("method is not allowed")
+	}
+	if !strings.HasPrefix(r.Path, "/") || strings.HasPrefix(r.Path, "//") || strings.Contains(r.Path, "://") {
+		return errors.New("path must be relative")
+	}
+	r.Headers = forwardedHeaders(r.Headers)
+	return nil
+}
+
+// 上流APIごとの結果
+type Outcome string
+
+const (
+	OutcomeSucceeded Outcome = "succeeded" // 2xx/3xxのレスポンスを受け取った
+	OutcomeFailed    Outcome = "failed"    // 通信エラー、または4xx/5xxのレスポンス
+	OutcomeTimedOut  Outcome = "timed_out" // 期限までに完了しなかった
+	OutcomeCanceled  Outcome = "canceled"  // 他の失敗(fail_fast)や切断で中断した
+)
+
+// 上流APIのレスポンス
+type UpstreamResponse struct {
+	Name      string          `json:"-"`
+	Outcome   Outcome         `json:"outcome,omitempty"`
+	Status    int             `json:"status"`
+	LatencyNs int64           `json:"latency_ns"`
+	Attempts  int             `json:"attempts,omitempty"` // 試行回数(再試行を含む)
+	Cache     string          `json:"cache,omitempty"`    // キャッシュの状態(HIT/MISS/STALE/REVALIDATED/BYPASS)
+	Size      int             `json:"size"`               // ボディのバイト数
+	Body      json.RawMessage `json:"body,omitempty"`     // JSONの場合はそのまま、それ以外は文字列
+	Error     string          `json:"error,omitempty"`    // 通信に失敗した場合のエラー
+}
+
+// レスポンスのボディを設定する
+// JSONとして正しい場合はそのまま、それ以外は文字列として保持する
+func (r *UpstreamResponse) SetBody(body []byte) {
+	r.Size = len(body)
+	if len(body) == 0 {
+		return
+	}
+	trimmed := bytes.TrimSpace(body)
+	if json.Valid(trimmed) {
+		r.Body = trimmed
+		return
+	}
+	r.Body, _ = json.Marshal(string(body))
+}
+
+// 上流APIのレスポンス一覧
+// JSONではリクエストの順序を保ったまま、名前をキーにしたオブジェクトとして書き出す
+type UpstreamResults []UpstreamResponse
+
+// JSONへの変換
+func (rs UpstreamResults) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, r := range rs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(r.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// 実行時間
+type FetchTiming struct {
+	Mode           string  `json:"mode"`             // parallel または series
+	Concurrency    int     `json:"concurrency"`      // 同時実行数
+	ElapsedNs      int64   `json:"elapsed_ns"`       // 全体の実行時間
+	TotalLatencyNs int64   `json:"total_latency_ns"` // 各リクエストの所要時間の合計(逐次実行した場合の目安)
+	Speedup        float64 `json:"speedup"`          // total_latency_ns / elapsed_ns
+	SeriesNs       *int64  `json:"series_ns,omitempty"`
+	ParallelNs     *int64  `json:"parallel_ns,omitempty"`
+}
+
+// 結果の集計
+type FetchSummary struct {
+	Succeeded int  `json:"succeeded"`
+	Failed    int  `json:"failed"`
+	TimedOut  int  `json:"timed_out"`
+	Canceled  int  `json:"canceled"`
+	Partial   bool `json:"partial"` // 成功しなかったものがあるか
+}
+
+// 結果を集計する
+func (rs UpstreamResults) Summary() FetchSummary {
+	s := FetchSummary{}
+	for _, r := range rs {
+		switch r.Outcome {
+		case OutcomeSucceeded:
+			s.Succeeded++
+		case OutcomeFailed:
+			s.Failed++
+		case OutcomeTimedOut:
+			s.TimedOut++
+		case OutcomeCanceled:
+			s.Canceled++
+		}
+	}
+	s.Partial = s.Succeeded < len(rs)
+	return s
+}
+
+// 一括取得の結果
+type FetchResult struct {
+	Results UpstreamResults `json:"results"`
+	Summary FetchSummary    `json:"summary"`
+	Timing  FetchTiming     `json:"timing"`
+}
+
+// 失敗時の動作
+type ErrorMode string
+
+const (
+	CollectAll ErrorMode = "collect_all" // 失敗しても残りのリクエストを続け、全ての結果を返す
+	FailFast   ErrorMode = "fail_fast"   // 最初の失敗で残りのリクエストを中断する
+)
+
+// 一括取得のオプション
+type FetchOptions struct {
+	Concurrency int           // 同時実行数(1の場合は逐次実行)
+	Compare     bool          // 逐次実行と並列実行の両方を行い、実行時間を比較する
+	Deadline    time.Duration // 全体の期限(0の場合は期限なし)
+	ErrorMode   ErrorMode     // 失敗時の動作(空の場合はcollect_all)
+}
@@ -0,0 +1,86 @@
+package domain_ratelimit
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// レート制限のアルゴリズム
+type Algorithm string
+
+const (
+	TokenBucket   Algorithm = "token_bucket"   // 一定の速度で補充されるトークンを消費する(バーストを許す)
+	SlidingWindow Algorithm = "sliding_window" // 直前のwindowの間のリクエスト数を数える(前後の窓の件数を按分して近似)
+)
+
+// 制限の単位
+type KeyBy string
+
+const (
+	KeyByIP     KeyBy = "ip"      // クライアントのIPアドレス
+	KeyByUser   KeyBy = "user"    // 認証済みのユーザーID(未認証の場合はIPアドレス)
+	KeyByAPIKey KeyBy = "api_key" // X-API-Keyヘッダ(ない場合はIPアドレス)
+)
+
+// レート制限のポリシー
+// MethodとPathに一致するルートに適用する。Pathの末尾が"*"の場合は前方一致
+type Policy struct {
+	Name      string
+	Method    string // 空の場合は全てのメソッド
+	Path      string
+	Algorithm Algorithm
+	KeyBy     KeyBy
+	Limit     int           // window当たりのリクエスト数(トークンバケットでは容量)
+	Window    time.Duration // 期間
+}
+
+// ポリシーの内容を確認する
+func (p Policy) Validate() error {
+	switch p.Algorithm {
+	case TokenBucket, SlidingWindow:
+	default:
+		return errors.New("unknown algorithm")
+	}
+	switch p.KeyBy {
+	case KeyByIP, KeyByUser, KeyByAPIKey:
+	default:
+		return errors.New("unknown key")
+	}
+	if p.Limit < 1 || p.Window <= 0 {
+		return errors.New("limit and window must be positive")
+	}
+	if !strings.HasPrefix(p.Path, "/") {
+		return errors.New("path must start with /")
+	}
+	return nil
+}
+
+// ルートに一致するか
+func (p Policy) Matches(method, path string) bool {
+	if p.Method != "" && !strings.EqualFold(p.Method, method) {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(p.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return p.Path == path
+}
+
+// キーごとの状態
+// トークンバケットはTokensとStamp(最後に補充した時刻)、スライディングウィンドウはCount・PrevCountとStamp(現在の窓の開始時刻)を使う
+type State struct {
+	Tokens    float64
+	Count     int
+	PrevCount int
+	Stamp     time.Time
+}
+
+// 判定の結果
+type Decision struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // 制限が元に戻るまでの時間
+	RetryAfter time.Duration // 拒否した場合に、次に許可されるまでの時間
+}
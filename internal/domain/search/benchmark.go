@@ -0,0 +1,76 @@
+package domain_search
+
+// データの分布
+type Distribution string
+
+const (
+	DistributionUniform    Distribution = "uniform"    // [min, max] の一様分布
+	DistributionSequential Distribution = "sequential" // min からの連番
+	DistributionNormal     Distribution = "normal"     // 中央に集まる正規分布
+	DistributionFewUnique  Distribution = "few_unique" // 少数の値が繰り返し現れる
+)
+
+// ベンチマーク対象の探索アルゴリズム
+type SearchAlgorithm string
+
+const (
+	AlgorithmLinear        SearchAlgorithm = "linear"
+	AlgorithmBinary        SearchAlgorithm = "binary"
+	AlgorithmJump          SearchAlgorithm = "jump"
+	AlgorithmInterpolation SearchAlgorithm = "interpolation"
+	AlgorithmExponential   SearchAlgorithm = "exponential"
+)
+
+// ソート済みのデータを必要とするか
+func (a SearchAlgorithm) RequiresSorted() bool {
+	return a != AlgorithmLinear
+}
+
+// データセットの生成条件
+// 同じseedからは常に同じデータが生成される
+type DatasetSpec struct {
+	Size         int          `json:"size"`
+	Distribution Distribution `json:"distribution"`
+	Sorted       bool         `json:"sorted"`
+	Seed         int64        `json:"seed"`
+	Min          int          `json:"min"`
+	Max          int          `json:"max"` // 0の場合は size*10
+}
+
+// ベンチマークのリクエスト
+// DatasetとDataのどちらかを指定する
+type BenchmarkRequest struct {
+	Dataset     *DatasetSpec      `json:"dataset"`
+	Data        []int             `json:"data"`
+	Targets     []int             `json:"targets"`      // 省略時はseedから生成する
+	TargetCount int               `json:"target_count"` // 生成するtargetの数
+	Algorithms  []SearchAlgorithm `json:"algorithms"`
+	Iterations  int               `json:"iterations"` // 計測回数
+	Warmup      int               `json:"warmup"`     // 計測前の空実行の回数(iterationsと共に省略時は既定値)
+}
+
+// アルゴリズムごとの計測結果
+// 1回の実行は全てのtargetを1回ずつ探索する
+type AlgorithmStats struct {
+	Algorithm    SearchAlgorithm `json:"algorithm"`
+	Runs         int             `json:"runs"`
+	MinNs        int64           `json:"min_ns"`
+	MedianNs     int64           `json:"median_ns"`
+	P95Ns        int64           `json:"p95_ns"`
+	MeanNs       int64           `json:"mean_ns"`
+	Comparisons  int64           `json:"comparisons"`        // 1回の実行あたりの比較回数
+	ApproxAllocs int64           `json:"approx_allocs"`      // 計測中のアロケーション回数(プロセス全体のため概算)
+	ApproxBytes  int64           `json:"approx_alloc_bytes"` // 計測中のアロケーションバイト数(プロセス全体のため概算)
+	Found        int             `json:"found"`              // 見つかったtargetの数
+}
+
+// ベンチマークの結果
+type BenchmarkResult struct {
+	Size       int              `json:"size"`
+	Sorted     bool             `json:"sorted"`
+	Seed       int64            `json:"seed"`
+	Targets    int              `json:"targets"`
+	Iterations int              `json:"iterations"`
+	Warmup     int              `json:"warmup"`
+	Results    []AlgorithmStats `json:"results"`
+}
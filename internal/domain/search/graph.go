@@ -0,0 +1,176 @@
+package domain_search
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+)
+
+// 辺(エッジリスト形式の入力)
+type Edge struct {
+	From   string   `json:"from"`
+	To     string   `json:"to"`
+	Weight *float64 `json:"weight,omitempty"` // 省略時は1
+}
+
+// 隣接ノード(隣接リスト形式の入力)
+// "b" のような文字列、または {"to": "b", "weight": 2} のどちらでも指定できる
+type Neighbor struct {
+	To     string   `json:"to"`
+	Weight *float64 `json:"weight,omitempty"` // 省略時は1
+}
+
+// 文字列またはオブジェクトからNeighborを読み込む
+func (n *Neighbor) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*n = Neighbor{To: id}
+		return nil
+	}
+	type neighbor Neighbor
+	var v neighbor
+	if err := json.Unmarshal(data, &v); err != nil {
+		return errors.New("neighbor must be a string or an object")
+	}
+	*n = Neighbor(v)
+	return nil
+}
+
+// グラフの入力
+// 隣接リスト(adjacency)とエッジリスト(edges)のどちらか、または両方で指定する
+type GraphInput struct {
+	Directed  bool                  `json:"directed"`
+	Nodes     []string              `json:"nodes,omitempty"` // 孤立ノードを含める場合に指定
+	Adjacency map[string][]Neighbor `json:"adjacency,omitempty"`
+	Edges     []Edge                `json:"edges,omitempty"`
+}
+
+// グラフの辺(内部表現)
+type GraphEdge struct {
+	To     int
+	Weight float64
+}
+
+// グラフ(隣接リスト)
+// ノードは0からの連番で管理し、Nodesでノードidに戻す
+type Graph struct {
+	Directed bool
+	Nodes    []string
+	Adj      [][]GraphEdge
+	index    map[string]int
+}
+
+// 入力からグラフを作成する
+// ノードの順序は nodes → adjacencyのキー(辞書順) → edges の出現順 とし、結果を決定的にする
+func NewGraph(input GraphInput) (*Graph, error) {
+	g := &Graph{
+		Directed: input.Directed,
+		index:    map[string]int{},
+	}
+
+	for _, id := range input.Nodes {
+		if id == "" {
+			return nil, errors.New("node id is empty")
+		}
+		g.addNode(id)
+	}
+
+	// 隣接リスト
+	keys := make([]string, 0, len(input.Adjacency))
+	for k := range input.Adjacency {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, from := range keys {
+		if from == "" {
+			return nil, errors.New("node id is empty")
+		}
+		g.addNode(from)
+		for _, n := range input.Adjacency[from] {
+			if n.To == "" {
+				return nil, errors.New("node id is empty")
+			}
+			g.addEdge(from, n.To, weightOf(n.Weight))
+		}
+	}
+
+	// エッジリスト
+	for _, e := range input.Edges {
+		if e.From == "" || e.To == "" {
+			return nil, errors.New("node id is empty")
+		}
+		g.addEdge(e.From, e.To, weightOf(e.Weight))
+	}
+
+	if len(g.Nodes) == 0 {
+		return nil, errors.New("graph is empty")
+	}
+	return g, nil
+}
+
+// 重みの既定値
+func weightOf(w *float64) float64 {
+	if w == nil {
+		return 1
+	}
+	return *w
+}
+
+// ノードを追加する(既に存在する場合は何もしない)
+func (g *Graph) addNode(id string) int {
+	if i, ok := g.index[id]; ok {
+		return i
+	}
+	g.index[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, id)
+	g.Adj = append(g.Adj, nil)
+	return len(g.Nodes) - 1
+}
+
+// 辺を追加する(無向グラフの場合は逆向きの辺も追加する)
+func (g *Graph) addEdge(from, to string, weight float64) {
+	f := g.addNode(from)
+	t := g.addNode(to)
+	g.Adj[f] = append(g.Adj[f], GraphEdge{To: t, Weight: weight})
+	if !g.Directed && f != t {
+		g.Adj[t] = append(g.Adj[t], GraphEdge{To: f, Weight: weight})
+	}
+}
+
+// ノードidから番号を取得する
+func (g *Graph) Index(id string) (int, bool) {
+	i, ok := g.index[id]
+	return i, ok
+}
+
+// 辺の数
+func (g *Graph) EdgeCount() int {
+	count := 0
+	for _, edges := range g.Adj {
+		count += len(edges)
+	}
+	return count
+}
+
+// 番号のリストをノードidのリストに変換する
+func (g *Graph) IDs(indexes []int) []string {
+	ids := make([]string, len(indexes))
+	for i, n := range indexes {
+		ids[i] = g.Nodes[n]
+	}
+	return ids
+}
+
+// グラフ探索の結果
+// アルゴリズムによって設定される項目が異なる
+type GraphResult struct {
+	Algorithm  string     `json:"algorithm"`
+	Order      []string   `json:"order,omitempty"`      // 訪問順/トポロジカル順
+	Components [][]string `json:"components,omitempty"` // 連結成分/強連結成分
+	HasCycle   *bool      `json:"has_cycle,omitempty"`  // 閉路の有無
+	Cycle      []string   `json:"cycle,omitempty"`      // 見つかった閉路
+	Nodes      int        `json:"nodes"`                // ノード数
+	Edges      int        `json:"edges"`                // 辺の数(無向グラフは両方向を数える)
+	Visited    int        `json:"visited"`              // 訪問したノード数
+	ElapsedNs  int64      `json:"elapsed_ns"`           // 実行時間(ナノ秒)
+}
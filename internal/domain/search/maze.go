@@ -0,0 +1,115 @@
+package domain_search
+
+import (
+	"errors"
+	"strings"
+)
+
+// 迷路の生成アルゴリズム
+type MazeAlgorithm string
+
+const (
+	MazeBacktracker MazeAlgorithm = "backtracker" // 再帰的バックトラッキング(長い一本道が多い)
+	MazePrim        MazeAlgorithm = "prim"        // プリム法(短い分岐が多い)
+	MazeKruskal     MazeAlgorithm = "kruskal"     // クラスカル法(偏りが少ない)
+)
+
+// 迷路の値(BFS/DFSのグリッドと同じ)
+const (
+	MazePath = GridPath
+	MazeWall = GridWall
+)
+
+// 迷路のテキスト形式
+type MazeFormat string
+
+const (
+	MazeFormatASCII  MazeFormat = "ascii"  // 壁: '#'、通路: '.'
+	MazeFormatBinary MazeFormat = "binary" // 壁: '1'、通路: '0'
+)
+
+// 迷路の生成条件
+// 行数・列数は奇数で、偶数の座標が部屋、奇数の座標が壁になる
+// 左上と右下は必ず通路になるため、そのままBFS/DFSに渡せる
+type MazeSpec struct {
+	Rows      int           `json:"rows"`
+	Cols      int           `json:"cols"`
+	Algorithm MazeAlgorithm `json:"algorithm"`
+	Seed      int64         `json:"seed"`
+	Density   *float64      `json:"density"` // 壁の密度(0〜1)。1(既定)で完全迷路、小さいほど壁を取り除いてループを増やす
+}
+
+// 生成した迷路
+type MazeResult struct {
+	Rows      int           `json:"rows"`
+	Cols      int           `json:"cols"`
+	Algorithm MazeAlgorithm `json:"algorithm"`
+	Seed      int64         `json:"seed"`
+	Grid      [][]int       `json:"grid"`
+	Text      string        `json:"text"`
+}
+
+// テキストを迷路に変換する
+// 壁: '#' '1' 'X'、通路: '.' '0' ' '。'S'と'G'は通路として扱う
+// エラーの場合は問題のある行番号(1始まり)を返す
+func ParseMaze(text string) ([][]int, int, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Trim(text, "\n")
+	if text == "" {
+		return nil, 0, errors.New("maze text is empty")
+	}
+
+	lines := strings.Split(text, "\n")
+	grid := make([][]int, len(lines))
+	width := -1
+	for i, line := range lines {
+		// 末尾の空白は省略されることがあるため、通路で補う
+		if width >= 0 && len(line) < width {
+			line += strings.Repeat(" ", width-len(line))
+		}
+		row := make([]int, 0, len(line))
+		for _, ch := range line {
+			switch ch {
+			case '#', '1', 'X':
+				row = append(row, MazeWall)
+			case '.', '0', ' ', 'S', 'G':
+				row = append(row, MazePath)
+			default:
+				return nil, i + 1, errors.New("invalid maze character")
+			}
+		}
+		if width < 0 {
+			width = len(row)
+		}
+		if len(row) != width {
+			return nil, i + 1, errors.New("maze rows must have the same length")
+		}
+		grid[i] = row
+	}
+	return grid, 0, nil
+}
+
+// 迷路をテキストに変換する
+func FormatMaze(grid [][]int, format MazeFormat) (string, error) {
+	wall, path := byte('#'), byte('.')
+	switch format {
+	case MazeFormatASCII, "":
+	case MazeFormatBinary:
+		wall, path = '1', '0'
+	default:
+		return "", errors.New("unknown maze format")
+	}
+
+	var sb strings.Builder
+	for _, row := range grid {
+		for _, cell := range row {
+			if cell == MazePath {
+				sb.WriteByte(path)
+			} else {
+				sb.WriteByte(wall)
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
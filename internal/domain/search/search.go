@@ -0,0 +1,32 @@
+package domain_search
+
+// グリッドの値(BFS/DFS/ダイクストラ法/A*・迷路で共通)
+const (
+	GridPath = 0
+	GridWall = 1
+)
+
+// グリッド上の座標
+type Point struct {
+	Row int `json:"row"`
+	Col int `json:"col"`
+}
+
+// 経路探索の結果
+type PathResult struct {
+	Found    bool    `json:"found"`    // ゴールに到達できたか
+	Cost     float64 `json:"cost"`     // 経路の総コスト(到達できない場合は-1)
+	Path     []Point `json:"path"`     // スタートからゴールまでの経路
+	Expanded int     `json:"expanded"` // 展開したノード数
+}
+
+// A*のヒューリスティック
+type Heuristic string
+
+const (
+	HeuristicManhattan Heuristic = "manhattan" // 上下左右のみの移動向け(斜め移動では使えない)
+	HeuristicEuclidean Heuristic = "euclidean" // 直線距離
+	HeuristicChebyshev Heuristic = "chebyshev" // 斜め移動のコストを1とみなす
+	HeuristicOctile    Heuristic = "octile"    // 斜め移動のコストを√2とみなす
+	HeuristicZero      Heuristic = "zero"      // 常に0(ダイクストラ法と同じ)
+)
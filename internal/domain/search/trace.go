@@ -0,0 +1,51 @@
+package domain_search
+
+// 途中経過のイベントの種類
+// argsの内容は種類ごとに決まっている
+type TraceEventType string
+
+const (
+	TraceCompare  TraceEventType = "compare"  // args: [index, value] 配列の要素とtargetを比較
+	TraceBounds   TraceEventType = "bounds"   // args: [low, high, mid] 二分探索の範囲
+	TraceEnqueue  TraceEventType = "enqueue"  // args: [row, col, size] キューに追加(sizeは追加後の長さ)
+	TraceDequeue  TraceEventType = "dequeue"  // args: [row, col, size] キューから取り出し(sizeは取り出し後の長さ)
+	TracePush     TraceEventType = "push"     // args: [row, col, size] スタックに追加
+	TracePop      TraceEventType = "pop"      // args: [row, col, size] スタックから取り出し
+	TraceVisit    TraceEventType = "visit"    // args: [row, col] マスを訪問済みにする
+	TraceFrontier TraceEventType = "frontier" // args: [depth], cells: BFSの各深さでのキューの中身
+	TraceFound    TraceEventType = "found"    // args: [index] または [row, col] 見つかった位置
+	TraceDone     TraceEventType = "done"     // args: [] 探索の終了
+)
+
+// 途中経過のイベント(可視化用)
+type TraceEvent struct {
+	Step  int            `json:"step"`
+	Type  TraceEventType `json:"type"`
+	Args  []int          `json:"args,omitempty"`
+	Cells []Point        `json:"cells,omitempty"`
+}
+
+// 途中経過を受け取る関数
+// nilの場合は記録しない。呼び出し側はnil判定の中でイベントを作るため、無効時のコストは判定のみ
+type Tracer func(e TraceEvent)
+
+// 途中経過の記録
+type TraceLog struct {
+	Events    []TraceEvent `json:"events"`
+	Truncated bool         `json:"truncated"` // 上限で打ち切られたか
+}
+
+// 途中経過をmax件まで記録するTracerを作成する
+func NewTraceRecorder(max int) (*TraceLog, Tracer) {
+	log := &TraceLog{Events: []TraceEvent{}}
+	step := 0
+	return log, func(e TraceEvent) {
+		step++
+		if len(log.Events) >= max {
+			log.Truncated = true
+			return
+		}
+		e.Step = step
+		log.Events = append(log.Events, e)
+	}
+}
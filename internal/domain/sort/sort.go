@@ -0,0 +1,254 @@
+package domain_sort
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ソートアルゴリズム
+type Algorithm string
+
+const (
+	AlgorithmQuick     Algorithm = "quick"
+	AlgorithmMerge     Algorithm = "merge"
+	AlgorithmHeap      Algorithm = "heap"
+	AlgorithmInsertion Algorithm = "insertion"
+	AlgorithmRadix     Algorithm = "radix"
+	AlgorithmTim       Algorithm = "tim"
+)
+
+// 元々安定なソートか
+// 不安定なソートでstableが指定された場合は、元の位置を比較に加えて安定にする
+func (a Algorithm) IsStable() bool {
+	return a != AlgorithmQuick && a != AlgorithmHeap
+}
+
+// O(n^2)のソートか
+func (a Algorithm) IsQuadratic() bool {
+	return a == AlgorithmInsertion
+}
+
+// ソートキーの種類
+type KeyKind int
+
+const (
+	KindInt KeyKind = iota
+	KindFloat
+	KindString
+)
+
+// ソートキー
+// 整数と小数は数値として比較する
+type Key struct {
+	Kind  KeyKind
+	Int   int64
+	Float float64
+	Str   string
+}
+
+// キーの比較(a < b: 負、a == b: 0、a > b: 正)
+func (a Key) Compare(b Key) int {
+	if a.Kind == KindString {
+		return strings.Compare(a.Str, b.Str)
+	}
+	if a.Kind == KindInt && b.Kind == KindInt {
+		switch {
+		case a.Int < b.Int:
+			return -1
+		case a.Int > b.Int:
+			return 1
+		}
+		return 0
+	}
+	af, bf := a.number(), b.number()
+	switch {
+	case af < bf:
+		return -1
+	case af > bf:
+		return 1
+	}
+	return 0
+}
+
+// 数値として取得する
+func (a Key) number() float64 {
+	if a.Kind == KindInt {
+		return float64(a.Int)
+	}
+	return a.Float
+}
+
+// ソート対象の要素
+// オブジェクトの場合のみ、出力のために元のJSONを保持する
+type Item struct {
+	Key   Key
+	Raw   json.RawMessage
+	Index int // 入力での位置
+}
+
+// JSONの値から要素を作成する
+// 数値、文字列、オブジェクトのみ受け付ける
+func NewItem(raw json.RawMessage, index int) (Item, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return Item{}, errors.New("value must be a number, string or object")
+	}
+	switch raw[0] {
+	case '{':
+		// キーは全ての値を読み込んだ後にApplyKeyで設定する
+		return Item{Raw: append(json.RawMessage(nil), raw...), Index: index}, nil
+	case '"':
+		key, err := parseKey(raw)
+		return Item{Key: key, Index: index}, err
+	}
+	key, err := parseKey(raw)
+	if err != nil || key.Kind == KindString {
+		return Item{}, errors.New("value must be a number, string or object")
+	}
+	return Item{Key: key, Index: index}, nil
+}
+
+// JSONの数値・文字列をキーに変換する
+func parseKey(raw json.RawMessage) (Key, error) {
+	if len(raw) > 0 && raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return Key{}, err
+		}
+		return Key{Kind: KindString, Str: s}, nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(raw, &n); err != nil {
+		return Key{}, errors.New("key must be a number or string")
+	}
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return Key{Kind: KindInt, Int: i}, nil
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return Key{}, errors.New("key must be a number or string")
+	}
+	return Key{Kind: KindFloat, Float: f}, nil
+}
+
+// オブジェクトの要素にキーを設定し、全ての要素のキーの種類が揃っているかを確認する
+// keyはドット区切りのパス(例: "user.age")
+// エラーの場合は問題のある要素の位置を返す
+func ApplyKey(items []Item, key string) (int, error) {
+	var path []string
+	if key != "" {
+		path = strings.Split(key, ".")
+	}
+	for i := range items {
+		if items[i].Raw == nil {
+			if i > 0 && items[0].Raw != nil {
+				return i, errors.New("values must have the same type")
+			}
+			continue
+		}
+		if i > 0 && items[0].Raw == nil {
+			return i, errors.New("values must have the same type")
+		}
+		if path == nil {
+			return i, errors.New("key is required for objects")
+		}
+		raw, err := lookup(items[i].Raw, path)
+		if err != nil {
+			return i, err
+		}
+		k, err := parseKey(raw)
+		if err != nil {
+			return i, errors.New("key must be a number or string")
+		}
+		items[i].Key = k
+	}
+
+	// 文字列と数値の混在は不可
+	for i := 1; i < len(items); i++ {
+		if (items[i].Key.Kind == KindString) != (items[0].Key.Kind == KindString) {
+			return i, errors.New("values must have the same type")
+		}
+	}
+	return -1, nil
+}
+
+// パスを辿って値を取得する
+func lookup(raw json.RawMessage, path []string) (json.RawMessage, error) {
+	for _, name := range path {
+		obj := map[string]json.RawMessage{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, errors.New("key not found")
+		}
+		v, ok := obj[name]
+		if !ok {
+			return nil, errors.New("key not found")
+		}
+		raw = v
+	}
+	return raw, nil
+}
+
+// 全ての要素のキーが整数か
+func AllInts(items []Item) bool {
+	for _, it := range items {
+		if it.Key.Kind != KindInt {
+			return false
+		}
+	}
+	return true
+}
+
+// 要素をJSONとして書き出す
+func (it Item) AppendJSON(b []byte) []byte {
+	if it.Raw != nil {
+		return append(b, it.Raw...)
+	}
+	switch it.Key.Kind {
+	case KindInt:
+		return strconv.AppendInt(b, it.Key.Int, 10)
+	case KindFloat:
+		return strconv.AppendFloat(b, it.Key.Float, 'g', -1, 64)
+	}
+	s, _ := json.Marshal(it.Key.Str)
+	return append(b, s...)
+}
+
+// ソートのオプション
+type SortOptions struct {
+	Stable        bool // 安定ソートにする
+	Desc          bool // 降順
+	Trace         bool // 途中経過を記録する
+	MaxTraceSteps int  // 記録する途中経過の上限
+}
+
+// 途中経過の操作
+type StepOp string
+
+const (
+	StepCompare StepOp = "compare" // i番目とj番目を比較
+	StepSwap    StepOp = "swap"    // i番目とj番目を交換
+	StepSet     StepOp = "set"     // i番目に入力のj番目の要素を書き込む
+)
+
+// 途中経過(可視化用)
+type Step struct {
+	Op StepOp `json:"op"`
+	I  int    `json:"i"`
+	J  int    `json:"j"`
+}
+
+// ソートの結果
+// ソート済みの要素は巨大になりうるため含めず、ハンドラが直接書き出す
+type SortResult struct {
+	Algorithm   Algorithm `json:"algorithm"`
+	Count       int       `json:"count"`
+	Stable      bool      `json:"stable"` // 結果が安定であることが保証されるか
+	Desc        bool      `json:"desc"`
+	Comparisons int64     `json:"comparisons"`
+	ElapsedNs   int64     `json:"elapsed_ns"`
+	Steps       []Step    `json:"steps,omitempty"`
+	Truncated   bool      `json:"truncated,omitempty"` // 途中経過が上限で打ち切られたか
+}
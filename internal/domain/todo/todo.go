@@ -4,10 +4,23 @@ import "time"
 
 // Todo情報
 type Todo struct {
-	ID          string    `json:"id"          db:"id"`          // UUID型
-	Description string    `json:"description" db:"description"` // タスクの説明
-	Completed   bool      `json:"completed"   db:"completed"`   // タスクが完了しているかどうか
-	UserId      string    `json:"user_id"     db:"user_id"`     // ユーザーID
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`   // タイムスタンプ
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`   // タイムスタンプ
+	ID           string     `json:"id"                      db:"id"`            // UUID型
+	Description  string     `json:"description"             db:"description"`   // タスクの説明
+	Completed    bool       `json:"completed"               db:"completed"`     // タスクが完了しているかどうか
+	UserId       string     `json:"user_id"                 db:"user_id"`       // ユーザーID
+	WorkspaceID  string     `json:"workspace_id"            db:"workspace_id"`  // ワークスペースID
+	ParentID     string     `json:"parent_id"               db:"parent_id"`     // 親のTodoのID(空の場合はルート)
+	Position     string     `json:"position"                db:"position"`      // 兄弟の中での並び順のキー(pkg_rank、バイト順に比較する)
+	DueAt        *time.Time `json:"due_at,omitempty"        db:"due_at"`        // 期日
+	RecurrenceID string     `json:"recurrence_id,omitempty" db:"recurrence_id"` // 繰り返しのID(繰り返しの予定の場合)
+	OccurrenceAt *time.Time `json:"occurrence_at,omitempty" db:"occurrence_at"` // 繰り返しの中での予定日時(期日を変更しても変わらない)
+	CreatedAt    time.Time  `json:"created_at"              db:"created_at"`    // タイムスタンプ
+	UpdatedAt    time.Time  `json:"updated_at"              db:"updated_at"`    // タイムスタンプ
+}
+
+// Todoを操作するユーザーと、操作中のワークスペース
+// WorkspaceIDが空の場合は、ユーザーが所属する全てのワークスペースを対象にする
+type Scope struct {
+	UserID      string
+	WorkspaceID string
 }
@@ -0,0 +1,35 @@
+package domain_todo
+
+import "time"
+
+// 繰り返しのTodo(シリーズ)
+// 予定ごとのTodoは、MaterializedUntilより前の予定まで作成済み(スケジューラ・完了時に先へ進める)
+type Recurrence struct {
+	ID                string     `json:"id"`
+	WorkspaceID       string     `json:"workspace_id"`
+	UserId            string     `json:"user_id"`
+	Description       string     `json:"description"`
+	RRule             string     `json:"rrule"`              // iCalendarのRRULE(例: "FREQ=WEEKLY;BYDAY=MO")
+	Timezone          string     `json:"timezone"`           // 予定の時刻を解釈するタイムゾーン(IANAの名前)
+	StartAt           time.Time  `json:"start_at"`           // 最初の予定の日時(以降の予定は現地の同じ時刻)
+	EndAt             *time.Time `json:"end_at,omitempty"`   // この日時より前の予定のみ(「以降の全て」を変更した場合に設定する)
+	MaterializedUntil time.Time  `json:"materialized_until"` // 予定のTodoを作成済みの日時(この日時より前)
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// 繰り返しの予定の変更範囲
+const (
+	OccurrenceScopeThis   = "this"   // この予定のみ
+	OccurrenceScopeFuture = "future" // この予定と以降の全て(新しい繰り返しに分ける)
+)
+
+// 繰り返しの予定の変更
+// 空の項目は変更しない。RRule・Timezoneは"future"の場合のみ変更できる
+type OccurrenceEdit struct {
+	Scope       string     `json:"scope"`
+	Description string     `json:"description"`
+	DueAt       *time.Time `json:"due_at"` // "future"の場合は以降の予定の時刻も変わる
+	RRule       string     `json:"rrule"`
+	Timezone    string     `json:"timezone"`
+}
@@ -0,0 +1,238 @@
+package domain_todo
+
+import (
+	"errors"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// ハイライトの開始・終了タグ(Postgresのts_headlineの既定値に合わせる)
+const (
+	HighlightStart = "<b>"
+	HighlightStop  = "</b>"
+)
+
+// ハイライトの目印(私用領域の文字)
+// 説明文はHTMLとしてエスケープする必要があるため、まず目印で囲み、エスケープしてからタグに置き換える
+const (
+	HighlightStartMark = "\uE000"
+	HighlightStopMark  = "\uE001"
+)
+
+// 説明文から目印の文字を取り除く(利用者が入力した目印をタグにしない)
+func StripHighlightMarks(s string) string {
+	return strings.NewReplacer(HighlightStartMark, "", HighlightStopMark, "").Replace(s)
+}
+
+// 目印で囲んだ説明文をHTMLとしてエスケープし、目印をタグに置き換える
+func RenderHighlight(marked string) string {
+	return strings.NewReplacer(HighlightStartMark, HighlightStart, HighlightStopMark, HighlightStop).Replace(html.EscapeString(marked))
+}
+
+// 検索語の種類
+type SearchTermKind string
+
+const (
+	SearchTermWord   SearchTermKind = "word"   // 単語一致
+	SearchTermPrefix SearchTermKind = "prefix" // 前方一致 (例: foo*)
+	SearchTermPhrase SearchTermKind = "phrase" // フレーズ一致 (例: "foo bar")
+)
+
+// 検索語
+type SearchTerm struct {
+	Kind   SearchTermKind `json:"kind"`
+	Tokens []string       `json:"tokens"`
+}
+
+// Todo検索クエリ
+// 全ての検索語を満たすTodoが対象(AND検索)
+type TodoSearchQuery struct {
+	Raw   string       `json:"raw"`
+	Terms []SearchTerm `json:"terms"`
+	Limit int          `json:"limit"`
+}
+
+// Todo検索結果
+type TodoSearchResult struct {
+	Todo      Todo    `json:"todo"`
+	Rank      float64 `json:"rank"`      // 関連度
+	Highlight string  `json:"highlight"` // ハイライト済みスニペット
+}
+
+// トークン(元テキスト上のバイト位置付き)
+type searchToken struct {
+	Text       string
+	Start, End int
+}
+
+// テキストをトークンに分割する
+// 文字・数字以外を区切りとし、小文字に正規化する
+func tokenize(text string) []searchToken {
+	tokens := []searchToken{}
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, searchToken{strings.ToLower(text[start:i]), start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, searchToken{strings.ToLower(text[start:]), start, len(text)})
+	}
+	return tokens
+}
+
+// 検索文字列をパースしてTodo検索クエリを作成する
+// - 空白区切りの単語はAND条件
+// - "foo bar" はフレーズ検索
+// - foo* は前方一致検索
+func ParseTodoSearchQuery(raw string, limit int) (TodoSearchQuery, error) {
+	query := TodoSearchQuery{Raw: raw, Limit: limit}
+
+	rest := raw
+	for {
+		rest = strings.TrimSpace(rest)
+		if rest == "" {
+			break
+		}
+
+		// フレーズ
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			var phrase string
+			if end < 0 {
+				phrase, rest = rest[1:], ""
+			} else {
+				phrase, rest = rest[1:end+1], rest[end+2:]
+			}
+			words := []string{}
+			for _, t := range tokenize(phrase) {
+				words = append(words, t.Text)
+			}
+			switch len(words) {
+			case 0:
+			case 1:
+				query.Terms = append(query.Terms, SearchTerm{Kind: SearchTermWord, Tokens: words})
+			default:
+				query.Terms = append(query.Terms, SearchTerm{Kind: SearchTermPhrase, Tokens: words})
+			}
+			continue
+		}
+
+		// 単語・前方一致
+		var word string
+		if end := strings.IndexFunc(rest, unicode.IsSpace); end < 0 {
+			word, rest = rest, ""
+		} else {
+			word, rest = rest[:end], rest[end:]
+		}
+		prefix := strings.HasSuffix(word, "*")
+		tokens := tokenize(word)
+		for i, t := range tokens {
+			kind := SearchTermWord
+			if prefix && i == len(tokens)-1 {
+				kind = SearchTermPrefix
+			}
+			query.Terms = append(query.Terms, SearchTerm{Kind: kind, Tokens: []string{t.Text}})
+		}
+	}
+
+	if len(query.Terms) == 0 {
+		return TodoSearchQuery{}, errors.New("query is empty")
+	}
+	return query, nil
+}
+
+// Postgresのto_tsquery用の文字列に変換する
+// トークンは文字・数字のみで構成されるため、そのまま引用符で囲める
+func (q TodoSearchQuery) ToTsQuery() string {
+	parts := make([]string, 0, len(q.Terms))
+	for _, term := range q.Terms {
+		switch term.Kind {
+		case SearchTermPrefix:
+			parts = append(parts, "'"+term.Tokens[0]+"':*")
+		case SearchTermPhrase:
+			quoted := make([]string, len(term.Tokens))
+			for i, t := range term.Tokens {
+				quoted[i] = "'" + t + "'"
+			}
+			parts = append(parts, "("+strings.Join(quoted, " <-> ")+")")
+		default:
+			parts = append(parts, "'"+term.Tokens[0]+"'")
+		}
+	}
+	return strings.Join(parts, " & ")
+}
+
+// トークン列の位置iで検索語が一致するか
+func (term SearchTerm) matchAt(tokens []searchToken, i int) bool {
+	if i+len(term.Tokens) > len(tokens) {
+		return false
+	}
+	if term.Kind == SearchTermPrefix {
+		return strings.HasPrefix(tokens[i].Text, term.Tokens[0])
+	}
+	for j, t := range term.Tokens {
+		if tokens[i+j].Text != t {
+			return false
+		}
+	}
+	return true
+}
+
+// Todoがクエリに一致するかを判定する(DBを使わない簡易マッチャー)
+// 一致した場合は関連度とハイライトを付与した検索結果を返す
+func (q TodoSearchQuery) Match(todo Todo) (TodoSearchResult, bool) {
+	tokens := tokenize(todo.Description)
+	if len(tokens) == 0 {
+		return TodoSearchResult{}, false
+	}
+
+	// 一致したトークンの位置
+	highlighted := make([]bool, len(tokens))
+	hits := 0
+	for _, term := range q.Terms {
+		found := false
+		for i := range tokens {
+			if term.matchAt(tokens, i) {
+				found = true
+				hits++
+				for j := i; j < i+len(term.Tokens); j++ {
+					highlighted[j] = true
+				}
+			}
+		}
+		// AND検索のため、1つでも一致しなければ対象外
+		if !found {
+			return TodoSearchResult{}, false
+		}
+	}
+
+	// ハイライトの作成
+	var b strings.Builder
+	last := 0
+	for i, t := range tokens {
+		if !highlighted[i] {
+			continue
+		}
+		b.WriteString(StripHighlightMarks(todo.Description[last:t.Start]))
+		b.WriteString(HighlightStartMark)
+		b.WriteString(StripHighlightMarks(todo.Description[t.Start:t.End]))
+		b.WriteString(HighlightStopMark)
+		last = t.End
+	}
+	b.WriteString(StripHighlightMarks(todo.Description[last:]))
+
+	return TodoSearchResult{
+		Todo:      todo,
+		Rank:      float64(hits) / float64(len(tokens)),
+		Highlight: RenderHighlight(b.String()),
+	}, true
+}
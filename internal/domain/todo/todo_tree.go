@@ -0,0 +1,115 @@
+package domain_todo
+
+import (
+	"errors"
+	"sort"
+)
+
+// Todoの階層の上限(ルートのTodoを1とする)
+const MaxTodoDepth = 5
+
+// 並び順のキーの長さの上限
+// 同じ位置への移動を繰り返してこれより長くなった場合は、兄弟のキーを振り直す
+const MaxTodoPositionLength = 16
+
+// Todoの階層(サブタスク)
+type TodoNode struct {
+	Todo
+	Progress int         `json:"progress"` // 完了率(%)。子孫の末端のTodoのうち完了したものの割合、子が無い場合は0か100
+	Children []*TodoNode `json:"children"` // 子のTodo(position順)
+}
+
+// Todoの移動先
+// 隣のTodoを指定しない場合は親の末尾に移動する。隣のTodoを指定した場合、親は隣のTodoの親になる
+type TodoMove struct {
+	ParentID string `json:"parent_id"` // 親のTodoのID(空の場合はルート)
+	AfterID  string `json:"after_id"`  // 直前になるTodoのID
+	BeforeID string `json:"before_id"` // 直後になるTodoのID(after_idと両方指定する場合は隣り合っていること)
+}
+
+// Todoの完了状態の変更
+type TodoCompletion struct {
+	Completed bool `json:"completed"`
+	// 子孫のTodoにも反映する
+	Descendants bool `json:"descendants"`
+	// 祖先のTodoに反映する
+	// 完了の場合は全ての子が完了した親を完了に、未完了の場合は全ての祖先を未完了にする
+	Ancestors bool `json:"ancestors"`
+}
+
+// Todoのリストから、rootIdのTodoを根とする階層を作成する
+// 親がリストに無いTodo(rootId以外)は無視する
+func BuildTodoTree(todos []Todo, rootId string) (*TodoNode, error) {
+	nodes := map[string]*TodoNode{}
+	for _, todo := range todos {
+		nodes[todo.ID] = &TodoNode{Todo: todo, Children: []*TodoNode{}}
+	}
+	root, ok := nodes[rootId]
+	if !ok {
+		return nil, errors.New("todo not found")
+	}
+
+	for _, todo := range todos {
+		if todo.ID == rootId {
+			continue
+		}
+		if parent, ok := nodes[todo.ParentID]; ok {
+			parent.Children = append(parent.Children, nodes[todo.ID])
+		}
+	}
+	root.sortChildren()
+	root.updateProgress()
+	return root, nil
+}
+
+// 子をposition順(同じ場合はID順)に並べる
+func (n *TodoNode) sortChildren() {
+	sort.SliceStable(n.Children, func(i, j int) bool {
+		if n.Children[i].Position != n.Children[j].Position {
+			return n.Children[i].Position < n.Children[j].Position
+		}
+		return n.Children[i].ID < n.Children[j].ID
+	})
+	for _, child := range n.Children {
+		child.sortChildren()
+	}
+}
+
+// 完了率を計算し、末端のTodoの数と完了したものの数を返す
+func (n *TodoNode) updateProgress() (leaves int, completed int) {
+	if len(n.Children) == 0 {
+		leaves = 1
+		if n.Completed {
+			completed = 1
+		}
+	} else {
+		for _, child := range n.Children {
+			l, c := child.updateProgress()
+			leaves += l
+			completed += c
+		}
+	}
+	n.Progress = completed * 100 / leaves
+	return leaves, completed
+}
+
+// 階層の高さ(子が無い場合は1)
+func (n *TodoNode) Height() int {
+	height := 0
+	for _, child := range n.Children {
+		if h := child.Height(); h > height {
+			height = h
+		}
+	}
+	return height + 1
+}
+
+// 子孫のTodoのID(自身を含まない)
+func (n *TodoNode) DescendantIDs() []string {
+	ids := []string{}
+	for _, child := range n.Children {
+		ids = append(ids, child.ID)
+		ids = append(ids, child.DescendantIDs()...)
+	}
+	return ids
+}
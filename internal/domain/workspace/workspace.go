@@ -0,0 +1,84 @@
+package domain_workspace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+)
+
+// ワークスペース名の最大文字数
+const MaxNameLength = 100
+
+// ワークスペースでの役割
+type Role string
+
+const (
+	RoleOwner  Role = "owner"  // メンバーと招待を管理できる
+	RoleEditor Role = "editor" // Todoを作成・更新・削除できる
+	RoleViewer Role = "viewer" // Todoを参照のみできる
+)
+
+// 有効な役割か
+func (r Role) Valid() bool {
+	return r == RoleOwner || r == RoleEditor || r == RoleViewer
+}
+
+// Todoを編集できるか
+func (r Role) CanEdit() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// ワークスペース
+// 各ユーザーには個人用のワークスペース(Personal)が1つあり、ワークスペースを指定せずに作成したTodoはそこに入る
+type Workspace struct {
+	ID        string    `json:"id"         db:"id"`
+	Name      string    `json:"name"       db:"name"`
+	Personal  bool      `json:"personal"   db:"personal"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// 所属するワークスペースと役割
+type Membership struct {
+	Workspace
+	Role Role `json:"role" db:"role"`
+}
+
+// ワークスペースのメンバー
+type Member struct {
+	WorkspaceID string    `json:"workspace_id" db:"workspace_id"`
+	UserID      string    `json:"user_id"      db:"user_id"`
+	Role        Role      `json:"role"         db:"role"`
+	CreatedAt   time.Time `json:"created_at"   db:"created_at"`
+}
+
+// ワークスペースへの招待
+// トークンそのものは保存せず、ハッシュのみ保存する
+type Invitation struct {
+	ID          string     `json:"id"           db:"id"`
+	WorkspaceID string     `json:"workspace_id" db:"workspace_id"`
+	Email       string     `json:"email"        db:"email"`
+	Role        Role       `json:"role"         db:"role"`
+	InvitedBy   string     `json:"invited_by"   db:"invited_by"`
+	TokenHash   string     `json:"-"            db:"token_hash"`
+	ExpiresAt   time.Time  `json:"expires_at"   db:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at"  db:"accepted_at"`
+	CreatedAt   time.Time  `json:"created_at"   db:"created_at"`
+}
+
+// 招待のトークンを生成する
+func GenerateInvitationToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashInvitationToken(token), nil
+}
+
+// 招待のトークンのハッシュ(SHA-256)
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,92 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// アカウントのリポジトリ(Impl)
+// スキーマは migrations/007_user_tokens.sql を参照
+type AccountRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// アカウントのリポジトリのインスタンス化
+func NewAccountRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IAccountRepository {
+	return &AccountRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// メールアドレスでアカウントを取得
+func (r *AccountRepositoryImpl) GetAccountByEmail(email string) (domain_auth.Account, error) {
+	query := `SELECT id, username, email, email_verified_at FROM users WHERE lower(email) = lower($1)`
+	return r.getAccount(query, email)
+}
+
+// IDでアカウントを取得
+func (r *AccountRepositoryImpl) GetAccountById(id string) (domain_auth.Account, error) {
+	query := `SELECT id, username, email, email_verified_at FROM users WHERE id = $1`
+	return r.getAccount(query, id)
+}
+
+// アカウントを1件取得
+func (r *AccountRepositoryImpl) getAccount(query string, arg string) (domain_auth.Account, error) {
+	var account domain_auth.Account
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, arg).
+		Scan(&account.ID, &account.Username, &account.Email, &account.EmailVerifiedAt)
+	if err == pgx.ErrNoRows {
+		return domain_auth.Account{}, errors.New("user not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch user: %v", err)
+		return domain_auth.Account{}, err
+	}
+	return account, nil
+}
+
+// パスワードを更新
+// ログインと同じ形式で保存する
+func (r *AccountRepositoryImpl) UpdatePassword(userId string, password string) error {
+	r.Logger.InfoLog.Println("UpdatePassword called")
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, `UPDATE users SET password = $2, updated_at = now() WHERE id = $1`, userId, password)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update password: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// メールアドレスを確認済みにする
+// トークンを発行した後にメールアドレスが変わった場合は確認しない
+func (r *AccountRepositoryImpl) VerifyEmail(userId string, email string, at time.Time) error {
+	r.Logger.InfoLog.Println("VerifyEmail called")
+
+	query := `
+		UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, $3)
+		WHERE id = $1 AND lower(email) = lower($2)
+	`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, userId, email, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to verify email: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// APIキーのリポジトリ(Impl)
+// スキーマは migrations/004_api_keys.sql を参照
+type APIKeyRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// APIキーのリポジトリのインスタンス化
+func NewAPIKeyRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IAPIKeyRepository {
+	return &APIKeyRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// 取得する列
+const apiKeyColumns = `id, user_id, name, kind, prefix, hash, scopes, expires_at, last_used_at, revoked_at, created_at`
+
+// APIキーを作成
+func (r *APIKeyRepositoryImpl) CreateAPIKey(key domain_auth.APIKey) (domain_auth.APIKey, error) {
+	r.Logger.InfoLog.Println("CreateAPIKey called")
+
+	query := `
+		INSERT INTO api_keys (user_id, name, kind, prefix, hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING ` + apiKeyColumns
+
+	created, err := scanAPIKey(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query,
+		key.UserID, key.Name, key.Kind, key.Prefix, key.Hash, key.Scopes, key.ExpiresAt))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create api key: %v", err)
+		return domain_auth.APIKey{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Created api key: %s", created.Prefix)
+	return created, nil
+}
+
+// プレフィックスでAPIキーを取得
+func (r *APIKeyRepositoryImpl) GetAPIKeyByPrefix(prefix string) (domain_auth.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE prefix = $1`
+
+	key, err := scanAPIKey(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, prefix))
+	if err == pgx.ErrNoRows {
+		return domain_auth.APIKey{}, errors.New("api key not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch api key: %v", err)
+		return domain_auth.APIKey{}, err
+	}
+	return key, nil
+}
+
+// ユーザーのAPIキーを取得(作成日時の新しい順)
+func (r *APIKeyRepositoryImpl) GetAPIKeysByUserId(userId string) ([]domain_auth.APIKey, error) {
+	r.Logger.InfoLog.Println("GetAPIKeysByUserId called")
+
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query, userId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch api keys: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []domain_auth.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan api key: %v", err)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate api keys: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Fetched %d api keys", len(keys))
+	return keys, nil
+}
+
+// ユーザーのAPIキーを失効させる(失効済みの場合は何もしない)
+func (r *APIKeyRepositoryImpl) RevokeAPIKey(id string, userId string, at time.Time) error {
+	r.Logger.InfoLog.Println("RevokeAPIKey called")
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, $3)
+		WHERE id = $1 AND user_id = $2
+	`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, id, userId, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to revoke api key: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("api key not found")
+	}
+	return nil
+}
+
+// 最終使用日時を更新
+func (r *APIKeyRepositoryImpl) TouchAPIKey(id string, at time.Time) error {
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, `UPDATE api_keys SET last_used_at = $2 WHERE id = $1`, id, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to touch api key: %v", err)
+		return err
+	}
+	return nil
+}
+
+// APIキーを読み込む
+func scanAPIKey(row pgx.Row) (domain_auth.APIKey, error) {
+	var key domain_auth.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.UserID,
+		&key.Name,
+		&key.Kind,
+		&key.Prefix,
+		&key.Hash,
+		&key.Scopes,
+		&key.ExpiresAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+		&key.CreatedAt,
+	)
+	return key, err
+}
@@ -0,0 +1,104 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// APIキーのリポジトリ(インメモリ)
+// テストやDBの無い環境で使用する
+type APIKeyMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu   sync.Mutex
+	keys []domain_auth.APIKey
+}
+
+// APIキーのリポジトリ(インメモリ)のインスタンス化
+func NewAPIKeyMemoryRepository(l *pkg_logger.AppLogger) repository_auth.IAPIKeyRepository {
+	return &APIKeyMemoryRepository{Logger: l}
+}
+
+// APIキーを作成
+func (r *APIKeyMemoryRepository) CreateAPIKey(key domain_auth.APIKey) (domain_auth.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.keys {
+		if k.Prefix == key.Prefix {
+			return domain_auth.APIKey{}, errors.New("duplicate api key prefix")
+		}
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return domain_auth.APIKey{}, err
+	}
+	key.ID = hex.EncodeToString(id)
+	key.Scopes = slices.Clone(key.Scopes)
+	key.CreatedAt = time.Now()
+	r.keys = append(r.keys, key)
+	return key, nil
+}
+
+// プレフィックスでAPIキーを取得
+func (r *APIKeyMemoryRepository) GetAPIKeyByPrefix(prefix string) (domain_auth.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, k := range r.keys {
+		if k.Prefix == prefix {
+			return k, nil
+		}
+	}
+	return domain_auth.APIKey{}, errors.New("api key not found")
+}
+
+// ユーザーのAPIキーを取得(作成日時の新しい順)
+func (r *APIKeyMemoryRepository) GetAPIKeysByUserId(userId string) ([]domain_auth.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := []domain_auth.APIKey{}
+	for _, k := range slices.Backward(r.keys) {
+		if k.UserID == userId {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+// ユーザーのAPIキーを失効させる
+func (r *APIKeyMemoryRepository) RevokeAPIKey(id string, userId string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k.ID == id && k.UserID == userId {
+			if k.RevokedAt == nil {
+				r.keys[i].RevokedAt = &at
+			}
+			return nil
+		}
+	}
+	return errors.New("api key not found")
+}
+
+// 最終使用日時を更新
+func (r *APIKeyMemoryRepository) TouchAPIKey(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, k := range r.keys {
+		if k.ID == id {
+			r.keys[i].LastUsedAt = &at
+		}
+	}
+	return nil
+}
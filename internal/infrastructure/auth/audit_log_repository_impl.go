@@ -0,0 +1,40 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+)
+
+// 監査ログのリポジトリ(Impl)
+// スキーマは migrations/003_login_lockout.sql を参照
+type AuditLogRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// 監査ログのリポジトリのインスタンス化
+func NewAuditLogRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IAuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// 監査ログを記録する
+func (r *AuditLogRepositoryImpl) CreateAuditLog(log domain_auth.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (action, actor_id, email, ip, detail)
+		VALUES ($1, NULLIF($2, '')::uuid, $3, $4, $5)
+	`
+
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, log.Action, log.ActorID, log.Email, log.IP, log.Detail)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create audit log: %v", err)
+		return err
+	}
+
+	r.Logger.InfoLog.Printf("Audit log created: %s", log.Action)
+	return nil
+}
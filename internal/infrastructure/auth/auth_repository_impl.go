@@ -1,10 +1,13 @@
 package infrastructure_auth
 
 import (
+	domain_auth "backend/internal/domain/auth"
 	domain_user "backend/internal/domain/user"
 	pkg_logger "backend/internal/pkg/logger"
 	pkg_supabase "backend/internal/pkg/supabase"
 	repository_auth "backend/internal/repository/auth"
+
+	"github.com/jackc/pgx/v4"
 )
 
 // 認証リポジトリの実装(Impl)
@@ -23,7 +26,7 @@ func NewAuthRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient)
 
 // ログイン
 func (r *AuthRepositoryImpl) Login(email string, password string) (string, error) {
-	r.Logger.InfoLog.Printf("Logging in with email: %s and password: %s", email, password)
+	r.Logger.InfoLog.Printf("Logging in with email: %s", email)
 
 	query := `
         SELECT id, username, email
@@ -36,6 +39,11 @@ func (r *AuthRepositoryImpl) Login(email string, password string) (string, error
 
 	user := domain_user.Users{}
 	err := row.Scan(&user.ID, &user.Username, &user.Email)
+	if err == pgx.ErrNoRows {
+		// ユーザーが存在しない場合とパスワードが違う場合を区別しない
+		r.Logger.ErrorLog.Println("Invalid email or password")
+		return "", domain_auth.ErrInvalidCredentials
+	}
 	if err != nil {
 		r.Logger.ErrorLog.Printf("Failed to fetch user: %v", err)
 		return "", err
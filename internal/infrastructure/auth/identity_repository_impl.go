@@ -0,0 +1,78 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// 外部のIDプロバイダのアカウントの紐付けのリポジトリ(Impl)
+// スキーマは migrations/005_user_identities.sql を参照
+type IdentityRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// 紐付けのリポジトリのインスタンス化
+func NewIdentityRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IIdentityRepository {
+	return &IdentityRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// プロバイダとsubで紐付けを取得
+func (r *IdentityRepositoryImpl) GetIdentity(provider string, subject string) (domain_auth.Identity, error) {
+	query := `
+		SELECT provider, subject, user_id, email, created_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2
+	`
+
+	var identity domain_auth.Identity
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, provider, subject).
+		Scan(&identity.Provider, &identity.Subject, &identity.UserID, &identity.Email, &identity.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return domain_auth.Identity{}, errors.New("identity not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch identity: %v", err)
+		return domain_auth.Identity{}, err
+	}
+	return identity, nil
+}
+
+// メールアドレスでユーザーIDを取得
+func (r *IdentityRepositoryImpl) GetUserIdByEmail(email string) (string, error) {
+	var id string
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, `SELECT id FROM users WHERE lower(email) = lower($1)`, email).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", errors.New("user not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch user: %v", err)
+		return "", err
+	}
+	return id, nil
+}
+
+// 紐付けを作成
+func (r *IdentityRepositoryImpl) CreateIdentity(identity domain_auth.Identity) error {
+	query := `
+		INSERT INTO user_identities (provider, subject, user_id, email)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, identity.Provider, identity.Subject, identity.UserID, identity.Email)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create identity: %v", err)
+		return err
+	}
+
+	r.Logger.InfoLog.Printf("Linked %s identity to user %s", identity.Provider, identity.UserID)
+	return nil
+}
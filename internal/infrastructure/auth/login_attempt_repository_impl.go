@@ -0,0 +1,128 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ログインの失敗の記録のリポジトリ(Impl)
+// スキーマは migrations/003_login_lockout.sql を参照
+type LoginAttemptRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// ログインの失敗の記録のリポジトリのインスタンス化
+func NewLoginAttemptRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.ILoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// キーの記録を取得する
+func (r *LoginAttemptRepositoryImpl) Get(key string) (domain_auth.LoginFailures, error) {
+	query := `
+		SELECT count, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	f, err := scanLoginFailures(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, key))
+	if err == pgx.ErrNoRows {
+		return domain_auth.LoginFailures{}, nil
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch login attempts: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+	return f, nil
+}
+
+// キーの記録をfnで更新して保存する
+// 行をSELECT ... FOR UPDATEでロックし、同時に失敗した場合も数え漏らさない
+func (r *LoginAttemptRepositoryImpl) Update(key string, fn func(f domain_auth.LoginFailures) domain_auth.LoginFailures) (domain_auth.LoginFailures, error) {
+	ctx := r.SupabaseClient.Ctx
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 行がなければ作成し、ロックを取得する
+	_, err = tx.Exec(ctx, `INSERT INTO login_attempts (key) VALUES ($1) ON CONFLICT (key) DO NOTHING`, key)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to insert login attempts: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+	f, err := scanLoginFailures(tx.QueryRow(ctx, `
+		SELECT count, last_failed_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+		FOR UPDATE
+	`, key))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to select login attempts: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+
+	f = fn(f)
+	_, err = tx.Exec(ctx, `
+		UPDATE login_attempts
+		SET count = $2, last_failed_at = $3, locked_until = $4
+		WHERE key = $1
+	`, key, f.Count, nullTime(f.LastFailedAt), nullTime(f.LockedUntil))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update login attempts: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+
+	// トランザクションをコミット
+	if err = tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return domain_auth.LoginFailures{}, err
+	}
+	return f, nil
+}
+
+// キーの記録を削除する
+func (r *LoginAttemptRepositoryImpl) Reset(key string) error {
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, `DELETE FROM login_attempts WHERE key = $1`, key)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to reset login attempts: %v", err)
+		return err
+	}
+	return nil
+}
+
+// 記録を読み込む(NULLの時刻はゼロ値)
+func scanLoginFailures(row pgx.Row) (domain_auth.LoginFailures, error) {
+	var f domain_auth.LoginFailures
+	var lastFailedAt, lockedUntil *time.Time
+	if err := row.Scan(&f.Count, &lastFailedAt, &lockedUntil); err != nil {
+		return domain_auth.LoginFailures{}, err
+	}
+	if lastFailedAt != nil {
+		f.LastFailedAt = *lastFailedAt
+	}
+	if lockedUntil != nil {
+		f.LockedUntil = *lockedUntil
+	}
+	return f, nil
+}
+
+// ゼロ値の時刻をNULLにする
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
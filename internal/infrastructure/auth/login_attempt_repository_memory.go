@@ -0,0 +1,49 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"sync"
+)
+
+// ログインの失敗の記録のリポジトリ(インメモリ)
+// 1つのインスタンス内でのみ記録を共有する。テストやDBの無い環境で使用する
+type LoginAttemptMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu       sync.Mutex
+	failures map[string]domain_auth.LoginFailures
+}
+
+// ログインの失敗の記録のリポジトリ(インメモリ)のインスタンス化
+func NewLoginAttemptMemoryRepository(l *pkg_logger.AppLogger) repository_auth.ILoginAttemptRepository {
+	return &LoginAttemptMemoryRepository{
+		Logger:   l,
+		failures: map[string]domain_auth.LoginFailures{},
+	}
+}
+
+// キーの記録を取得する
+func (r *LoginAttemptMemoryRepository) Get(key string) (domain_auth.LoginFailures, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.failures[key], nil
+}
+
+// キーの記録をfnで更新して保存する
+func (r *LoginAttemptMemoryRepository) Update(key string, fn func(f domain_auth.LoginFailures) domain_auth.LoginFailures) (domain_auth.LoginFailures, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := fn(r.failures[key])
+	r.failures[key] = f
+	return f, nil
+}
+
+// キーの記録を削除する
+func (r *LoginAttemptMemoryRepository) Reset(key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, key)
+	return nil
+}
@@ -0,0 +1,121 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// 2要素認証のリポジトリ(Impl)
+// スキーマは migrations/006_user_mfa.sql を参照
+type MFARepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// 2要素認証のリポジトリのインスタンス化
+func NewMFARepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IMFARepository {
+	return &MFARepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// ユーザーの2要素認証を取得
+func (r *MFARepositoryImpl) GetMFA(userId string) (domain_auth.MFA, error) {
+	query := `
+		SELECT user_id, secret, confirmed_at, last_used_step, recovery_codes, created_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+
+	var m domain_auth.MFA
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, userId).Scan(
+		&m.UserID,
+		&m.Secret,
+		&m.ConfirmedAt,
+		&m.LastUsedStep,
+		&m.RecoveryCodes,
+		&m.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return domain_auth.MFA{}, errors.New("mfa not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch mfa: %v", err)
+		return domain_auth.MFA{}, err
+	}
+	return m, nil
+}
+
+// ユーザーの2要素認証を保存
+func (r *MFARepositoryImpl) SaveMFA(mfa domain_auth.MFA) error {
+	r.Logger.InfoLog.Println("SaveMFA called")
+
+	query := `
+		INSERT INTO user_mfa (user_id, secret, confirmed_at, last_used_step, recovery_codes)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+		    confirmed_at = EXCLUDED.confirmed_at,
+		    last_used_step = EXCLUDED.last_used_step,
+		    recovery_codes = EXCLUDED.recovery_codes
+	`
+
+	codes := mfa.RecoveryCodes
+	if codes == nil {
+		codes = []string{}
+	}
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query,
+		mfa.UserID, mfa.Secret, mfa.ConfirmedAt, mfa.LastUsedStep, codes)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to save mfa: %v", err)
+		return err
+	}
+	return nil
+}
+
+// ユーザーの2要素認証を削除
+func (r *MFARepositoryImpl) DeleteMFA(userId string) error {
+	r.Logger.InfoLog.Println("DeleteMFA called")
+
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to delete mfa: %v", err)
+		return err
+	}
+	return nil
+}
+
+// 最後に使ったTOTPのステップを更新する
+// 条件付きのUPDATEにして、同時に同じコードが使われても1件だけ成功させる
+func (r *MFARepositoryImpl) UseStep(userId string, step int64) (bool, error) {
+	query := `UPDATE user_mfa SET last_used_step = $2 WHERE user_id = $1 AND last_used_step < $2`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, userId, step)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to use totp step: %v", err)
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// 未使用の回復コードを使用済みにする
+func (r *MFARepositoryImpl) UseRecoveryCode(userId string, hash string) (bool, error) {
+	query := `
+		UPDATE user_mfa
+		SET recovery_codes = array_remove(recovery_codes, $2)
+		WHERE user_id = $1 AND $2 = ANY (recovery_codes)
+	`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, userId, hash)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to use recovery code: %v", err)
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,90 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// 2要素認証のリポジトリ(インメモリ)
+// 1つのインスタンス内でのみ共有する。テストやDBの無い環境で使用する
+type MFAMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu  sync.Mutex
+	mfa map[string]domain_auth.MFA
+}
+
+// 2要素認証のリポジトリ(インメモリ)のインスタンス化
+func NewMFAMemoryRepository(l *pkg_logger.AppLogger) repository_auth.IMFARepository {
+	return &MFAMemoryRepository{
+		Logger: l,
+		mfa:    map[string]domain_auth.MFA{},
+	}
+}
+
+// ユーザーの2要素認証を取得
+func (r *MFAMemoryRepository) GetMFA(userId string) (domain_auth.MFA, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.mfa[userId]
+	if !ok {
+		return domain_auth.MFA{}, errors.New("mfa not found")
+	}
+	m.RecoveryCodes = slices.Clone(m.RecoveryCodes)
+	return m, nil
+}
+
+// ユーザーの2要素認証を保存
+func (r *MFAMemoryRepository) SaveMFA(mfa domain_auth.MFA) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if mfa.CreatedAt.IsZero() {
+		mfa.CreatedAt = time.Now()
+	}
+	mfa.RecoveryCodes = slices.Clone(mfa.RecoveryCodes)
+	r.mfa[mfa.UserID] = mfa
+	return nil
+}
+
+// ユーザーの2要素認証を削除
+func (r *MFAMemoryRepository) DeleteMFA(userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.mfa, userId)
+	return nil
+}
+
+// 最後に使ったTOTPのステップを更新する
+func (r *MFAMemoryRepository) UseStep(userId string, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.mfa[userId]
+	if !ok || step <= m.LastUsedStep {
+		return false, nil
+	}
+	m.LastUsedStep = step
+	r.mfa[userId] = m
+	return true, nil
+}
+
+// 未使用の回復コードを使用済みにする
+func (r *MFAMemoryRepository) UseRecoveryCode(userId string, hash string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.mfa[userId]
+	if !ok {
+		return false, nil
+	}
+	i := slices.Index(m.RecoveryCodes, hash)
+	if i < 0 {
+		return false, nil
+	}
+	m.RecoveryCodes = slices.Delete(slices.Clone(m.RecoveryCodes), i, i+1)
+	r.mfa[userId] = m
+	return true, nil
+}
@@ -0,0 +1,39 @@
+package infrastructure_auth
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ユーザーのロールのリポジトリ(Impl)
+// スキーマは migrations/014_user_roles.sql を参照
+type RoleRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// ユーザーのロールのリポジトリのインスタンス化
+func NewRoleRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IRoleRepository {
+	return &RoleRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// ユーザーの現在のロールを取得
+func (r *RoleRepositoryImpl) GetRole(userId string) (string, error) {
+	var role string
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, `SELECT role FROM users WHERE id = $1`, userId).Scan(&role)
+	if err == pgx.ErrNoRows {
+		return "", errors.New("user not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch role: %v", err)
+		return "", err
+	}
+	return role, nil
+}
@@ -0,0 +1,156 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ログインのセッションのリポジトリ(Impl)
+// スキーマは migrations/008_sessions.sql を参照
+type SessionRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// ログインのセッションのリポジトリのインスタンス化
+func NewSessionRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.ISessionRepository {
+	return &SessionRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// 取得する列
+const sessionColumns = `id, user_id, device, ip, user_agent, created_at, last_seen_at, revoked_at`
+
+// セッションを作成
+func (r *SessionRepositoryImpl) CreateSession(session domain_auth.Session) (domain_auth.Session, error) {
+	query := `
+		INSERT INTO sessions (user_id, device, ip, user_agent)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + sessionColumns
+
+	created, err := scanSession(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query,
+		session.UserID, session.Device, session.IP, session.UserAgent))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create session: %v", err)
+		return domain_auth.Session{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Created session %s for user %s", created.ID, created.UserID)
+	return created, nil
+}
+
+// セッションを取得
+func (r *SessionRepositoryImpl) GetSession(id string) (domain_auth.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = $1`
+
+	session, err := scanSession(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return domain_auth.Session{}, errors.New("session not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch session: %v", err)
+		return domain_auth.Session{}, err
+	}
+	return session, nil
+}
+
+// ユーザーの有効なセッションを取得
+func (r *SessionRepositoryImpl) GetActiveSessions(userId string, now time.Time) ([]domain_auth.Session, error) {
+	r.Logger.InfoLog.Println("GetActiveSessions called")
+
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND created_at > $2
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query, userId, now.Add(-domain_auth.SessionLifetime))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch sessions: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain_auth.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan session: %v", err)
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate sessions: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Fetched %d sessions", len(sessions))
+	return sessions, nil
+}
+
+// ユーザーのセッションを失効させる
+func (r *SessionRepositoryImpl) RevokeSession(id string, userId string, at time.Time) error {
+	r.Logger.InfoLog.Println("RevokeSession called")
+
+	query := `UPDATE sessions SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, id, userId, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to revoke session: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("session not found")
+	}
+	return nil
+}
+
+// ユーザーの全てのセッションを失効させる
+func (r *SessionRepositoryImpl) RevokeSessions(userId string, at time.Time) (int, error) {
+	r.Logger.InfoLog.Println("RevokeSessions called")
+
+	query := `UPDATE sessions SET revoked_at = $2 WHERE user_id = $1 AND revoked_at IS NULL`
+
+	tag, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, query, userId, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to revoke sessions: %v", err)
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// 最終使用日時を更新
+func (r *SessionRepositoryImpl) TouchSession(id string, at time.Time) error {
+	_, err := r.SupabaseClient.Pool.Exec(r.SupabaseClient.Ctx, `UPDATE sessions SET last_seen_at = $2 WHERE id = $1`, id, at)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to touch session: %v", err)
+		return err
+	}
+	return nil
+}
+
+// セッションを読み込む
+func scanSession(row pgx.Row) (domain_auth.Session, error) {
+	var session domain_auth.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Device,
+		&session.IP,
+		&session.UserAgent,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.RevokedAt,
+	)
+	return session, err
+}
@@ -0,0 +1,119 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ログインのセッションのリポジトリ(インメモリ)
+// テストやDBの無い環境で使用する
+type SessionMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu       sync.Mutex
+	sessions map[string]domain_auth.Session
+}
+
+// ログインのセッションのリポジトリ(インメモリ)のインスタンス化
+func NewSessionMemoryRepository(l *pkg_logger.AppLogger) repository_auth.ISessionRepository {
+	return &SessionMemoryRepository{
+		Logger:   l,
+		sessions: map[string]domain_auth.Session{},
+	}
+}
+
+// セッションを作成
+func (r *SessionMemoryRepository) CreateSession(session domain_auth.Session) (domain_auth.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return domain_auth.Session{}, err
+	}
+	now := time.Now()
+	session.ID = hex.EncodeToString(id)
+	session.CreatedAt = now
+	session.LastSeenAt = now
+	session.RevokedAt = nil
+	r.sessions[session.ID] = session
+	return session, nil
+}
+
+// セッションを取得
+func (r *SessionMemoryRepository) GetSession(id string) (domain_auth.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return domain_auth.Session{}, errors.New("session not found")
+	}
+	return session, nil
+}
+
+// ユーザーの有効なセッションを取得
+func (r *SessionMemoryRepository) GetActiveSessions(userId string, now time.Time) ([]domain_auth.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := []domain_auth.Session{}
+	for _, s := range r.sessions {
+		if s.UserID == userId && s.Active(now) {
+			sessions = append(sessions, s)
+		}
+	}
+	slices.SortFunc(sessions, func(a, b domain_auth.Session) int {
+		return b.LastSeenAt.Compare(a.LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// ユーザーのセッションを失効させる
+func (r *SessionMemoryRepository) RevokeSession(id string, userId string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, ok := r.sessions[id]
+	if !ok || session.UserID != userId || session.RevokedAt != nil {
+		return errors.New("session not found")
+	}
+	session.RevokedAt = &at
+	r.sessions[id] = session
+	return nil
+}
+
+// ユーザーの全てのセッションを失効させる
+func (r *SessionMemoryRepository) RevokeSessions(userId string, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for id, session := range r.sessions {
+		if session.UserID == userId && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			r.sessions[id] = session
+			count++
+		}
+	}
+	return count, nil
+}
+
+// 最終使用日時を更新
+func (r *SessionMemoryRepository) TouchSession(id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session, ok := r.sessions[id]; ok {
+		session.LastSeenAt = at
+		r.sessions[id] = session
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// メールで送るトークンのリポジトリ(Impl)
+// スキーマは migrations/007_user_tokens.sql を参照
+type UserTokenRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// メールで送るトークンのリポジトリのインスタンス化
+func NewUserTokenRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_auth.IUserTokenRepository {
+	return &UserTokenRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// トークンを作成
+// 以前に送ったリンクを使えなくするため、同じユーザー・用途の未使用のトークンを削除してから作成する
+func (r *UserTokenRepositoryImpl) CreateUserToken(token domain_auth.UserToken) error {
+	r.Logger.InfoLog.Println("CreateUserToken called")
+	ctx := r.SupabaseClient.Ctx
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `DELETE FROM user_tokens WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`, token.UserID, token.Purpose)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to delete user tokens: %v", err)
+		return err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_tokens (user_id, purpose, hash, email, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, token.UserID, token.Purpose, token.Hash, token.Email, token.ExpiresAt)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create user token: %v", err)
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// 未使用・期限内のトークンを使用済みにして返す
+// 条件付きのUPDATEにして、同時に使われても1件だけ成功させる
+func (r *UserTokenRepositoryImpl) ConsumeUserToken(purpose domain_auth.TokenPurpose, hash string, now time.Time) (domain_auth.UserToken, error) {
+	query := `
+		UPDATE user_tokens
+		SET used_at = $3
+		WHERE purpose = $1 AND hash = $2 AND used_at IS NULL AND expires_at > $3
+		RETURNING id, user_id, purpose, hash, email, expires_at, used_at, created_at
+	`
+
+	var token domain_auth.UserToken
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, purpose, hash, now).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.Hash,
+		&token.Email,
+		&token.ExpiresAt,
+		&token.UsedAt,
+		&token.CreatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return domain_auth.UserToken{}, errors.New("token not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to consume user token: %v", err)
+		return domain_auth.UserToken{}, err
+	}
+	return token, nil
+}
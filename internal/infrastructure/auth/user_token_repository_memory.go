@@ -0,0 +1,56 @@
+package infrastructure_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"sync"
+	"time"
+)
+
+// メールで送るトークンのリポジトリ(インメモリ)
+// 1つのインスタンス内でのみ共有する。テストやDBの無い環境で使用する
+type UserTokenMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu     sync.Mutex
+	tokens map[string]domain_auth.UserToken // ハッシュごと
+}
+
+// メールで送るトークンのリポジトリ(インメモリ)のインスタンス化
+func NewUserTokenMemoryRepository(l *pkg_logger.AppLogger) repository_auth.IUserTokenRepository {
+	return &UserTokenMemoryRepository{
+		Logger: l,
+		tokens: map[string]domain_auth.UserToken{},
+	}
+}
+
+// トークンを作成
+func (r *UserTokenMemoryRepository) CreateUserToken(token domain_auth.UserToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, t := range r.tokens {
+		if t.UserID == token.UserID && t.Purpose == token.Purpose && t.UsedAt == nil {
+			delete(r.tokens, hash)
+		}
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	r.tokens[token.Hash] = token
+	return nil
+}
+
+// 未使用・期限内のトークンを使用済みにして返す
+func (r *UserTokenMemoryRepository) ConsumeUserToken(purpose domain_auth.TokenPurpose, hash string, now time.Time) (domain_auth.UserToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[hash]
+	if !ok || token.Purpose != purpose || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return domain_auth.UserToken{}, errors.New("token not found")
+	}
+	token.UsedAt = &now
+	r.tokens[hash] = token
+	return token, nil
+}
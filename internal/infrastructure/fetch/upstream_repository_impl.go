@@ -0,0 +1,63 @@
+package infrastructure_fetch
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_fetch "backend/internal/repository/fetch"
+	"context"
+	"net/http"
+	"time"
+)
+
+// 上流APIリポジトリ(Impl)
+type UpstreamRepositoryImpl struct {
+	Logger  *pkg_logger.AppLogger
+	BaseURL string
+	Client  pkg_httpclient.Doer
+}
+
+// 上流APIリポジトリのインスタンス化
+func NewUpstreamRepository(l *pkg_logger.AppLogger, baseURL string, client pkg_httpclient.Doer) repository_fetch.IUpstreamRepository {
+	return &UpstreamRepositoryImpl{
+		Logger:  l,
+		BaseURL: baseURL,
+		Client:  client,
+	}
+}
+
+// 上流APIにリクエストを送る
+func (r *UpstreamRepositoryImpl) Do(ctx context.Context, req domain_fetch.UpstreamRequest) (domain_fetch.UpstreamResponse, error) {
+	r.Logger.InfoLog.Printf("Upstream request: %s %s %s", req.Name, req.Method, req.Path)
+	res := domain_fetch.UpstreamResponse{Name: req.Name}
+	start := time.Now()
+
+	headers := map[string]string{}
+	for k, v := range req.Headers {
+		headers[http.CanonicalHeaderKey(k)] = v
+	}
+	if len(req.Body) > 0 && headers["Content-Type"] == "" {
+		headers["Content-Type"] = "application/json"
+	}
+
+	httpRes, err := r.Client.Do(ctx, pkg_httpclient.Request{
+		Method:  req.Method,
+		URL:     r.BaseURL + req.Path,
+		Headers: headers,
+		Body:    req.Body,
+	})
+	res.LatencyNs = time.Since(start).Nanoseconds()
+	if httpRes != nil {
+		res.Status = httpRes.Status
+		res.Attempts = httpRes.Attempts
+		res.Cache = httpRes.Cache
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to request %s: %v", req.Name, err)
+		return res, err
+	}
+	res.SetBody(httpRes.Body)
+
+	r.Logger.InfoLog.Printf("Upstream response: %s status=%d size=%d attempts=%d cache=%s latency=%dns", req.Name, res.Status, res.Size, res.Attempts, res.Cache, res.LatencyNs)
+	return res, nil
+}
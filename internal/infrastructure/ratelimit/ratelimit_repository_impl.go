@@ -0,0 +1,84 @@
+package infrastructure_ratelimit
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_ratelimit "backend/internal/repository/ratelimit"
+	"context"
+	"time"
+)
+
+// レート制限リポジトリ(Impl)
+// rate_limitsテーブルの行をSELECT ... FOR UPDATEでロックして更新し、複数のインスタンスで状態を共有する
+// スキーマは migrations/002_rate_limits.sql を参照
+type RateLimitRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// レート制限リポジトリのインスタンス化
+func NewRateLimitRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_ratelimit.IRateLimitRepository {
+	return &RateLimitRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// キーの状態をfnで更新して保存する
+func (r *RateLimitRepositoryImpl) Update(ctx context.Context, key string, ttl time.Duration, fn func(s domain_ratelimit.State) domain_ratelimit.State) (domain_ratelimit.State, error) {
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return domain_ratelimit.State{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 行がなければ作成し、ロックを取得する
+	_, err = tx.Exec(ctx, `
+		INSERT INTO rate_limits (key, expires_at) VALUES ($1, now())
+		ON CONFLICT (key) DO NOTHING
+	`, key)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to insert rate limit: %v", err)
+		return domain_ratelimit.State{}, err
+	}
+
+	var state domain_ratelimit.State
+	var stamp *time.Time
+	var expired bool
+	err = tx.QueryRow(ctx, `
+		SELECT tokens, count, prev_count, stamp, expires_at <= now()
+		FROM rate_limits
+		WHERE key = $1
+		FOR UPDATE
+	`, key).Scan(&state.Tokens, &state.Count, &state.PrevCount, &stamp, &expired)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to select rate limit: %v", err)
+		return domain_ratelimit.State{}, err
+	}
+	if expired || stamp == nil {
+		state = domain_ratelimit.State{}
+	} else {
+		state.Stamp = *stamp
+	}
+
+	state = fn(state)
+	_, err = tx.Exec(ctx, `
+		UPDATE rate_limits
+		SET tokens = $2, count = $3, prev_count = $4, stamp = $5, expires_at = now() + $6 * interval '1 millisecond'
+		WHERE key = $1
+	`, key, state.Tokens, state.Count, state.PrevCount, state.Stamp, ttl.Milliseconds())
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update rate limit: %v", err)
+		return domain_ratelimit.State{}, err
+	}
+
+	// トランザクションをコミット
+	if err = tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return domain_ratelimit.State{}, err
+	}
+	return state, nil
+}
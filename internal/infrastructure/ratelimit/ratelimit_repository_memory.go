@@ -0,0 +1,72 @@
+package infrastructure_ratelimit
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_ratelimit "backend/internal/repository/ratelimit"
+	"context"
+	"sync"
+	"time"
+)
+
+// 期限切れの状態を掃除する間隔
+const sweepInterval = time.Minute
+
+// レート制限リポジトリ(インメモリ)
+// 1つのインスタンス内でのみ状態を共有する。テストや単一インスタンスの環境で使用する
+type RateLimitMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	lastSweep time.Time
+}
+
+// 状態と有効期限
+type memoryEntry struct {
+	state     domain_ratelimit.State
+	expiresAt time.Time
+}
+
+// レート制限リポジトリ(インメモリ)のインスタンス化
+func NewRateLimitMemoryRepository(l *pkg_logger.AppLogger) repository_ratelimit.IRateLimitRepository {
+	return &RateLimitMemoryRepository{
+		Logger:    l,
+		entries:   map[string]memoryEntry{},
+		lastSweep: time.Now(),
+	}
+}
+
+// キーの状態をfnで更新して保存する
+func (r *RateLimitMemoryRepository) Update(ctx context.Context, key string, ttl time.Duration, fn func(s domain_ratelimit.State) domain_ratelimit.State) (domain_ratelimit.State, error) {
+	if err := ctx.Err(); err != nil {
+		return domain_ratelimit.State{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.sweep(now)
+
+	var state domain_ratelimit.State
+	if e, ok := r.entries[key]; ok && now.Before(e.expiresAt) {
+		state = e.state
+	}
+	state = fn(state)
+	r.entries[key] = memoryEntry{state: state, expiresAt: now.Add(ttl)}
+	return state, nil
+}
+
+// 期限切れの状態を削除する(sweepIntervalに1回まで)
+func (r *RateLimitMemoryRepository) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < sweepInterval {
+		return
+	}
+	r.lastSweep = now
+	for key, e := range r.entries {
+		if !now.Before(e.expiresAt) {
+			delete(r.entries, key)
+		}
+	}
+}
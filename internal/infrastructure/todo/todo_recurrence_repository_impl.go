@@ -0,0 +1,345 @@
+package infrastructure_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_todo "backend/internal/repository/todo"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// 繰り返しのTodoリポジトリ(Impl)
+// スキーマは migrations/013_todo_recurrences.sql を参照
+// scopeを受け取る操作はscopeのユーザーとして実行する(RLSのポリシーも適用する)
+type TodoRecurrenceRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+	// 作成先のワークスペース・並び順のキーはTodoリポジトリと同じ方法で決める
+	todos *TodoRepositoryImpl
+}
+
+// 繰り返しのTodoリポジトリのインスタンス化
+func NewTodoRecurrenceRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_todo.ITodoRecurrenceRepository {
+	return &TodoRecurrenceRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+		todos:          &TodoRepositoryImpl{Logger: l, SupabaseClient: sc},
+	}
+}
+
+// 繰り返しの列(scanRecurrenceの順)
+const recurrenceColumns = `rc.id, rc.workspace_id, rc.user_id, rc.description, rc.rrule, rc.timezone,
+	rc.start_at, rc.end_at, rc.materialized_until, rc.created_at, rc.updated_at`
+
+// ユーザーが参照できる繰り返しの条件
+// $1にユーザーID、$2に操作中のワークスペースID(空の場合は所属する全てのワークスペース)を渡す
+const visibleRecurrences = `
+	rc.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)
+	AND ($2 = '' OR rc.workspace_id::text = $2)
+`
+
+// 繰り返しを作成
+// 予定のTodoは作成しない(作成済みの日時は開始日時と現在の遅い方にし、過去の予定は作成しない)
+func (r *TodoRecurrenceRepositoryImpl) CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, error) {
+	r.Logger.InfoLog.Println("CreateRecurrence called")
+
+	var created domain_todo.Recurrence
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		// 作成先のワークスペース(オーナー・編集者のみ)
+		workspaceId, err := r.todos.editableWorkspace(tx, scope, recurrence.WorkspaceID)
+		if err != nil {
+			return err
+		}
+
+		created, err = scanRecurrence(tx.QueryRow(r.SupabaseClient.Ctx, `
+			INSERT INTO todo_recurrences AS rc (workspace_id, user_id, description, rrule, timezone, start_at, materialized_until)
+			VALUES ($1, $2, $3, $4, $5, $6, GREATEST($6, date_trunc('second', now())))
+			RETURNING `+recurrenceColumns,
+			workspaceId, recurrence.UserId, recurrence.Description, recurrence.RRule, recurrence.Timezone, recurrence.StartAt))
+		return err
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+		return domain_todo.Recurrence{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Created recurrence: %v", created.ID)
+	return created, nil
+}
+
+// 特定の繰り返しを取得
+func (r *TodoRecurrenceRepositoryImpl) GetRecurrenceById(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error) {
+	r.Logger.InfoLog.Println("GetRecurrenceById called")
+
+	query := `
+		SELECT ` + recurrenceColumns + `
+		FROM todo_recurrences rc
+		WHERE rc.id::text = $3 AND ` + visibleRecurrences
+
+	var recurrence domain_todo.Recurrence
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) (err error) {
+		recurrence, err = scanRecurrence(tx.QueryRow(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, id))
+		return err
+	})
+	if err == pgx.ErrNoRows {
+		return domain_todo.Recurrence{}, errors.New("recurrence not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch recurrence: %v", err)
+		return domain_todo.Recurrence{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Fetched recurrence: %v", recurrence.ID)
+	return recurrence, nil
+}
+
+// 予定のTodoの作成がuntilより前で止まっている繰り返しを取得
+// スケジューラから呼ぶため、接続プールのロールで全てのワークスペースを対象にする
+// (作成済みの日時, ID)でページを区切る(作成に失敗し続ける繰り返しがあっても、後の繰り返しを取得できる)
+func (r *TodoRecurrenceRepositoryImpl) GetPendingRecurrences(until time.Time, after domain_todo.Recurrence, limit int) ([]domain_todo.Recurrence, error) {
+	r.Logger.InfoLog.Println("GetPendingRecurrences called")
+
+	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, `
+		SELECT `+recurrenceColumns+`
+		FROM todo_recurrences rc
+		WHERE rc.materialized_until < $1
+			AND (rc.end_at IS NULL OR rc.materialized_until < rc.end_at)
+			AND ($3 = '' OR (rc.materialized_until, rc.id) > ($2, NULLIF($3, '')::uuid))
+		ORDER BY rc.materialized_until, rc.id
+		LIMIT $4
+	`, until, after.MaterializedUntil, after.ID, limit)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch recurrences: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	recurrences := []domain_todo.Recurrence{}
+	for rows.Next() {
+		recurrence, err := scanRecurrence(rows)
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan recurrence: %v", err)
+			return nil, err
+		}
+		recurrences = append(recurrences, recurrence)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate recurrences: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Fetched %d recurrences", len(recurrences))
+	return recurrences, nil
+}
+
+// 予定のTodoを作成し、作成済みの日時をuntilまで進める
+// ワークスペースのルートの末尾に追加する。作成済みの予定は一意制約(recurrence_id, occurrence_at)で飛ばす
+// スケジューラ・完了時のどちらからも呼ぶため、接続プールのロールで実行する
+func (r *TodoRecurrenceRepositoryImpl) MaterializeOccurrences(recurrence domain_todo.Recurrence, occurrences []time.Time, until time.Time) ([]domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("MaterializeOccurrences called")
+	ctx := r.SupabaseClient.Ctx
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// 繰り返しをロックし、現在の終了日時を確認する(同時に分けられても終了日時以降の予定を作らない)
+	var endAt *time.Time
+	err = tx.QueryRow(ctx, `SELECT end_at FROM todo_recurrences WHERE id::text = $1 FOR UPDATE`, recurrence.ID).Scan(&endAt)
+	if err == pgx.ErrNoRows {
+		return nil, errors.New("recurrence not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to lock recurrence: %v", err)
+		return nil, err
+	}
+	if err := r.todos.lockPositions(tx, recurrence.WorkspaceID); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to lock positions: %v", err)
+		return nil, err
+	}
+
+	// ルートのTodoは1回だけ取得し、作成した予定を末尾に追加していく
+	// 末尾のキーは最後に作成した予定のキーのため、振り直しても取得し直す必要はない
+	ids, keys, err := r.todos.siblings(tx, recurrence.WorkspaceID, "", "")
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch siblings: %v", err)
+		return nil, err
+	}
+
+	created := []domain_todo.Todo{}
+	for _, occurrenceAt := range occurrences {
+		if endAt != nil && !occurrenceAt.Before(*endAt) {
+			break
+		}
+
+		position, err := r.todos.positionAt(tx, ids, keys, len(ids))
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to generate position: %v", err)
+			return nil, err
+		}
+
+		todo, err := scanTodo(tx.QueryRow(ctx, `
+			INSERT INTO todos AS t (description, completed, user_id, workspace_id, position, due_at, recurrence_id, occurrence_at)
+			VALUES ($1, false, $2, $3, $4, $5, $6, $5)
+			ON CONFLICT (recurrence_id, occurrence_at) DO NOTHING
+			RETURNING `+todoColumns,
+			recurrence.Description, recurrence.UserId, recurrence.WorkspaceID, position, occurrenceAt, recurrence.ID))
+		if err == pgx.ErrNoRows {
+			// 作成済み(完了時とスケジューラが同時に作成した場合など)
+			continue
+		}
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to create occurrence: %v", err)
+			return nil, err
+		}
+		ids, keys = append(ids, todo.ID), append(keys, position)
+		created = append(created, todo)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE todo_recurrences
+		SET materialized_until = GREATEST(materialized_until, $2), updated_at = now()
+		WHERE id::text = $1
+	`, recurrence.ID, until)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update recurrence: %v", err)
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Created %d occurrences of recurrence: %v", len(created), recurrence.ID)
+	return created, nil
+}
+
+// 予定のTodoの説明・期日を変更
+// 繰り返しの中での予定日時(occurrence_at)は変更しない(同じ予定を作り直さないため)
+func (r *TodoRecurrenceRepositoryImpl) UpdateOccurrence(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("UpdateOccurrence called")
+
+	query := `
+		UPDATE todos t
+		SET description = $3, due_at = $4, updated_at = now()
+		WHERE t.id::text = $5 AND ` + editableTodos + `
+		RETURNING ` + todoColumns
+
+	var updated domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) (err error) {
+		updated, err = scanTodo(tx.QueryRow(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID,
+			todo.Description, todo.DueAt, todo.ID))
+		if err == pgx.ErrNoRows {
+			return r.todos.deniedTodo(tx, scope, todo.ID)
+		}
+		return err
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update occurrence: %v", err)
+		return domain_todo.Todo{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Updated occurrence: %v", updated)
+	return updated, nil
+}
+
+// 予定のTodoの日時で繰り返しを終了し、以降の予定の繰り返しを作成して予定のTodoを移す
+// 新しい繰り返しは元の繰り返しと同じワークスペースに作成し、作成済みの日時は開始日時と現在の遅い方にする
+// (開始日時の予定は移したTodoが一意制約で占めるため作り直さず、過去の予定も作成しない)
+func (r *TodoRecurrenceRepositoryImpl) SplitRecurrence(scope domain_todo.Scope, todoId string, next domain_todo.Recurrence) (domain_todo.Recurrence, domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("SplitRecurrence called")
+
+	var created domain_todo.Recurrence
+	var moved domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		ctx := r.SupabaseClient.Ctx
+
+		// 変更する予定のTodo(編集できるもののみ)
+		todo, err := scanTodo(tx.QueryRow(ctx, `
+			SELECT `+todoColumns+`
+			FROM todos t
+			WHERE t.id::text = $3 AND `+editableTodos+`
+			FOR UPDATE`, scope.UserID, scope.WorkspaceID, todoId))
+		if err == pgx.ErrNoRows {
+			return r.todos.deniedTodo(tx, scope, todoId)
+		}
+		if err != nil {
+			return err
+		}
+		if todo.RecurrenceID == "" || todo.OccurrenceAt == nil {
+			return errors.New("todo is not recurring")
+		}
+
+		// 元の繰り返しを予定の日時で終了する(既に早く終了している場合はそのまま)
+		_, err = tx.Exec(ctx, `
+			UPDATE todo_recurrences
+			SET end_at = LEAST(COALESCE(end_at, $2), $2), updated_at = now()
+			WHERE id::text = $1
+		`, todo.RecurrenceID, *todo.OccurrenceAt)
+		if err != nil {
+			return err
+		}
+
+		// 以降の未完了の予定を削除する(完了したものは履歴として残す)
+		_, err = tx.Exec(ctx, `
+			DELETE FROM todos
+			WHERE recurrence_id::text = $1 AND occurrence_at > $2 AND NOT completed
+		`, todo.RecurrenceID, *todo.OccurrenceAt)
+		if err != nil {
+			return err
+		}
+
+		created, err = scanRecurrence(tx.QueryRow(ctx, `
+			INSERT INTO todo_recurrences AS rc (workspace_id, user_id, description, rrule, timezone, start_at, materialized_until)
+			SELECT workspace_id, $2, $3, $4, $5, $6, GREATEST($6, date_trunc('second', now()))
+			FROM todo_recurrences
+			WHERE id::text = $1
+			RETURNING `+recurrenceColumns,
+			todo.RecurrenceID, next.UserId, next.Description, next.RRule, next.Timezone, next.StartAt))
+		if err != nil {
+			return err
+		}
+
+		// 予定のTodoを新しい繰り返しの最初の予定にする
+		moved, err = scanTodo(tx.QueryRow(ctx, `
+			UPDATE todos t
+			SET recurrence_id = $1, occurrence_at = $2, due_at = $2, description = $3, updated_at = now()
+			WHERE t.id::text = $4
+			RETURNING `+todoColumns, created.ID, created.StartAt, created.Description, todoId))
+		return err
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to split recurrence: %v", err)
+		return domain_todo.Recurrence{}, domain_todo.Todo{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Split recurrence: %v", created.ID)
+	return created, moved, nil
+}
+
+// 繰り返しを読み込む
+func scanRecurrence(row pgx.Row) (domain_todo.Recurrence, error) {
+	var recurrence domain_todo.Recurrence
+	err := row.Scan(
+		&recurrence.ID,
+		&recurrence.WorkspaceID,
+		&recurrence.UserId,
+		&recurrence.Description,
+		&recurrence.RRule,
+		&recurrence.Timezone,
+		&recurrence.StartAt,
+		&recurrence.EndAt,
+		&recurrence.MaterializedUntil,
+		&recurrence.CreatedAt,
+		&recurrence.UpdatedAt,
+	)
+	return recurrence, err
+}
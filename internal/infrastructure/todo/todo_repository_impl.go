@@ -3,11 +3,17 @@ package infrastructure_todo
 import (
 	domain_todo "backend/internal/domain/todo"
 	pkg_logger "backend/internal/pkg/logger"
+	pkg_rank "backend/internal/pkg/rank"
 	pkg_supabase "backend/internal/pkg/supabase"
 	repository_todo "backend/internal/repository/todo"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
 )
 
 // Todoリポジトリ(Impl)
+// ワークスペースのスキーマは migrations/009_workspaces.sql を参照
+// 全てのクエリはscopeのユーザーとして実行し、RLSのポリシーでも所属するワークスペースに限定する
 type TodoRepositoryImpl struct {
 	Logger         *pkg_logger.AppLogger
 	SupabaseClient *pkg_supabase.SupabaseClient
@@ -21,65 +27,75 @@ func NewTodoRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient)
 	}
 }
 
+// Todoの列(scanTodoの順)
+const todoColumns = `t.id, t.description, t.completed, t.user_id, t.workspace_id, COALESCE(t.parent_id::text, ''), t.position,
+	t.due_at, COALESCE(t.recurrence_id::text, ''), t.occurrence_at, t.created_at, t.updated_at`
+
+// Todoのリストの並び順
+// ワークスペース・親ごとにまとめ、兄弟の中ではposition順(同じ場合はID順)
+const todoOrder = `t.workspace_id, t.parent_id NULLS FIRST, t.position, t.id`
+
+// ユーザーが参照できるTodoの条件
+// $1にユーザーID、$2に操作中のワークスペースID(空の場合は所属する全てのワークスペース)を渡す
+const visibleTodos = `
+	t.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)
+	AND ($2 = '' OR t.workspace_id::text = $2)
+`
+
+// ユーザーが編集できるTodoの条件(オーナー・編集者)
+const editableTodos = `
+	t.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1 AND role IN ('owner', 'editor'))
+	AND ($2 = '' OR t.workspace_id::text = $2)
+`
+
 // 全てのTodoを取得
-func (r *TodoRepositoryImpl) GetAllTodos() ([]domain_todo.Todo, error) {
+func (r *TodoRepositoryImpl) GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error) {
 	r.Logger.InfoLog.Println("GetAllTodos called")
 
 	query := `
-		SELECT id, description, completed, user_id, created_at, updated_at
-		FROM todos
-	`
-
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query)
+		SELECT ` + todoColumns + `
+		FROM todos t
+		WHERE ` + visibleTodos + `
+		ORDER BY ` + todoOrder
+
+	// Supabaseからクエリを実行し、条件に一致するTodoを取得
+	var todos []domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		todos, err = r.scanTodos(rows)
+		return err
+	})
 	if err != nil {
 		r.Logger.ErrorLog.Printf("Failed to fetch todos: %v", err)
 		return nil, err
 	}
 
-	// Todosのリストを作成
-	todos := []domain_todo.Todo{}
-	for rows.Next() {
-		var todo domain_todo.Todo
-		err = rows.Scan(
-			&todo.ID,
-			&todo.Description,
-			&todo.Completed,
-			&todo.UserId,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
-		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to scan todo: %v", err)
-			return nil, err
-		}
-		todos = append(todos, todo)
-	}
-
 	r.Logger.InfoLog.Printf("Fetched %d todos", len(todos))
 	return todos, nil
 }
 
 // 特定のTodoを取得
-func (r *TodoRepositoryImpl) GetTodoById(id string) (domain_todo.Todo, error) {
+func (r *TodoRepositoryImpl) GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error) {
 	r.Logger.InfoLog.Println("GetTodoById called")
 
 	query := `
-		SELECT id, description, completed, user_id, created_at, updated_at
-		FROM todos
-		WHERE id = $1
-	`
+		SELECT ` + todoColumns + `
+		FROM todos t
+		WHERE t.id = $3 AND ` + visibleTodos
 
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
+	// Supabaseからクエリを実行し、条件に一致するTodoを取得
 	var todo domain_todo.Todo
-	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, id).
-		Scan(&todo.ID,
-			&todo.Description,
-			&todo.Completed,
-			&todo.UserId,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) (err error) {
+		todo, err = scanTodo(tx.QueryRow(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, id))
+		return err
+	})
+	if err == pgx.ErrNoRows {
+		return domain_todo.Todo{}, errors.New("todo not found")
+	}
 	if err != nil {
 		r.Logger.ErrorLog.Printf("Failed to fetch todo: %v", err)
 		return domain_todo.Todo{}, err
@@ -90,187 +106,517 @@ func (r *TodoRepositoryImpl) GetTodoById(id string) (domain_todo.Todo, error) {
 }
 
 // 特定のユーザーのTodoを取得
-func (r *TodoRepositoryImpl) GetTodoByUserId(userId string) ([]domain_todo.Todo, error) {
+func (r *TodoRepositoryImpl) GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error) {
 	r.Logger.InfoLog.Println("GetTodoByUserId called")
 
 	query := `
-		SELECT id, description, completed, user_id, created_at, updated_at
-		FROM todos
-		WHERE user_id = $1
-	`
-
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query, userId)
+		SELECT ` + todoColumns + `
+		FROM todos t
+		WHERE t.user_id = $3 AND ` + visibleTodos + `
+		ORDER BY ` + todoOrder
+
+	// Supabaseからクエリを実行し、条件に一致するTodoを取得
+	var todos []domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, userId)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		todos, err = r.scanTodos(rows)
+		return err
+	})
 	if err != nil {
 		r.Logger.ErrorLog.Printf("Failed to fetch todos: %v", err)
 		return nil, err
 	}
 
-	// Todosのリストを作成
-	todos := []domain_todo.Todo{}
-	for rows.Next() {
-		var todo domain_todo.Todo
-		err = rows.Scan(
-			&todo.ID,
-			&todo.Description,
-			&todo.Completed,
-			&todo.UserId,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
-		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to scan todo: %v", err)
-			return nil, err
-		}
-		todos = append(todos, todo)
-	}
-
 	r.Logger.InfoLog.Printf("Fetched %d todos", len(todos))
 	return todos, nil
 }
 
 // 新しいTodoを作成
-func (r *TodoRepositoryImpl) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
+// 作成先はtodo.WorkspaceID、空の場合はユーザーの個人用のワークスペース
+// 兄弟の末尾に追加する(親は同じワークスペースのTodoに限る)
+func (r *TodoRepositoryImpl) CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
 	r.Logger.InfoLog.Println("CreateTodo called")
 
-	query := `
-		INSERT INTO todos (description, completed, user_id)
-		VALUES ($1, $2, $3)
-		RETURNING id, description, completed, user_id, created_at, updated_at
-	`
+	// Supabaseからクエリを実行し、作成したTodoを取得
+	var created domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		ctx := r.SupabaseClient.Ctx
 
-	// トランザクション開始
-	tx, err := r.SupabaseClient.Pool.Begin(r.SupabaseClient.Ctx)
-	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
-		return domain_todo.Todo{}, err
-	}
-	defer func() {
+		// 作成先のワークスペース(オーナー・編集者のみ)
+		workspaceId, err := r.editableWorkspace(tx, scope, todo.WorkspaceID)
 		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to rollback transaction: %v", err)
-			tx.Rollback(r.SupabaseClient.Ctx)
+			return err
 		}
-	}()
-
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	err = tx.QueryRow(r.SupabaseClient.Ctx, query, todo.Description, todo.Completed, todo.UserId).
-		Scan(&todo.ID,
-			&todo.Description,
-			&todo.Completed,
-			&todo.UserId,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
+
+		// 兄弟の末尾のキー
+		if err := r.lockPositions(tx, workspaceId); err != nil {
+			return err
+		}
+		ids, keys, err := r.siblings(tx, workspaceId, todo.ParentID, "")
+		if err != nil {
+			return err
+		}
+		position, err := r.positionAt(tx, ids, keys, len(ids))
+		if err != nil {
+			return err
+		}
+
+		created, err = scanTodo(tx.QueryRow(ctx, `
+			INSERT INTO todos AS t (description, completed, user_id, workspace_id, parent_id, position, due_at)
+			VALUES ($1, $2, $3, $4::uuid, NULLIF($5, '')::uuid, $6, $7)
+			RETURNING `+todoColumns,
+			todo.Description, todo.Completed, todo.UserId, workspaceId, todo.ParentID, position, todo.DueAt))
+		return err
+	})
 	if err != nil {
 		r.Logger.ErrorLog.Printf("Failed to create todo: %v", err)
 		return domain_todo.Todo{}, err
 	}
 
-	// トランザクションをコミット
-	err = tx.Commit(r.SupabaseClient.Ctx)
+	r.Logger.InfoLog.Printf("Created todo: %v", created)
+	return created, nil
+}
+
+// 特定のTodoを更新
+// ワークスペース・親・位置は変更しない(MoveTodoで変更する)。作成者は変更できない
+func (r *TodoRepositoryImpl) UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("UpdateTodo called")
+
+	query := `
+		UPDATE todos t
+		SET description = $3, completed = $4, created_at = $5, updated_at = $6
+		WHERE t.id = $7 AND ` + editableTodos + `
+		RETURNING ` + todoColumns
+
+	// Supabaseからクエリを実行し、更新したTodoを取得
+	var updated domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) (err error) {
+		updated, err = scanTodo(tx.QueryRow(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID,
+			todo.Description, todo.Completed, todo.CreatedAt, todo.UpdatedAt, todo.ID))
+		if err == pgx.ErrNoRows {
+			return r.deniedTodo(tx, scope, todo.ID)
+		}
+		return err
+	})
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		r.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
 		return domain_todo.Todo{}, err
 	}
 
-	// 正常系にし、ロールバックを防ぐ
-	err = nil
+	r.Logger.InfoLog.Printf("Updated todo: %v", updated)
+	return updated, nil
+}
 
-	r.Logger.InfoLog.Printf("Created todo: %v", todo)
-	return todo, nil
+// 特定のTodoを削除
+// 子孫のTodoは外部キー(ON DELETE CASCADE)で削除する
+func (r *TodoRepositoryImpl) DeleteTodo(scope domain_todo.Scope, id string) error {
+	r.Logger.InfoLog.Println("DeleteTodo called")
+
+	query := `
+		DELETE FROM todos t
+		WHERE t.id = $3 AND ` + editableTodos
+
+	// Supabaseからクエリを実行し、条件に一致するTodoを削除
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, id)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return r.deniedTodo(tx, scope, id)
+		}
+		return nil
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
+		return err
+	}
+
+	r.Logger.InfoLog.Printf("Deleted todo: %v", id)
+	return nil
 }
 
-// 特定のTodoを更新
-func (r *TodoRepositoryImpl) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
-	r.Logger.InfoLog.Println("UpdateTodo called")
+// 特定のTodoと子孫のTodoを取得
+// 再帰CTEで階層をたどる(深さはMaxTodoDepthまで)。階層の浅い順、兄弟の中ではposition順
+func (r *TodoRepositoryImpl) GetTodoSubtree(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("GetTodoSubtree called")
 
 	query := `
-		UPDATE todos
-		SET description = $1, completed = $2, user_id = $3, created_at = $4, updated_at = $5
-		WHERE id = $6
-		RETURNING id, description, completed, user_id, created_at, updated_at
+		WITH RECURSIVE subtree AS (
+			SELECT t.*, 1 AS depth
+			FROM todos t
+			WHERE t.id::text = $3 AND ` + visibleTodos + `
+			UNION ALL
+			SELECT c.*, s.depth + 1
+			FROM todos c
+			JOIN subtree s ON c.parent_id = s.id
+			WHERE s.depth < $4
+		)
+		SELECT ` + todoColumns + `
+		FROM subtree t
+		ORDER BY t.depth, t.position, t.id
 	`
 
-	// トランザクションを開始
-	tx, err := r.SupabaseClient.Pool.Begin(r.SupabaseClient.Ctx)
+	// Supabaseからクエリを実行し、階層のTodoを取得
+	var todos []domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, id, domain_todo.MaxTodoDepth)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		todos, err = r.scanTodos(rows)
+		return err
+	})
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
-		return domain_todo.Todo{}, err
+		r.Logger.ErrorLog.Printf("Failed to fetch subtree: %v", err)
+		return nil, err
 	}
-	defer func() {
+	if len(todos) == 0 {
+		return nil, errors.New("todo not found")
+	}
+
+	r.Logger.InfoLog.Printf("Fetched %d todos", len(todos))
+	return todos, nil
+}
+
+// 特定のTodoの祖先のTodoを取得
+// 再帰CTEで親をたどる。親から順に、ルートのTodoの場合は空
+func (r *TodoRepositoryImpl) GetTodoAncestors(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("GetTodoAncestors called")
+
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT p.*, 1 AS depth
+			FROM todos c
+			JOIN todos p ON p.id = c.parent_id
+			WHERE c.id::text = $3
+			UNION ALL
+			SELECT p.*, a.depth + 1
+			FROM todos p
+			JOIN ancestors a ON p.id = a.parent_id
+			WHERE a.depth < $4
+		)
+		SELECT ` + todoColumns + `
+		FROM ancestors t
+		WHERE ` + visibleTodos + `
+		ORDER BY t.depth
+	`
+
+	// Supabaseからクエリを実行し、祖先のTodoを取得
+	var todos []domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, id, domain_todo.MaxTodoDepth)
 		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to rollback transaction: %v", err)
-			tx.Rollback(r.SupabaseClient.Ctx)
+			return err
 		}
-	}()
-
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	err = tx.QueryRow(r.SupabaseClient.Ctx, query, todo.Description, todo.Completed, todo.UserId, todo.CreatedAt, todo.UpdatedAt, todo.ID).
-		Scan(&todo.ID,
-			&todo.Description,
-			&todo.Completed,
-			&todo.UserId,
-			&todo.CreatedAt,
-			&todo.UpdatedAt,
-		)
+		defer rows.Close()
+		todos, err = r.scanTodos(rows)
+		return err
+	})
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
-		return domain_todo.Todo{}, err
+		r.Logger.ErrorLog.Printf("Failed to fetch ancestors: %v", err)
+		return nil, err
 	}
 
-	// トランザクションをコミット
-	err = tx.Commit(r.SupabaseClient.Ctx)
+	r.Logger.InfoLog.Printf("Fetched %d todos", len(todos))
+	return todos, nil
+}
+
+// 特定のTodoの親と兄弟の中での位置を変更
+// 隣のTodoのキーの間のキーを設定する。同じワークスペースの並びの変更はアドバイザリロックで直列にする
+func (r *TodoRepositoryImpl) MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("MoveTodo called")
+
+	var moved domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		ctx := r.SupabaseClient.Ctx
+
+		// 移動するTodo(編集できるもののみ)
+		todo, err := scanTodo(tx.QueryRow(ctx, `
+			SELECT `+todoColumns+`
+			FROM todos t
+			WHERE t.id::text = $3 AND `+editableTodos, scope.UserID, scope.WorkspaceID, id))
+		if err == pgx.ErrNoRows {
+			return r.deniedTodo(tx, scope, id)
+		}
+		if err != nil {
+			return err
+		}
+		if err := r.lockPositions(tx, todo.WorkspaceID); err != nil {
+			return err
+		}
+
+		if move.ParentID != "" {
+			// 親は同じワークスペースのTodoに限る
+			var exists bool
+			err := tx.QueryRow(ctx, `
+				SELECT EXISTS (SELECT 1 FROM todos t WHERE t.id::text = $3 AND t.workspace_id::text = $4 AND `+visibleTodos+`)
+			`, scope.UserID, scope.WorkspaceID, move.ParentID, todo.WorkspaceID).Scan(&exists)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return errors.New("parent not found")
+			}
+
+			// 親の祖先(親自身を含む)に移動するTodoがあれば循環する
+			var cycle bool
+			err = tx.QueryRow(ctx, `
+				WITH RECURSIVE up AS (
+					SELECT id, parent_id FROM todos WHERE id::text = $1
+					UNION
+					SELECT p.id, p.parent_id FROM todos p JOIN up ON p.id = up.parent_id
+				)
+				SELECT EXISTS (SELECT 1 FROM up WHERE id::text = $2)
+			`, move.ParentID, id).Scan(&cycle)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return errors.New("cycle detected")
+			}
+		}
+
+		// 移動先の兄弟の中での位置
+		ids, keys, err := r.siblings(tx, todo.WorkspaceID, move.ParentID, id)
+		if err != nil {
+			return err
+		}
+		index := len(ids)
+		switch {
+		case move.AfterID != "":
+			index = indexOf(ids, move.AfterID) + 1
+			if index == 0 {
+				return errors.New("neighbour not found")
+			}
+			if move.BeforeID != "" && (index == len(ids) || ids[index] != move.BeforeID) {
+				return errors.New("neighbours are not adjacent")
+			}
+		case move.BeforeID != "":
+			index = indexOf(ids, move.BeforeID)
+			if index < 0 {
+				return errors.New("neighbour not found")
+			}
+		}
+		position, err := r.positionAt(tx, ids, keys, index)
+		if err != nil {
+			return err
+		}
+
+		moved, err = scanTodo(tx.QueryRow(ctx, `
+			UPDATE todos t
+			SET parent_id = NULLIF($1, '')::uuid, position = $2, updated_at = now()
+			WHERE t.id::text = $3
+			RETURNING `+todoColumns, move.ParentID, position, id))
+		return err
+	})
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		r.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
 		return domain_todo.Todo{}, err
 	}
 
-	// 正常系にし、ロールバックを防ぐ
-	err = nil
-
-	r.Logger.InfoLog.Printf("Updated todo: %v", todo)
-	return todo, nil
+	r.Logger.InfoLog.Printf("Moved todo: %v", moved)
+	return moved, nil
 }
 
-// 特定のTodoを削除
-func (r *TodoRepositoryImpl) DeleteTodo(id string) error {
-	r.Logger.InfoLog.Println("DeleteTodo called")
+// 複数のTodoの完了状態を変更
+// 全て同じトランザクションで更新する(編集できないものがあれば何も変更しない)
+func (r *TodoRepositoryImpl) SetTodosCompleted(scope domain_todo.Scope, ids []string, completed bool) ([]domain_todo.Todo, error) {
+	r.Logger.InfoLog.Println("SetTodosCompleted called")
 
 	query := `
-		DELETE FROM todos
-		WHERE id = $1
-	`
+		UPDATE todos t
+		SET completed = $3, updated_at = now()
+		WHERE t.id::text = ANY ($4::text[]) AND ` + editableTodos + `
+		RETURNING ` + todoColumns
+
+	// Supabaseからクエリを実行し、更新したTodoを取得
+	var todos []domain_todo.Todo
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, scope.UserID, scope.WorkspaceID, completed, ids)
+		if err != nil {
+			return err
+		}
+		todos, err = r.scanTodos(rows)
+		rows.Close()
+		if err != nil {
+			return err
+		}
+		// 更新できなかったTodoの理由を返す(トランザクションはロールバックする)
+		updated := map[string]bool{}
+		for _, todo := range todos {
+			updated[todo.ID] = true
+		}
+		for _, id := range ids {
+			if !updated[id] {
+				return r.deniedTodo(tx, scope, id)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update todos: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Updated %d todos", len(todos))
+	return todos, nil
+}
 
-	// トランザクションを開始
-	tx, err := r.SupabaseClient.Pool.Begin(r.SupabaseClient.Ctx)
+// Todoを編集できなかった理由を返す
+// 参照できれば権限不足、できなければ存在しない
+func (r *TodoRepositoryImpl) deniedTodo(tx pgx.Tx, scope domain_todo.Scope, id string) error {
+	return r.denied(tx, `SELECT EXISTS (
+		SELECT 1 FROM todos t WHERE t.id::text = $3 AND `+visibleTodos+`
+	)`, scope.UserID, scope.WorkspaceID, errors.New("todo not found"), id)
+}
+
+// 参照できるかを確認するクエリを実行し、できれば"permission denied"、できなければnotFoundを返す
+func (r *TodoRepositoryImpl) denied(tx pgx.Tx, query string, userId string, workspaceId string, notFound error, args ...any) error {
+	var visible bool
+	err := tx.QueryRow(r.SupabaseClient.Ctx, query, append([]any{userId, workspaceId}, args...)...).Scan(&visible)
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		r.Logger.ErrorLog.Printf("Failed to check permission: %v", err)
 		return err
 	}
-	defer func() {
-		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to rollback transaction: %v", err)
-			tx.Rollback(r.SupabaseClient.Ctx)
-		}
-	}()
+	if visible {
+		return errors.New("permission denied")
+	}
+	return notFound
+}
 
-	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	_, err = tx.Exec(r.SupabaseClient.Ctx, query, id)
+// 作成先のワークスペース(空の場合は個人用のワークスペース)
+// オーナー・編集者でない場合は、所属していれば"permission denied"、していなければ"workspace not found"
+func (r *TodoRepositoryImpl) editableWorkspace(tx pgx.Tx, scope domain_todo.Scope, workspaceId string) (string, error) {
+	var id string
+	err := tx.QueryRow(r.SupabaseClient.Ctx, `
+		SELECT m.workspace_id::text
+		FROM workspace_members m
+		WHERE m.user_id = $1 AND m.role IN ('owner', 'editor')
+			AND m.workspace_id::text = COALESCE(NULLIF($2, ''), (SELECT id::text FROM workspaces WHERE personal_user_id = $1))
+	`, scope.UserID, workspaceId).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", r.denied(tx, `SELECT EXISTS (
+			SELECT 1 FROM workspace_members WHERE user_id = $1 AND workspace_id::text = $2
+		)`, scope.UserID, workspaceId, errors.New("workspace not found"))
+	}
+	return id, err
+}
+
+// 並び順を変更するため、ワークスペースをロックする(トランザクションの終了まで)
+// 同時に作成・移動しても同じキーを作らない
+func (r *TodoRepositoryImpl) lockPositions(tx pgx.Tx, workspaceId string) error {
+	_, err := tx.Exec(r.SupabaseClient.Ctx, `SELECT pg_advisory_xact_lock(hashtext('todo_positions'), hashtext($1))`, workspaceId)
+	return err
+}
+
+// 兄弟のTodoのIDとキー(position順、exceptIdのTodoを除く)
+func (r *TodoRepositoryImpl) siblings(tx pgx.Tx, workspaceId string, parentId string, exceptId string) ([]string, []string, error) {
+	rows, err := tx.Query(r.SupabaseClient.Ctx, `
+		SELECT id::text, position FROM todos
+		WHERE workspace_id::text = $1 AND parent_id IS NOT DISTINCT FROM NULLIF($2, '')::uuid AND id::text <> $3
+		ORDER BY position, id
+	`, workspaceId, parentId, exceptId)
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
-		return err
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	ids, keys := []string{}, []string{}
+	for rows.Next() {
+		var id, key string
+		if err := rows.Scan(&id, &key); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		keys = append(keys, key)
 	}
+	return ids, keys, rows.Err()
+}
 
-	// トランザクションをコミット
-	err = tx.Commit(r.SupabaseClient.Ctx)
+// 兄弟のindex番目に入れるキー
+// キーがMaxTodoPositionLengthより長くなる場合は、兄弟のキーを等間隔に振り直す
+func (r *TodoRepositoryImpl) positionAt(tx pgx.Tx, ids []string, keys []string, index int) (string, error) {
+	before, after := "", ""
+	if index > 0 {
+		before = keys[index-1]
+	}
+	if index < len(keys) {
+		after = keys[index]
+	}
+	key, err := pkg_rank.Between(before, after)
+	if err == nil && len(key) <= domain_todo.MaxTodoPositionLength {
+		return key, nil
+	}
+
+	// 振り直す間は一意制約の確認をコミットまで遅らせる
+	spread := pkg_rank.Spread(len(ids) + 1)
+	newKeys := append(append([]string{}, spread[:index]...), spread[index+1:]...)
+	if _, err := tx.Exec(r.SupabaseClient.Ctx, `SET CONSTRAINTS todos_position_unique DEFERRED`); err != nil {
+		return "", err
+	}
+	_, err = tx.Exec(r.SupabaseClient.Ctx, `
+		UPDATE todos t SET position = k.position
+		FROM unnest($1::text[], $2::text[]) AS k (id, position)
+		WHERE t.id::text = k.id
+	`, ids, newKeys)
 	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
-		return err
+		return "", err
 	}
 
-	// 正常系にし、ロールバックを防ぐ
-	err = nil
+	r.Logger.InfoLog.Printf("Rebalanced %d todo positions", len(ids))
+	return spread[index], nil
+}
 
-	r.Logger.InfoLog.Printf("Deleted todo: %v", id)
-	return nil
+// スライスの中の位置(無い場合は-1)
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// Todoのリストを読み込む
+func (r *TodoRepositoryImpl) scanTodos(rows pgx.Rows) ([]domain_todo.Todo, error) {
+	todos := []domain_todo.Todo{}
+	for rows.Next() {
+		todo, err := scanTodo(rows)
+		if err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan todo: %v", err)
+			return nil, err
+		}
+		todos = append(todos, todo)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate todos: %v", err)
+		return nil, err
+	}
+	return todos, nil
+}
+
+// Todoを読み込む
+func scanTodo(row pgx.Row) (domain_todo.Todo, error) {
+	var todo domain_todo.Todo
+	err := row.Scan(
+		&todo.ID,
+		&todo.Description,
+		&todo.Completed,
+		&todo.UserId,
+		&todo.WorkspaceID,
+		&todo.ParentID,
+		&todo.Position,
+		&todo.DueAt,
+		&todo.RecurrenceID,
+		&todo.OccurrenceAt,
+		&todo.CreatedAt,
+		&todo.UpdatedAt,
+	)
+	return todo, err
 }
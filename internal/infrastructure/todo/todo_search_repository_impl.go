@@ -0,0 +1,89 @@
+package infrastructure_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_todo "backend/internal/repository/todo"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// Todo検索リポジトリ(Impl)
+// todos.search_vector(tsvector + GINインデックス)を利用する
+// スキーマは migrations/001_todo_search.sql を参照
+type TodoSearchRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// Todo検索リポジトリのインスタンス化
+func NewTodoSearchRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_todo.ITodoSearchRepository {
+	return &TodoSearchRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// ユーザーが参照できるTodoを全文検索
+func (r *TodoSearchRepositoryImpl) SearchTodos(scope domain_todo.Scope, query domain_todo.TodoSearchQuery) ([]domain_todo.TodoSearchResult, error) {
+	r.Logger.InfoLog.Println("SearchTodos called")
+
+	sql := `
+		SELECT ` + todoColumns + `,
+			ts_rank(t.search_vector, q) AS rank,
+			ts_headline('simple', translate(t.description, $5::text, ''), q, 'StartSel=' || $6::text || ', StopSel=' || $7::text || ', HighlightAll=true') AS highlight
+		FROM todos t, to_tsquery('simple', $3) q
+		WHERE t.search_vector @@ q AND ` + visibleTodos + `
+		ORDER BY rank DESC, t.updated_at DESC, t.id
+		LIMIT $4
+	`
+
+	// Supabaseからクエリを実行し、条件に一致するTodoを取得(RLSのポリシーも適用する)
+	results := []domain_todo.TodoSearchResult{}
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, scope.UserID, func(tx pgx.Tx) error {
+		// 説明文は目印でハイライトし、エスケープしてからタグに置き換える
+		marks := domain_todo.HighlightStartMark + domain_todo.HighlightStopMark
+		rows, err := tx.Query(r.SupabaseClient.Ctx, sql, scope.UserID, scope.WorkspaceID, query.ToTsQuery(), query.Limit, marks, domain_todo.HighlightStartMark, domain_todo.HighlightStopMark)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		// 検索結果のリストを作成
+		for rows.Next() {
+			var result domain_todo.TodoSearchResult
+			var rank float32
+			err = rows.Scan(
+				&result.Todo.ID,
+				&result.Todo.Description,
+				&result.Todo.Completed,
+				&result.Todo.UserId,
+				&result.Todo.WorkspaceID,
+				&result.Todo.ParentID,
+				&result.Todo.Position,
+				&result.Todo.DueAt,
+				&result.Todo.RecurrenceID,
+				&result.Todo.OccurrenceAt,
+				&result.Todo.CreatedAt,
+				&result.Todo.UpdatedAt,
+				&rank,
+				&result.Highlight,
+			)
+			if err != nil {
+				return err
+			}
+			result.Rank = float64(rank)
+			result.Highlight = domain_todo.RenderHighlight(result.Highlight)
+			results = append(results, result)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to search todos: %v", err)
+		return nil, err
+	}
+
+	r.Logger.InfoLog.Printf("Found %d todos", len(results))
+	return results, nil
+}
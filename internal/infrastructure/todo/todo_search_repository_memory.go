@@ -0,0 +1,60 @@
+package infrastructure_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_todo "backend/internal/repository/todo"
+	"sort"
+)
+
+// Todo検索リポジトリ(インメモリ)
+// DBを使わず、Todoリポジトリから取得したTodoを簡易マッチャーで検索する
+// テストやDBの無い環境で使用する
+type TodoSearchMemoryRepository struct {
+	Logger         *pkg_logger.AppLogger
+	todoRepository repository_todo.ITodoRepository
+}
+
+// Todo検索リポジトリ(インメモリ)のインスタンス化
+func NewTodoSearchMemoryRepository(l *pkg_logger.AppLogger, tr repository_todo.ITodoRepository) repository_todo.ITodoSearchRepository {
+	return &TodoSearchMemoryRepository{
+		Logger:         l,
+		todoRepository: tr,
+	}
+}
+
+// ユーザーが参照できるTodoを全文検索
+func (r *TodoSearchMemoryRepository) SearchTodos(scope domain_todo.Scope, query domain_todo.TodoSearchQuery) ([]domain_todo.TodoSearchResult, error) {
+	r.Logger.InfoLog.Println("SearchTodos called (memory)")
+
+	// 検索対象は所属するワークスペースのTodo(絞り込みはTodoリポジトリに任せる)
+	todos, err := r.todoRepository.GetAllTodos(scope)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch todos: %v", err)
+		return nil, err
+	}
+
+	results := []domain_todo.TodoSearchResult{}
+	for _, todo := range todos {
+		if result, ok := query.Match(todo); ok {
+			results = append(results, result)
+		}
+	}
+
+	// 関連度の高い順、同じ場合は更新日時の新しい順、ID順(DBの実装と合わせる)
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		if !results[i].Todo.UpdatedAt.Equal(results[j].Todo.UpdatedAt) {
+			return results[i].Todo.UpdatedAt.After(results[j].Todo.UpdatedAt)
+		}
+		return results[i].Todo.ID < results[j].Todo.ID
+	})
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	r.Logger.InfoLog.Printf("Found %d todos", len(results))
+	return results, nil
+}
@@ -5,6 +5,8 @@ import (
 	pkg_logger "backend/internal/pkg/logger"
 	pkg_supabase "backend/internal/pkg/supabase"
 	repository_user "backend/internal/repository/user"
+
+	"github.com/jackc/pgx/v4"
 )
 
 // ユーザーリポジトリ(Impl)
@@ -21,38 +23,49 @@ func NewUserRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient)
 	}
 }
 
-// 全てのユーザーを取得
-func (r *UserRepositoryImpl) GetAllUsers() ([]domain_user.Users, error) {
+// 参照できる全てのユーザーを取得
+// 自分と、同じワークスペースのメンバーに限定する(RLSのポリシーでも同じ条件を適用する)
+func (r *UserRepositoryImpl) GetAllUsers(userId string) ([]domain_user.Users, error) {
 	r.Logger.InfoLog.Printf("Fetching users from Supabase.")
 
 	query := `
-        SELECT id, username, email, created_at, updated_at
-        FROM users
+        SELECT u.id, u.username, u.email, u.created_at, u.updated_at
+        FROM users u
+        WHERE u.id = $1 OR u.id IN (
+            SELECT m.user_id FROM workspace_members m
+            WHERE m.workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1)
+        )
     `
 
 	// Supabaseからクエリを実行し、条件に一致するユーザーを取得
-	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query)
-	if err != nil {
-		r.Logger.ErrorLog.Printf("Failed to fetch users: %v", err)
-		return nil, err
-	}
-
-	// ユーザーのリストを作成
 	users := []domain_user.Users{}
-	for rows.Next() {
-		var user domain_user.Users
-		err = rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.Email,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
+	err := r.SupabaseClient.AsUser(r.SupabaseClient.Ctx, userId, func(tx pgx.Tx) error {
+		rows, err := tx.Query(r.SupabaseClient.Ctx, query, userId)
 		if err != nil {
-			r.Logger.ErrorLog.Printf("Failed to scan user: %v", err)
-			return nil, err
+			return err
 		}
-		users = append(users, user)
+		defer rows.Close()
+
+		// ユーザーのリストを作成
+		for rows.Next() {
+			var user domain_user.Users
+			err = rows.Scan(
+				&user.ID,
+				&user.Username,
+				&user.Email,
+				&user.CreatedAt,
+				&user.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+			users = append(users, user)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch users: %v", err)
+		return nil, err
 	}
 
 	// ユーザーのリストを返す
@@ -0,0 +1,416 @@
+package infrastructure_workspace
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	repository_workspace "backend/internal/repository/workspace"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// ワークスペースのリポジトリ(Impl)
+// スキーマは migrations/009_workspaces.sql を参照
+type WorkspaceRepositoryImpl struct {
+	Logger         *pkg_logger.AppLogger
+	SupabaseClient *pkg_supabase.SupabaseClient
+}
+
+// ワークスペースのリポジトリのインスタンス化
+func NewWorkspaceRepository(l *pkg_logger.AppLogger, sc *pkg_supabase.SupabaseClient) repository_workspace.IWorkspaceRepository {
+	return &WorkspaceRepositoryImpl{
+		Logger:         l,
+		SupabaseClient: sc,
+	}
+}
+
+// SELECTするカラム(scanWorkspaceの順)
+const workspaceColumns = `w.id, w.name, w.personal_user_id IS NOT NULL, w.created_at`
+
+// SELECTするカラム(scanInvitationの順)
+const invitationColumns = `id, workspace_id, email, role, COALESCE(invited_by::text, ''), token_hash, expires_at, accepted_at, created_at`
+
+// ワークスペースを作成し、作成したユーザーをオーナーにする
+func (r *WorkspaceRepositoryImpl) CreateWorkspace(workspace domain_workspace.Workspace, ownerId string) (domain_workspace.Workspace, error) {
+	r.Logger.InfoLog.Println("CreateWorkspace called")
+	ctx := r.SupabaseClient.Ctx
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return domain_workspace.Workspace{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	created, err := scanWorkspace(tx.QueryRow(ctx, `
+		INSERT INTO workspaces AS w (name)
+		VALUES ($1)
+		RETURNING `+workspaceColumns, workspace.Name))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create workspace: %v", err)
+		return domain_workspace.Workspace{}, err
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+	`, created.ID, ownerId, domain_workspace.RoleOwner)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to add workspace owner: %v", err)
+		return domain_workspace.Workspace{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return domain_workspace.Workspace{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Created workspace: %s", created.ID)
+	return created, nil
+}
+
+// ワークスペースを取得
+func (r *WorkspaceRepositoryImpl) GetWorkspace(id string) (domain_workspace.Workspace, error) {
+	query := `SELECT ` + workspaceColumns + ` FROM workspaces w WHERE w.id = $1`
+
+	workspace, err := scanWorkspace(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Workspace{}, errors.New("workspace not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch workspace: %v", err)
+		return domain_workspace.Workspace{}, err
+	}
+	return workspace, nil
+}
+
+// ユーザーが所属するワークスペースを取得(個人用のワークスペースを先頭にする)
+func (r *WorkspaceRepositoryImpl) GetMemberships(userId string) ([]domain_workspace.Membership, error) {
+	r.Logger.InfoLog.Println("GetMemberships called")
+
+	query := `
+		SELECT ` + workspaceColumns + `, m.role
+		FROM workspace_members m
+		JOIN workspaces w ON w.id = m.workspace_id
+		WHERE m.user_id = $1
+		ORDER BY w.personal_user_id IS NULL, w.created_at
+	`
+
+	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query, userId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch workspaces: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	memberships := []domain_workspace.Membership{}
+	for rows.Next() {
+		var m domain_workspace.Membership
+		if err := rows.Scan(&m.ID, &m.Name, &m.Personal, &m.CreatedAt, &m.Role); err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan workspace: %v", err)
+			return nil, err
+		}
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate workspaces: %v", err)
+		return nil, err
+	}
+	return memberships, nil
+}
+
+// メンバーを取得
+func (r *WorkspaceRepositoryImpl) GetMember(workspaceId string, userId string) (domain_workspace.Member, error) {
+	query := `
+		SELECT workspace_id, user_id, role, created_at
+		FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2
+	`
+
+	var member domain_workspace.Member
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, workspaceId, userId).
+		Scan(&member.WorkspaceID, &member.UserID, &member.Role, &member.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Member{}, errors.New("member not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch member: %v", err)
+		return domain_workspace.Member{}, err
+	}
+	return member, nil
+}
+
+// ワークスペースのメンバーを取得(参加した順)
+func (r *WorkspaceRepositoryImpl) GetMembers(workspaceId string) ([]domain_workspace.Member, error) {
+	r.Logger.InfoLog.Println("GetMembers called")
+
+	query := `
+		SELECT workspace_id, user_id, role, created_at
+		FROM workspace_members
+		WHERE workspace_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.SupabaseClient.Pool.Query(r.SupabaseClient.Ctx, query, workspaceId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to fetch members: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []domain_workspace.Member{}
+	for rows.Next() {
+		var member domain_workspace.Member
+		if err := rows.Scan(&member.WorkspaceID, &member.UserID, &member.Role, &member.CreatedAt); err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan member: %v", err)
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate members: %v", err)
+		return nil, err
+	}
+	return members, nil
+}
+
+// メンバーを追加
+func (r *WorkspaceRepositoryImpl) AddMember(member domain_workspace.Member) (domain_workspace.Member, error) {
+	query := `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+		RETURNING workspace_id, user_id, role, created_at
+	`
+
+	var added domain_workspace.Member
+	err := r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, member.WorkspaceID, member.UserID, member.Role).
+		Scan(&added.WorkspaceID, &added.UserID, &added.Role, &added.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Member{}, errors.New("already a member")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to add member: %v", err)
+		return domain_workspace.Member{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Added %s to workspace %s as %s", added.UserID, added.WorkspaceID, added.Role)
+	return added, nil
+}
+
+// メンバーの役割を変更
+// 最後のオーナーの降格を防ぐため、確認と変更を1つのトランザクションで行う
+func (r *WorkspaceRepositoryImpl) UpdateMemberRole(workspaceId string, userId string, role domain_workspace.Role) error {
+	ctx := r.SupabaseClient.Ctx
+
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if role != domain_workspace.RoleOwner {
+		if err := r.keepOwner(ctx, tx, workspaceId, userId); err != nil {
+			return err
+		}
+	}
+	tag, err := tx.Exec(ctx, `UPDATE workspace_members SET role = $3 WHERE workspace_id = $1 AND user_id = $2`, workspaceId, userId, role)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to update member: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("member not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// メンバーを削除
+// 最後のオーナーの削除を防ぐため、確認と削除を1つのトランザクションで行う
+func (r *WorkspaceRepositoryImpl) RemoveMember(workspaceId string, userId string) error {
+	ctx := r.SupabaseClient.Ctx
+
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := r.keepOwner(ctx, tx, workspaceId, userId); err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceId, userId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to remove member: %v", err)
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errors.New("member not found")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return err
+	}
+	return nil
+}
+
+// オーナーが居なくならないことを確認する
+// userIdのオーナー権限を外す前に呼ぶ。メンバーの行をロックし、同時に降格しあっても片方だけ成功させる
+func (r *WorkspaceRepositoryImpl) keepOwner(ctx context.Context, tx pgx.Tx, workspaceId string, userId string) error {
+	query := `
+		SELECT user_id, role
+		FROM workspace_members
+		WHERE workspace_id = $1
+		ORDER BY user_id
+		FOR UPDATE
+	`
+
+	rows, err := tx.Query(ctx, query, workspaceId)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to lock members: %v", err)
+		return err
+	}
+	defer rows.Close()
+
+	owners, target := 0, false
+	for rows.Next() {
+		var memberId string
+		var role domain_workspace.Role
+		if err := rows.Scan(&memberId, &role); err != nil {
+			r.Logger.ErrorLog.Printf("Failed to scan member: %v", err)
+			return err
+		}
+		if role == domain_workspace.RoleOwner {
+			owners++
+			target = target || memberId == userId
+		}
+	}
+	if err := rows.Err(); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to iterate members: %v", err)
+		return err
+	}
+	if target && owners == 1 {
+		return errors.New("workspace must have an owner")
+	}
+	return nil
+}
+
+// 招待を作成
+func (r *WorkspaceRepositoryImpl) CreateInvitation(invitation domain_workspace.Invitation) (domain_workspace.Invitation, error) {
+	query := `
+		INSERT INTO workspace_invitations (workspace_id, email, role, invited_by, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + invitationColumns
+
+	created, err := scanInvitation(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query,
+		invitation.WorkspaceID, invitation.Email, invitation.Role, invitation.InvitedBy, invitation.TokenHash, invitation.ExpiresAt))
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to create invitation: %v", err)
+		return domain_workspace.Invitation{}, err
+	}
+	return created, nil
+}
+
+// 未使用・期限内の招待を取得
+func (r *WorkspaceRepositoryImpl) GetInvitation(tokenHash string, now time.Time) (domain_workspace.Invitation, error) {
+	query := `
+		SELECT ` + invitationColumns + `
+		FROM workspace_invitations
+		WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $2`
+
+	invitation, err := scanInvitation(r.SupabaseClient.Pool.QueryRow(r.SupabaseClient.Ctx, query, tokenHash, now))
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Invitation{}, errors.New("invitation not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to get invitation: %v", err)
+		return domain_workspace.Invitation{}, err
+	}
+	return invitation, nil
+}
+
+// 未使用・期限内の招待を使用済みにし、メンバーに追加する
+// 条件付きのUPDATEにして同時に使われても1件だけ成功させ、追加に失敗した場合は招待を使用済みにしない
+func (r *WorkspaceRepositoryImpl) AcceptInvitation(tokenHash string, userId string, now time.Time) (domain_workspace.Member, error) {
+	ctx := r.SupabaseClient.Ctx
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := r.SupabaseClient.Pool.Begin(ctx)
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to begin transaction: %v", err)
+		return domain_workspace.Member{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	invitation, err := scanInvitation(tx.QueryRow(ctx, `
+		UPDATE workspace_invitations
+		SET accepted_at = $2
+		WHERE token_hash = $1 AND accepted_at IS NULL AND expires_at > $2
+		RETURNING `+invitationColumns, tokenHash, now))
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Member{}, errors.New("invitation not found")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to consume invitation: %v", err)
+		return domain_workspace.Member{}, err
+	}
+
+	var added domain_workspace.Member
+	err = tx.QueryRow(ctx, `
+		INSERT INTO workspace_members (workspace_id, user_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (workspace_id, user_id) DO NOTHING
+		RETURNING workspace_id, user_id, role, created_at
+	`, invitation.WorkspaceID, userId, invitation.Role).
+		Scan(&added.WorkspaceID, &added.UserID, &added.Role, &added.CreatedAt)
+	if err == pgx.ErrNoRows {
+		return domain_workspace.Member{}, errors.New("already a member")
+	}
+	if err != nil {
+		r.Logger.ErrorLog.Printf("Failed to add member: %v", err)
+		return domain_workspace.Member{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		r.Logger.ErrorLog.Printf("Failed to commit transaction: %v", err)
+		return domain_workspace.Member{}, err
+	}
+
+	r.Logger.InfoLog.Printf("Added %s to workspace %s as %s", added.UserID, added.WorkspaceID, added.Role)
+	return added, nil
+}
+
+// ワークスペースを読み込む
+func scanWorkspace(row pgx.Row) (domain_workspace.Workspace, error) {
+	var workspace domain_workspace.Workspace
+	err := row.Scan(&workspace.ID, &workspace.Name, &workspace.Personal, &workspace.CreatedAt)
+	return workspace, err
+}
+
+// 招待を読み込む
+func scanInvitation(row pgx.Row) (domain_workspace.Invitation, error) {
+	var invitation domain_workspace.Invitation
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.WorkspaceID,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.InvitedBy,
+		&invitation.TokenHash,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.CreatedAt,
+	)
+	return invitation, err
+}
@@ -0,0 +1,241 @@
+package infrastructure_workspace
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_workspace "backend/internal/repository/workspace"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ワークスペースのリポジトリ(インメモリ)
+// テストやDBの無い環境で使用する
+type WorkspaceMemoryRepository struct {
+	Logger *pkg_logger.AppLogger
+
+	mu          sync.Mutex
+	workspaces  map[string]domain_workspace.Workspace
+	members     map[string]map[string]domain_workspace.Member // ワークスペースID → ユーザーID → メンバー
+	invitations map[string]domain_workspace.Invitation        // トークンのハッシュ → 招待
+}
+
+// ワークスペースのリポジトリ(インメモリ)のインスタンス化
+func NewWorkspaceMemoryRepository(l *pkg_logger.AppLogger) repository_workspace.IWorkspaceRepository {
+	return &WorkspaceMemoryRepository{
+		Logger:      l,
+		workspaces:  map[string]domain_workspace.Workspace{},
+		members:     map[string]map[string]domain_workspace.Member{},
+		invitations: map[string]domain_workspace.Invitation{},
+	}
+}
+
+// ワークスペースを作成し、作成したユーザーをオーナーにする
+func (r *WorkspaceMemoryRepository) CreateWorkspace(workspace domain_workspace.Workspace, ownerId string) (domain_workspace.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, err := newID()
+	if err != nil {
+		return domain_workspace.Workspace{}, err
+	}
+	now := time.Now()
+	workspace.ID = id
+	workspace.CreatedAt = now
+	r.workspaces[id] = workspace
+	r.members[id] = map[string]domain_workspace.Member{
+		ownerId: {WorkspaceID: id, UserID: ownerId, Role: domain_workspace.RoleOwner, CreatedAt: now},
+	}
+	return workspace, nil
+}
+
+// ワークスペースを取得
+func (r *WorkspaceMemoryRepository) GetWorkspace(id string) (domain_workspace.Workspace, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	workspace, ok := r.workspaces[id]
+	if !ok {
+		return domain_workspace.Workspace{}, errors.New("workspace not found")
+	}
+	return workspace, nil
+}
+
+// ユーザーが所属するワークスペースを取得(個人用のワークスペースを先頭にする)
+func (r *WorkspaceMemoryRepository) GetMemberships(userId string) ([]domain_workspace.Membership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	memberships := []domain_workspace.Membership{}
+	for id, members := range r.members {
+		if m, ok := members[userId]; ok {
+			memberships = append(memberships, domain_workspace.Membership{Workspace: r.workspaces[id], Role: m.Role})
+		}
+	}
+	slices.SortFunc(memberships, func(a, b domain_workspace.Membership) int {
+		if a.Personal != b.Personal {
+			if a.Personal {
+				return -1
+			}
+			return 1
+		}
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return memberships, nil
+}
+
+// メンバーを取得
+func (r *WorkspaceMemoryRepository) GetMember(workspaceId string, userId string) (domain_workspace.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[workspaceId][userId]
+	if !ok {
+		return domain_workspace.Member{}, errors.New("member not found")
+	}
+	return member, nil
+}
+
+// ワークスペースのメンバーを取得(参加した順)
+func (r *WorkspaceMemoryRepository) GetMembers(workspaceId string) ([]domain_workspace.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members := []domain_workspace.Member{}
+	for _, m := range r.members[workspaceId] {
+		members = append(members, m)
+	}
+	slices.SortFunc(members, func(a, b domain_workspace.Member) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+	return members, nil
+}
+
+// メンバーを追加
+func (r *WorkspaceMemoryRepository) AddMember(member domain_workspace.Member) (domain_workspace.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	members, ok := r.members[member.WorkspaceID]
+	if !ok {
+		return domain_workspace.Member{}, errors.New("workspace not found")
+	}
+	if _, ok := members[member.UserID]; ok {
+		return domain_workspace.Member{}, errors.New("already a member")
+	}
+	member.CreatedAt = time.Now()
+	members[member.UserID] = member
+	return member, nil
+}
+
+// メンバーの役割を変更
+func (r *WorkspaceMemoryRepository) UpdateMemberRole(workspaceId string, userId string, role domain_workspace.Role) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	member, ok := r.members[workspaceId][userId]
+	if !ok {
+		return errors.New("member not found")
+	}
+	if role != domain_workspace.RoleOwner {
+		if err := r.keepOwner(workspaceId, userId); err != nil {
+			return err
+		}
+	}
+	member.Role = role
+	r.members[workspaceId][userId] = member
+	return nil
+}
+
+// メンバーを削除
+func (r *WorkspaceMemoryRepository) RemoveMember(workspaceId string, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.members[workspaceId][userId]; !ok {
+		return errors.New("member not found")
+	}
+	if err := r.keepOwner(workspaceId, userId); err != nil {
+		return err
+	}
+	delete(r.members[workspaceId], userId)
+	return nil
+}
+
+// 招待を作成
+func (r *WorkspaceMemoryRepository) CreateInvitation(invitation domain_workspace.Invitation) (domain_workspace.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, err := newID()
+	if err != nil {
+		return domain_workspace.Invitation{}, err
+	}
+	invitation.ID = id
+	invitation.CreatedAt = time.Now()
+	invitation.AcceptedAt = nil
+	r.invitations[invitation.TokenHash] = invitation
+	return invitation, nil
+}
+
+// 未使用・期限内の招待を取得
+func (r *WorkspaceMemoryRepository) GetInvitation(tokenHash string, now time.Time) (domain_workspace.Invitation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation, ok := r.invitations[tokenHash]
+	if !ok || invitation.AcceptedAt != nil || !now.Before(invitation.ExpiresAt) {
+		return domain_workspace.Invitation{}, errors.New("invitation not found")
+	}
+	return invitation, nil
+}
+
+// 未使用・期限内の招待を使用済みにし、メンバーに追加する
+func (r *WorkspaceMemoryRepository) AcceptInvitation(tokenHash string, userId string, now time.Time) (domain_workspace.Member, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	invitation, ok := r.invitations[tokenHash]
+	if !ok || invitation.AcceptedAt != nil || !now.Before(invitation.ExpiresAt) {
+		return domain_workspace.Member{}, errors.New("invitation not found")
+	}
+	members, ok := r.members[invitation.WorkspaceID]
+	if !ok {
+		return domain_workspace.Member{}, errors.New("invitation not found")
+	}
+	if _, ok := members[userId]; ok {
+		return domain_workspace.Member{}, errors.New("already a member")
+	}
+
+	invitation.AcceptedAt = &now
+	r.invitations[tokenHash] = invitation
+	member := domain_workspace.Member{WorkspaceID: invitation.WorkspaceID, UserID: userId, Role: invitation.Role, CreatedAt: time.Now()}
+	members[userId] = member
+	return member, nil
+}
+
+// オーナーが居なくならないことを確認する(ロックを取得して呼ぶ)
+func (r *WorkspaceMemoryRepository) keepOwner(workspaceId string, userId string) error {
+	owners := 0
+	for _, m := range r.members[workspaceId] {
+		if m.Role == domain_workspace.RoleOwner {
+			owners++
+		}
+	}
+	if r.members[workspaceId][userId].Role == domain_workspace.RoleOwner && owners == 1 {
+		return errors.New("workspace must have an owner")
+	}
+	return nil
+}
+
+// IDを生成する
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
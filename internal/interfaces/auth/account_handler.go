@@ -0,0 +1,161 @@
+package interfaces_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_auth "backend/internal/usecase/auth"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// パスワードの再設定・メールアドレスの確認のハンドラ
+type AccountHandler struct {
+	Logger         *pkg_logger.AppLogger
+	accountUsecase usecase_auth.IAccountUsecase
+}
+
+// パスワードの再設定・メールアドレスの確認のハンドラのインスタンス化
+func NewAccountHandler(l *pkg_logger.AppLogger, au usecase_auth.IAccountUsecase) *AccountHandler {
+	return &AccountHandler{
+		Logger:         l,
+		accountUsecase: au,
+	}
+}
+
+// パスワードの再設定のメールを送る
+// メールアドレスが登録されていない場合も同じレスポンスを返す
+func (h *AccountHandler) ForgotPassword(c echo.Context) error {
+	h.Logger.InfoLog.Println("ForgotPassword called")
+
+	var req struct {
+		Email string `json:"email"`
+		Lang  string `json:"lang"` // 省略時はAccept-Language
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse forgot password request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if req.Email == "" {
+		errs := pkg_validation.Errors{}
+		errs.Add("email", "is required")
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	if err := h.accountUsecase.RequestPasswordReset(c.Request().Context(), req.Email, language(c, req.Lang)); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to request password reset: %v", err)
+		if err.Error() == "invalid email format" {
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid email format"})
+		}
+		// その他のエラーでも、登録の有無が分からないよう同じ応答を返す
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "If the email is registered, a password reset link has been sent"})
+}
+
+// トークンを確認してパスワードを再設定する
+func (h *AccountHandler) ResetPassword(c echo.Context) error {
+	h.Logger.InfoLog.Println("ResetPassword called")
+
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse reset password request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if req.Token == "" {
+		errs.Add("token", "is required")
+	}
+	if len([]rune(req.Password)) < domain_auth.MinPasswordLength {
+		errs.Add("password", "must be at least %d characters", domain_auth.MinPasswordLength)
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	if err := h.accountUsecase.ResetPassword(req.Token, req.Password); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to reset password: %v", err)
+		switch err.Error() {
+		case "invalid or expired token":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid or expired token"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to reset password"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メールアドレスの確認のメールを送る
+func (h *AccountHandler) RequestEmailVerification(c echo.Context) error {
+	h.Logger.InfoLog.Println("RequestEmailVerification called")
+
+	userId, _ := c.Get("userId").(string)
+	var req struct {
+		Lang string `json:"lang"` // 省略時はAccept-Language
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse email verification request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	if err := h.accountUsecase.RequestEmailVerification(c.Request().Context(), userId, language(c, req.Lang)); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to request email verification: %v", err)
+		switch err.Error() {
+		case "user not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "User not found"})
+		case "email is already verified":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "Email is already verified"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to send email"})
+		}
+	}
+
+	return c.JSON(http.StatusAccepted, map[string]string{"message": "A verification link has been sent"})
+}
+
+// トークンを確認してメールアドレスを確認済みにする
+func (h *AccountHandler) VerifyEmail(c echo.Context) error {
+	h.Logger.InfoLog.Println("VerifyEmail called")
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse verify email request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if req.Token == "" {
+		errs := pkg_validation.Errors{}
+		errs.Add("token", "is required")
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	if err := h.accountUsecase.VerifyEmail(req.Token); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to verify email: %v", err)
+		switch err.Error() {
+		case "invalid or expired token":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid or expired token"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to verify email"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メールの言語
+// リクエストボディで指定がなければAccept-Languageを使う(対応していない言語は既定の言語になる)
+func language(c echo.Context, lang string) string {
+	if lang != "" {
+		return lang
+	}
+	return c.Request().Header.Get("Accept-Language")
+}
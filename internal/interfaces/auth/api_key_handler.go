@@ -0,0 +1,144 @@
+package interfaces_auth
+
+import (
+	"backend/config"
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_auth "backend/internal/usecase/auth"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIキーの名前の最大文字数
+const maxAPIKeyNameLength = 100
+
+// APIキーのハンドラ
+type APIKeyHandler struct {
+	AppConfig     *config.AppConfig
+	Logger        *pkg_logger.AppLogger
+	apiKeyUsecase usecase_auth.IAPIKeyUsecase
+}
+
+// APIキーのハンドラのインスタンス化
+func NewAPIKeyHandler(appConfig *config.AppConfig, logger *pkg_logger.AppLogger, aku usecase_auth.IAPIKeyUsecase) *APIKeyHandler {
+	return &APIKeyHandler{
+		AppConfig:     appConfig,
+		Logger:        logger,
+		apiKeyUsecase: aku,
+	}
+}
+
+// APIキーの作成
+// キーそのものはこのレスポンスでのみ返す
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	h.Logger.InfoLog.Println("CreateAPIKey called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage API keys"})
+	}
+	userId, _ := c.Get("userId").(string)
+	role, _ := c.Get("role").(string)
+
+	var req domain_auth.CreateAPIKeyRequest
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse api key request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	// 既定値
+	if req.Kind == "" {
+		req.Kind = domain_auth.APIKeyPersonal
+	}
+	if len(req.Scopes) == 0 {
+		req.Scopes = []string{role}
+	}
+	now := time.Now()
+	if req.ExpiresAt == nil {
+		expiresAt := now.Add(h.AppConfig.Auth.APIKeyLifetime)
+		req.ExpiresAt = &expiresAt
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if req.Name == "" || len([]rune(req.Name)) > maxAPIKeyNameLength {
+		errs.Add("name", "must be between 1 and %d characters", maxAPIKeyNameLength)
+	}
+	if req.Kind != domain_auth.APIKeyPersonal && req.Kind != domain_auth.APIKeyService {
+		errs.Add("kind", "must be one of %s, %s", domain_auth.APIKeyPersonal, domain_auth.APIKeyService)
+	}
+	for _, scope := range req.Scopes {
+		if !slices.Contains(domain_auth.APIKeyScopes, scope) {
+			errs.Add("scopes", "unknown scope: %s", scope)
+		}
+	}
+	if !req.ExpiresAt.After(now) || req.ExpiresAt.After(now.Add(h.AppConfig.Auth.APIKeyMaxLifetime)) {
+		errs.Add("expires_at", "must be in the future and within %v", h.AppConfig.Auth.APIKeyMaxLifetime)
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+	slices.Sort(req.Scopes)
+	req.Scopes = slices.Compact(req.Scopes)
+
+	key, plaintext, err := h.apiKeyUsecase.CreateAPIKey(userId, req)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to create api key: %v", err)
+		switch err.Error() {
+		case "insufficient permissions":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "Insufficient permissions"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create api key"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]any{"api_key": key, "key": plaintext})
+}
+
+// APIキーの一覧
+func (h *APIKeyHandler) GetAPIKeys(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetAPIKeys called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage API keys"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	keys, err := h.apiKeyUsecase.GetAPIKeys(userId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to get api keys: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to get api keys"})
+	}
+
+	return c.JSON(http.StatusOK, keys)
+}
+
+// APIキーの失効
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	h.Logger.InfoLog.Println("RevokeAPIKey called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage API keys"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	err := h.apiKeyUsecase.RevokeAPIKey(userId, c.Param("id"))
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to revoke api key: %v", err)
+		switch err.Error() {
+		case "api key not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "API key not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to revoke api key"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// APIキーで認証したリクエストか
+// 漏れたキーで新しいキーを作れないよう、キーの管理はJWTでのみ受け付ける
+func rejectAPIKey(c echo.Context) bool {
+	return c.Get("apiKeyId") != nil
+}
@@ -1,10 +1,15 @@
 package interfaces_auth
 
 import (
+	domain_auth "backend/internal/domain/auth"
 	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
 	usecase_auth "backend/internal/usecase/auth"
 	"errors"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,17 +17,26 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+// 操作中のワークスペースを指定するヘッダ(トークンのwidより優先する)
+const WorkspaceHeader = "X-Workspace-ID"
+
 // 認証ハンドラ(Impl)
 type AuthHandler struct {
-	Logger      *pkg_logger.AppLogger
-	authUsecase usecase_auth.IAuthUsecase
+	Logger         *pkg_logger.AppLogger
+	authUsecase    usecase_auth.IAuthUsecase
+	apiKeyUsecase  usecase_auth.IAPIKeyUsecase
+	mfaUsecase     usecase_auth.IMFAUsecase
+	sessionUsecase usecase_auth.ISessionUsecase
 }
 
 // 認証ハンドラのインスタンス化
-func NewAuthHandler(l *pkg_logger.AppLogger, u usecase_auth.IAuthUsecase) *AuthHandler {
+func NewAuthHandler(l *pkg_logger.AppLogger, u usecase_auth.IAuthUsecase, aku usecase_auth.IAPIKeyUsecase, mu usecase_auth.IMFAUsecase, su usecase_auth.ISessionUsecase) *AuthHandler {
 	return &AuthHandler{
-		Logger:      l,
-		authUsecase: u,
+		Logger:         l,
+		authUsecase:    u,
+		apiKeyUsecase:  aku,
+		mfaUsecase:     mu,
+		sessionUsecase: su,
 	}
 }
 
@@ -43,82 +57,227 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	}
 
 	// ログイン(usecase層)
-	id, err := h.authUsecase.Login(loginRequest.Email, loginRequest.Password)
+	id, err := h.authUsecase.Login(loginRequest.Email, loginRequest.Password, c.RealIP())
 	// エラーハンドリング
 	if err != nil {
-		switch err {
-		case errors.New("invalid email or password"):
+		switch err.Error() {
+		case "invalid email or password":
 			h.Logger.ErrorLog.Println("Invalid email or password")
 			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid email or password"})
-		case errors.New("invalid email format"):
+		case "invalid email format":
 			h.Logger.ErrorLog.Println("Invalid email format")
 			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid email format"})
-		case errors.New("failed to login"):
+		case "too many failed attempts":
+			h.Logger.ErrorLog.Println("Too many failed attempts")
+			setRetryAfter(c, err)
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"message": "Too many failed attempts"})
+		case "failed to login":
 			h.Logger.ErrorLog.Println("Failed to login")
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to login"})
 		default:
-			h.Logger.ErrorLog.Printf("Failed to get all users: %v", err)
+			h.Logger.ErrorLog.Printf("Failed to login: %v", err)
 			return c.JSON(http.StatusInternalServerError, map[string]string{"message": err.Error()})
 		}
 	}
 
-	// JWTトークンを生成
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"id":   id,
-		"role": "user",
-		"exp":  time.Now().Add(time.Hour * 24).Unix(),
-	})
+	h.Logger.InfoLog.Println("Login successful. 1 user found")
+	// JWTトークンを生成(2要素認証が有効な場合はチャレンジを返す)
+	return completeLogin(c, h.Logger, h.mfaUsecase, h.sessionUsecase, id)
+}
 
-	// JWTトークンをシグネーション
-	tokenString, err := token.SignedString([]byte("secret"))
-	if err != nil {
-		h.Logger.ErrorLog.Printf("Failed to sign token: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to sign token"})
+// ロックの解除(管理者)
+// メールアドレスとIPアドレスのどちらか、または両方の失敗の記録を消す
+func (h *AuthHandler) Unlock(c echo.Context) error {
+	h.Logger.InfoLog.Println("Unlock called")
+
+	var unlockRequest struct {
+		Email string `json:"email"`
+		IP    string `json:"ip"`
+	}
+	if err := c.Bind(&unlockRequest); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse unlock request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
 	}
 
-	h.Logger.InfoLog.Println("Login successful. 1 user found")
-	return c.JSON(http.StatusOK, map[string]string{"token": tokenString})
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if unlockRequest.Email == "" && unlockRequest.IP == "" {
+		errs.Add("email", "email or ip is required")
+	}
+	if unlockRequest.IP != "" && net.ParseIP(unlockRequest.IP) == nil {
+		errs.Add("ip", "must be an IP address")
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	actorId, _ := c.Get("userId").(string)
+	if err := h.authUsecase.Unlock(unlockRequest.Email, unlockRequest.IP, actorId); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to unlock: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to unlock"})
+	}
+
+	return c.NoContent(http.StatusNoContent)
 }
 
 // 認証ミドルウェア
+// Authorization: Bearer <JWT> または X-API-Key: <APIキー> で認証する
+// APIキーの場合は、requiredRoleの権限(scope)を持つキーのみ受け付ける
+// JWTの場合は、トークンのセッション(sid)が失効していないことも確認する
 func (h *AuthHandler) AuthorizationMiddleware(next echo.HandlerFunc, requiredRole string) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		authHeader := c.Request().Header.Get("Authorization")
-		if authHeader == "" {
+		if c.Request().Header.Get("Authorization") == "" {
+			if apiKey := c.Request().Header.Get("X-API-Key"); apiKey != "" {
+				return h.authorizeAPIKey(c, next, apiKey, requiredRole)
+			}
 			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Missing Authorization header"})
 		}
 
-		// "Bearer " を取り除く
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-
-		// JWT をパース
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, echo.NewHTTPError(http.StatusUnauthorized, "Invalid token signing method")
-			}
-			return []byte("secret"), nil
-		})
+		// クレームからユーザーIDとロールを取得
+		claims, err := parseToken(c)
+		if err != nil {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": err.Error()})
+		}
 
-		if err != nil || !token.Valid {
+		// セッションを確認(ログアウト済みの端末のトークンを拒否する)
+		id, _ := claims["id"].(string)
+		sessionId, _ := claims["sid"].(string)
+		if sessionId == "" {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid token"})
 		}
-
-		// クレームからユーザーIDとロールを取得
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid token claims"})
+		if err := h.sessionUsecase.Validate(sessionId, id); err != nil {
+			switch err.Error() {
+			case "invalid session":
+				return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Session has been revoked"})
+			default:
+				h.Logger.ErrorLog.Printf("Failed to validate session: %v", err)
+				return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to authenticate"})
+			}
 		}
 
-		// ロールを確認（例: "admin", "user" など）
+		// ロールを確認（例: "admin", "user" など。管理者は一般ユーザーの権限も持つ）
 		role, ok := claims["role"].(string)
-		if !ok || role != requiredRole {
+		if !ok || !domain_auth.RoleAllows(role, requiredRole) {
 			return c.JSON(http.StatusForbidden, map[string]string{"message": "Insufficient permissions"})
 		}
 
 		// ユーザーIDをコンテキストに保存
 		c.Set("userId", claims["id"])
 		c.Set("role", role)
+		c.Set("sessionId", sessionId)
+
+		// 操作中のワークスペースをコンテキストに保存(所属の確認は各リポジトリで行う)
+		workspaceId := c.Request().Header.Get(WorkspaceHeader)
+		if workspaceId == "" {
+			workspaceId, _ = claims["wid"].(string)
+		}
+		if workspaceId != "" {
+			c.Set("workspaceId", workspaceId)
+		}
 
 		return next(c)
 	}
 }
+
+// APIキーで認証する
+// JWTと同じくuserIdとroleをコンテキストに保存し、キーのIDをapiKeyIdに保存する
+func (h *AuthHandler) authorizeAPIKey(c echo.Context, next echo.HandlerFunc, apiKey string, requiredRole string) error {
+	key, err := h.apiKeyUsecase.Authenticate(apiKey)
+	if err != nil {
+		switch err.Error() {
+		case "invalid api key":
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid API key"})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to authenticate api key: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to authenticate"})
+		}
+	}
+	if !key.HasScope(requiredRole) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "Insufficient permissions"})
+	}
+
+	c.Set("userId", key.UserID)
+	c.Set("role", requiredRole)
+	c.Set("apiKeyId", key.ID)
+	if workspaceId := c.Request().Header.Get(WorkspaceHeader); workspaceId != "" {
+		c.Set("workspaceId", workspaceId)
+	}
+
+	return next(c)
+}
+
+// ユーザーのJWTトークンを発行する
+// sidにはログインのセッションのIDを、roleにはセッション作成時のユーザーのロールを入れる
+func issueToken(id string, sessionId string, role string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":   id,
+		"sid":  sessionId,
+		"role": role,
+		"exp":  time.Now().Add(domain_auth.SessionLifetime).Unix(),
+	})
+
+	// JWTトークンをシグネーション
+	return token.SignedString([]byte("secret"))
+}
+
+// 操作中のワークスペース(wid)を含めてアクセストークンを発行し直す
+// セッションと有効期限は元のトークンから引き継ぐ
+func (h *AuthHandler) WorkspaceToken(c echo.Context, workspaceId string) (string, error) {
+	claims, err := parseToken(c)
+	if err != nil {
+		return "", err
+	}
+	claims["wid"] = workspaceId
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte("secret"))
+}
+
+// ロック中のエラーであればRetry-Afterヘッダを設定する
+func setRetryAfter(c echo.Context, err error) {
+	var locked *domain_auth.LockedError
+	if errors.As(err, &locked) {
+		retryAfter := int(math.Ceil(time.Until(locked.RetryAt).Seconds()))
+		c.Response().Header().Set("Retry-After", strconv.Itoa(max(1, retryAfter)))
+	}
+}
+
+// リクエストのユーザーID
+// ロールは確認しない。トークンがない・不正な場合はfalseを返す
+func (h *AuthHandler) UserID(c echo.Context) (string, bool) {
+	claims, err := parseToken(c)
+	if err != nil {
+		return "", false
+	}
+	id, ok := claims["id"].(string)
+	return id, ok && id != ""
+}
+
+// AuthorizationヘッダのJWTをパースしてクレームを取得する
+func parseToken(c echo.Context) (jwt.MapClaims, error) {
+	// "Bearer " を取り除く
+	tokenString := strings.TrimPrefix(c.Request().Header.Get("Authorization"), "Bearer ")
+
+	// JWT をパース
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, "Invalid token signing method")
+		}
+		return []byte("secret"), nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, errors.New("Invalid token")
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("Invalid token claims")
+	}
+	// 2要素認証のチャレンジなど、用途を限ったトークンはアクセストークンとして受け付けない
+	if _, ok := claims["purpose"]; ok {
+		return nil, errors.New("Invalid token")
+	}
+	return claims, nil
+}
@@ -0,0 +1,295 @@
+package interfaces_auth
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_auth "backend/internal/usecase/auth"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+// 2要素認証のチャレンジトークンの有効期間
+const mfaTokenMaxAge = 5 * time.Minute
+
+// 2要素認証のハンドラ
+type MFAHandler struct {
+	Logger         *pkg_logger.AppLogger
+	mfaUsecase     usecase_auth.IMFAUsecase
+	sessionUsecase usecase_auth.ISessionUsecase
+}
+
+// 2要素認証のハンドラのインスタンス化
+func NewMFAHandler(l *pkg_logger.AppLogger, mu usecase_auth.IMFAUsecase, su usecase_auth.ISessionUsecase) *MFAHandler {
+	return &MFAHandler{
+		Logger:         l,
+		mfaUsecase:     mu,
+		sessionUsecase: su,
+	}
+}
+
+// 登録を始める
+// レスポンスのURIをQRコードにして認証アプリで読み取る
+func (h *MFAHandler) Enroll(c echo.Context) error {
+	h.Logger.InfoLog.Println("Enroll MFA called")
+
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage MFA"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	// 認証アプリに表示する名前(省略時はユーザーID)
+	var req struct {
+		Account string `json:"account"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse enroll request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if req.Account == "" {
+		req.Account = userId
+	}
+
+	secret, uri, err := h.mfaUsecase.Enroll(userId, req.Account)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to enroll mfa: %v", err)
+		switch err.Error() {
+		case "mfa is already enabled":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "MFA is already enabled"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to enroll MFA"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{"secret": secret, "uri": uri})
+}
+
+// 登録を確認して有効にする
+// 回復コードはこのレスポンスでのみ返す
+func (h *MFAHandler) Confirm(c echo.Context) error {
+	h.Logger.InfoLog.Println("Confirm MFA called")
+
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage MFA"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	var req codeRequest
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse code request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if errs := req.validate(); errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	codes, err := h.mfaUsecase.Confirm(userId, req.Code)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to confirm mfa: %v", err)
+		switch err.Error() {
+		case "mfa is not enrolled":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "MFA is not enrolled"})
+		case "mfa is already enabled":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "MFA is already enabled"})
+		case "invalid code":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid code"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to confirm MFA"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+// 回復コードを作り直す
+func (h *MFAHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	h.Logger.InfoLog.Println("RegenerateRecoveryCodes called")
+
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage MFA"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	var req codeRequest
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse code request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if errs := req.validate(); errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	codes, err := h.mfaUsecase.RegenerateRecoveryCodes(userId, req.Code)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to regenerate recovery codes: %v", err)
+		switch err.Error() {
+		case "mfa is not enabled":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "MFA is not enabled"})
+		case "invalid code":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid code"})
+		case "too many failed attempts":
+			setRetryAfter(c, err)
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"message": "Too many failed attempts"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to regenerate recovery codes"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string][]string{"recovery_codes": codes})
+}
+
+// ログイン時のチャレンジにコードで答え、アクセストークンを発行する
+func (h *MFAHandler) Verify(c echo.Context) error {
+	h.Logger.InfoLog.Println("Verify MFA called")
+
+	var req struct {
+		MFAToken string `json:"mfa_token"`
+		Code     string `json:"code"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse verify request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if req.MFAToken == "" {
+		errs.Add("mfa_token", "is required")
+	}
+	if req.Code == "" {
+		errs.Add("code", "is required")
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	userId, err := parseMFAToken(req.MFAToken)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Invalid mfa token: %v", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid MFA token"})
+	}
+
+	if err := h.mfaUsecase.Verify(userId, req.Code); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to verify mfa: %v", err)
+		switch err.Error() {
+		case "invalid code":
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid code"})
+		case "mfa is not enabled":
+			// チャレンジの発行後に管理者が解除した場合。ログインからやり直させる
+			return c.JSON(http.StatusUnauthorized, map[string]string{"message": "Invalid MFA token"})
+		case "too many failed attempts":
+			setRetryAfter(c, err)
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"message": "Too many failed attempts"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to verify MFA"})
+		}
+	}
+
+	return respondWithToken(c, h.Logger, h.sessionUsecase, userId)
+}
+
+// 2要素認証を解除する(管理者)
+func (h *MFAHandler) Reset(c echo.Context) error {
+	h.Logger.InfoLog.Println("Reset MFA called")
+
+	actorId, _ := c.Get("userId").(string)
+	if err := h.mfaUsecase.Reset(c.Param("userId"), actorId); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to reset mfa: %v", err)
+		switch err.Error() {
+		case "mfa is not enrolled":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "MFA is not enrolled"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to reset MFA"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// コードのリクエストボディ
+type codeRequest struct {
+	Code string `json:"code"`
+}
+
+// 入力チェック
+func (r codeRequest) validate() pkg_validation.Errors {
+	errs := pkg_validation.Errors{}
+	if r.Code == "" {
+		errs.Add("code", "is required")
+	}
+	return errs
+}
+
+// 本人確認を終えたユーザーのログインを完了する
+// 2要素認証が有効な場合はアクセストークンの代わりにチャレンジトークンを返す
+func completeLogin(c echo.Context, l *pkg_logger.AppLogger, mu usecase_auth.IMFAUsecase, su usecase_auth.ISessionUsecase, userId string) error {
+	required, err := mu.Required(userId)
+	if err != nil {
+		l.ErrorLog.Printf("Failed to check mfa: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to login"})
+	}
+
+	if required {
+		tokenString, err := issueMFAToken(userId)
+		if err != nil {
+			l.ErrorLog.Printf("Failed to sign mfa token: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to sign token"})
+		}
+		l.InfoLog.Printf("MFA required: user %s", userId)
+		return c.JSON(http.StatusOK, map[string]any{"mfa_required": true, "mfa_token": tokenString})
+	}
+
+	return respondWithToken(c, l, su, userId)
+}
+
+// ログインのセッションを作成し、アクセストークンを返す
+func respondWithToken(c echo.Context, l *pkg_logger.AppLogger, su usecase_auth.ISessionUsecase, userId string) error {
+	session, err := su.Create(userId, c.RealIP(), c.Request().UserAgent())
+	if err != nil {
+		l.ErrorLog.Printf("Failed to create session: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to login"})
+	}
+
+	tokenString, err := issueToken(userId, session.ID, session.Role)
+	if err != nil {
+		l.ErrorLog.Printf("Failed to sign token: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to sign token"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"token": tokenString})
+}
+
+// 2要素認証のチャレンジトークンを発行する
+// purposeを付けて、アクセストークンとしては使えないようにする
+func issueMFAToken(id string) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":      id,
+		"purpose": "mfa",
+		"exp":     time.Now().Add(mfaTokenMaxAge).Unix(),
+	})
+	return token.SignedString([]byte("secret"))
+}
+
+// チャレンジトークンを検証してユーザーIDを取り出す
+func parseMFAToken(value string) (string, error) {
+	parser := jwt.Parser{ValidMethods: []string{"HS256"}}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(value, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte("secret"), nil
+	}); err != nil {
+		return "", err
+	}
+	if purpose, _ := claims["purpose"].(string); purpose != "mfa" {
+		return "", errors.New("not an mfa token")
+	}
+	id, _ := claims["id"].(string)
+	if id == "" {
+		return "", errors.New("missing user id")
+	}
+	return id, nil
+}
@@ -0,0 +1,156 @@
+package interfaces_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_auth "backend/internal/usecase/auth"
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+)
+
+// 認可リクエストからコールバックまでの値を保持するクッキー
+const (
+	oidcFlowCookie = "oidc_flow"
+	oidcFlowPath   = "/api/auth/oidc"
+	oidcFlowMaxAge = 10 * time.Minute
+)
+
+// OIDCログインのハンドラ
+type OIDCHandler struct {
+	Logger         *pkg_logger.AppLogger
+	oidcUsecase    usecase_auth.IOIDCUsecase
+	mfaUsecase     usecase_auth.IMFAUsecase
+	sessionUsecase usecase_auth.ISessionUsecase
+}
+
+// OIDCログインのハンドラのインスタンス化
+func NewOIDCHandler(l *pkg_logger.AppLogger, ou usecase_auth.IOIDCUsecase, mu usecase_auth.IMFAUsecase, su usecase_auth.ISessionUsecase) *OIDCHandler {
+	return &OIDCHandler{
+		Logger:         l,
+		oidcUsecase:    ou,
+		mfaUsecase:     mu,
+		sessionUsecase: su,
+	}
+}
+
+// 設定されたプロバイダの一覧
+func (h *OIDCHandler) Providers(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string][]string{"providers": h.oidcUsecase.Providers()})
+}
+
+// ログインの開始
+// state・nonce・code_verifierを署名付きのクッキーに保存し、プロバイダの認可画面にリダイレクトする
+func (h *OIDCHandler) Login(c echo.Context) error {
+	h.Logger.InfoLog.Println("OIDC Login called")
+
+	flow, url, err := h.oidcUsecase.Begin(c.Request().Context(), c.Param("provider"))
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to start oidc login: %v", err)
+		switch err.Error() {
+		case "unknown provider":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Unknown provider"})
+		case "identity provider error":
+			return c.JSON(http.StatusBadGateway, map[string]string{"message": "Identity provider error"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to start login"})
+		}
+	}
+
+	value, err := signFlow(flow)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to sign oidc flow: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to start login"})
+	}
+	c.SetCookie(flowCookie(c, value, int(oidcFlowMaxAge.Seconds())))
+	return c.Redirect(http.StatusFound, url)
+}
+
+// コールバック
+// stateをクッキーと照合してから認可コードを交換し、このアプリのJWTトークンを返す
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	h.Logger.InfoLog.Println("OIDC Callback called")
+
+	// クッキーは1回だけ使う
+	cookie, cookieErr := c.Cookie(oidcFlowCookie)
+	c.SetCookie(flowCookie(c, "", -1))
+
+	if e := c.QueryParam("error"); e != "" {
+		h.Logger.ErrorLog.Printf("Authorization failed: %s", e)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Authorization failed"})
+	}
+	if cookieErr != nil {
+		h.Logger.ErrorLog.Println("Missing oidc flow cookie")
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid state"})
+	}
+	flow, err := parseFlow(cookie.Value)
+	state := c.QueryParam("state")
+	if err != nil || flow.Provider != c.Param("provider") || state == "" || subtle.ConstantTimeCompare([]byte(flow.State), []byte(state)) != 1 {
+		h.Logger.ErrorLog.Println("Invalid oidc state")
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid state"})
+	}
+
+	userId, err := h.oidcUsecase.Complete(c.Request().Context(), flow, c.QueryParam("code"))
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to complete oidc login: %v", err)
+		switch err.Error() {
+		case "unknown provider":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Unknown provider"})
+		case "email is not verified", "no linked account":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "No account is linked to this identity"})
+		case "identity provider error", "invalid id token":
+			return c.JSON(http.StatusBadGateway, map[string]string{"message": "Identity provider error"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to login"})
+		}
+	}
+
+	h.Logger.InfoLog.Println("OIDC login successful")
+	return completeLogin(c, h.Logger, h.mfaUsecase, h.sessionUsecase, userId)
+}
+
+// フローのクッキー
+// プロバイダからのリダイレクト(トップレベルのGET)で送られるようSameSite=Laxにする
+func flowCookie(c echo.Context, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcFlowCookie,
+		Value:    value,
+		Path:     oidcFlowPath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// フローの値に署名する
+func signFlow(flow domain_auth.OIDCFlow) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"provider": flow.Provider,
+		"state":    flow.State,
+		"nonce":    flow.Nonce,
+		"verifier": flow.Verifier,
+		"exp":      time.Now().Add(oidcFlowMaxAge).Unix(),
+	})
+	return token.SignedString([]byte("secret"))
+}
+
+// 署名を確認してフローの値を取り出す
+func parseFlow(value string) (domain_auth.OIDCFlow, error) {
+	parser := jwt.Parser{ValidMethods: []string{"HS256"}}
+	claims := jwt.MapClaims{}
+	if _, err := parser.ParseWithClaims(value, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte("secret"), nil
+	}); err != nil {
+		return domain_auth.OIDCFlow{}, err
+	}
+	flow := domain_auth.OIDCFlow{}
+	flow.Provider, _ = claims["provider"].(string)
+	flow.State, _ = claims["state"].(string)
+	flow.Nonce, _ = claims["nonce"].(string)
+	flow.Verifier, _ = claims["verifier"].(string)
+	return flow, nil
+}
@@ -0,0 +1,91 @@
+package interfaces_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_auth "backend/internal/usecase/auth"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ログインのセッションのハンドラ
+type SessionHandler struct {
+	Logger         *pkg_logger.AppLogger
+	sessionUsecase usecase_auth.ISessionUsecase
+}
+
+// ログインのセッションのハンドラのインスタンス化
+func NewSessionHandler(l *pkg_logger.AppLogger, su usecase_auth.ISessionUsecase) *SessionHandler {
+	return &SessionHandler{
+		Logger:         l,
+		sessionUsecase: su,
+	}
+}
+
+// 一覧のセッション(リクエスト元のセッションにcurrentを付ける)
+type sessionResponse struct {
+	domain_auth.Session
+	Current bool `json:"current"`
+}
+
+// 有効なセッションの一覧
+func (h *SessionHandler) GetSessions(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetSessions called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage sessions"})
+	}
+	userId, _ := c.Get("userId").(string)
+	sessionId, _ := c.Get("sessionId").(string)
+
+	sessions, err := h.sessionUsecase.GetSessions(userId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to get sessions: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to get sessions"})
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		response = append(response, sessionResponse{Session: s, Current: s.ID == sessionId})
+	}
+	return c.JSON(http.StatusOK, response)
+}
+
+// セッションの失効(その端末からログアウト)
+func (h *SessionHandler) RevokeSession(c echo.Context) error {
+	h.Logger.InfoLog.Println("RevokeSession called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage sessions"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	if err := h.sessionUsecase.Revoke(userId, c.Param("id")); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to revoke session: %v", err)
+		switch err.Error() {
+		case "session not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Session not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to revoke session"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// 全てのセッションの失効(全ての端末からログアウト)
+// リクエスト元のセッションも失効する
+func (h *SessionHandler) RevokeAllSessions(c echo.Context) error {
+	h.Logger.InfoLog.Println("RevokeAllSessions called")
+	if rejectAPIKey(c) {
+		return c.JSON(http.StatusForbidden, map[string]string{"message": "API keys cannot manage sessions"})
+	}
+	userId, _ := c.Get("userId").(string)
+
+	count, err := h.sessionUsecase.RevokeAll(userId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to revoke sessions: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to revoke sessions"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{"revoked": count})
+}
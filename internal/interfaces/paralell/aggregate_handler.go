@@ -0,0 +1,93 @@
+package interfaces_paralell
+
+import (
+	"backend/config"
+	domain_fetch "backend/internal/domain/fetch"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_fetch "backend/internal/usecase/fetch"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 集約のハンドラ
+type AggregateHandler struct {
+	AppConfig        *config.AppConfig
+	Logger           *pkg_logger.AppLogger
+	aggregateUsecase usecase_fetch.IAggregateUsecase
+}
+
+// 集約のハンドラのインスタンス化
+func NewAggregateHandler(appConfig *config.AppConfig, logger *pkg_logger.AppLogger, au usecase_fetch.IAggregateUsecase) *AggregateHandler {
+	return &AggregateHandler{
+		AppConfig:        appConfig,
+		Logger:           logger,
+		aggregateUsecase: au,
+	}
+}
+
+// コメント付きの投稿一覧
+// ?fields=id,title,comments.email で項目を、?page=N&per_page=M でページを指定できる
+func (h *AggregateHandler) Posts(c echo.Context) error {
+	h.Logger.InfoLog.Println("AggregatePostsHandler started")
+	return h.aggregate(c, domain_fetch.AggregatePosts)
+}
+
+// アルバム付きのユーザー一覧
+func (h *AggregateHandler) Users(c echo.Context) error {
+	h.Logger.InfoLog.Println("AggregateUsersHandler started")
+	return h.aggregate(c, domain_fetch.AggregateUsers)
+}
+
+// 集約を実行して結果を返す
+func (h *AggregateHandler) aggregate(c echo.Context, resource domain_fetch.AggregateResource) error {
+	q := domain_fetch.AggregateQuery{Resource: resource, Page: 1, PerPage: h.AppConfig.Fetch.AggregatePerPage}
+	if v := c.QueryParam("fields"); v != "" {
+		for _, f := range strings.Split(v, ",") {
+			q.Fields = append(q.Fields, strings.TrimSpace(f))
+		}
+	}
+	// 数値でない場合は範囲外(0)として扱う
+	if v := c.QueryParam("page"); v != "" {
+		q.Page, _ = strconv.Atoi(v)
+	}
+	if v := c.QueryParam("per_page"); v != "" {
+		q.PerPage, _ = strconv.Atoi(v)
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if field, err := domain_fetch.ValidateFields(resource, q.Fields); err != nil {
+		errs.Add("fields", "unknown field: %s", field)
+	}
+	if q.Page < 1 || q.Page > h.AppConfig.Fetch.AggregateMaxPage {
+		errs.Add("page", "must be between 1 and %d", h.AppConfig.Fetch.AggregateMaxPage)
+	}
+	if q.PerPage < 1 || q.PerPage > h.AppConfig.Fetch.AggregateMaxPerPage {
+		errs.Add("per_page", "must be between 1 and %d", h.AppConfig.Fetch.AggregateMaxPerPage)
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request().Context(), h.AppConfig.Fetch.Deadline)
+	defer cancel()
+	result, err := h.aggregateUsecase.Aggregate(ctx, q)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to aggregate: %v", err)
+		switch err.Error() {
+		case "upstream request failed", "upstream response is invalid":
+			return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to aggregate"})
+		}
+	}
+
+	h.Logger.InfoLog.Printf("Aggregate completed: %d items", len(result.Items))
+	return c.JSON(http.StatusOK, result)
+}
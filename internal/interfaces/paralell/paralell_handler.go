@@ -2,11 +2,14 @@ package interfaces_paralell
 
 import (
 	"backend/config"
+	domain_fetch "backend/internal/domain/fetch"
 	pkg_logger "backend/internal/pkg/logger"
-	"backend/utils"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_fetch "backend/internal/usecase/fetch"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -14,86 +17,205 @@ import (
 
 // 並列動作のハンドラ
 type ParalellHandler struct {
-	AppConfig *config.AppConfig
-	Logger    *pkg_logger.AppLogger
+	AppConfig    *config.AppConfig
+	Logger       *pkg_logger.AppLogger
+	fetchUsecase usecase_fetch.IFetchUsecase
 }
 
 // 並列動作のインスタンス化
-func NewParalellHandler(appConfig *config.AppConfig, logger *pkg_logger.AppLogger) *ParalellHandler {
+func NewParalellHandler(appConfig *config.AppConfig, logger *pkg_logger.AppLogger, fu usecase_fetch.IFetchUsecase) *ParalellHandler {
 	return &ParalellHandler{
-		AppConfig: appConfig,
-		Logger:    logger,
+		AppConfig:    appConfig,
+		Logger:       logger,
+		fetchUsecase: fu,
 	}
 }
 
+// 設定の上流APIへのリクエスト
+func (h *ParalellHandler) defaultRequests() []domain_fetch.UpstreamRequest {
+	reqs := make([]domain_fetch.UpstreamRequest, len(h.AppConfig.Fetch.Upstreams))
+	for i, u := range h.AppConfig.Fetch.Upstreams {
+		reqs[i] = domain_fetch.UpstreamRequest{Name: u.Name, Method: u.Method, Path: u.Path}
+	}
+	return reqs
+}
+
 // 並列動作のサンプル
+// 設定の上流APIに並行してリクエストを送る
+// ?concurrency=N で同時実行数を、?compare=true で逐次実行との比較を指定できる
+// ?deadline_ms=N で全体の期限を、?error_mode=fail_fast で最初の失敗での中断を指定できる
 func (h *ParalellHandler) ExecParallel(c echo.Context) error {
 	h.Logger.InfoLog.Println("ParallelHandler started")
 
-	// 開始時間を計測
-	start := time.Now()
-
-	urls := []string{
-		h.AppConfig.TestAPI + "/posts",
-		h.AppConfig.TestAPI + "/comments",
-		h.AppConfig.TestAPI + "/albums",
+	errs := pkg_validation.Errors{}
+	concurrency := h.AppConfig.Fetch.Concurrency
+	if v := c.QueryParam("concurrency"); v != "" {
+		// 数値でない場合は範囲外(0)として扱う
+		concurrency, _ = strconv.Atoi(v)
+	}
+	deadlineMs := int(h.AppConfig.Fetch.Deadline / time.Millisecond)
+	if v := c.QueryParam("deadline_ms"); v != "" {
+		deadlineMs, _ = strconv.Atoi(v)
+	}
+	errorMode := domain_fetch.ErrorMode(c.QueryParam("error_mode"))
+	h.validateConcurrency(&errs, concurrency)
+	h.validateDeadline(&errs, deadlineMs)
+	h.validateErrorMode(&errs, errorMode)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
 	}
 
-	var wg sync.WaitGroup
-	results := make(chan string, len(urls))
+	return h.exec(c, h.defaultRequests(), domain_fetch.FetchOptions{
+		Concurrency: concurrency,
+		Compare:     c.QueryParam("compare") == "true",
+		Deadline:    time.Duration(deadlineMs) * time.Millisecond,
+		ErrorMode:   errorMode,
+	})
+}
 
-	for i, url := range urls {
-		wg.Add(1)
-		go func(url string, i int) {
-			defer wg.Done()
-			h.Logger.InfoLog.Println("Request", i, "started")
-			data := utils.FetchAPI(url)
-			results <- data
-		}(url, i)
-	}
+// 逐次処理のサンプル
+// 設定の上流APIに1件ずつリクエストを送る
+func (h *ParalellHandler) ExecSeries(c echo.Context) error {
+	h.Logger.InfoLog.Println("SeriesHandler started")
 
-	wg.Wait()
-	close(results)
+	return h.exec(c, h.defaultRequests(), domain_fetch.FetchOptions{Concurrency: 1, Deadline: h.AppConfig.Fetch.Deadline})
+}
 
-	var allResults []string
-	for data := range results {
-		allResults = append(allResults, data)
+// 指定した上流APIへの一括取得
+// requestsを省略した場合は設定の上流APIを使う
+func (h *ParalellHandler) Fetch(c echo.Context) error {
+	h.Logger.InfoLog.Println("FetchHandler started")
+
+	// リクエストボディ
+	body := struct {
+		Requests    []domain_fetch.UpstreamRequest `json:"requests"`
+		Concurrency *int                           `json:"concurrency"`
+		Compare     bool                           `json:"compare"`
+		DeadlineMs  *int                           `json:"deadline_ms"`
+		ErrorMode   domain_fetch.ErrorMode         `json:"error_mode"`
+	}{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if body.Requests == nil {
+		body.Requests = h.defaultRequests()
+	}
+	concurrency := h.AppConfig.Fetch.Concurrency
+	if body.Concurrency != nil {
+		concurrency = *body.Concurrency
+	}
+	deadlineMs := int(h.AppConfig.Fetch.Deadline / time.Millisecond)
+	if body.DeadlineMs != nil {
+		deadlineMs = *body.DeadlineMs
 	}
 
-	// 終了時間を計測
-	end := time.Now()
-
-	h.Logger.InfoLog.Println("ParallelHandler completed")
-	h.Logger.InfoLog.Println("Time taken:", end.Sub(start))
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validateConcurrency(&errs, concurrency)
+	h.validateDeadline(&errs, deadlineMs)
+	h.validateErrorMode(&errs, body.ErrorMode)
+	h.validateRequests(&errs, body.Requests)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
 
-	return c.String(http.StatusOK, strings.Join(allResults, "\n"))
+	return h.exec(c, body.Requests, domain_fetch.FetchOptions{
+		Concurrency: concurrency,
+		Compare:     body.Compare,
+		Deadline:    time.Duration(deadlineMs) * time.Millisecond,
+		ErrorMode:   body.ErrorMode,
+	})
 }
 
-// 逐次処理のサンプル
-func (h *ParalellHandler) ExecSeries(c echo.Context) error {
-	h.Logger.InfoLog.Println("SeriesHandler started")
+// 同時実行数のチェック
+func (h *ParalellHandler) validateConcurrency(errs *pkg_validation.Errors, concurrency int) {
+	if concurrency < 1 || concurrency > h.AppConfig.Fetch.MaxConcurrency {
+		errs.Add("concurrency", "must be between 1 and %d", h.AppConfig.Fetch.MaxConcurrency)
+	}
+}
 
-	// 開始時間を計測
-	start := time.Now()
+// 全体の期限のチェック
+func (h *ParalellHandler) validateDeadline(errs *pkg_validation.Errors, deadlineMs int) {
+	max := int(h.AppConfig.Fetch.MaxDeadline / time.Millisecond)
+	if deadlineMs < 1 || deadlineMs > max {
+		errs.Add("deadline_ms", "must be between 1 and %d", max)
+	}
+}
 
-	urls := []string{
-		h.AppConfig.TestAPI + "/posts",
-		h.AppConfig.TestAPI + "/comments",
-		h.AppConfig.TestAPI + "/albums",
+// 失敗時の動作のチェック
+func (h *ParalellHandler) validateErrorMode(errs *pkg_validation.Errors, mode domain_fetch.ErrorMode) {
+	switch mode {
+	case "", domain_fetch.CollectAll, domain_fetch.FailFast:
+	default:
+		errs.Add("error_mode", "must be one of collect_all, fail_fast")
 	}
-	var allResults []string
+}
 
-	for i, url := range urls {
-		h.Logger.InfoLog.Println("Request", i, "started")
-		data := utils.FetchAPI(url)
-		allResults = append(allResults, data)
+// リクエスト一覧のチェック
+func (h *ParalellHandler) validateRequests(errs *pkg_validation.Errors, reqs []domain_fetch.UpstreamRequest) {
+	if len(reqs) == 0 {
+		errs.Add("requests", "must not be empty")
+		return
+	}
+	if len(reqs) > h.AppConfig.Fetch.MaxRequests {
+		errs.Add("requests", "must have at most %d requests", h.AppConfig.Fetch.MaxRequests)
+		return
 	}
+	names := map[string]bool{}
+	for i, r := range reqs {
+		field := fmt.Sprintf("requests[%d]", i)
+		if err := r.Normalize(); err != nil {
+			switch err.Error() {
+			case "name is empty":
+				errs.Add(field+".name", "must not be empty")
+			case "method is not allowed":
+				errs.Add(field+".method", "must be one of GET, HEAD, POST, PUT, PATCH, DELETE")
+			case "path must be relative":
+				errs.Add(field+".path", "must start with '/' and must not contain a host")
+			default:
+				errs.Add(field, "%s", err.Error())
+			}
+			continue
+		}
+		if names[r.Name] {
+			errs.Add(field+".name", "must be unique")
+		}
+		names[r.Name] = true
+	}
+}
 
-	// 終了時間を計測
-	end := time.Now()
+// 一括取得を実行して結果を返す
+func (h *ParalellHandler) exec(c echo.Context, reqs []domain_fetch.UpstreamRequest, opts domain_fetch.FetchOptions) error {
+	result, err := h.fetchUsecase.Fetch(c.Request().Context(), reqs, opts)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to fetch: %v", err)
+		switch err.Error() {
+		case "compare is only allowed for GET or HEAD":
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
 
-	h.Logger.InfoLog.Println("SeriesHandler completed")
-	h.Logger.InfoLog.Println("Time taken:", end.Sub(start))
+	h.Logger.InfoLog.Printf("Fetch completed (%s)", result.Timing.Mode)
+	h.Logger.InfoLog.Println("Time taken:", result.Timing.ElapsedNs, "ns")
+	if v := cacheHeader(result.Results); v != "" {
+		c.Response().Header().Set("X-Cache", v)
+	}
+	return c.JSON(http.StatusOK, result)
+}
 
-	return c.String(http.StatusOK, strings.Join(allResults, "\n"))
+// X-Cacheヘッダ(上流APIごとのキャッシュの状態)
+// 例: "posts=HIT, comments=MISS"
+func cacheHeader(results domain_fetch.UpstreamResults) string {
+	parts := []string{}
+	for _, r := range results {
+		if r.Cache != "" {
+			parts = append(parts, r.Name+"="+r.Cache)
+		}
+	}
+	return strings.Join(parts, ", ")
 }
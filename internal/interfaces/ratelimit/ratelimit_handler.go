@@ -0,0 +1,122 @@
+package interfaces_ratelimit
+
+import (
+	"backend/config"
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_ratelimit "backend/internal/usecase/ratelimit"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// レート制限のハンドラ
+type RateLimitHandler struct {
+	AppConfig        *config.AppConfig
+	Logger           *pkg_logger.AppLogger
+	Policies         []domain_ratelimit.Policy
+	rateLimitUsecase usecase_ratelimit.IRateLimitUsecase
+	userID           func(c echo.Context) (string, bool)
+}
+
+// レート制限のハンドラのインスタンス化
+// userIDはリクエストの認証済みユーザーIDを返す(KeyByUserのポリシーで使う)
+// 不正なポリシーはログに出して無視する
+func NewRateLimitHandler(appConfig *config.AppConfig, logger *pkg_logger.AppLogger, ru usecase_ratelimit.IRateLimitUsecase, userID func(c echo.Context) (string, bool)) *RateLimitHandler {
+	policies := []domain_ratelimit.Policy{}
+	for _, pc := range appConfig.RateLimit.Policies {
+		p := domain_ratelimit.Policy{
+			Name:      pc.Name,
+			Method:    pc.Method,
+			Path:      pc.Path,
+			Algorithm: domain_ratelimit.Algorithm(pc.Algorithm),
+			KeyBy:     domain_ratelimit.KeyBy(pc.KeyBy),
+			Limit:     pc.Limit,
+			Window:    pc.Window,
+		}
+		if err := p.Validate(); err != nil {
+			logger.ErrorLog.Printf("Invalid rate limit policy %s: %v", pc.Name, err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	return &RateLimitHandler{
+		AppConfig:        appConfig,
+		Logger:           logger,
+		Policies:         policies,
+		rateLimitUsecase: ru,
+		userID:           userID,
+	}
+}
+
+// レート制限ミドルウェア
+// ルートに一致する全てのポリシーを確認し、最も残りの少ないポリシーをRateLimit-*ヘッダで返す
+// 制限を超えた場合は429とRetry-Afterを返す。状態の保存に失敗した場合は制限しない
+func (h *RateLimitHandler) Middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var reported *domain_ratelimit.Decision
+		var reportedPolicy domain_ratelimit.Policy
+		for _, p := range h.Policies {
+			if !p.Matches(c.Request().Method, c.Path()) {
+				continue
+			}
+			d, err := h.rateLimitUsecase.Allow(c.Request().Context(), p, h.key(c, p.KeyBy))
+			if err != nil {
+				h.Logger.ErrorLog.Printf("Failed to check rate limit %s: %v", p.Name, err)
+				continue
+			}
+			if !d.Allowed {
+				setHeaders(c, p, d)
+				c.Response().Header().Set("Retry-After", strconv.Itoa(max(1, ceilSeconds(d.RetryAfter))))
+				return c.JSON(http.StatusTooManyRequests, map[string]string{"message": "Too many requests"})
+			}
+			if reported == nil || d.Remaining < reported.Remaining {
+				reported, reportedPolicy = &d, p
+			}
+		}
+		if reported != nil {
+			setHeaders(c, reportedPolicy, *reported)
+		}
+		return next(c)
+	}
+}
+
+// 制限の単位となるキー
+// ユーザーIDとAPIキーがない場合はIPアドレスで制限する
+func (h *RateLimitHandler) key(c echo.Context, keyBy domain_ratelimit.KeyBy) string {
+	switch keyBy {
+	case domain_ratelimit.KeyByUser:
+		if h.userID != nil {
+			if id, ok := h.userID(c); ok {
+				return "user:" + id
+			}
+		}
+	case domain_ratelimit.KeyByAPIKey:
+		if apiKey := c.Request().Header.Get("X-API-Key"); apiKey != "" {
+			// キーそのものを保存しない
+			sum := sha256.Sum256([]byte(apiKey))
+			return "api_key:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+// RateLimit-*ヘッダを設定する
+func setHeaders(c echo.Context, p domain_ratelimit.Policy, d domain_ratelimit.Decision) {
+	header := c.Response().Header()
+	header.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+	header.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit, ceilSeconds(p.Window)))
+}
+
+// 秒に切り上げる
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
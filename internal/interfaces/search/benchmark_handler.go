@@ -0,0 +1,64 @@
+package search_handler
+
+import (
+	"backend/config"
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_search "backend/internal/usecase/search"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ベンチマークハンドラ(Impl)
+type BenchmarkHandler struct {
+	Logger           *pkg_logger.AppLogger
+	benchmarkUsecase usecase_search.IBenchmarkUsecase
+	validator        searchValidator
+}
+
+// ベンチマークハンドラのインスタンス化
+func NewBenchmarkHandler(appConfig *config.AppConfig, l *pkg_logger.AppLogger, bu usecase_search.IBenchmarkUsecase) *BenchmarkHandler {
+	return &BenchmarkHandler{
+		Logger:           l,
+		benchmarkUsecase: bu,
+		validator:        searchValidator{limits: appConfig.SearchLimits},
+	}
+}
+
+// 探索アルゴリズムのベンチマーク
+func (h *BenchmarkHandler) Benchmark(c echo.Context) error {
+	h.Logger.InfoLog.Println("Benchmark called")
+
+	// リクエストボディ
+	body := domain_search.BenchmarkRequest{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.benchmark(&errs, body)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// ベンチマークを実行
+	result, err := h.benchmarkUsecase.Run(body)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to run benchmark: %v", err)
+		if strings.HasPrefix(err.Error(), "algorithm requires sorted data") {
+			errs.Add("algorithms", "%s", err.Error())
+			return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Printf("size: %d algorithms: %d", result.Size, len(result.Results))
+	return c.JSON(http.StatusOK, result)
+}
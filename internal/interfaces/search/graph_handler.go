@@ -0,0 +1,119 @@
+package search_handler
+
+import (
+	"backend/config"
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_search "backend/internal/usecase/search"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// グラフ探索ハンドラ(Impl)
+type GraphHandler struct {
+	Logger       *pkg_logger.AppLogger
+	graphUsecase usecase_search.IGraphUsecase
+	validator    searchValidator
+}
+
+// グラフ探索ハンドラのインスタンス化
+func NewGraphHandler(appConfig *config.AppConfig, l *pkg_logger.AppLogger, gu usecase_search.IGraphUsecase) *GraphHandler {
+	return &GraphHandler{
+		Logger:       l,
+		graphUsecase: gu,
+		validator:    searchValidator{limits: appConfig.SearchLimits},
+	}
+}
+
+// グラフ探索のリクエストボディ
+type graphRequest struct {
+	domain_search.GraphInput
+	Start string `json:"start"` // BFS/DFSの開始ノード(省略時は全ノード)
+}
+
+// リクエストボディを読み込み、アルゴリズムを実行して結果を返す
+func (h *GraphHandler) exec(c echo.Context, name string, fn func(body graphRequest) (domain_search.GraphResult, error)) error {
+	h.Logger.InfoLog.Printf("Graph %s called", name)
+
+	// リクエストボディ
+	body := graphRequest{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.graph(&errs, body.GraphInput)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// アルゴリズムを実行
+	result, err := fn(body)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to run graph %s: %v", name, err)
+		switch err.Error() {
+		case "node id is empty":
+			errs.Add("graph", "node id must not be empty")
+		case "start node not found":
+			errs.Add("start", "must be a node of the graph")
+		case "graph must be directed":
+			errs.Add("directed", "must be true for this algorithm")
+		case "graph has a cycle":
+			errs.Add("graph", "must not contain a cycle")
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Printf("visited: %d elapsed: %dns", result.Visited, result.ElapsedNs)
+	return c.JSON(http.StatusOK, result)
+}
+
+// 幅優先探索
+func (h *GraphHandler) BFS(c echo.Context) error {
+	return h.exec(c, "bfs", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.BFS(body.GraphInput, body.Start)
+	})
+}
+
+// 深さ優先探索
+func (h *GraphHandler) DFS(c echo.Context) error {
+	return h.exec(c, "dfs", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.DFS(body.GraphInput, body.Start)
+	})
+}
+
+// 連結成分
+func (h *GraphHandler) ConnectedComponents(c echo.Context) error {
+	return h.exec(c, "components", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.ConnectedComponents(body.GraphInput)
+	})
+}
+
+// 閉路検出
+func (h *GraphHandler) DetectCycle(c echo.Context) error {
+	return h.exec(c, "cycle", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.DetectCycle(body.GraphInput)
+	})
+}
+
+// トポロジカルソート
+func (h *GraphHandler) TopologicalSort(c echo.Context) error {
+	return h.exec(c, "toposort", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.TopologicalSort(body.GraphInput)
+	})
+}
+
+// 強連結成分
+func (h *GraphHandler) StronglyConnectedComponents(c echo.Context) error {
+	return h.exec(c, "scc", func(body graphRequest) (domain_search.GraphResult, error) {
+		return h.graphUsecase.StronglyConnectedComponents(body.GraphInput)
+	})
+}
@@ -0,0 +1,178 @@
+package search_handler
+
+import (
+	"backend/config"
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_search "backend/internal/usecase/search"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 迷路ハンドラ(Impl)
+type MazeHandler struct {
+	Logger      *pkg_logger.AppLogger
+	mazeUsecase usecase_search.IMazeUsecase
+	validator   searchValidator
+}
+
+// 迷路ハンドラのインスタンス化
+func NewMazeHandler(appConfig *config.AppConfig, l *pkg_logger.AppLogger, mu usecase_search.IMazeUsecase) *MazeHandler {
+	return &MazeHandler{
+		Logger:      l,
+		mazeUsecase: mu,
+		validator:   searchValidator{limits: appConfig.SearchLimits},
+	}
+}
+
+// 迷路の生成
+// 結果のgridはそのまま /api/search/bfs, /api/search/dfs に渡せる
+func (h *MazeHandler) Generate(c echo.Context) error {
+	h.Logger.InfoLog.Println("Maze Generate called")
+
+	// リクエストボディ
+	body := domain_search.MazeSpec{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if body.Rows < 1 || body.Rows%2 == 0 {
+		errs.Add("rows", "must be a positive odd number")
+	}
+	if body.Cols < 1 || body.Cols%2 == 0 {
+		errs.Add("cols", "must be a positive odd number")
+	}
+	if !errs.HasErrors() && body.Rows > h.validator.limits.MaxGridCells/body.Cols {
+		errs.Add("rows", "rows x cols must be at most %d", h.validator.limits.MaxGridCells)
+	}
+	switch body.Algorithm {
+	case "", domain_search.MazeBacktracker, domain_search.MazePrim, domain_search.MazeKruskal:
+	default:
+		errs.Add("algorithm", "must be one of backtracker, prim, kruskal")
+	}
+	if body.Density != nil && (*body.Density < 0 || *body.Density > 1) {
+		errs.Add("density", "must be between 0 and 1")
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 迷路を生成
+	result, err := h.mazeUsecase.Generate(body)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to generate maze: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// 結果を返す(format=textの場合はテキストのみ)
+	h.Logger.InfoLog.Printf("maze: %dx%d", result.Rows, result.Cols)
+	if c.QueryParam("format") == "text" {
+		return c.String(http.StatusOK, result.Text)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// 迷路の読み込み
+// Content-Typeがtext/plainの場合はボディ全体を、それ以外は{"text": "..."}を読み込む
+func (h *MazeHandler) Import(c echo.Context) error {
+	h.Logger.InfoLog.Println("Maze Import called")
+
+	// リクエストボディ
+	var text string
+	if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMETextPlain) {
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			h.Logger.ErrorLog.Println("Invalid request body")
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		}
+		text = string(b)
+	} else {
+		body := struct {
+			Text string `json:"text"`
+		}{}
+		if err := c.Bind(&body); err != nil {
+			h.Logger.ErrorLog.Println("Invalid request body")
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		}
+		text = body.Text
+	}
+
+	// 迷路に変換
+	grid, line, err := h.mazeUsecase.Import(text)
+	errs := pkg_validation.Errors{}
+	if err != nil {
+		switch err.Error() {
+		case "maze text is empty":
+			errs.Add("text", "must not be empty")
+		case "invalid maze character":
+			errs.Add(fmt.Sprintf("text[%d]", line), "must only contain '#', '1', 'X' (wall) or '.', '0', ' ', 'S', 'G' (path)")
+		case "maze rows must have the same length":
+			errs.Add(fmt.Sprintf("text[%d]", line), "must have the same length as the first line")
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 入力チェック
+	if h.validator.grid(&errs, "text", grid); errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Printf("maze: %dx%d", len(grid), len(grid[0]))
+	return c.JSON(http.StatusOK, map[string]interface{}{"rows": len(grid), "cols": len(grid[0]), "grid": grid})
+}
+
+// 迷路の書き出し
+// ?format=textの場合はテキストのみを返す
+func (h *MazeHandler) Export(c echo.Context) error {
+	h.Logger.InfoLog.Println("Maze Export called")
+
+	// リクエストボディ
+	body := struct {
+		Grid   [][]int                  `json:"grid"`
+		Format domain_search.MazeFormat `json:"format"`
+	}{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.maze(&errs, "grid", body.Grid)
+	switch body.Format {
+	case "", domain_search.MazeFormatASCII, domain_search.MazeFormatBinary:
+	default:
+		errs.Add("format", "must be one of ascii, binary")
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// テキストに変換
+	text, err := h.mazeUsecase.Export(body.Grid, body.Format)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to export maze: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	// 結果を返す
+	if c.QueryParam("format") == "text" {
+		return c.String(http.StatusOK, text)
+	}
+	return c.JSON(http.StatusOK, map[string]string{"text": text})
+}
@@ -1,9 +1,13 @@
 package search_handler
 
 import (
+	"backend/config"
+	domain_search "backend/internal/domain/search"
 	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
 	usecase_search "backend/internal/usecase/search"
 	"net/http"
+	"sort"
 
 	"github.com/labstack/echo/v4"
 )
@@ -12,17 +16,26 @@ import (
 type SearchHandler struct {
 	Logger        *pkg_logger.AppLogger
 	searchUsecase usecase_search.ISearchUsecase
+	validator     searchValidator
 }
 
 // Todoハンドラのインスタンス化
-func NewSearchHandler(l *pkg_logger.AppLogger, su usecase_search.ISearchUsecase) *SearchHandler {
+func NewSearchHandler(appConfig *config.AppConfig, l *pkg_logger.AppLogger, su usecase_search.ISearchUsecase) *SearchHandler {
 	return &SearchHandler{
 		Logger:        l,
 		searchUsecase: su,
+		validator:     searchValidator{limits: appConfig.SearchLimits},
 	}
 }
 
+// 入力エラーのレスポンス(422)
+func (h *SearchHandler) validationError(c echo.Context, errs pkg_validation.Errors) error {
+	h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+	return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+}
+
 // 線形探索
+// trace=trueの場合は途中経過を返す(format=ndjsonの場合はストリーミング)
 func (h *SearchHandler) LinearSearch(c echo.Context) error {
 	h.Logger.InfoLog.Println("LinearSearch called")
 
@@ -30,15 +43,30 @@ func (h *SearchHandler) LinearSearch(c echo.Context) error {
 	body := struct {
 		Arr    []int `json:"arr"`
 		Target int   `json:"target"`
+		Trace  bool  `json:"trace"`
 	}{}
 	if err := c.Bind(&body); err != nil {
 		h.Logger.ErrorLog.Println("Invalid request body")
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
-	h.Logger.InfoLog.Println("request arr: ", body.Arr)
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.array(&errs, "arr", body.Arr)
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	h.Logger.InfoLog.Println("request arr length: ", len(body.Arr))
 	h.Logger.InfoLog.Println("request target: ", body.Target)
 
+	// 途中経過が必要な場合
+	if h.wantsTrace(c, body.Trace) {
+		return h.traced(c, func(t domain_search.Tracer) map[string]interface{} {
+			return map[string]interface{}{"index": h.searchUsecase.LinearSearchTrace(body.Arr, body.Target, t)}
+		})
+	}
+
 	// 線形探索を実行
 	index := h.searchUsecase.LinearSearch(body.Arr, body.Target)
 
@@ -48,6 +76,8 @@ func (h *SearchHandler) LinearSearch(c echo.Context) error {
 }
 
 // 二分探索
+// 未ソートの配列は422を返す。sort=trueの場合はソートしてから探索し、
+// ソート後の位置(index)と元の配列での位置(original_index)を返す
 func (h *SearchHandler) BinarySearch(c echo.Context) error {
 	h.Logger.InfoLog.Println("BinarySearch called")
 
@@ -55,15 +85,65 @@ func (h *SearchHandler) BinarySearch(c echo.Context) error {
 	body := struct {
 		Arr    []int `json:"arr"`
 		Target int   `json:"target"`
+		Sort   bool  `json:"sort"`
+		Trace  bool  `json:"trace"`
 	}{}
 	if err := c.Bind(&body); err != nil {
 		h.Logger.ErrorLog.Println("Invalid request body")
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
-	h.Logger.InfoLog.Println("request arr: ", body.Arr)
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.array(&errs, "arr", body.Arr)
+	sorted := isSorted(body.Arr)
+	if !sorted && !body.Sort {
+		errs.Add("arr", "must be sorted in ascending order (set sort=true to sort before searching)")
+	}
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	h.Logger.InfoLog.Println("request arr length: ", len(body.Arr))
 	h.Logger.InfoLog.Println("request target: ", body.Target)
 
+	if !sorted {
+		// 元の位置を保持したままソートする
+		positions := make([]int, len(body.Arr))
+		for i := range positions {
+			positions[i] = i
+		}
+		sort.SliceStable(positions, func(i, j int) bool {
+			return body.Arr[positions[i]] < body.Arr[positions[j]]
+		})
+		arr := make([]int, len(body.Arr))
+		for i, p := range positions {
+			arr[i] = body.Arr[p]
+		}
+
+		// 二分探索を実行
+		search := func(t domain_search.Tracer) map[string]interface{} {
+			index := h.searchUsecase.BinarySearchTrace(arr, body.Target, t)
+			original := -1
+			if index >= 0 {
+				original = positions[index]
+			}
+			h.Logger.InfoLog.Println("index: ", index)
+			return map[string]interface{}{"index": index, "original_index": original, "sorted": true}
+		}
+		if h.wantsTrace(c, body.Trace) {
+			return h.traced(c, search)
+		}
+		return c.JSON(http.StatusOK, search(nil))
+	}
+
+	// 途中経過が必要な場合
+	if h.wantsTrace(c, body.Trace) {
+		return h.traced(c, func(t domain_search.Tracer) map[string]interface{} {
+			return map[string]interface{}{"index": h.searchUsecase.BinarySearchTrace(body.Arr, body.Target, t)}
+		})
+	}
+
 	// 二分探索を実行
 	index := h.searchUsecase.BinarySearch(body.Arr, body.Target)
 
@@ -78,14 +158,38 @@ func (h *SearchHandler) BFS(c echo.Context) error {
 
 	// リクエストボディ
 	body := struct {
-		Graph [][]int `json:"graph"`
+		Graph      [][]int `json:"graph"`
+		ReturnPath bool    `json:"return_path"`
+		Trace      bool    `json:"trace"`
 	}{}
 	if err := c.Bind(&body); err != nil {
 		h.Logger.ErrorLog.Println("Invalid request body")
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
-	h.Logger.InfoLog.Println("request graph: ", body.Graph)
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.maze(&errs, "graph", body.Graph)
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	h.Logger.InfoLog.Printf("request graph: %dx%d", len(body.Graph), len(body.Graph[0]))
+
+	// 途中経過が必要な場合(経路も返す)
+	if h.wantsTrace(c, body.Trace) {
+		return h.traced(c, func(t domain_search.Tracer) map[string]interface{} {
+			steps, path := h.searchUsecase.BFSTrace(body.Graph, t)
+			return map[string]interface{}{"steps": steps, "path": path}
+		})
+	}
+
+	// 経路が必要な場合
+	if body.ReturnPath {
+		steps, path := h.searchUsecase.BFSPath(body.Graph)
+		h.Logger.InfoLog.Println("steps: ", steps)
+		return c.JSON(http.StatusOK, map[string]interface{}{"steps": steps, "path": path})
+	}
 
 	// BFSを実行
 	steps := h.searchUsecase.BFS(body.Graph)
@@ -101,14 +205,38 @@ func (h *SearchHandler) DFS(c echo.Context) error {
 
 	// リクエストボディ
 	body := struct {
-		Graph [][]int `json:"graph"`
+		Graph      [][]int `json:"graph"`
+		ReturnPath bool    `json:"return_path"`
+		Trace      bool    `json:"trace"`
 	}{}
 	if err := c.Bind(&body); err != nil {
 		h.Logger.ErrorLog.Println("Invalid request body")
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
 	}
 
-	h.Logger.InfoLog.Println("request graph: ", body.Graph)
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	h.validator.maze(&errs, "graph", body.Graph)
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	h.Logger.InfoLog.Printf("request graph: %dx%d", len(body.Graph), len(body.Graph[0]))
+
+	// 途中経過が必要な場合(経路も返す)
+	if h.wantsTrace(c, body.Trace) {
+		return h.traced(c, func(t domain_search.Tracer) map[string]interface{} {
+			result, path := h.searchUsecase.DFSTrace(body.Graph, t)
+			return map[string]interface{}{"result": result, "path": path}
+		})
+	}
+
+	// 経路が必要な場合
+	if body.ReturnPath {
+		result, path := h.searchUsecase.DFSPath(body.Graph)
+		h.Logger.InfoLog.Println("result: ", result)
+		return c.JSON(http.StatusOK, map[string]interface{}{"result": result, "path": path})
+	}
 
 	// DFSを実行
 	result := h.searchUsecase.DFS(body.Graph)
@@ -117,3 +245,103 @@ func (h *SearchHandler) DFS(c echo.Context) error {
 	h.Logger.InfoLog.Println("result: ", result)
 	return c.JSON(http.StatusOK, map[string]bool{"result": result})
 }
+
+// 加重グリッドの経路探索のリクエストボディ
+// gridはBFS/DFSと同じく0が通路、1が壁。costsはマスに入るコスト(省略時は全て1)
+// start/goalを省略した場合は左上/右下とする
+type pathRequest struct {
+	Grid      [][]int                 `json:"grid"`
+	Costs     [][]int                 `json:"costs"`
+	Start     *domain_search.Point    `json:"start"`
+	Goal      *domain_search.Point    `json:"goal"`
+	Diagonal  bool                    `json:"diagonal"`
+	Heuristic domain_search.Heuristic `json:"heuristic"`
+}
+
+// スタートとゴールを取得する
+func (r pathRequest) points() (domain_search.Point, domain_search.Point) {
+	start := domain_search.Point{Row: 0, Col: 0}
+	if r.Start != nil {
+		start = *r.Start
+	}
+	goal := domain_search.Point{Row: len(r.Grid) - 1}
+	if len(r.Grid) > 0 {
+		goal.Col = len(r.Grid[len(r.Grid)-1]) - 1
+	}
+	if r.Goal != nil {
+		goal = *r.Goal
+	}
+	return start, goal
+}
+
+// 加重グリッドの経路探索の入力チェック
+func (h *SearchHandler) validatePathRequest(body pathRequest) pkg_validation.Errors {
+	errs := pkg_validation.Errors{}
+	if !h.validator.maze(&errs, "grid", body.Grid) {
+		return errs
+	}
+	h.validator.costs(&errs, "costs", body.Grid, body.Costs)
+	start, goal := body.points()
+	h.validator.point(&errs, "start", body.Grid, start)
+	h.validator.point(&errs, "goal", body.Grid, goal)
+	h.validator.heuristic(&errs, "heuristic", body.Heuristic, body.Diagonal)
+	return errs
+}
+
+// ダイクストラ法
+func (h *SearchHandler) Dijkstra(c echo.Context) error {
+	h.Logger.InfoLog.Println("Dijkstra called")
+
+	// リクエストボディ
+	body := pathRequest{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	if errs := h.validatePathRequest(body); errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	// ダイクストラ法を実行
+	start, goal := body.points()
+	result, err := h.searchUsecase.Dijkstra(body.Grid, body.Costs, start, goal, body.Diagonal)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to run dijkstra: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Println("cost: ", result.Cost, " expanded: ", result.Expanded)
+	return c.JSON(http.StatusOK, result)
+}
+
+// A*
+func (h *SearchHandler) AStar(c echo.Context) error {
+	h.Logger.InfoLog.Println("AStar called")
+
+	// リクエストボディ
+	body := pathRequest{}
+	if err := c.Bind(&body); err != nil {
+		h.Logger.ErrorLog.Println("Invalid request body")
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+
+	// 入力チェック
+	if errs := h.validatePathRequest(body); errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	// A*を実行
+	start, goal := body.points()
+	result, err := h.searchUsecase.AStar(body.Grid, body.Costs, start, goal, body.Diagonal, body.Heuristic)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to run astar: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Println("cost: ", result.Cost, " expanded: ", result.Expanded)
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,71 @@
+package search_handler
+
+import (
+	domain_search "backend/internal/domain/search"
+	"bufio"
+	"encoding/json"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NDJSONのContent-Type
+const mimeNDJSON = "application/x-ndjson"
+
+// NDJSONでクライアントへ送り出すイベントの間隔
+const ndjsonFlushEvery = 1000
+
+// 途中経過が必要か
+// bodyのtrace=true、またはクエリのformat=ndjsonで有効になる
+func (h *SearchHandler) wantsTrace(c echo.Context, trace bool) bool {
+	return trace || c.QueryParam("format") == "ndjson"
+}
+
+// 途中経過付きで探索を実行して結果を返す
+// 通常は結果に"trace"(上限件数まで)を加えたJSONを返す
+// format=ndjsonの場合はイベントを1行ずつ書き出し、最後の行に"type":"result"の結果を書き出す
+func (h *SearchHandler) traced(c echo.Context, run func(t domain_search.Tracer) map[string]interface{}) error {
+	if c.QueryParam("format") != "ndjson" {
+		log, t := domain_search.NewTraceRecorder(h.validator.limits.MaxTraceEvents)
+		result := run(t)
+		result["trace"] = log
+		h.Logger.InfoLog.Printf("trace events: %d truncated: %v", len(log.Events), log.Truncated)
+		return c.JSON(http.StatusOK, result)
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeNDJSON)
+	res.WriteHeader(http.StatusOK)
+
+	w := bufio.NewWriter(res)
+	enc := json.NewEncoder(w)
+	step := 0
+	var writeErr error
+	result := run(func(e domain_search.TraceEvent) {
+		if writeErr != nil {
+			return
+		}
+		step++
+		e.Step = step
+		writeErr = enc.Encode(e)
+		if step%ndjsonFlushEvery == 0 && writeErr == nil {
+			writeErr = w.Flush()
+			res.Flush()
+		}
+	})
+	if writeErr != nil {
+		h.Logger.ErrorLog.Printf("Failed to stream trace: %v", writeErr)
+		return writeErr
+	}
+
+	result["type"] = "result"
+	if err := enc.Encode(result); err != nil {
+		return err
+	}
+	h.Logger.InfoLog.Printf("trace events: %d (ndjson)", step)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	res.Flush()
+	return nil
+}
@@ -0,0 +1,243 @@
+package search_handler
+
+import (
+	"backend/config"
+	domain_search "backend/internal/domain/search"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_search "backend/internal/usecase/search"
+	"fmt"
+)
+
+// 探索APIの入力チェック
+// 上限値は設定(SearchLimits)から取得する
+type searchValidator struct {
+	limits config.SearchLimits
+}
+
+// 配列のチェック
+func (v searchValidator) array(errs *pkg_validation.Errors, field string, arr []int) {
+	if len(arr) > v.limits.MaxArrayLength {
+		errs.Add(field, "must have at most %d elements", v.limits.MaxArrayLength)
+	}
+}
+
+// グリッドのチェック(空、行の長さが不揃い、マス数の上限)
+// 問題が無い場合はtrueを返す
+func (v searchValidator) grid(errs *pkg_validation.Errors, field string, grid [][]int) bool {
+	if len(grid) == 0 {
+		errs.Add(field, "must not be empty")
+		return false
+	}
+	width := len(grid[0])
+	if width == 0 {
+		errs.Add(field+"[0]", "must not be empty")
+		return false
+	}
+	for i, row := range grid {
+		if len(row) != width {
+			errs.Add(fmt.Sprintf("%s[%d]", field, i), "must have %d columns (got %d)", width, len(row))
+			return false
+		}
+	}
+	if len(grid) > v.limits.MaxGridCells/width {
+		errs.Add(field, "must have at most %d cells", v.limits.MaxGridCells)
+		return false
+	}
+	return true
+}
+
+// 迷路(0: 通路、1: 壁)のチェック
+// 問題が無い場合はtrueを返す
+func (v searchValidator) maze(errs *pkg_validation.Errors, field string, grid [][]int) bool {
+	if !v.grid(errs, field, grid) {
+		return false
+	}
+	for i, row := range grid {
+		for j, cell := range row {
+			if cell != domain_search.GridPath && cell != domain_search.GridWall {
+				errs.Add(fmt.Sprintf("%s[%d][%d]", field, i, j), "must be 0 or 1")
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// マスに入るコストのチェック(省略可。グリッドと同じ形で、負でないこと)
+func (v searchValidator) costs(errs *pkg_validation.Errors, field string, grid [][]int, costs [][]int) {
+	if costs == nil {
+		return
+	}
+	if len(costs) != len(grid) {
+		errs.Add(field, "must have %d rows (got %d)", len(grid), len(costs))
+		return
+	}
+	for i, row := range costs {
+		if len(row) != len(grid[i]) {
+			errs.Add(fmt.Sprintf("%s[%d]", field, i), "must have %d columns (got %d)", len(grid[i]), len(row))
+			return
+		}
+		for j, cost := range row {
+			if cost < 0 {
+				errs.Add(fmt.Sprintf("%s[%d][%d]", field, i, j), "must not be negative")
+				return
+			}
+		}
+	}
+}
+
+// 座標のチェック(グリッド内で、壁ではないこと)
+func (v searchValidator) point(errs *pkg_validation.Errors, field string, grid [][]int, p domain_search.Point) {
+	if p.Row < 0 || p.Row >= len(grid) || p.Col < 0 || p.Col >= len(grid[p.Row]) {
+		errs.Add(field, "must be inside the grid")
+		return
+	}
+	if grid[p.Row][p.Col] == domain_search.GridWall {
+		errs.Add(field, "must not be a wall")
+	}
+}
+
+// ヒューリスティックのチェック
+// マンハッタン距離は斜め移動では過大評価になるため、斜め移動と組み合わせられない
+func (v searchValidator) heuristic(errs *pkg_validation.Errors, field string, h domain_search.Heuristic, diagonal bool) {
+	switch h {
+	case "", domain_search.HeuristicManhattan, domain_search.HeuristicEuclidean,
+		domain_search.HeuristicChebyshev, domain_search.HeuristicOctile, domain_search.HeuristicZero:
+	default:
+		errs.Add(field, "must be one of manhattan, euclidean, chebyshev, octile, zero")
+		return
+	}
+	if h == domain_search.HeuristicManhattan && diagonal {
+		errs.Add(field, "must not be manhattan when diagonal is true")
+	}
+}
+
+// グラフのチェック(空、ノード数・辺数の上限)
+func (v searchValidator) graph(errs *pkg_validation.Errors, input domain_search.GraphInput) {
+	edges := len(input.Edges)
+	for _, neighbors := range input.Adjacency {
+		edges += len(neighbors)
+	}
+	if edges > v.limits.MaxGraphEdges {
+		errs.Add("edges", "must have at most %d edges", v.limits.MaxGraphEdges)
+		return
+	}
+
+	// 辺の端点を含めたノード数
+	ids := map[string]struct{}{}
+	for _, id := range input.Nodes {
+		ids[id] = struct{}{}
+	}
+	for from, neighbors := range input.Adjacency {
+		ids[from] = struct{}{}
+		for _, n := range neighbors {
+			ids[n.To] = struct{}{}
+		}
+	}
+	for _, e := range input.Edges {
+		ids[e.From] = struct{}{}
+		ids[e.To] = struct{}{}
+	}
+	if len(ids) == 0 {
+		errs.Add("graph", "must have at least one node or edge")
+		return
+	}
+	if len(ids) > v.limits.MaxGraphNodes {
+		errs.Add("nodes", "must have at most %d nodes", v.limits.MaxGraphNodes)
+	}
+}
+
+// ベンチマークのチェック(データ、アルゴリズム、実行回数、作業量の上限)
+func (v searchValidator) benchmark(errs *pkg_validation.Errors, req domain_search.BenchmarkRequest) {
+	// データ
+	size, sorted := 0, true
+	switch {
+	case req.Dataset != nil && req.Data != nil:
+		errs.Add("data", "must not be specified together with dataset")
+		return
+	case req.Dataset != nil:
+		size, sorted = req.Dataset.Size, req.Dataset.Sorted
+		if size <= 0 || size > v.limits.MaxArrayLength {
+			errs.Add("dataset.size", "must be between 1 and %d", v.limits.MaxArrayLength)
+		}
+		switch req.Dataset.Distribution {
+		case "", domain_search.DistributionUniform, domain_search.DistributionSequential,
+			domain_search.DistributionNormal, domain_search.DistributionFewUnique:
+		default:
+			errs.Add("dataset.distribution", "must be one of uniform, sequential, normal, few_unique")
+		}
+		if req.Dataset.Max != 0 && req.Dataset.Max < req.Dataset.Min {
+			errs.Add("dataset.max", "must be greater than or equal to min")
+		}
+	case req.Data != nil:
+		size, sorted = len(req.Data), isSorted(req.Data)
+		if size == 0 {
+			errs.Add("data", "must not be empty")
+		}
+		v.array(errs, "data", req.Data)
+	default:
+		errs.Add("dataset", "dataset or data is required")
+	}
+
+	// アルゴリズム
+	if len(req.Algorithms) == 0 {
+		errs.Add("algorithms", "must not be empty")
+	}
+	for i, a := range req.Algorithms {
+		switch a {
+		case domain_search.AlgorithmLinear, domain_search.AlgorithmBinary, domain_search.AlgorithmJump,
+			domain_search.AlgorithmInterpolation, domain_search.AlgorithmExponential:
+		default:
+			errs.Add(fmt.Sprintf("algorithms[%d]", i), "must be one of linear, binary, jump, interpolation, exponential")
+			continue
+		}
+		if a.RequiresSorted() && !sorted {
+			if req.Dataset != nil {
+				errs.Add("dataset.sorted", "must be true for %s search", a)
+			} else {
+				errs.Add("data", "must be sorted for %s search", a)
+			}
+		}
+	}
+
+	// 実行回数とtarget数
+	if req.Iterations < 0 || req.Warmup < 0 || req.Iterations+req.Warmup > v.limits.MaxBenchmarkIterations {
+		errs.Add("iterations", "iterations + warmup must be between 0 and %d", v.limits.MaxBenchmarkIterations)
+	}
+	targets := req.TargetCount
+	if req.Targets != nil {
+		targets = len(req.Targets)
+	}
+	if targets < 0 || targets > v.limits.MaxBenchmarkTargets {
+		errs.Add("targets", "must have at most %d targets", v.limits.MaxBenchmarkTargets)
+	}
+	if errs.HasErrors() {
+		return
+	}
+
+	// 作業量(最悪ケースの線形探索を想定)
+	iterations, warmup := req.Iterations, req.Warmup
+	if iterations == 0 {
+		iterations = usecase_search.DefaultBenchmarkIterations
+	}
+	if req.Iterations == 0 && req.Warmup == 0 {
+		warmup = usecase_search.DefaultBenchmarkWarmup
+	}
+	if targets == 0 && req.Targets == nil {
+		targets = usecase_search.DefaultBenchmarkTargetCount
+	}
+	work := int64(size) * int64(targets) * int64(iterations+warmup) * int64(len(req.Algorithms))
+	if work > v.limits.MaxBenchmarkWork {
+		errs.Add("iterations", "size x targets x runs x algorithms must be at most %d (got %d)", v.limits.MaxBenchmarkWork, work)
+	}
+}
+
+// 昇順にソートされているか
+func isSorted(arr []int) bool {
+	for i := 1; i < len(arr); i++ {
+		if arr[i-1] > arr[i] {
+			return false
+		}
+	}
+	return true
+}
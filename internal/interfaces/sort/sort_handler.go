@@ -0,0 +1,233 @@
+package sort_handler
+
+import (
+	"backend/config"
+	domain_sort "backend/internal/domain/sort"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_sort "backend/internal/usecase/sort"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ソートハンドラ(Impl)
+type SortHandler struct {
+	Logger      *pkg_logger.AppLogger
+	sortUsecase usecase_sort.ISortUsecase
+	limits      config.SortLimits
+}
+
+// ソートハンドラのインスタンス化
+func NewSortHandler(appConfig *config.AppConfig, l *pkg_logger.AppLogger, su usecase_sort.ISortUsecase) *SortHandler {
+	return &SortHandler{
+		Logger:      l,
+		sortUsecase: su,
+		limits:      appConfig.SortLimits,
+	}
+}
+
+// ソートのリクエストボディ
+// {"values": [...], "key": "user.age", "stable": true, "desc": false, "trace": false}
+type sortRequest struct {
+	Items  []domain_sort.Item
+	Key    string
+	Stable bool
+	Desc   bool
+	Trace  bool
+}
+
+// リクエストボディを1要素ずつ読み込む
+// ボディ全体と要素の両方をメモリに持たないよう、json.Decoderでトークン単位に読み進める
+// JSONとして不正な場合はerrを、値が不正な場合はerrsを返す
+func decodeSortRequest(r io.Reader, maxValues int) (sortRequest, pkg_validation.Errors, error) {
+	body := sortRequest{}
+	errs := pkg_validation.Errors{}
+	dec := json.NewDecoder(r)
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return body, errs, errors.New("body must be a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return body, errs, err
+		}
+		switch tok {
+		case "values":
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+				errs.Add("values", "must be an array")
+				return body, errs, nil
+			}
+			body.Items = []domain_sort.Item{}
+			for dec.More() {
+				if len(body.Items) >= maxValues {
+					errs.Add("values", "must have at most %d elements", maxValues)
+					return body, errs, nil
+				}
+				var raw json.RawMessage
+				if err := dec.Decode(&raw); err != nil {
+					return body, errs, err
+				}
+				item, err := domain_sort.NewItem(raw, len(body.Items))
+				if err != nil {
+					errs.Add(fmt.Sprintf("values[%d]", len(body.Items)), "must be a number, string or object")
+					return body, errs, nil
+				}
+				body.Items = append(body.Items, item)
+			}
+			if _, err := dec.Token(); err != nil {
+				return body, errs, err
+			}
+		case "key":
+			err = dec.Decode(&body.Key)
+		case "stable":
+			err = dec.Decode(&body.Stable)
+		case "desc":
+			err = dec.Decode(&body.Desc)
+		case "trace":
+			err = dec.Decode(&body.Trace)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return body, errs, err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return body, errs, err
+	}
+
+	if body.Items == nil {
+		errs.Add("values", "is required")
+	}
+	return body, errs, nil
+}
+
+// 入力エラーのレスポンス(422)
+func (h *SortHandler) validationError(c echo.Context, errs pkg_validation.Errors) error {
+	h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+	return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+}
+
+// リクエストボディを読み込み、ソートして結果を返す
+func (h *SortHandler) exec(c echo.Context, algorithm domain_sort.Algorithm) error {
+	h.Logger.InfoLog.Printf("Sort %s called", algorithm)
+
+	// リクエストボディ
+	body, errs, err := decodeSortRequest(c.Request().Body, h.limits.MaxValues)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Invalid request body: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	// 入力チェック
+	if i, err := domain_sort.ApplyKey(body.Items, body.Key); err != nil {
+		switch err.Error() {
+		case "key is required for objects":
+			errs.Add("key", "is required when values are objects")
+		case "key not found":
+			errs.Add(fmt.Sprintf("values[%d]", i), "does not have key %q", body.Key)
+		default:
+			errs.Add(fmt.Sprintf("values[%d]", i), "%s", err.Error())
+		}
+		return h.validationError(c, errs)
+	}
+	if algorithm.IsQuadratic() && len(body.Items) > h.limits.MaxQuadraticValues {
+		errs.Add("values", "must have at most %d elements for %s sort", h.limits.MaxQuadraticValues, algorithm)
+	}
+	if body.Trace && len(body.Items) > h.limits.MaxTraceValues {
+		errs.Add("values", "must have at most %d elements when trace is enabled", h.limits.MaxTraceValues)
+	}
+	if errs.HasErrors() {
+		return h.validationError(c, errs)
+	}
+
+	// ソートを実行
+	result, err := h.sortUsecase.Sort(algorithm, body.Items, domain_sort.SortOptions{
+		Stable:        body.Stable,
+		Desc:          body.Desc,
+		Trace:         body.Trace,
+		MaxTraceSteps: h.limits.MaxTraceSteps,
+	})
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to sort: %v", err)
+		switch err.Error() {
+		case "radix sort supports integers only":
+			errs.Add("values", "must be integers for radix sort")
+			return h.validationError(c, errs)
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		}
+	}
+
+	// 結果をJSON形式で返す
+	h.Logger.InfoLog.Printf("count: %d comparisons: %d elapsed: %dns", result.Count, result.Comparisons, result.ElapsedNs)
+	return writeSortResponse(c, result, body.Items)
+}
+
+// 結果を書き出す
+// ソート済みの要素は1つずつ書き出し、レスポンス全体をメモリに持たない
+func writeSortResponse(c echo.Context, result domain_sort.SortResult, items []domain_sort.Item) error {
+	head, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	res.WriteHeader(http.StatusOK)
+
+	w := bufio.NewWriterSize(res, 64*1024)
+	w.Write(head[:len(head)-1])
+	w.WriteString(`,"sorted":[`)
+	buf := make([]byte, 0, 64)
+	for i, it := range items {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		buf = it.AppendJSON(buf[:0])
+		w.Write(buf)
+	}
+	w.WriteString("]}\n")
+	return w.Flush()
+}
+
+// クイックソート
+func (h *SortHandler) QuickSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmQuick)
+}
+
+// マージソート
+func (h *SortHandler) MergeSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmMerge)
+}
+
+// ヒープソート
+func (h *SortHandler) HeapSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmHeap)
+}
+
+// 挿入ソート
+func (h *SortHandler) InsertionSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmInsertion)
+}
+
+// 基数ソート
+func (h *SortHandler) RadixSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmRadix)
+}
+
+// ティムソート
+func (h *SortHandler) TimSort(c echo.Context) error {
+	return h.exec(c, domain_sort.AlgorithmTim)
+}
@@ -28,7 +28,7 @@ func (h *TodoHandler) GetAllTodos(c echo.Context) error {
 	h.Logger.InfoLog.Println("GetAllTodos called")
 
 	// Todoユースケースから全てのTodoを取得
-	todos, err := h.todoUsecase.GetAllTodos()
+	todos, err := h.todoUsecase.GetAllTodos(scope(c))
 	// エラーがあればエラーレスポンスを返す
 	if err != nil {
 		h.Logger.ErrorLog.Printf("Failed to get all todos: %v", err)
@@ -50,11 +50,11 @@ func (h *TodoHandler) GetTodoById(c echo.Context) error {
 	id := c.Param("id")
 
 	// Todoユースケースからidを指定してTodoを取得
-	todo, err := h.todoUsecase.GetTodoById(id)
+	todo, err := h.todoUsecase.GetTodoById(scope(c), id)
 	// エラーハンドリング
 	if err != nil {
 		switch err.Error() {
-		case "id is empty":
+		case "id is empty", "todo not found":
 			h.Logger.ErrorLog.Printf("Failed to get todo by id: %v", err)
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"message": err.Error(),
@@ -77,10 +77,10 @@ func (h *TodoHandler) GetTodoByUserId(c echo.Context) error {
 	h.Logger.InfoLog.Println("GetTodoByUserId called")
 
 	// Contextからuser_idを取得
-	userID := c.Get("userId")
+	s := scope(c)
 
 	// Todoユースケースから特定のユーザーのTodoを取得
-	todos, err := h.todoUsecase.GetTodoByUserId(userID.(string))
+	todos, err := h.todoUsecase.GetTodoByUserId(s, s.UserID)
 	// エラーハンドリング
 	if err != nil {
 		switch err.Error() {
@@ -116,7 +116,7 @@ func (h *TodoHandler) CreateTodo(c echo.Context) error {
 	}
 
 	// Todoユースケースから新しいTodoを作成
-	createdTodo, err := h.todoUsecase.CreateTodo(todo)
+	createdTodo, err := h.todoUsecase.CreateTodo(scope(c), todo)
 	// エラーがあればエラーレスポンスを返す
 	if err != nil {
 		switch err.Error() {
@@ -130,6 +130,16 @@ func (h *TodoHandler) CreateTodo(c echo.Context) error {
 			return c.JSON(http.StatusBadRequest, map[string]string{
 				"message": err.Error(),
 			})
+		case "workspace not found":
+			h.Logger.ErrorLog.Printf("Failed to create todo: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to create todo: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
 		default:
 			h.Logger.ErrorLog.Printf("Failed to create todo: %v", err)
 			return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -162,15 +172,20 @@ func (h *TodoHandler) UpdateTodo(c echo.Context) error {
 	todo.ID = id
 
 	// TodoユースケースからTodoを更新
-	updatedTodo, err := h.todoUsecase.UpdateTodo(todo)
+	updatedTodo, err := h.todoUsecase.UpdateTodo(scope(c), todo)
 	// エラーがあればエラーレスポンスを返す
 	if err != nil {
 		switch err.Error() {
-		case "id is empty":
+		case "id is empty", "todo not found":
 			h.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"message": err.Error(),
 			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
 		case "description is empty":
 			h.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
 			return c.JSON(http.StatusBadRequest, map[string]string{
@@ -202,15 +217,20 @@ func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 	id := c.Param("id")
 
 	// Todoユースケースからidを指定してTodoを削除
-	err := h.todoUsecase.DeleteTodo(id)
+	err := h.todoUsecase.DeleteTodo(scope(c), id)
 	// エラーがあればエラーレスポンスを返す
 	if err != nil {
 		switch err.Error() {
-		case "id is empty":
+		case "id is empty", "todo not found":
 			h.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
 			return c.JSON(http.StatusNotFound, map[string]string{
 				"message": err.Error(),
 			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
 		default:
 			h.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
 			return c.JSON(http.StatusInternalServerError, map[string]string{
@@ -225,3 +245,134 @@ func (h *TodoHandler) DeleteTodo(c echo.Context) error {
 		"message": "Todo deleted successfully",
 	})
 }
+
+// Todoと子孫のTodoを階層で取得
+func (h *TodoHandler) GetTodoTree(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetTodoTree called")
+
+	// パスパラメータからidを取得
+	id := c.Param("id")
+
+	// Todoユースケースから階層を取得
+	tree, err := h.todoUsecase.GetTodoTree(scope(c), id)
+	// エラーハンドリング
+	if err != nil {
+		switch err.Error() {
+		case "id is empty", "todo not found":
+			h.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 階層をJSON形式で返す
+	h.Logger.InfoLog.Printf("Todo tree: %v", tree.ID)
+	return c.JSON(http.StatusOK, tree)
+}
+
+// Todoの親と兄弟の中での位置を変更
+func (h *TodoHandler) MoveTodo(c echo.Context) error {
+	h.Logger.InfoLog.Println("MoveTodo called")
+
+	// パスパラメータからidを取得
+	id := c.Param("id")
+
+	// リクエストボディから移動先を取得
+	move := domain_todo.TodoMove{}
+	if err := c.Bind(&move); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to bind move: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// TodoユースケースからTodoを移動
+	movedTodo, err := h.todoUsecase.MoveTodo(scope(c), id, move)
+	// エラーがあればエラーレスポンスを返す
+	if err != nil {
+		switch err.Error() {
+		case "id is empty", "todo not found", "parent not found", "neighbour not found":
+			h.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
+		case "cycle detected", "max depth exceeded", "neighbours are not adjacent":
+			h.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
+			return c.JSON(http.StatusConflict, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 移動したTodoをJSON形式で返す
+	h.Logger.InfoLog.Printf("Moved todo: %v", movedTodo)
+	return c.JSON(http.StatusOK, movedTodo)
+}
+
+// Todoの完了状態を変更
+func (h *TodoHandler) CompleteTodo(c echo.Context) error {
+	h.Logger.InfoLog.Println("CompleteTodo called")
+
+	// パスパラメータからidを取得
+	id := c.Param("id")
+
+	// リクエストボディから完了状態を取得
+	completion := domain_todo.TodoCompletion{}
+	if err := c.Bind(&completion); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to bind completion: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// Todoユースケースから完了状態を変更
+	todos, err := h.todoUsecase.CompleteTodo(scope(c), id, completion)
+	// エラーがあればエラーレスポンスを返す
+	if err != nil {
+		switch err.Error() {
+		case "id is empty", "todo not found":
+			h.Logger.ErrorLog.Printf("Failed to complete todo: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to complete todo: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to complete todo: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 変更したTodoのリストをJSON形式で返す
+	h.Logger.InfoLog.Printf("Completed todos: %v", len(todos))
+	return c.JSON(http.StatusOK, todos)
+}
+
+// リクエストのユーザーと操作中のワークスペース
+// ワークスペースは認証ミドルウェアがX-Workspace-IDヘッダ・トークンから設定する
+func scope(c echo.Context) domain_todo.Scope {
+	userId, _ := c.Get("userId").(string)
+	workspaceId, _ := c.Get("workspaceId").(string)
+	return domain_todo.Scope{UserID: userId, WorkspaceID: workspaceId}
+}
@@ -0,0 +1,206 @@
+package interfaces_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_rrule "backend/internal/pkg/rrule"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_todo "backend/internal/usecase/todo"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 繰り返しのTodoハンドラ(Impl)
+type TodoRecurrenceHandler struct {
+	Logger            *pkg_logger.AppLogger
+	recurrenceUsecase usecase_todo.ITodoRecurrenceUsecase
+}
+
+// 繰り返しのTodoハンドラのインスタンス化
+func NewTodoRecurrenceHandler(l *pkg_logger.AppLogger, ru usecase_todo.ITodoRecurrenceUsecase) *TodoRecurrenceHandler {
+	return &TodoRecurrenceHandler{
+		Logger:            l,
+		recurrenceUsecase: ru,
+	}
+}
+
+// 繰り返しを作成
+// POST /api/todo/recurrences
+func (h *TodoRecurrenceHandler) CreateRecurrence(c echo.Context) error {
+	h.Logger.InfoLog.Println("CreateRecurrence called")
+
+	// リクエストボディから繰り返しを取得
+	recurrence := domain_todo.Recurrence{}
+	if err := c.Bind(&recurrence); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to bind recurrence: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if recurrence.Description == "" {
+		errs.Add("description", "is required")
+	}
+	if recurrence.StartAt.IsZero() {
+		errs.Add("start_at", "is required")
+	}
+	validateRecurrence(&errs, recurrence.RRule, recurrence.Timezone, true)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 繰り返しのTodoユースケースから繰り返しを作成
+	created, todos, err := h.recurrenceUsecase.CreateRecurrence(scope(c), recurrence)
+	// エラーがあればエラーレスポンスを返す
+	if err != nil {
+		switch err.Error() {
+		case "description is empty", "start_at is empty", "invalid rrule", "invalid timezone":
+			h.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		case "workspace not found":
+			h.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 作成した繰り返しと予定のTodoをJSON形式で返す
+	h.Logger.InfoLog.Printf("Created recurrence: %v", created.ID)
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"recurrence": created,
+		"todos":      todos,
+	})
+}
+
+// 繰り返しを取得
+// GET /api/todo/recurrences/:id
+func (h *TodoRecurrenceHandler) GetRecurrence(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetRecurrence called")
+
+	// パスパラメータからidを取得
+	id := c.Param("id")
+
+	// 繰り返しのTodoユースケースから繰り返しを取得
+	recurrence, err := h.recurrenceUsecase.GetRecurrence(scope(c), id)
+	// エラーハンドリング
+	if err != nil {
+		switch err.Error() {
+		case "id is empty", "recurrence not found":
+			h.Logger.ErrorLog.Printf("Failed to get recurrence: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to get recurrence: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 繰り返しをJSON形式で返す
+	h.Logger.InfoLog.Printf("Recurrence: %v", recurrence.ID)
+	return c.JSON(http.StatusOK, recurrence)
+}
+
+// 繰り返しの予定を変更("this": この予定のみ、"future": この予定と以降の全て)
+// PUT /api/todo/:id/occurrence
+func (h *TodoRecurrenceHandler) EditOccurrence(c echo.Context) error {
+	h.Logger.InfoLog.Println("EditOccurrence called")
+
+	// パスパラメータからidを取得
+	id := c.Param("id")
+
+	// リクエストボディから変更内容を取得
+	edit := domain_todo.OccurrenceEdit{}
+	if err := c.Bind(&edit); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to bind occurrence edit: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			"message": err.Error(),
+		})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	switch edit.Scope {
+	case domain_todo.OccurrenceScopeThis:
+		// ルール・タイムゾーンは以降の全ての予定でのみ変更できる
+		if edit.RRule != "" {
+			errs.Add("rrule", "can only be changed with scope %q", domain_todo.OccurrenceScopeFuture)
+		}
+		if edit.Timezone != "" {
+			errs.Add("timezone", "can only be changed with scope %q", domain_todo.OccurrenceScopeFuture)
+		}
+	case domain_todo.OccurrenceScopeFuture:
+		validateRecurrence(&errs, edit.RRule, edit.Timezone, false)
+	default:
+		errs.Add("scope", "must be %q or %q", domain_todo.OccurrenceScopeThis, domain_todo.OccurrenceScopeFuture)
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	// 繰り返しのTodoユースケースから予定を変更
+	todos, err := h.recurrenceUsecase.EditOccurrence(scope(c), id, edit)
+	// エラーがあればエラーレスポンスを返す
+	if err != nil {
+		switch err.Error() {
+		case "id is empty", "todo not found", "recurrence not found":
+			h.Logger.ErrorLog.Printf("Failed to edit occurrence: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "todo is not recurring", "invalid scope", "invalid rrule", "invalid timezone":
+			h.Logger.ErrorLog.Printf("Failed to edit occurrence: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		case "permission denied":
+			h.Logger.ErrorLog.Printf("Failed to edit occurrence: %v", err)
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to edit occurrence: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 変更・作成したTodoのリストをJSON形式で返す
+	h.Logger.InfoLog.Printf("Edited occurrences: %v", len(todos))
+	return c.JSON(http.StatusOK, todos)
+}
+
+// RRULE・タイムゾーンの入力チェック(requiredでない場合、空は変更しないものとして扱う)
+func validateRecurrence(errs *pkg_validation.Errors, rrule string, timezone string, required bool) {
+	if rrule != "" || required {
+		if _, err := pkg_rrule.Parse(rrule); err != nil {
+			errs.Add("rrule", "%v", err)
+		}
+	}
+	if timezone != "" || required {
+		if _, err := pkg_rrule.LoadLocation(timezone); err != nil {
+			errs.Add("timezone", "must be an IANA time zone name")
+		}
+	}
+}
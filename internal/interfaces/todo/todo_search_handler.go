@@ -0,0 +1,71 @@
+package interfaces_todo
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_todo "backend/internal/usecase/todo"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Todo検索ハンドラ(Impl)
+type TodoSearchHandler struct {
+	Logger            *pkg_logger.AppLogger
+	todoSearchUsecase usecase_todo.ITodoSearchUsecase
+}
+
+// Todo検索ハンドラのインスタンス化
+func NewTodoSearchHandler(l *pkg_logger.AppLogger, tsu usecase_todo.ITodoSearchUsecase) *TodoSearchHandler {
+	return &TodoSearchHandler{
+		Logger:            l,
+		todoSearchUsecase: tsu,
+	}
+}
+
+// Todoを全文検索
+// GET /api/todo/search?q=...&limit=...
+func (h *TodoSearchHandler) SearchTodos(c echo.Context) error {
+	h.Logger.InfoLog.Println("SearchTodos called")
+
+	// クエリパラメータを取得
+	q := c.QueryParam("q")
+	limit := 0
+	if s := c.QueryParam("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			h.Logger.ErrorLog.Printf("Invalid limit: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": "limit must be an integer",
+			})
+		}
+		limit = n
+	}
+
+	// Todo検索ユースケースから検索
+	results, err := h.todoSearchUsecase.SearchTodos(scope(c), q, limit)
+	// エラーハンドリング
+	if err != nil {
+		switch err.Error() {
+		case "user_id is empty":
+			h.Logger.ErrorLog.Printf("Failed to search todos: %v", err)
+			return c.JSON(http.StatusNotFound, map[string]string{
+				"message": err.Error(),
+			})
+		case "query is empty":
+			h.Logger.ErrorLog.Printf("Failed to search todos: %v", err)
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				"message": err.Error(),
+			})
+		default:
+			h.Logger.ErrorLog.Printf("Failed to search todos: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// 検索結果をJSON形式で返す
+	h.Logger.InfoLog.Printf("Results: %v", len(results))
+	return c.JSON(http.StatusOK, results)
+}
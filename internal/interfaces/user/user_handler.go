@@ -26,8 +26,9 @@ func NewUserHandler(l *pkg_logger.AppLogger, u usecase_user.IUserUsecase) *UserH
 func (h *UserHandler) GetAllUsers(c echo.Context) error {
 	h.Logger.InfoLog.Println("GetAllUsers called")
 
-	// 全てのユーザーを取得(usecase層)
-	users, err := h.userUsecase.GetAllUsers()
+	// リクエストのユーザーが参照できる全てのユーザーを取得(usecase層)
+	userId, _ := c.Get("userId").(string)
+	users, err := h.userUsecase.GetAllUsers(userId)
 	// エラーハンドリング
 	if err != nil {
 		h.Logger.ErrorLog.Printf("Failed to get all users: %v", err)
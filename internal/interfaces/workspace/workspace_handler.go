@@ -0,0 +1,280 @@
+package interfaces_workspace
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_validation "backend/internal/pkg/validation"
+	usecase_workspace "backend/internal/usecase/workspace"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ワークスペースのハンドラ
+type WorkspaceHandler struct {
+	Logger           *pkg_logger.AppLogger
+	workspaceUsecase usecase_workspace.IWorkspaceUsecase
+	workspaceToken   func(c echo.Context, workspaceId string) (string, error)
+}
+
+// ワークスペースのハンドラのインスタンス化
+// workspaceTokenは操作中のワークスペースを含めてアクセストークンを発行し直す(Switchで使う)
+func NewWorkspaceHandler(l *pkg_logger.AppLogger, wu usecase_workspace.IWorkspaceUsecase, workspaceToken func(c echo.Context, workspaceId string) (string, error)) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		Logger:           l,
+		workspaceUsecase: wu,
+		workspaceToken:   workspaceToken,
+	}
+}
+
+// 役割のリクエストボディの入力チェック
+func validateRole(errs *pkg_validation.Errors, role domain_workspace.Role) {
+	if !role.Valid() {
+		errs.Add("role", "must be one of %s, %s, %s", domain_workspace.RoleOwner, domain_workspace.RoleEditor, domain_workspace.RoleViewer)
+	}
+}
+
+// 所属するワークスペースの一覧
+func (h *WorkspaceHandler) GetWorkspaces(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetWorkspaces called")
+	userId, _ := c.Get("userId").(string)
+
+	workspaces, err := h.workspaceUsecase.GetWorkspaces(userId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to get workspaces: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to get workspaces"})
+	}
+
+	return c.JSON(http.StatusOK, workspaces)
+}
+
+// ワークスペースの作成
+func (h *WorkspaceHandler) CreateWorkspace(c echo.Context) error {
+	h.Logger.InfoLog.Println("CreateWorkspace called")
+	userId, _ := c.Get("userId").(string)
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse workspace request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	// 入力チェック
+	errs := pkg_validation.Errors{}
+	if name := strings.TrimSpace(req.Name); name == "" || len([]rune(name)) > domain_workspace.MaxNameLength {
+		errs.Add("name", "must be between 1 and %d characters", domain_workspace.MaxNameLength)
+	}
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	workspace, err := h.workspaceUsecase.CreateWorkspace(userId, req.Name)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to create workspace: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to create workspace"})
+	}
+
+	return c.JSON(http.StatusCreated, workspace)
+}
+
+// ワークスペースのメンバーの一覧
+func (h *WorkspaceHandler) GetMembers(c echo.Context) error {
+	h.Logger.InfoLog.Println("GetMembers called")
+	userId, _ := c.Get("userId").(string)
+
+	members, err := h.workspaceUsecase.GetMembers(userId, c.Param("id"))
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to get members: %v", err)
+		switch err.Error() {
+		case "workspace not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Workspace not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to get members"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, members)
+}
+
+// メンバーの役割の変更(オーナー)
+func (h *WorkspaceHandler) UpdateMember(c echo.Context) error {
+	h.Logger.InfoLog.Println("UpdateMember called")
+	actorId, _ := c.Get("userId").(string)
+
+	var req struct {
+		Role domain_workspace.Role `json:"role"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse member request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	errs := pkg_validation.Errors{}
+	validateRole(&errs, req.Role)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	if err := h.workspaceUsecase.UpdateMemberRole(actorId, c.Param("id"), c.Param("userId"), req.Role); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to update member: %v", err)
+		switch err.Error() {
+		case "workspace not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Workspace not found"})
+		case "member not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Member not found"})
+		case "permission denied":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "Only owners can manage members"})
+		case "workspace must have an owner":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "Workspace must have an owner"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to update member"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メンバーの削除(オーナー、または本人の脱退)
+func (h *WorkspaceHandler) RemoveMember(c echo.Context) error {
+	h.Logger.InfoLog.Println("RemoveMember called")
+	actorId, _ := c.Get("userId").(string)
+
+	if err := h.workspaceUsecase.RemoveMember(actorId, c.Param("id"), c.Param("userId")); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to remove member: %v", err)
+		switch err.Error() {
+		case "workspace not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Workspace not found"})
+		case "member not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Member not found"})
+		case "permission denied":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "Only owners can manage members"})
+		case "workspace must have an owner":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "Workspace must have an owner"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to remove member"})
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// メールでの招待(オーナー)
+// トークンはメールでのみ送り、レスポンスには含めない
+func (h *WorkspaceHandler) Invite(c echo.Context) error {
+	h.Logger.InfoLog.Println("Invite called")
+	actorId, _ := c.Get("userId").(string)
+
+	var req struct {
+		Email string                `json:"email"`
+		Role  domain_workspace.Role `json:"role"`
+		Lang  string                `json:"lang"` // 省略時はAccept-Language
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse invitation request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+
+	// 入力チェック(役割の既定は編集者)
+	if req.Role == "" {
+		req.Role = domain_workspace.RoleEditor
+	}
+	errs := pkg_validation.Errors{}
+	if req.Email == "" {
+		errs.Add("email", "is required")
+	}
+	validateRole(&errs, req.Role)
+	if errs.HasErrors() {
+		h.Logger.ErrorLog.Printf("Validation failed: %v", errs)
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	lang := req.Lang
+	if lang == "" {
+		lang = c.Request().Header.Get("Accept-Language")
+	}
+	invitation, err := h.workspaceUsecase.Invite(c.Request().Context(), actorId, c.Param("id"), req.Email, req.Role, lang)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to invite: %v", err)
+		switch err.Error() {
+		case "invalid email format":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid email format"})
+		case "workspace not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Workspace not found"})
+		case "permission denied":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "Only owners can invite members"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to invite"})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, invitation)
+}
+
+// 招待を受けてメンバーになる
+func (h *WorkspaceHandler) AcceptInvitation(c echo.Context) error {
+	h.Logger.InfoLog.Println("AcceptInvitation called")
+	userId, _ := c.Get("userId").(string)
+
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.Bind(&req); err != nil {
+		h.Logger.ErrorLog.Printf("Failed to parse accept request: %v", err)
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid request body"})
+	}
+	if req.Token == "" {
+		errs := pkg_validation.Errors{}
+		errs.Add("token", "is required")
+		return c.JSON(http.StatusUnprocessableEntity, errs.Response())
+	}
+
+	membership, err := h.workspaceUsecase.AcceptInvitation(userId, req.Token)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to accept invitation: %v", err)
+		switch err.Error() {
+		case "invalid or expired invitation":
+			return c.JSON(http.StatusBadRequest, map[string]string{"message": "Invalid or expired invitation"})
+		case "already a member":
+			return c.JSON(http.StatusConflict, map[string]string{"message": "Already a member"})
+		case "permission denied":
+			return c.JSON(http.StatusForbidden, map[string]string{"message": "This invitation was sent to a different email address"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to accept invitation"})
+		}
+	}
+
+	return c.JSON(http.StatusOK, membership)
+}
+
+// 操作中のワークスペースを切り替える
+// ワークスペース(wid)を含めたアクセストークンを返す。APIキーの場合はX-Workspace-IDヘッダを使う
+func (h *WorkspaceHandler) Switch(c echo.Context) error {
+	h.Logger.InfoLog.Println("Switch workspace called")
+	if c.Get("apiKeyId") != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"message": "API keys select a workspace with the X-Workspace-ID header"})
+	}
+	userId, _ := c.Get("userId").(string)
+	workspaceId := c.Param("id")
+
+	role, err := h.workspaceUsecase.Authorize(userId, workspaceId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to switch workspace: %v", err)
+		switch err.Error() {
+		case "workspace not found":
+			return c.JSON(http.StatusNotFound, map[string]string{"message": "Workspace not found"})
+		default:
+			return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to switch workspace"})
+		}
+	}
+
+	token, err := h.workspaceToken(c, workspaceId)
+	if err != nil {
+		h.Logger.ErrorLog.Printf("Failed to sign token: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"message": "Failed to sign token"})
+	}
+	return c.JSON(http.StatusOK, map[string]string{"token": token, "workspace_id": workspaceId, "role": string(role)})
+}
@@ -0,0 +1,234 @@
+package pkg_httpcache
+
+import (
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	pkg_logger "backend/internal/pkg/logger"
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// キャッシュの状態
+const (
+	StatusHit         = "HIT"         // 新しいエントリを返した
+	StatusMiss        = "MISS"        // 上流から取得した
+	StatusStale       = "STALE"       // 古いエントリを返し、裏で再検証した
+	StatusRevalidated = "REVALIDATED" // 上流に確認し、変更がなかったためエントリを返した
+	StatusBypass      = "BYPASS"      // キャッシュの対象外
+)
+
+// キャッシュの設定
+type Options struct {
+	TTL                  time.Duration // エントリの鮮度(上流のCache-Control: max-ageがあればそちらを優先)
+	StaleWhileRevalidate time.Duration // 鮮度が切れた後、古いエントリを返しながら裏で再検証する時間
+	Retention            time.Duration // さらにその後、条件付きリクエストでの再検証用にエントリを残す時間
+}
+
+// 上流へのリクエストの前に置くキャッシュ
+// GETのみを対象とし、同じリクエスト(URLとヘッダ)の同時の取得は1つにまとめる
+type Cache struct {
+	Logger  *pkg_logger.AppLogger
+	next    pkg_httpclient.Doer
+	store   Store
+	options Options
+	group   singleflight.Group
+}
+
+// キャッシュのインスタンス化
+func NewCache(l *pkg_logger.AppLogger, next pkg_httpclient.Doer, store Store, opts Options) *Cache {
+	return &Cache{
+		Logger:  l,
+		next:    next,
+		store:   store,
+		options: opts,
+	}
+}
+
+// リクエストを送る(キャッシュがあればそれを返す)
+func (c *Cache) Do(ctx context.Context, req pkg_httpclient.Request) (*pkg_httpclient.Response, error) {
+	if !cacheableRequest(req) {
+		res, err := c.next.Do(ctx, req)
+		if res != nil {
+			res.Cache = StatusBypass
+		}
+		return res, err
+	}
+
+	key := cacheKey(req)
+	entry, err := c.store.Get(ctx, key)
+	if err != nil {
+		// 保存先の障害ではリクエストを失敗させず、上流から取得する
+		c.Logger.WarnLog.Printf("Failed to get cache %s: %v", key, err)
+		entry = nil
+	}
+
+	now := time.Now()
+	if entry != nil && now.Before(entry.FreshUntil) {
+		return entry.response(StatusHit), nil
+	}
+	if entry != nil && now.Before(entry.StaleUntil) {
+		// 同じキーの再検証が実行中であればまとめられ、結果は待たない
+		c.group.DoChan(key, func() (interface{}, error) {
+			return c.fetch(context.Background(), key, req, entry)
+		})
+		return entry.response(StatusStale), nil
+	}
+
+	// 呼び出し元がキャンセルしても取得は続け、結果をキャッシュに残す
+	ch := c.group.DoChan(key, func() (interface{}, error) {
+		return c.fetch(context.WithoutCancel(ctx), key, req, entry)
+	})
+	var result singleflight.Result
+	select {
+	case result = <-ch:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	res, _ := result.Val.(*pkg_httpclient.Response)
+	if res == nil {
+		return nil, result.Err
+	}
+	// 結果は複数の呼び出し元で共有するため、コピーを返す
+	out := *res
+	return &out, result.Err
+}
+
+// 上流から取得してキャッシュに保存する
+// エントリがあればETag/Last-Modifiedで条件付きリクエストにする
+func (c *Cache) fetch(ctx context.Context, key string, req pkg_httpclient.Request, entry *Entry) (*pkg_httpclient.Response, error) {
+	if entry != nil {
+		headers := map[string]string{}
+		for k, v := range req.Headers {
+			headers[k] = v
+		}
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			headers["If-None-Match"] = etag
+		}
+		if lm := entry.Header.Get("Last-Modified"); lm != "" {
+			headers["If-Modified-Since"] = lm
+		}
+		req.Headers = headers
+	}
+
+	res, err := c.next.Do(ctx, req)
+	if err != nil {
+		c.Logger.ErrorLog.Printf("Failed to fetch %s: %v", key, err)
+		return res, err
+	}
+
+	if res.Status == http.StatusNotModified && entry != nil {
+		refreshed := *entry
+		c.save(ctx, key, &refreshed, res.Header)
+		out := refreshed.response(StatusRevalidated)
+		out.Attempts = res.Attempts
+		c.Logger.InfoLog.Printf("Cache revalidated: %s", key)
+		return out, nil
+	}
+
+	res.Cache = StatusMiss
+	if res.Status == http.StatusOK && !hasDirective(res.Header, "no-store") {
+		c.save(ctx, key, &Entry{Status: res.Status, Header: res.Header, Body: res.Body}, res.Header)
+	}
+	return res, nil
+}
+
+// エントリの期限を設定して保存する
+func (c *Cache) save(ctx context.Context, key string, e *Entry, header http.Header) {
+	keep := c.expire(e, header)
+	if keep <= 0 {
+		return
+	}
+	if err := c.store.Set(ctx, key, e, keep); err != nil {
+		c.Logger.WarnLog.Printf("Failed to set cache %s: %v", key, err)
+	}
+}
+
+// エントリの期限を設定し、保存先に残す時間を返す
+func (c *Cache) expire(e *Entry, header http.Header) time.Duration {
+	ttl := c.options.TTL
+	if maxAge, ok := maxAge(header); ok {
+		ttl = maxAge
+	}
+	stale := c.options.StaleWhileRevalidate
+	// no-cacheは毎回再検証する(古いまま返さない)。must-revalidateも鮮度が切れたら古いまま返さない
+	if hasDirective(header, "no-cache") {
+		ttl = 0
+		stale = 0
+	}
+	if hasDirective(header, "must-revalidate") {
+		stale = 0
+	}
+
+	e.StoredAt = time.Now()
+	e.FreshUntil = e.StoredAt.Add(ttl)
+	e.StaleUntil = e.FreshUntil.Add(stale)
+
+	keep := ttl + stale
+	// 検証子がない場合は条件付きリクエストができないため、再検証用には残さない
+	if e.Header.Get("ETag") != "" || e.Header.Get("Last-Modified") != "" {
+		keep += c.options.Retention
+	}
+	return keep
+}
+
+// エントリをレスポンスにする
+func (e *Entry) response(status string) *pkg_httpclient.Response {
+	return &pkg_httpclient.Response{Status: e.Status, Header: e.Header, Body: e.Body, Cache: status}
+}
+
+// キャッシュのキー
+// 転送するヘッダ(Acceptなど)でレスポンスが変わりうるため、メソッド・URLに加えてヘッダも含める
+func cacheKey(req pkg_httpclient.Request) string {
+	headers := make([]string, 0, len(req.Headers))
+	for k, v := range req.Headers {
+		headers = append(headers, http.CanonicalHeaderKey(k)+": "+v)
+	}
+	sort.Strings(headers)
+	return strings.Join(append([]string{req.Method + " " + req.URL}, headers...), "\n")
+}
+
+// キャッシュの対象となるリクエストか
+// 認証情報を含むリクエストは利用者ごとに結果が異なるため対象外
+func cacheableRequest(req pkg_httpclient.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	for k := range req.Headers {
+		switch http.CanonicalHeaderKey(k) {
+		case "Authorization", "Cookie":
+			return false
+		}
+	}
+	return true
+}
+
+// Cache-Controlに指定のディレクティブがあるか
+func hasDirective(header http.Header, name string) bool {
+	for _, d := range strings.Split(header.Get("Cache-Control"), ",") {
+		if strings.EqualFold(strings.TrimSpace(d), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache-Controlのmax-age
+func maxAge(header http.Header) (time.Duration, bool) {
+	for _, d := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(d), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
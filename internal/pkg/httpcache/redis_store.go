@@ -0,0 +1,61 @@
+package pkg_httpcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// キーが存在しない場合にRedisClient.Getが返すエラー
+var ErrNotFound = errors.New("cache key not found")
+
+// Redis互換のクライアント
+// go-redis等のクライアントをこの形に合わせれば、キャッシュを複数のサーバーで共有できる
+type RedisClient interface {
+	Get(ctx context.Context, key string) ([]byte, error) // キーがない場合はErrNotFound
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// Redis互換のクライアントを使う保存先
+// エントリはJSONにして保存する
+type RedisStore struct {
+	client RedisClient
+	prefix string
+}
+
+// Redis互換の保存先の作成
+func NewRedisStore(client RedisClient, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+// エントリの取得
+func (s *RedisStore) Get(ctx context.Context, key string) (*Entry, error) {
+	data, err := s.client.Get(ctx, s.prefix+key)
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// エントリの保存
+func (s *RedisStore) Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return s.client.Set(ctx, s.prefix+key, data, ttl)
+}
+
+// エントリの削除
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key)
+}
@@ -0,0 +1,113 @@
+package pkg_httpcache
+
+import (
+	"container/list"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// キャッシュしたレスポンス
+type Entry struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+	FreshUntil time.Time   `json:"fresh_until"` // この時刻まではそのまま返す
+	StaleUntil time.Time   `json:"stale_until"` // この時刻までは古いまま返し、裏で再検証する
+}
+
+// キャッシュの保存先
+// Getでエントリがない場合は(nil, nil)を返す
+type Store interface {
+	Get(ctx context.Context, key string) (*Entry, error)
+	Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// メモリ上のLRUキャッシュ
+// 件数が上限を超えると、最も長く使われていないエントリから捨てる
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	items      map[string]*list.Element
+	order      *list.List // 先頭ほど最近使われたもの
+}
+
+// LRUの要素
+type memoryItem struct {
+	key       string
+	entry     *Entry
+	expiresAt time.Time
+}
+
+// メモリ上のLRUキャッシュの作成
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		maxEntries: maxEntries,
+		items:      map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+// エントリの取得
+func (s *MemoryStore) Get(ctx context.Context, key string) (*Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, nil
+	}
+	item := el.Value.(*memoryItem)
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		s.order.Remove(el)
+		delete(s.items, key)
+		return nil, nil
+	}
+	s.order.MoveToFront(el)
+	return item.entry, nil
+}
+
+// エントリの保存(ttlが0の場合は期限なし)
+func (s *MemoryStore) Set(ctx context.Context, key string, e *Entry, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item := &memoryItem{key: key, entry: e}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	if el, ok := s.items[key]; ok {
+		el.Value = item
+		s.order.MoveToFront(el)
+		return nil
+	}
+	s.items[key] = s.order.PushFront(item)
+	for s.maxEntries > 0 && s.order.Len() > s.maxEntries {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.items, oldest.Value.(*memoryItem).key)
+	}
+	return nil
+}
+
+// エントリの削除
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.order.Remove(el)
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// 保存件数
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
@@ -0,0 +1,86 @@
+package pkg_httpclient
+
+import (
+	"sync"
+	"time"
+)
+
+// サーキットブレーカーの状態
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // 通常
+	breakerOpen                         // 遮断中
+	breakerHalfOpen                     // 試行を1件だけ通して回復を確認する
+)
+
+// ホストごとのサーキットブレーカー
+// 連続してthreshold回失敗すると回路を開き、cooldownの間はリクエストを送らずに失敗させる
+type breakers struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	hosts     map[string]*breaker
+}
+
+// 1ホスト分の状態
+type breaker struct {
+	state    breakerState
+	failures int
+	openedAt time.Time // 回路を開いた時刻(半開では試行を通した時刻)
+}
+
+// サーキットブレーカーの作成
+func newBreakers(threshold int, cooldown time.Duration) *breakers {
+	return &breakers{
+		threshold: threshold,
+		cooldown:  cooldown,
+		hosts:     map[string]*breaker{},
+	}
+}
+
+// リクエストを送ってよいか
+func (b *breakers) allow(host string) bool {
+	if b.threshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.hosts[host]
+	if br == nil || br.state == breakerClosed {
+		return true
+	}
+	// 遮断中(または半開の試行の結果が返らないまま)cooldownが経過したら、次の1件を試行として通す
+	if time.Since(br.openedAt) >= b.cooldown {
+		br.state = breakerHalfOpen
+		br.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// 結果を記録する
+func (b *breakers) record(host string, success bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	br := b.hosts[host]
+	if br == nil {
+		br = &breaker{}
+		b.hosts[host] = br
+	}
+	if success {
+		br.state = breakerClosed
+		br.failures = 0
+		return
+	}
+	br.failures++
+	if br.state == breakerHalfOpen || br.failures >= b.threshold {
+		br.state = breakerOpen
+		br.openedAt = time.Now()
+	}
+}
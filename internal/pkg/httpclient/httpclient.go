@@ -0,0 +1,213 @@
+package pkg_httpclient
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// エラー
+var (
+	ErrCircuitOpen      = errors.New("circuit breaker is open")
+	ErrResponseTooLarge = errors.New("response body is too large")
+)
+
+// 外部APIクライアントの設定
+type Options struct {
+	Timeout          time.Duration // 1回の試行のタイムアウト
+	MaxRetries       int           // 再試行の回数(冪等なリクエストのみ)
+	BaseBackoff      time.Duration // 再試行の待ち時間の基準
+	MaxBackoff       time.Duration // 再試行の待ち時間の上限
+	MaxResponseSize  int64         // レスポンスボディの上限(バイト)
+	BreakerThreshold int           // 回路を開くまでの連続失敗回数(0で無効)
+	BreakerCooldown  time.Duration // 回路を開いてから試行を再開するまでの時間
+}
+
+// 外部APIへのリクエスト
+// 再試行で何度でも送れるよう、ボディはバイト列で持つ
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// 外部APIのレスポンス
+type Response struct {
+	Status   int
+	Header   http.Header
+	Body     []byte
+	Attempts int    // 試行回数
+	Cache    string // キャッシュの状態(キャッシュを通さない場合は空)
+}
+
+// リクエストを送るもの(Clientとキャッシュで共通)
+type Doer interface {
+	Do(ctx context.Context, req Request) (*Response, error)
+}
+
+// 外部APIクライアント
+type Client struct {
+	Logger   *pkg_logger.AppLogger
+	options  Options
+	client   *http.Client
+	breakers *breakers
+}
+
+// 外部APIクライアントのインスタンス化
+func NewClient(l *pkg_logger.AppLogger, opts Options) *Client {
+	return &Client{
+		Logger:   l,
+		options:  opts,
+		client:   &http.Client{},
+		breakers: newBreakers(opts.BreakerThreshold, opts.BreakerCooldown),
+	}
+}
+
+// 再試行してよいメソッド(冪等なもの)
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// 再試行するステータス
+var retryableStatus = map[int]bool{
+	http.StatusTooManyRequests:    true,
+	http.StatusBadGateway:         true,
+	http.StatusServiceUnavailable: true,
+	http.StatusGatewayTimeout:     true,
+}
+
+// リクエストを送る
+// 通信エラーと再試行するステータスの場合、冪等なリクエストは待ち時間を空けて再試行する
+// 再試行しても失敗した場合は最後のレスポンス(またはエラー)を返す
+// ステータスが2xx以外でもエラーにはしない
+func (c *Client) Do(ctx context.Context, req Request) (*Response, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return nil, err
+	}
+	host := u.Host
+
+	retries := 0
+	if idempotentMethods[req.Method] {
+		retries = c.options.MaxRetries
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !c.breakers.allow(host) {
+			c.Logger.WarnLog.Printf("Circuit breaker is open: %s", host)
+			return nil, ErrCircuitOpen
+		}
+
+		res, err := c.attempt(ctx, req)
+		if res != nil {
+			res.Attempts = attempt
+		}
+		// 呼び出し側のキャンセルとサイズ超過は上流の障害として扱わない
+		networkErr := err != nil && ctx.Err() == nil && !errors.Is(err, ErrResponseTooLarge)
+		if ctx.Err() == nil {
+			c.breakers.record(host, !networkErr && (res == nil || res.Status < http.StatusInternalServerError))
+		}
+
+		retryable := networkErr || (err == nil && retryableStatus[res.Status])
+		if !retryable || attempt > retries {
+			return res, err
+		}
+
+		wait := c.backoff(attempt)
+		if res != nil {
+			if d, ok := retryAfter(res.Header); ok {
+				wait = min(d, c.options.MaxBackoff)
+			}
+		}
+		c.Logger.WarnLog.Printf("Retrying %s %s in %v (attempt %d)", req.Method, req.URL, wait, attempt)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// 1回の試行
+func (c *Client) attempt(ctx context.Context, req Request) (*Response, error) {
+	if c.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.options.Timeout)
+		defer cancel()
+	}
+
+	var body io.Reader
+	if req.Body != nil {
+		body = bytes.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	httpRes, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpRes.Body.Close()
+
+	res := &Response{Status: httpRes.StatusCode, Header: httpRes.Header}
+	limit := c.options.MaxResponseSize
+	if limit > 0 && httpRes.ContentLength > limit {
+		return res, ErrResponseTooLarge
+	}
+	reader := io.Reader(httpRes.Body)
+	if limit > 0 {
+		// 上限を1バイト超えて読めたら超過と判定する
+		reader = io.LimitReader(httpRes.Body, limit+1)
+	}
+	res.Body, err = io.ReadAll(reader)
+	if err != nil {
+		return res, err
+	}
+	if limit > 0 && int64(len(res.Body)) > limit {
+		res.Body = nil
+		return res, ErrResponseTooLarge
+	}
+	return res, nil
+}
+
+// 再試行までの待ち時間
+// 指数的に増やした上限の範囲でランダムに選ぶ(Full Jitter)
+func (c *Client) backoff(attempt int) time.Duration {
+	ceiling := c.options.MaxBackoff
+	if shift := attempt - 1; shift < 32 {
+		ceiling = min(c.options.BaseBackoff<<shift, c.options.MaxBackoff)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// Retry-Afterヘッダ(秒数)
+func retryAfter(h http.Header) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(h.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,83 @@
+package pkg_mail
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// メールをファイルに書き出す(ローカル環境・テスト用)
+// 1通ごとに .eml ファイルを作る。メールソフトでそのまま開ける
+type FileMailer struct {
+	Logger *pkg_logger.AppLogger
+	dir    string
+	from   string
+}
+
+// メールをファイルに書き出すもののインスタンス化
+func NewFileMailer(l *pkg_logger.AppLogger, dir string, from string) Mailer {
+	return &FileMailer{
+		Logger: l,
+		dir:    dir,
+		from:   from,
+	}
+}
+
+// メールを書き出す
+// ファイル名は送信日時の順に並ぶようにする
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return errInvalidRecipient
+	}
+
+	now := time.Now()
+	data, err := format(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		m.Logger.ErrorLog.Printf("Failed to create mail directory: %v", err)
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := filepath.Join(m.dir, now.UTC().Format("20060102T150405.000000000")+"-"+hex.EncodeToString(suffix)+".eml")
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		m.Logger.ErrorLog.Printf("Failed to write mail: %v", err)
+		return err
+	}
+
+	m.Logger.InfoLog.Printf("Wrote mail to %s: %s", name, msg.Subject)
+	return nil
+}
+
+// メールをログに出す(ローカル環境用)
+// 本文にはトークンなどが含まれるため、本番環境では使わない
+type LogMailer struct {
+	Logger *pkg_logger.AppLogger
+}
+
+// メールをログに出すもののインスタンス化
+func NewLogMailer(l *pkg_logger.AppLogger) Mailer {
+	return &LogMailer{Logger: l}
+}
+
+// メールをログに出す
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.Logger.InfoLog.Printf("Mail to %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
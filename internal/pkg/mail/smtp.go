@@ -0,0 +1,64 @@
+package pkg_mail
+
+import (
+	pkg_logger "backend/internal/pkg/logger"
+	"context"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPの設定
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // 空の場合は認証しない
+	Password string
+	From     string
+}
+
+// SMTPでメールを送る
+type SMTPMailer struct {
+	Logger *pkg_logger.AppLogger
+	config SMTPConfig
+}
+
+// SMTPでメールを送るもののインスタンス化
+func NewSMTPMailer(l *pkg_logger.AppLogger, cfg SMTPConfig) Mailer {
+	return &SMTPMailer{
+		Logger: l,
+		config: cfg,
+	}
+}
+
+// メールを送る
+// net/smtpはcontextに対応しないため、送信前にキャンセルを確認するのみ
+// サーバーがSTARTTLSに対応していれば暗号化する(認証はTLS上でのみ行われる)
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return errInvalidRecipient
+	}
+
+	data, err := format(m.config.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+	addr := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{msg.To}, data); err != nil {
+		m.Logger.ErrorLog.Printf("Failed to send mail via %s: %v", addr, err)
+		return err
+	}
+
+	m.Logger.InfoLog.Printf("Sent mail via %s: %s", addr, msg.Subject)
+	return nil
+}
@@ -0,0 +1,90 @@
+package pkg_mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// メールのテンプレート
+// templates/{name}.{lang}.tmpl に "subject" と "body" を定義する
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// メールのテンプレート(言語ごと)
+type Templates struct {
+	defaultLanguage string
+	languages       []string
+	templates       map[string]*template.Template // "{name}.{lang}"
+}
+
+// メールのテンプレートの読み込み
+// 既定の言語はテンプレートが無い言語の代わりに使う
+func NewTemplates(defaultLanguage string) (*Templates, error) {
+	files, err := templateFiles.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{defaultLanguage: defaultLanguage, templates: map[string]*template.Template{}}
+	for _, file := range files {
+		key := strings.TrimSuffix(file.Name(), ".tmpl")
+		_, lang, ok := strings.Cut(key, ".")
+		if !ok {
+			return nil, fmt.Errorf("invalid template name: %s", file.Name())
+		}
+		tmpl, err := template.ParseFS(templateFiles, path.Join("templates", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		t.templates[key] = tmpl
+		if !slices.Contains(t.languages, lang) {
+			t.languages = append(t.languages, lang)
+		}
+	}
+	if !slices.Contains(t.languages, defaultLanguage) {
+		return nil, fmt.Errorf("no templates for default language: %s", defaultLanguage)
+	}
+	return t, nil
+}
+
+// 希望する言語から、テンプレートのある言語を選ぶ
+// "en-US" や Accept-Language("ja,en;q=0.8")の形式も受け付け、先に書かれたものを優先する
+func (t *Templates) Language(preferred ...string) string {
+	for _, p := range preferred {
+		for _, tag := range strings.Split(p, ",") {
+			tag, _, _ = strings.Cut(tag, ";")
+			tag, _, _ = strings.Cut(strings.TrimSpace(tag), "-")
+			tag = strings.ToLower(tag)
+			if slices.Contains(t.languages, tag) {
+				return tag
+			}
+		}
+	}
+	return t.defaultLanguage
+}
+
+// テンプレートからメールの件名と本文を作る
+// 言語のテンプレートが無い場合は既定の言語で作る
+func (t *Templates) Render(name string, lang string, data any) (string, string, error) {
+	tmpl, ok := t.templates[name+"."+t.Language(lang)]
+	if !ok {
+		if tmpl, ok = t.templates[name+"."+t.defaultLanguage]; !ok {
+			return "", "", fmt.Errorf("unknown template: %s", name)
+		}
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), strings.TrimLeft(body.String(), "\n"), nil
+}
@@ -0,0 +1,177 @@
+package pkg_oidc
+
+import (
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	pkg_logger "backend/internal/pkg/logger"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// エラー
+var (
+	ErrDiscovery     = errors.New("failed to discover provider")
+	ErrTokenExchange = errors.New("failed to exchange code")
+	ErrInvalidToken  = errors.New("invalid id token")
+)
+
+// IDプロバイダの設定
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // 空の場合は openid email profile
+}
+
+// ディスカバリ(/.well-known/openid-configuration)の内容
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// IDトークンのクレーム
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCのRelying Party
+// ディスカバリと公開鍵(JWKS)は初回に取得してキャッシュし、未知の鍵IDが来たらJWKSを取得し直す
+type Provider struct {
+	Logger *pkg_logger.AppLogger
+	config Config
+	client pkg_httpclient.Doer
+
+	mu       sync.Mutex
+	metadata *metadata
+	keys     map[string]any
+}
+
+// OIDCのRelying Partyのインスタンス化
+func NewProvider(l *pkg_logger.AppLogger, cfg Config, client pkg_httpclient.Doer) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Provider{
+		Logger: l,
+		config: cfg,
+		client: client,
+	}
+}
+
+// プロバイダ名
+func (p *Provider) Name() string {
+	return p.config.Name
+}
+
+// 認可リクエストのURL(認可コードフロー + PKCE S256)
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+	sep := "?"
+	if strings.Contains(md.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return md.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// 認可コードをIDトークンに交換し、検証したクレームを返す
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (Claims, error) {
+	md, err := p.discover(ctx)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	basic := base64.StdEncoding.EncodeToString([]byte(url.QueryEscape(p.config.ClientID) + ":" + url.QueryEscape(p.config.ClientSecret)))
+	res, err := p.client.Do(ctx, pkg_httpclient.Request{
+		Method: http.MethodPost,
+		URL:    md.TokenEndpoint,
+		Headers: map[string]string{
+			"Content-Type":  "application/x-www-form-urlencoded",
+			"Accept":        "application/json",
+			"Authorization": "Basic " + basic,
+		},
+		Body: []byte(form.Encode()),
+	})
+	if err != nil {
+		p.Logger.ErrorLog.Printf("Failed to request token from %s: %v", p.config.Name, err)
+		return Claims{}, ErrTokenExchange
+	}
+	if res.Status != http.StatusOK {
+		p.Logger.ErrorLog.Printf("Token endpoint of %s returned %d: %s", p.config.Name, res.Status, res.Body)
+		return Claims{}, ErrTokenExchange
+	}
+	var token struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.Unmarshal(res.Body, &token); err != nil || token.IDToken == "" {
+		p.Logger.ErrorLog.Printf("Token response of %s has no id_token", p.config.Name)
+		return Claims{}, ErrTokenExchange
+	}
+
+	return p.verify(ctx, md, token.IDToken, nonce)
+}
+
+// ディスカバリ
+func (p *Provider) discover(ctx context.Context) (*metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	var md metadata
+	if err := p.getJSON(ctx, strings.TrimSuffix(p.config.Issuer, "/")+"/.well-known/openid-configuration", &md); err != nil {
+		p.Logger.ErrorLog.Printf("Failed to discover %s: %v", p.config.Name, err)
+		return nil, ErrDiscovery
+	}
+	// 発行者が設定と異なる場合は使わない(なりすまし防止)
+	if md.Issuer != p.config.Issuer || md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		p.Logger.ErrorLog.Printf("Invalid discovery document of %s: issuer=%s", p.config.Name, md.Issuer)
+		return nil, ErrDiscovery
+	}
+	p.metadata = &md
+	return p.metadata, nil
+}
+
+// JSONを取得する
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	res, err := p.client.Do(ctx, pkg_httpclient.Request{Method: http.MethodGet, URL: url, Headers: map[string]string{"Accept": "application/json"}})
+	if err != nil {
+		return err
+	}
+	if res.Status != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", res.Status)
+	}
+	return json.Unmarshal(res.Body, v)
+}
@@ -0,0 +1,116 @@
+package pkg_oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"slices"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// IDトークンを検証する
+// 署名(RS256のみ)・発行者・対象者(client_id)・有効期限・nonceを確認する
+func (p *Provider) verify(ctx context.Context, md *metadata, raw, nonce string) (Claims, error) {
+	parser := jwt.Parser{ValidMethods: []string{"RS256"}}
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, md, kid)
+	})
+	if err != nil {
+		p.Logger.ErrorLog.Printf("Failed to verify id token of %s: %v", p.config.Name, err)
+		return Claims{}, ErrInvalidToken
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		p.Logger.ErrorLog.Printf("Id token of %s has no exp", p.config.Name)
+		return Claims{}, ErrInvalidToken
+	}
+	if iss, _ := claims["iss"].(string); iss != md.Issuer {
+		p.Logger.ErrorLog.Printf("Id token of %s has unexpected issuer: %s", p.config.Name, iss)
+		return Claims{}, ErrInvalidToken
+	}
+	if !audienceContains(claims["aud"], p.config.ClientID) {
+		p.Logger.ErrorLog.Printf("Id token of %s has unexpected audience", p.config.Name)
+		return Claims{}, ErrInvalidToken
+	}
+	if n, _ := claims["nonce"].(string); n == "" || n != nonce {
+		p.Logger.ErrorLog.Printf("Id token of %s has unexpected nonce", p.config.Name)
+		return Claims{}, ErrInvalidToken
+	}
+
+	result := Claims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Email, _ = claims["email"].(string)
+	result.Name, _ = claims["name"].(string)
+	// email_verifiedを文字列で返すプロバイダもある
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = v
+	case string:
+		result.EmailVerified = v == "true"
+	}
+	if result.Subject == "" {
+		p.Logger.ErrorLog.Printf("Id token of %s has no sub", p.config.Name)
+		return Claims{}, ErrInvalidToken
+	}
+	return result, nil
+}
+
+// audが文字列または配列でclient_idを含むか
+func audienceContains(aud interface{}, clientID string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == clientID
+	case []interface{}:
+		return slices.ContainsFunc(v, func(a interface{}) bool { return a == clientID })
+	}
+	return false
+}
+
+// 鍵IDの公開鍵(キャッシュにない場合はJWKSを取得し直す)
+func (p *Provider) key(ctx context.Context, md *metadata, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, md.JWKSURI, &jwks); err != nil {
+		p.Logger.ErrorLog.Printf("Failed to fetch jwks of %s: %v", p.config.Name, err)
+		return nil, ErrInvalidToken
+	}
+	keys := map[string]any{}
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	p.keys = keys
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return key, nil
+}
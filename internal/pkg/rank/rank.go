@@ -0,0 +1,146 @@
+package pkg_rank
+
+import (
+	"errors"
+	"strings"
+)
+
+// 並び順のキー(フラクショナルインデックス)
+// キーは62進数の小数部("0.xxx")を表す文字列で、バイト順に比較すると小数の大小と一致する
+// 末尾は"0"にしない(同じ値の別表記を作らないため)。DBでは COLLATE "C" で比較する
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// a と b の間のキー
+// a が空の場合は先頭、b が空の場合は末尾。a < b でない場合はエラー
+func Between(a, b string) (string, error) {
+	if !valid(a) || !valid(b) {
+		return "", errors.New("invalid key")
+	}
+	if a != "" && b != "" && a >= b {
+		return "", errors.New("keys are not ordered")
+	}
+
+	switch {
+	case b == "":
+		return after(a), nil
+	case a == "":
+		return before(b), nil
+	default:
+		return midpoint(a, b), nil
+	}
+}
+
+// 等間隔に並んだn個のキー(並び直し用)
+// 桁数はn個を区別できる最小の桁数にする
+func Spread(n int) []string {
+	width, size := 1, base
+	for size <= n {
+		width++
+		size *= base
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strings.TrimRight(encode((i+1)*size/(n+1), width), "0")
+	}
+	return keys
+}
+
+// キーの形式か(空はBetweenで先頭・末尾を表す)
+func valid(key string) bool {
+	if strings.HasSuffix(key, "0") {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// aより後のキー
+// 先頭の桁を1つ増やす(末尾に追加し続けても桁が増えにくい)
+func after(a string) string {
+	if a == "" {
+		return digits[base/2 : base/2+1]
+	}
+	d := strings.IndexByte(digits, a[0])
+	if d < base-1 {
+		return digits[d+1 : d+2]
+	}
+	return a[:1] + after(a[1:])
+}
+
+// bより前のキー
+// 先頭の桁を1つ減らす(先頭に追加し続けても桁が増えにくい)
+func before(b string) string {
+	d := strings.IndexByte(digits, b[0])
+	switch {
+	case d > 1:
+		return digits[d-1 : d]
+	case d == 1:
+		return "0" + digits[base/2:base/2+1]
+	default:
+		return "0" + before(b[1:])
+	}
+}
+
+// a < b の間のキー(bが空の場合は1)
+func midpoint(a, b string) string {
+	// 共通の先頭部分はそのまま使う(aが短い場合は"0"で埋めて比べる)
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := base
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return digits[(da+db)/2 : (da+db)/2+1]
+	}
+	// 先頭の桁が隣り合う場合
+	if b != "" && len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return digits[da:da+1] + midpoint(rest, "")
+}
+
+// n桁目(範囲外は"0")
+func digitAt(key string, n int) byte {
+	if n < len(key) {
+		return key[n]
+	}
+	return '0'
+}
+
+// 62進数でwidth桁に符号化する
+func encode(v int, width int) string {
+	b := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		b[i] = digits[v%base]
+		v /= base
+	}
+	return string(b)
+}
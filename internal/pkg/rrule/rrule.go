@@ -0,0 +1,210 @@
+package pkg_rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 繰り返しの単位
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+// BYDAYの曜日(Nは月・年の中での序数。0の場合は全ての該当する曜日、負の場合は末尾から)
+type WeekdayNum struct {
+	N       int
+	Weekday time.Weekday
+}
+
+// 繰り返しのルール(iCalendar RFC 5545 のRRULE)
+// 対応するのは FREQ(DAILY/WEEKLY/MONTHLY/YEARLY)・INTERVAL・COUNT・UNTIL・BYDAY・BYMONTHDAY・BYMONTH・WKST のみ
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int          // 0の場合は無制限
+	Until      time.Time    // ゼロ値の場合は無制限
+	ByDay      []WeekdayNum // 曜日
+	ByMonthDay []int        // 日(負の場合は月末から)
+	ByMonth    []int        // 月
+	WeekStart  time.Weekday // 週の始まり(既定は月曜)
+
+	// UNTILがタイムゾーンの無い日時("Z"無し)の場合はスケジュールのタイムゾーンで解釈する
+	untilFloating bool
+}
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RRULEの文字列を読み込む
+// 例: "FREQ=WEEKLY;BYDAY=MO,WE;UNTIL=20241231T235959Z"(先頭の"RRULE:"は省略できる)
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, errors.New("rrule is empty")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid rrule part: %q", part)
+		}
+		key = strings.ToUpper(key)
+		value = strings.ToUpper(value)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate rrule part: %s", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			freq, ok := frequencies[value]
+			if !ok {
+				return nil, fmt.Errorf("unsupported frequency: %s", value)
+			}
+			rule.Freq = freq
+		case "INTERVAL":
+			rule.Interval, err = parseInt(value, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseInt(value, 1, 10000)
+		case "UNTIL":
+			rule.Until, rule.untilFloating, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseIntList(value, -31, 31)
+		case "BYMONTH":
+			rule.ByMonth, err = parseIntList(value, 1, 12)
+		case "WKST":
+			weekday, ok := weekdays[value]
+			if !ok {
+				return nil, fmt.Errorf("invalid weekday: %s", value)
+			}
+			rule.WeekStart = weekday
+		default:
+			return nil, fmt.Errorf("unsupported rrule part: %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %v", key, err)
+		}
+	}
+
+	// BYMONTHは月の順に展開する
+	sort.Ints(rule.ByMonth)
+
+	if !seen["FREQ"] {
+		return nil, errors.New("FREQ is required")
+	}
+	if seen["COUNT"] && seen["UNTIL"] {
+		return nil, errors.New("COUNT and UNTIL cannot be used together")
+	}
+	for _, day := range rule.ByDay {
+		if day.N == 0 {
+			continue
+		}
+		// 序数は月の中で数える(年の中の序数は対応しない)
+		if rule.Freq == Daily || rule.Freq == Weekly {
+			return nil, errors.New("BYDAY with an ordinal requires FREQ=MONTHLY or FREQ=YEARLY")
+		}
+		if rule.Freq == Yearly && len(rule.ByMonth) == 0 {
+			return nil, errors.New("BYDAY with an ordinal requires BYMONTH for FREQ=YEARLY")
+		}
+	}
+	return rule, nil
+}
+
+// minからmaxの整数
+func parseInt(value string, min int, max int) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d is out of range", n)
+	}
+	return n, nil
+}
+
+// カンマ区切りの整数(0は不可)
+func parseIntList(value string, min int, max int) ([]int, error) {
+	values := []int{}
+	for _, v := range strings.Split(value, ",") {
+		n, err := parseInt(v, min, max)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, errors.New("0 is out of range")
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// カンマ区切りの曜日(序数付き、例: "MO,-1FR,2TU")
+func parseByDay(value string) ([]WeekdayNum, error) {
+	days := []WeekdayNum{}
+	for _, v := range strings.Split(value, ",") {
+		if len(v) < 2 {
+			return nil, fmt.Errorf("invalid weekday: %s", v)
+		}
+		weekday, ok := weekdays[v[len(v)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday: %s", v)
+		}
+		n := 0
+		if ordinal := v[:len(v)-2]; ordinal != "" {
+			var err error
+			n, err = parseInt(strings.TrimPrefix(ordinal, "+"), -5, 5)
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("invalid weekday: %s", v)
+			}
+		}
+		days = append(days, WeekdayNum{N: n, Weekday: weekday})
+	}
+	return days, nil
+}
+
+// UNTILの日時
+// "Z"付きはUTC、無いものと日付のみ(その日の終わりまで)はタイムゾーンの無い日時として返す
+func parseUntil(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("20060102T150405Z", value); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("20060102T150405", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, false, errors.New("must be a date or date-time")
+	}
+	return t.Add(24*time.Hour - time.Second), true, nil
+}
@@ -0,0 +1,247 @@
+package pkg_rrule
+
+import (
+	"errors"
+	"time"
+
+	// サーバーにタイムゾーンのデータが無くても同じ結果にする
+	_ "time/tzdata"
+)
+
+// 展開する期間(日・週・月・年)の数の上限
+// 一致する日が無いルール(例: 2月30日)でも止まるようにする
+const maxPeriods = 100000
+
+// 繰り返しの予定
+// 開始日時のタイムゾーンの日付・時刻(壁時計の時刻)で展開するため、夏時間の切り替えの前後でも同じ時刻になる
+// 切り替えで存在しない時刻は切り替えの分だけ後ろにずらし、2回ある時刻は早い方にする
+type Schedule struct {
+	rule  *Rule
+	start time.Time
+	until time.Time
+	loc   *time.Location
+}
+
+// タイムゾーン(IANAの名前)を読み込む
+// 空・"Local"はサーバーによって変わるため受け付けない
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errors.New("invalid timezone")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+	return loc, nil
+}
+
+// ルール・開始日時・タイムゾーンから予定を作成する
+// 開始日時以降でルールに一致する日時が予定になる(COUNTは開始日時から数える)
+func NewSchedule(rule *Rule, start time.Time, timezone string) (*Schedule, error) {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return nil, err
+	}
+	s := &Schedule{rule: rule, start: start.In(loc), until: rule.Until, loc: loc}
+	if rule.untilFloating {
+		u := rule.Until
+		s.until = wallTime(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), loc)
+	}
+	return s, nil
+}
+
+// from以降、untilより前の予定(時刻順)
+func (s *Schedule) Between(from time.Time, until time.Time) []time.Time {
+	times := []time.Time{}
+	s.each(func(t time.Time) bool {
+		if !t.Before(until) {
+			return false
+		}
+		if !t.Before(from) {
+			times = append(times, t)
+		}
+		return true
+	})
+	return times
+}
+
+// afterより後の最初の予定(無い場合はfalse)
+func (s *Schedule) Next(after time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	s.each(func(t time.Time) bool {
+		if t.After(after) {
+			next, found = t, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// 開始日時から順に予定をfnに渡す(fnがfalseを返すか、COUNT・UNTILに達するまで)
+func (s *Schedule) each(fn func(time.Time) bool) {
+	count := 0
+	hour, min, sec := s.start.Clock()
+	for period := 0; period < maxPeriods; period++ {
+		for _, date := range s.dates(period) {
+			t := wallTime(date.Year(), date.Month(), date.Day(), hour, min, sec, s.loc)
+			if t.Before(s.start) {
+				continue
+			}
+			if !s.until.IsZero() && t.After(s.until) {
+				return
+			}
+			if !fn(t) {
+				return
+			}
+			count++
+			if s.rule.Count > 0 && count >= s.rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// period番目の期間(日・週・月・年)の中で、ルールに一致する日付(日付順、UTCの0時で表す)
+func (s *Schedule) dates(period int) []time.Time {
+	r := s.rule
+	y, m, d := s.start.Date()
+	dates := []time.Time{}
+
+	switch r.Freq {
+	case Daily:
+		date := civil(y, m, d+period*r.Interval)
+		if s.matches(date) {
+			dates = append(dates, date)
+		}
+	case Weekly:
+		// 開始日を含む週(WKSTから始まる)から数える
+		offset := (int(s.start.Weekday()) - int(r.WeekStart) + 7) % 7
+		first := civil(y, m, d-offset+period*r.Interval*7)
+		for i := 0; i < 7; i++ {
+			date := first.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && date.Weekday() != s.start.Weekday() {
+				continue
+			}
+			if s.matches(date) {
+				dates = append(dates, date)
+			}
+		}
+	case Monthly:
+		first := civil(y, m+time.Month(period*r.Interval), 1)
+		if len(r.ByMonth) == 0 || contains(r.ByMonth, int(first.Month())) {
+			dates = s.monthDates(first)
+		}
+	case Yearly:
+		year := y + period*r.Interval
+		months := r.ByMonth
+		if len(months) == 0 {
+			if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+				months = []int{int(m)}
+			} else {
+				months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, month := range months {
+			dates = append(dates, s.monthDates(civil(year, time.Month(month), 1))...)
+		}
+	}
+	return dates
+}
+
+// firstの月の中で、ルールに一致する日付
+// BYMONTHDAY・BYDAYが無い場合は開始日と同じ日(その日が無い月は飛ばす)
+func (s *Schedule) monthDates(first time.Time) []time.Time {
+	n := daysIn(first)
+	if len(s.rule.ByMonthDay) == 0 && len(s.rule.ByDay) == 0 {
+		if day := s.start.Day(); day <= n {
+			return []time.Time{first.AddDate(0, 0, day-1)}
+		}
+		return nil
+	}
+
+	dates := []time.Time{}
+	for day := 1; day <= n; day++ {
+		date := first.AddDate(0, 0, day-1)
+		if s.matches(date) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// 日付がBYMONTH・BYMONTHDAY・BYDAYに一致するか(指定が無いものは一致とする)
+func (s *Schedule) matches(date time.Time) bool {
+	r := s.rule
+	if len(r.ByMonth) > 0 && !contains(r.ByMonth, int(date.Month())) {
+		return false
+	}
+
+	day, n := date.Day(), daysIn(date)
+	if len(r.ByMonthDay) > 0 && !contains(r.ByMonthDay, day) && !contains(r.ByMonthDay, day-n-1) {
+		return false
+	}
+
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if wd.Weekday != date.Weekday() {
+			continue
+		}
+		// 月の中で何番目の曜日か(先頭から・末尾から)
+		switch {
+		case wd.N == 0,
+			wd.N > 0 && (day-1)/7+1 == wd.N,
+			wd.N < 0 && (n-day)/7+1 == -wd.N:
+			return true
+		}
+	}
+	return false
+}
+
+// タイムゾーンlocの日付・時刻の日時
+// 夏時間の切り替えで2回ある時刻は早い方、存在しない時刻は切り替え前のオフセットで解釈する(切り替えの分だけ後ろにずれる)
+func wallTime(year int, month time.Month, day int, hour int, min int, sec int, loc *time.Location) time.Time {
+	naive := time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+
+	// 前後12時間のオフセットのどちらか(切り替えが無ければ同じ)
+	_, before := naive.Add(-12 * time.Hour).In(loc).Zone()
+	_, after := naive.Add(12 * time.Hour).In(loc).Zone()
+
+	var found time.Time
+	for _, offset := range []int{before, after} {
+		t := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, o := t.Zone(); o != offset {
+			continue
+		}
+		if found.IsZero() || t.Before(found) {
+			found = t
+		}
+	}
+	if !found.IsZero() {
+		return found
+	}
+	return naive.Add(-time.Duration(before) * time.Second).In(loc)
+}
+
+// 日付(UTCの0時で表す。範囲外の日は正規化する)
+func civil(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// 月の日数
+func daysIn(date time.Time) int {
+	return civil(date.Year(), date.Month()+1, 0).Day()
+}
+
+// スライスに値が含まれるか
+func contains(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
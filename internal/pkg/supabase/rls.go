@@ -0,0 +1,42 @@
+package pkg_supabase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// RLSのポリシーを適用するロール(Supabaseのログイン済みユーザーのロール)
+// ポリシーは migrations/010_row_level_security.sql を参照
+const AuthenticatedRole = "authenticated"
+
+// 認証済みのユーザーとしてトランザクションを実行する
+// request.jwt.claims とロールをトランザクション内だけ設定するため、プールの接続に設定は残らない
+// fnがエラーを返した場合はロールバックする。ユーザーが空の場合は実行しない
+func (c *SupabaseClient) AsUser(ctx context.Context, userId string, fn func(tx pgx.Tx) error) error {
+	if userId == "" {
+		return errors.New("user is not authenticated")
+	}
+	claims, err := json.Marshal(map[string]string{"sub": userId, "role": AuthenticatedRole})
+	if err != nil {
+		return err
+	}
+
+	// トランザクション開始(コミット後のRollbackは何もしない)
+	tx, err := c.Pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// SET LOCALと同じく、コミット・ロールバックで元に戻る
+	if _, err := tx.Exec(ctx, `SELECT set_config('request.jwt.claims', $1, true), set_config('role', $2, true)`, string(claims), AuthenticatedRole); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
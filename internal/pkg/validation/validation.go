@@ -0,0 +1,43 @@
+package pkg_validation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 項目ごとの入力エラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// 入力エラーのリスト
+// 1件以上ある場合はエラーとして扱う
+type Errors []FieldError
+
+// エラーを追加する
+func (e *Errors) Add(field string, format string, args ...any) {
+	*e = append(*e, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// エラーが1件以上あるか
+func (e Errors) HasErrors() bool {
+	return len(e) > 0
+}
+
+// errorインターフェースの実装
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Field + ": " + fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// レスポンスボディ(422 Unprocessable Entity)
+func (e Errors) Response() map[string]interface{} {
+	return map[string]interface{}{
+		"error":  "validation failed",
+		"errors": e,
+	}
+}
@@ -0,0 +1,18 @@
+package repository_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"time"
+)
+
+// パスワードの再設定・メールアドレスの確認のためのアカウントのリポジトリ(IF)
+type IAccountRepository interface {
+	// メールアドレスでアカウントを取得(大文字小文字は区別しない。ない場合は"user not found")
+	GetAccountByEmail(email string) (domain_auth.Account, error)
+	// IDでアカウントを取得(ない場合は"user not found")
+	GetAccountById(id string) (domain_auth.Account, error)
+	// パスワードを更新
+	UpdatePassword(userId string, password string) error
+	// メールアドレスを確認済みにする(メールアドレスが変わっている場合は"user not found")
+	VerifyEmail(userId string, email string, at time.Time) error
+}
@@ -0,0 +1,20 @@
+package repository_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"time"
+)
+
+// APIキーのリポジトリ(IF)
+type IAPIKeyRepository interface {
+	// APIキーを作成
+	CreateAPIKey(key domain_auth.APIKey) (domain_auth.APIKey, error)
+	// プレフィックスでAPIキーを取得(ない場合は"api key not found")
+	GetAPIKeyByPrefix(prefix string) (domain_auth.APIKey, error)
+	// ユーザーのAPIキーを取得
+	GetAPIKeysByUserId(userId string) ([]domain_auth.APIKey, error)
+	// ユーザーのAPIキーを失効させる(ない場合は"api key not found")
+	RevokeAPIKey(id string, userId string, at time.Time) error
+	// 最終使用日時を更新
+	TouchAPIKey(id string, at time.Time) error
+}
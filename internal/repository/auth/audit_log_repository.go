@@ -0,0 +1,9 @@
+package repository_auth
+
+import domain_auth "backend/internal/domain/auth"
+
+// 監査ログのリポジトリ(IF)
+type IAuditLogRepository interface {
+	// 監査ログを記録する
+	CreateAuditLog(log domain_auth.AuditLog) error
+}
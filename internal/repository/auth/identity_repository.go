@@ -0,0 +1,13 @@
+package repository_auth
+
+import domain_auth "backend/internal/domain/auth"
+
+// 外部のIDプロバイダのアカウントの紐付けのリポジトリ(IF)
+type IIdentityRepository interface {
+	// プロバイダとsubで紐付けを取得(ない場合は"identity not found")
+	GetIdentity(provider string, subject string) (domain_auth.Identity, error)
+	// メールアドレス(大文字・小文字を区別しない)でユーザーIDを取得(ない場合は"user not found")
+	GetUserIdByEmail(email string) (string, error)
+	// 紐付けを作成
+	CreateIdentity(identity domain_auth.Identity) error
+}
@@ -0,0 +1,13 @@
+package repository_auth
+
+import domain_auth "backend/internal/domain/auth"
+
+// ログインの失敗の記録のリポジトリ(IF)
+type ILoginAttemptRepository interface {
+	// キーの記録を取得する(ない場合はゼロ値)
+	Get(key string) (domain_auth.LoginFailures, error)
+	// キーの記録をfnで更新して保存し、更新後の記録を返す(同じキーの更新は排他的に行う)
+	Update(key string, fn func(f domain_auth.LoginFailures) domain_auth.LoginFailures) (domain_auth.LoginFailures, error)
+	// キーの記録を削除する
+	Reset(key string) error
+}
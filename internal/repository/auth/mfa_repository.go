@@ -0,0 +1,17 @@
+package repository_auth
+
+import domain_auth "backend/internal/domain/auth"
+
+// 2要素認証のリポジトリ(IF)
+type IMFARepository interface {
+	// ユーザーの2要素認証を取得(ない場合は"mfa not found")
+	GetMFA(userId string) (domain_auth.MFA, error)
+	// ユーザーの2要素認証を保存(既にある場合は置き換える)
+	SaveMFA(mfa domain_auth.MFA) error
+	// ユーザーの2要素認証を削除
+	DeleteMFA(userId string) error
+	// 最後に使ったTOTPのステップを更新する(stepが最後に使ったステップより後の場合のみ更新し、trueを返す)
+	UseStep(userId string, step int64) (bool, error)
+	// 未使用の回復コードを使用済みにする(未使用のコードがあった場合のみtrueを返す)
+	UseRecoveryCode(userId string, hash string) (bool, error)
+}
@@ -0,0 +1,7 @@
+package repository_auth
+
+// ユーザーのロールのリポジトリ(IF)
+type IRoleRepository interface {
+	// ユーザーの現在のロールを取得(ない場合は"user not found")
+	GetRole(userId string) (string, error)
+}
@@ -0,0 +1,22 @@
+package repository_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"time"
+)
+
+// ログインのセッションのリポジトリ(IF)
+type ISessionRepository interface {
+	// セッションを作成
+	CreateSession(session domain_auth.Session) (domain_auth.Session, error)
+	// セッションを取得(ない場合は"session not found")
+	GetSession(id string) (domain_auth.Session, error)
+	// ユーザーの有効なセッションを取得(最終使用日時の新しい順)
+	GetActiveSessions(userId string, now time.Time) ([]domain_auth.Session, error)
+	// ユーザーのセッションを失効させる(ない・失効済みの場合は"session not found")
+	RevokeSession(id string, userId string, at time.Time) error
+	// ユーザーの全てのセッションを失効させ、失効させた件数を返す
+	RevokeSessions(userId string, at time.Time) (int, error)
+	// 最終使用日時を更新
+	TouchSession(id string, at time.Time) error
+}
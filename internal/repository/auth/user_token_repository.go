@@ -0,0 +1,14 @@
+package repository_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"time"
+)
+
+// メールで送るトークンのリポジトリ(IF)
+type IUserTokenRepository interface {
+	// トークンを作成(同じユーザー・用途の未使用のトークンは使えなくする)
+	CreateUserToken(token domain_auth.UserToken) error
+	// 未使用・期限内のトークンを使用済みにして返す(ない場合は"token not found")
+	ConsumeUserToken(purpose domain_auth.TokenPurpose, hash string, now time.Time) (domain_auth.UserToken, error)
+}
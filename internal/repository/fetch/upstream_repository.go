@@ -0,0 +1,13 @@
+package repository_fetch
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+)
+
+// 上流APIリポジトリ(IF)
+type IUpstreamRepository interface {
+	// 上流APIにリクエストを送る
+	// 通信に失敗した場合はerrorを返す(4xx/5xxはエラーにしない)
+	Do(ctx context.Context, req domain_fetch.UpstreamRequest) (domain_fetch.UpstreamResponse, error)
+}
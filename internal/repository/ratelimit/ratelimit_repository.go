@@ -0,0 +1,15 @@
+package repository_ratelimit
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	"context"
+	"time"
+)
+
+// レート制限の状態のリポジトリ(IF)
+type IRateLimitRepository interface {
+	// キーの状態をfnで更新して保存し、更新後の状態を返す
+	// 同じキーの更新は排他的に行う(複数のインスタンスで共有する場合も含む)
+	// 状態がない、またはttlを過ぎている場合はゼロ値をfnに渡す
+	Update(ctx context.Context, key string, ttl time.Duration, fn func(s domain_ratelimit.State) domain_ratelimit.State) (domain_ratelimit.State, error)
+}
@@ -0,0 +1,29 @@
+package repository_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"time"
+)
+
+// 繰り返しのTodoリポジトリ(IF)
+// scopeを受け取る操作は、scopeのユーザーが所属するワークスペースに限る(作成・変更はオーナー・編集者のみ)
+// 予定のTodoの作成はスケジューラからも呼ぶため、ユーザーに依らず実行する
+type ITodoRecurrenceRepository interface {
+	// 繰り返しを作成(ワークスペースの指定が無い場合は個人用のワークスペースに作成する)
+	// 作成済みの日時は開始日時と現在の遅い方にする(過去の予定は作成しない)
+	CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, error)
+	// 特定の繰り返しを取得(参照できない場合は"recurrence not found")
+	GetRecurrenceById(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error)
+	// 予定のTodoの作成がuntilより前で止まっている繰り返しを取得(作成済みの日時・IDの順にlimit件まで)
+	// afterを指定した場合は、afterより後(作成済みの日時・IDの順)のもののみ取得する
+	GetPendingRecurrences(until time.Time, after domain_todo.Recurrence, limit int) ([]domain_todo.Recurrence, error)
+	// 予定のTodoを作成し、作成済みの日時をuntilまで進める
+	// 作成済みの予定・終了日時以降の予定は作成しない。作成したTodoを返す
+	MaterializeOccurrences(recurrence domain_todo.Recurrence, occurrences []time.Time, until time.Time) ([]domain_todo.Todo, error)
+	// 予定のTodoの説明・期日を変更
+	UpdateOccurrence(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error)
+	// 予定のTodoの日時で繰り返しを終了し、以降の予定の繰り返し(next)を作成して予定のTodoを移す
+	// 新しい繰り返しの作成済みの日時は、開始日時と現在の遅い方にする
+	// 元の繰り返しの以降の未完了の予定は削除する(繰り返しでないTodoの場合は"todo is not recurring")
+	SplitRecurrence(scope domain_todo.Scope, todoId string, next domain_todo.Recurrence) (domain_todo.Recurrence, domain_todo.Todo, error)
+}
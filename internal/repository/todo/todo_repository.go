@@ -5,17 +5,27 @@ import (
 )
 
 // Todoリポジトリ(IF)
+// 全ての操作はscopeのユーザーが所属するワークスペースのTodoに限る
+// 作成・更新・削除はオーナー・編集者のみ("permission denied")
 type ITodoRepository interface {
 	// 全てのTodoを取得
-	GetAllTodos() ([]domain_todo.Todo, error)
-	// 特定のTodoを取得
-	GetTodoById(id string) (domain_todo.Todo, error)
+	GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error)
+	// 特定のTodoを取得(参照できない場合は"todo not found")
+	GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error)
 	// 特定のユーザーのTodoを取得
-	GetTodoByUserId(userId string) ([]domain_todo.Todo, error)
-	// 新しいTodoを作成
-	CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error)
+	GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error)
+	// 新しいTodoを作成(ワークスペースの指定が無い場合は個人用のワークスペースに作成する)
+	CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error)
 	// 特定のTodoを更新
-	UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error)
-	// 特定のTodoを削除
-	DeleteTodo(id string) error
+	UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error)
+	// 特定のTodoを削除(子孫のTodoも削除する)
+	DeleteTodo(scope domain_todo.Scope, id string) error
+	// 特定のTodoと子孫のTodoを取得(参照できない場合は"todo not found")
+	GetTodoSubtree(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error)
+	// 特定のTodoの祖先のTodoを取得(親から順に)
+	GetTodoAncestors(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error)
+	// 特定のTodoの親と兄弟の中での位置を変更(親が子孫の場合は"cycle detected")
+	MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error)
+	// 複数のTodoの完了状態を変更
+	SetTodosCompleted(scope domain_todo.Scope, ids []string, completed bool) ([]domain_todo.Todo, error)
 }
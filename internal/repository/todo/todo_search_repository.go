@@ -0,0 +1,11 @@
+package repository_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+)
+
+// Todo検索リポジトリ(IF)
+type ITodoSearchRepository interface {
+	// ユーザーが参照できるTodoを全文検索
+	SearchTodos(scope domain_todo.Scope, query domain_todo.TodoSearchQuery) ([]domain_todo.TodoSearchResult, error)
+}
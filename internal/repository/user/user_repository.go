@@ -6,6 +6,6 @@ import (
 
 // ユーザーリポジトリ(IF)
 type IUserRepository interface {
-	// 全ユーザー取得
-	GetAllUsers() ([]domain_user.Users, error)
+	// 参照できる全ユーザー取得(自分と、同じワークスペースのメンバー)
+	GetAllUsers(userId string) ([]domain_user.Users, error)
 }
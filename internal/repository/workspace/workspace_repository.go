@@ -0,0 +1,33 @@
+package repository_workspace
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	"time"
+)
+
+// ワークスペースのリポジトリ(IF)
+type IWorkspaceRepository interface {
+	// ワークスペースを作成し、作成したユーザーをオーナーにする
+	CreateWorkspace(workspace domain_workspace.Workspace, ownerId string) (domain_workspace.Workspace, error)
+	// ワークスペースを取得(存在しない場合は"workspace not found")
+	GetWorkspace(id string) (domain_workspace.Workspace, error)
+	// ユーザーが所属するワークスペースを取得
+	GetMemberships(userId string) ([]domain_workspace.Membership, error)
+	// メンバーを取得(所属していない場合は"member not found")
+	GetMember(workspaceId string, userId string) (domain_workspace.Member, error)
+	// ワークスペースのメンバーを取得
+	GetMembers(workspaceId string) ([]domain_workspace.Member, error)
+	// メンバーを追加(既に所属している場合は"already a member")
+	AddMember(member domain_workspace.Member) (domain_workspace.Member, error)
+	// メンバーの役割を変更(所属していない場合は"member not found"、最後のオーナーを降格する場合は"workspace must have an owner")
+	UpdateMemberRole(workspaceId string, userId string, role domain_workspace.Role) error
+	// メンバーを削除(所属していない場合は"member not found"、最後のオーナーを削除する場合は"workspace must have an owner")
+	RemoveMember(workspaceId string, userId string) error
+	// 招待を作成
+	CreateInvitation(invitation domain_workspace.Invitation) (domain_workspace.Invitation, error)
+	// 未使用・期限内の招待を取得(該当しない場合は"invitation not found")
+	GetInvitation(tokenHash string, now time.Time) (domain_workspace.Invitation, error)
+	// 未使用・期限内の招待を使用済みにし、招待された役割でメンバーに追加する
+	// 該当しない場合は"invitation not found"、既に所属している場合は"already a member"(招待は使用済みにしない)
+	AcceptInvitation(tokenHash string, userId string, now time.Time) (domain_workspace.Member, error)
+}
@@ -1,27 +1,48 @@
 package router
 
 import (
+	"backend/config"
 	interfaces_auth "backend/internal/interfaces/auth"
 	interfaces_paralell "backend/internal/interfaces/paralell"
+	interfaces_ratelimit "backend/internal/interfaces/ratelimit"
 	interfaces_sample "backend/internal/interfaces/sample"
 	interfaces_search "backend/internal/interfaces/search"
+	interfaces_sort "backend/internal/interfaces/sort"
 	interfaces_todo "backend/internal/interfaces/todo"
 	interfaces_user "backend/internal/interfaces/user"
+	interfaces_workspace "backend/internal/interfaces/workspace"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
 )
 
 // ルーティングの設定
 func SetUpRouter(
 	e *echo.Echo,
+	appConfig *config.AppConfig,
 	sampleHandler *interfaces_sample.SampleHandler,
 	paralellHandler *interfaces_paralell.ParalellHandler,
+	aggregateHandler *interfaces_paralell.AggregateHandler,
 	userHandler *interfaces_user.UserHandler,
 	authHandler *interfaces_auth.AuthHandler,
+	apiKeyHandler *interfaces_auth.APIKeyHandler,
+	oidcHandler *interfaces_auth.OIDCHandler,
+	mfaHandler *interfaces_auth.MFAHandler,
+	sessionHandler *interfaces_auth.SessionHandler,
+	accountHandler *interfaces_auth.AccountHandler,
 	todoHandler *interfaces_todo.TodoHandler,
+	todoSearchHandler *interfaces_todo.TodoSearchHandler,
+	todoRecurrenceHandler *interfaces_todo.TodoRecurrenceHandler,
+	workspaceHandler *interfaces_workspace.WorkspaceHandler,
 	searchHandler *interfaces_search.SearchHandler,
+	graphHandler *interfaces_search.GraphHandler,
+	benchmarkHandler *interfaces_search.BenchmarkHandler,
+	mazeHandler *interfaces_search.MazeHandler,
+	sortHandler *interfaces_sort.SortHandler,
+	rateLimitHandler *interfaces_ratelimit.RateLimitHandler,
 ) {
-	api := e.Group("/api")
+	// ルートごとのポリシーでレート制限する
+	api := e.Group("/api", rateLimitHandler.Middleware)
 	{
 		sample := api.Group("/sample")
 		{
@@ -32,6 +53,9 @@ func SetUpRouter(
 		{
 			fetch.GET("/parallel", paralellHandler.ExecParallel)
 			fetch.GET("/series", paralellHandler.ExecSeries)
+			fetch.POST("", authHandler.AuthorizationMiddleware(paralellHandler.Fetch, "user"))
+			fetch.GET("/posts", aggregateHandler.Posts)
+			fetch.GET("/users", aggregateHandler.Users)
 		}
 		user := api.Group("/user")
 		{
@@ -40,22 +64,91 @@ func SetUpRouter(
 		todo := api.Group("/todo")
 		{
 			todo.GET("", authHandler.AuthorizationMiddleware(todoHandler.GetAllTodos, "user"))
+			todo.GET("/search", authHandler.AuthorizationMiddleware(todoSearchHandler.SearchTodos, "user"))
 			todo.GET("/:id", authHandler.AuthorizationMiddleware(todoHandler.GetTodoById, "user"))
 			todo.GET("/user", authHandler.AuthorizationMiddleware(todoHandler.GetTodoByUserId, "user"))
 			todo.POST("", authHandler.AuthorizationMiddleware(todoHandler.CreateTodo, "user"))
 			todo.PUT("/:id", authHandler.AuthorizationMiddleware(todoHandler.UpdateTodo, "user"))
 			todo.DELETE("/:id", authHandler.AuthorizationMiddleware(todoHandler.DeleteTodo, "user"))
+			todo.GET("/:id/tree", authHandler.AuthorizationMiddleware(todoHandler.GetTodoTree, "user"))
+			todo.POST("/:id/move", authHandler.AuthorizationMiddleware(todoHandler.MoveTodo, "user"))
+			todo.PUT("/:id/complete", authHandler.AuthorizationMiddleware(todoHandler.CompleteTodo, "user"))
+			todo.POST("/recurrences", authHandler.AuthorizationMiddleware(todoRecurrenceHandler.CreateRecurrence, "user"))
+			todo.GET("/recurrences/:id", authHandler.AuthorizationMiddleware(todoRecurrenceHandler.GetRecurrence, "user"))
+			todo.PUT("/:id/occurrence", authHandler.AuthorizationMiddleware(todoRecurrenceHandler.EditOccurrence, "user"))
 		}
-		search := api.Group("/search")
+		workspaces := api.Group("/workspaces")
+		{
+			workspaces.GET("", authHandler.AuthorizationMiddleware(workspaceHandler.GetWorkspaces, "user"))
+			workspaces.POST("", authHandler.AuthorizationMiddleware(workspaceHandler.CreateWorkspace, "user"))
+			workspaces.POST("/invitations/accept", authHandler.AuthorizationMiddleware(workspaceHandler.AcceptInvitation, "user"))
+			workspaces.POST("/:id/switch", authHandler.AuthorizationMiddleware(workspaceHandler.Switch, "user"))
+			workspaces.GET("/:id/members", authHandler.AuthorizationMiddleware(workspaceHandler.GetMembers, "user"))
+			workspaces.PUT("/:id/members/:userId", authHandler.AuthorizationMiddleware(workspaceHandler.UpdateMember, "user"))
+			workspaces.DELETE("/:id/members/:userId", authHandler.AuthorizationMiddleware(workspaceHandler.RemoveMember, "user"))
+			workspaces.POST("/:id/invitations", authHandler.AuthorizationMiddleware(workspaceHandler.Invite, "user"))
+		}
+		// 巨大なリクエストボディを受け付けない
+		search := api.Group("/search", middleware.BodyLimit(appConfig.SearchLimits.MaxBodySize))
 		{
 			search.POST("/linear", searchHandler.LinearSearch)
 			search.POST("/binary", searchHandler.BinarySearch)
 			search.POST("/bfs", searchHandler.BFS)
 			search.POST("/dfs", searchHandler.DFS)
+			search.POST("/dijkstra", searchHandler.Dijkstra)
+			search.POST("/astar", searchHandler.AStar)
+			search.POST("/benchmark", benchmarkHandler.Benchmark)
+
+			maze := search.Group("/maze")
+			{
+				maze.POST("/generate", mazeHandler.Generate)
+				maze.POST("/import", mazeHandler.Import)
+				maze.POST("/export", mazeHandler.Export)
+			}
+
+			graph := search.Group("/graph")
+			{
+				graph.POST("/bfs", graphHandler.BFS)
+				graph.POST("/dfs", graphHandler.DFS)
+				graph.POST("/components", graphHandler.ConnectedComponents)
+				graph.POST("/cycle", graphHandler.DetectCycle)
+				graph.POST("/toposort", graphHandler.TopologicalSort)
+				graph.POST("/scc", graphHandler.StronglyConnectedComponents)
+			}
+		}
+		// 大きな入力はハンドラ側でストリーミングして読み書きする
+		sort := api.Group("/sort", middleware.BodyLimit(appConfig.SortLimits.MaxBodySize))
+		{
+			sort.POST("/quick", sortHandler.QuickSort)
+			sort.POST("/merge", sortHandler.MergeSort)
+			sort.POST("/heap", sortHandler.HeapSort)
+			sort.POST("/insertion", sortHandler.InsertionSort)
+			sort.POST("/radix", sortHandler.RadixSort)
+			sort.POST("/tim", sortHandler.TimSort)
 		}
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
+			auth.POST("/unlock", authHandler.AuthorizationMiddleware(authHandler.Unlock, "admin"))
+			auth.POST("/api-keys", authHandler.AuthorizationMiddleware(apiKeyHandler.CreateAPIKey, "user"))
+			auth.GET("/api-keys", authHandler.AuthorizationMiddleware(apiKeyHandler.GetAPIKeys, "user"))
+			auth.DELETE("/api-keys/:id", authHandler.AuthorizationMiddleware(apiKeyHandler.RevokeAPIKey, "user"))
+			auth.GET("/oidc", oidcHandler.Providers)
+			auth.GET("/oidc/:provider/login", oidcHandler.Login)
+			auth.GET("/oidc/:provider/callback", oidcHandler.Callback)
+			auth.POST("/mfa/enroll", authHandler.AuthorizationMiddleware(mfaHandler.Enroll, "user"))
+			auth.POST("/mfa/confirm", authHandler.AuthorizationMiddleware(mfaHandler.Confirm, "user"))
+			auth.POST("/mfa/recovery-codes", authHandler.AuthorizationMiddleware(mfaHandler.RegenerateRecoveryCodes, "user"))
+			auth.POST("/mfa/verify", mfaHandler.Verify)
+			auth.DELETE("/mfa/:userId", authHandler.AuthorizationMiddleware(mfaHandler.Reset, "admin"))
+			// ログイン中の端末(セッション)
+			auth.GET("/sessions", authHandler.AuthorizationMiddleware(sessionHandler.GetSessions, "user"))
+			auth.DELETE("/sessions", authHandler.AuthorizationMiddleware(sessionHandler.RevokeAllSessions, "user"))
+			auth.DELETE("/sessions/:id", authHandler.AuthorizationMiddleware(sessionHandler.RevokeSession, "user"))
+			auth.POST("/password/forgot", accountHandler.ForgotPassword)
+			auth.POST("/password/reset", accountHandler.ResetPassword)
+			auth.POST("/email/verification", authHandler.AuthorizationMiddleware(accountHandler.RequestEmailVerification, "user"))
+			auth.POST("/email/verify", accountHandler.VerifyEmail)
 		}
 	}
 }
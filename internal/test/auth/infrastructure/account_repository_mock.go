@@ -0,0 +1,49 @@
+package test_auth_repository
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのアカウントのリポジトリ作成
+type MockAccountRepository struct {
+	mock.Mock
+}
+
+// GetAccountByEmailのモック
+func (m *MockAccountRepository) GetAccountByEmail(email string) (domain_auth.Account, error) {
+	args := m.Called(email)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.Account{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_auth.Account), args.Error(1)
+}
+
+// GetAccountByIdのモック
+func (m *MockAccountRepository) GetAccountById(id string) (domain_auth.Account, error) {
+	args := m.Called(id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.Account{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_auth.Account), args.Error(1)
+}
+
+// UpdatePasswordのモック
+func (m *MockAccountRepository) UpdatePassword(userId string, password string) error {
+	args := m.Called(userId, password)
+	return args.Error(0)
+}
+
+// VerifyEmailのモック
+func (m *MockAccountRepository) VerifyEmail(userId string, email string, at time.Time) error {
+	args := m.Called(userId, email, at)
+	return args.Error(0)
+}
@@ -0,0 +1,18 @@
+package test_auth_repository
+
+import (
+	domain_auth "backend/internal/domain/auth"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの監査ログのリポジトリ作成
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+// CreateAuditLogのモック
+func (m *MockAuditLogRepository) CreateAuditLog(log domain_auth.AuditLog) error {
+	args := m.Called(log)
+	return args.Error(0)
+}
@@ -0,0 +1,36 @@
+package test_auth_repository
+
+import (
+	domain_auth "backend/internal/domain/auth"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの紐付けのリポジトリ作成
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+// GetIdentityのモック
+func (m *MockIdentityRepository) GetIdentity(provider string, subject string) (domain_auth.Identity, error) {
+	args := m.Called(provider, subject)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.Identity{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_auth.Identity), args.Error(1)
+}
+
+// GetUserIdByEmailのモック
+func (m *MockIdentityRepository) GetUserIdByEmail(email string) (string, error) {
+	args := m.Called(email)
+	return args.String(0), args.Error(1)
+}
+
+// CreateIdentityのモック
+func (m *MockIdentityRepository) CreateIdentity(identity domain_auth.Identity) error {
+	args := m.Called(identity)
+	return args.Error(0)
+}
@@ -0,0 +1,16 @@
+package test_auth_repository
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのユーザーのロールのリポジトリ作成
+type MockRoleRepository struct {
+	mock.Mock
+}
+
+// GetRoleのモック
+func (m *MockRoleRepository) GetRole(userId string) (string, error) {
+	args := m.Called(userId)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,94 @@
+package test_auth_handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Accept-Language付きのリクエストを送る
+func callWithLanguage(target, body, acceptLanguage string, h echo.HandlerFunc, values map[string]any) *httptest.ResponseRecorder {
+	e := echo.New()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", acceptLanguage)
+	ctx := e.NewContext(request, response)
+	for k, v := range values {
+		ctx.Set(k, v)
+	}
+	h(ctx)
+	return response
+}
+
+// ForgotPasswordのテスト
+// 登録の有無に関わらず202を返す。言語はボディの指定を優先する
+func TestForgotPassword(t *testing.T) {
+	mockAccountUsecase.ExpectedCalls = nil
+	mockAccountUsecase.On("RequestPasswordReset", mock.Anything, "alice@example.com", "en-US").Return(nil)
+	mockAccountUsecase.On("RequestPasswordReset", mock.Anything, "bob@example.com", "ja").Return(nil)
+	mockAccountUsecase.On("RequestPasswordReset", mock.Anything, "bad", "").Return(errors.New("invalid email format"))
+
+	response := callWithLanguage("/api/auth/password/forgot", `{"email": "alice@example.com"}`, "en-US", accountHandler.ForgotPassword, nil)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	response = callWithLanguage("/api/auth/password/forgot", `{"email": "bob@example.com", "lang": "ja"}`, "en-US", accountHandler.ForgotPassword, nil)
+	assert.Equal(t, http.StatusAccepted, response.Code)
+
+	response = call("/api/auth/password/forgot", `{"email": "bad"}`, accountHandler.ForgotPassword, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = call("/api/auth/password/forgot", `{}`, accountHandler.ForgotPassword, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	mockAccountUsecase.AssertExpectations(t)
+}
+
+// ResetPasswordのテスト
+func TestResetPassword(t *testing.T) {
+	mockAccountUsecase.ExpectedCalls = nil
+	mockAccountUsecase.Calls = nil
+	mockAccountUsecase.On("ResetPassword", "token-1", "new-password").Return(nil)
+	mockAccountUsecase.On("ResetPassword", "token-2", "new-password").Return(errors.New("invalid or expired token"))
+
+	response := call("/api/auth/password/reset", `{"token": "token-1", "password": "new-password"}`, accountHandler.ResetPassword, nil)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = call("/api/auth/password/reset", `{"token": "token-2", "password": "new-password"}`, accountHandler.ResetPassword, nil)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.JSONEq(t, `{"message":"Invalid or expired token"}`, response.Body.String())
+
+	response = call("/api/auth/password/reset", `{"password": "short"}`, accountHandler.ResetPassword, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"token"`)
+	assert.Contains(t, response.Body.String(), `must be at least 8 characters`)
+	mockAccountUsecase.AssertNumberOfCalls(t, "ResetPassword", 2)
+}
+
+// RequestEmailVerificationのテスト
+func TestRequestEmailVerification(t *testing.T) {
+	mockAccountUsecase.ExpectedCalls = nil
+	mockAccountUsecase.On("RequestEmailVerification", mock.Anything, "user-1", "en").Return(nil)
+	mockAccountUsecase.On("RequestEmailVerification", mock.Anything, "user-2", "").Return(errors.New("email is already verified"))
+
+	response := callWithLanguage("/api/auth/email/verification", `{}`, "en", accountHandler.RequestEmailVerification, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusAccepted, response.Code)
+	response = call("/api/auth/email/verification", `{}`, accountHandler.RequestEmailVerification, map[string]any{"userId": "user-2"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+	mockAccountUsecase.AssertExpectations(t)
+}
+
+// VerifyEmailのテスト
+func TestVerifyEmail(t *testing.T) {
+	mockAccountUsecase.ExpectedCalls = nil
+	mockAccountUsecase.On("VerifyEmail", "token-1").Return(nil)
+	mockAccountUsecase.On("VerifyEmail", "token-2").Return(errors.New("invalid or expired token"))
+
+	assert.Equal(t, http.StatusNoContent, call("/api/auth/email/verify", `{"token": "token-1"}`, accountHandler.VerifyEmail, nil).Code)
+	assert.Equal(t, http.StatusBadRequest, call("/api/auth/email/verify", `{"token": "token-2"}`, accountHandler.VerifyEmail, nil).Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, call("/api/auth/email/verify", `{}`, accountHandler.VerifyEmail, nil).Code)
+	mockAccountUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,175 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 認証ミドルウェアを通してリクエストを送る
+func callProtected(header map[string]string, requiredRole string) (*httptest.ResponseRecorder, echo.Context) {
+	e := echo.New()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", "/api/todo", nil)
+	for k, v := range header {
+		request.Header.Set(k, v)
+	}
+	ctx := e.NewContext(request, response)
+	var reached echo.Context
+	next := func(c echo.Context) error {
+		reached = c
+		return c.NoContent(http.StatusOK)
+	}
+	handler.AuthorizationMiddleware(next, requiredRole)(ctx)
+	return response, reached
+}
+
+// AuthorizationMiddlewareのテスト(APIキー)
+func TestAuthorizationMiddlewareAPIKey(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("Authenticate", "bk_valid").Return(domain_auth.APIKey{ID: "key-1", UserID: "user-1", Scopes: []string{"user"}}, nil)
+
+	response, c := callProtected(map[string]string{"X-API-Key": "bk_valid"}, "user")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "user-1", c.Get("userId"))
+	assert.Equal(t, "user", c.Get("role"))
+	assert.Equal(t, "key-1", c.Get("apiKeyId"))
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
+
+// AuthorizationMiddlewareのテスト(APIキー - 権限がない)
+func TestAuthorizationMiddlewareAPIKeyScope(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("Authenticate", "bk_valid").Return(domain_auth.APIKey{ID: "key-1", UserID: "user-1", Scopes: []string{"user"}}, nil)
+
+	response, c := callProtected(map[string]string{"X-API-Key": "bk_valid"}, "admin")
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	assert.Nil(t, c)
+}
+
+// AuthorizationMiddlewareのテスト(APIキー - 不正)
+func TestAuthorizationMiddlewareAPIKeyInvalid(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("Authenticate", "bk_invalid").Return(nil, errors.New("invalid api key"))
+
+	response, c := callProtected(map[string]string{"X-API-Key": "bk_invalid"}, "user")
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.JSONEq(t, `{"message":"Invalid API key"}`, response.Body.String())
+	assert.Nil(t, c)
+}
+
+// AuthorizationMiddlewareのテスト(認証情報がない)
+func TestAuthorizationMiddlewareMissing(t *testing.T) {
+	response, c := callProtected(nil, "user")
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.Nil(t, c)
+}
+
+// CreateAPIKeyのテスト(正常系 - 既定値)
+func TestCreateAPIKey(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	matchesDefaults := mock.MatchedBy(func(req domain_auth.CreateAPIKeyRequest) bool {
+		return req.Name == "ci" && req.Kind == domain_auth.APIKeyPersonal && len(req.Scopes) == 1 && req.Scopes[0] == "user" &&
+			req.ExpiresAt != nil && req.ExpiresAt.After(time.Now())
+	})
+	mockAPIKeyUsecase.On("CreateAPIKey", "user-1", matchesDefaults).Return(domain_auth.APIKey{ID: "key-1", Prefix: "abcd1234", Hash: "secret-hash"}, "bk_abcd1234_secret", nil)
+
+	response := call("/api/auth/api-keys", `{"name": "ci"}`, apiKeyHandler.CreateAPIKey, map[string]any{"userId": "user-1", "role": "user"})
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Equal(t, "bk_abcd1234_secret", body["key"])
+	// ハッシュは返さない
+	assert.NotContains(t, response.Body.String(), "secret-hash")
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
+
+// CreateAPIKeyのテスト(異常系 - 入力が不正)
+func TestCreateAPIKeyErrorValidation(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.Calls = nil
+
+	bodies := []string{
+		`{}`,
+		`{"name": "ci", "kind": "robot"}`,
+		`{"name": "ci", "scopes": ["root"]}`,
+		`{"name": "ci", "expires_at": "2000-01-01T00:00:00Z"}`,
+		`{"name": "ci", "expires_at": "` + time.Now().Add(400*24*time.Hour).Format(time.RFC3339) + `"}`,
+	}
+	for _, body := range bodies {
+		response := call("/api/auth/api-keys", body, apiKeyHandler.CreateAPIKey, map[string]any{"userId": "user-1", "role": "user"})
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code, body)
+	}
+	mockAPIKeyUsecase.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+// CreateAPIKeyのテスト(異常系 - APIキーでの操作)
+func TestCreateAPIKeyErrorWithAPIKey(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.Calls = nil
+
+	response := call("/api/auth/api-keys", `{"name": "ci"}`, apiKeyHandler.CreateAPIKey, map[string]any{"userId": "user-1", "role": "user", "apiKeyId": "key-1"})
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	mockAPIKeyUsecase.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+}
+
+// CreateAPIKeyのテスト(異常系 - 権限を超える)
+func TestCreateAPIKeyErrorPermissions(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("CreateAPIKey", "user-1", mock.Anything).Return(nil, "", errors.New("insufficient permissions"))
+
+	response := call("/api/auth/api-keys", `{"name": "ci", "scopes": ["admin"]}`, apiKeyHandler.CreateAPIKey, map[string]any{"userId": "user-1", "role": "user"})
+
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
+
+// GetAPIKeysのテスト(正常系)
+func TestGetAPIKeys(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("GetAPIKeys", "user-1").Return([]domain_auth.APIKey{{ID: "key-1", Name: "ci"}}, nil)
+
+	e := echo.New()
+	response := httptest.NewRecorder()
+	ctx := e.NewContext(httptest.NewRequest("GET", "/api/auth/api-keys", nil), response)
+	ctx.Set("userId", "user-1")
+	apiKeyHandler.GetAPIKeys(ctx)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, strings.Contains(response.Body.String(), `"name":"ci"`))
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
+
+// RevokeAPIKeyのテスト
+func TestRevokeAPIKey(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("RevokeAPIKey", "user-1", "key-1").Return(nil)
+	mockAPIKeyUsecase.On("RevokeAPIKey", "user-1", "key-2").Return(errors.New("api key not found"))
+
+	for id, status := range map[string]int{"key-1": http.StatusNoContent, "key-2": http.StatusNotFound} {
+		e := echo.New()
+		response := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest("DELETE", "/api/auth/api-keys/"+id, nil), response)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		ctx.Set("userId", "user-1")
+		apiKeyHandler.RevokeAPIKey(ctx)
+		assert.Equal(t, status, response.Code, id)
+	}
+	mockAPIKeyUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,88 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// リクエストを送ってレスポンスを返す
+func call(target, body string, h echo.HandlerFunc, values map[string]any) *httptest.ResponseRecorder {
+	e := echo.New()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("POST", target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	ctx := e.NewContext(request, response)
+	for k, v := range values {
+		ctx.Set(k, v)
+	}
+	h(ctx)
+	return response
+}
+
+// Loginのテスト(異常系 - 認証情報が一致しない)
+func TestLoginErrorInvalidCredentials(t *testing.T) {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Login", "test@example.com", "wrong", "192.0.2.1").Return("", errors.New("invalid email or password"))
+
+	response := call("/api/auth/login", `{"email": "test@example.com", "password": "wrong"}`, handler.Login, nil)
+
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+	assert.JSONEq(t, `{"message":"Invalid email or password"}`, response.Body.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+// Loginのテスト(異常系 - ロック中)
+func TestLoginErrorLocked(t *testing.T) {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Login", "test@example.com", "password", "192.0.2.1").Return("", &domain_auth.LockedError{RetryAt: time.Now().Add(90 * time.Second)})
+
+	response := call("/api/auth/login", `{"email": "test@example.com", "password": "password"}`, handler.Login, nil)
+
+	assert.Equal(t, http.StatusTooManyRequests, response.Code)
+	assert.JSONEq(t, `{"message":"Too many failed attempts"}`, response.Body.String())
+	assert.Equal(t, "90", response.Header().Get("Retry-After"))
+	mockUsecase.AssertExpectations(t)
+}
+
+// Unlockのテスト(正常系)
+func TestUnlock(t *testing.T) {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Unlock", "test@example.com", "198.51.100.1", "admin-1").Return(nil)
+
+	response := call("/api/auth/unlock", `{"email": "test@example.com", "ip": "198.51.100.1"}`, handler.Unlock, map[string]any{"userId": "admin-1"})
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockUsecase.AssertExpectations(t)
+}
+
+// Unlockのテスト(異常系 - 入力が不正)
+func TestUnlockErrorValidation(t *testing.T) {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.Calls = nil
+
+	for _, body := range []string{`{}`, `{"ip": "not-an-ip"}`} {
+		response := call("/api/auth/unlock", body, handler.Unlock, nil)
+		assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	}
+	mockUsecase.AssertNotCalled(t, "Unlock", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Unlockのテスト(異常系 - 失敗)
+func TestUnlockError(t *testing.T) {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Unlock", "test@example.com", "", "").Return(errors.New("failed to unlock"))
+
+	response := call("/api/auth/unlock", `{"email": "test@example.com"}`, handler.Unlock, nil)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	mockUsecase.AssertExpectations(t)
+}
@@ -0,0 +1,86 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ログインしてアクセストークンを取得する(セッションにはroleを設定する)
+func loginAs(t *testing.T, userId string, role string) string {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.On("Create", userId, mock.Anything, mock.Anything).Return(domain_auth.Session{ID: "session-" + userId, UserID: userId, Role: role}, nil)
+	mockSessionUsecase.On("Validate", "session-"+userId, userId).Return(nil).Maybe()
+	resetMFAMock()
+
+	token, _ := loginBody(t, userId)["token"].(string)
+	assert.NotEmpty(t, token)
+	return token
+}
+
+// 管理者用のルートを本番と同じミドルウェアを通して呼び出す
+func callAdmin(method string, target string, body string, token string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.POST("/api/auth/unlock", handler.AuthorizationMiddleware(handler.Unlock, "admin"))
+	e.DELETE("/api/auth/mfa/:userId", handler.AuthorizationMiddleware(mfaHandler.Reset, "admin"))
+
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+token)
+	e.ServeHTTP(response, request)
+	return response
+}
+
+// 管理者のロックの解除(ログインで発行したトークンで管理者用のルートを使える)
+func TestUnlockAsAdmin(t *testing.T) {
+	defer resetSessionMock()
+	token := loginAs(t, "admin-1", domain_auth.RoleAdmin)
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Unlock", "test@example.com", "", "admin-1").Return(nil)
+
+	response := callAdmin("POST", "/api/auth/unlock", `{"email": "test@example.com"}`, token)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockUsecase.AssertExpectations(t)
+
+	// 管理者は一般ユーザーのルートも使える
+	response, c := callProtected(map[string]string{"Authorization": "Bearer " + token}, "user")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, domain_auth.RoleAdmin, c.Get("role"))
+}
+
+// 管理者の2要素認証のリセット
+func TestMFAResetAsAdmin(t *testing.T) {
+	defer resetSessionMock()
+	token := loginAs(t, "admin-1", domain_auth.RoleAdmin)
+	mockMFAUsecase.On("Reset", "user-2", "admin-1").Return(nil)
+
+	response := callAdmin("DELETE", "/api/auth/mfa/user-2", "", token)
+
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// 一般ユーザーのトークンでは管理者用のルートを使えない
+func TestAdminRoutesAsUser(t *testing.T) {
+	defer resetSessionMock()
+	token := loginAs(t, "user-1", domain_auth.RoleUser)
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.Calls = nil
+
+	response := callAdmin("POST", "/api/auth/unlock", `{"email": "test@example.com"}`, token)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = callAdmin("DELETE", "/api/auth/mfa/user-2", "", token)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	mockUsecase.AssertNotCalled(t, "Unlock", mock.Anything, mock.Anything, mock.Anything)
+	mockMFAUsecase.AssertNotCalled(t, "Reset", mock.Anything, mock.Anything)
+}
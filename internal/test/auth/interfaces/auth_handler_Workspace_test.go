@@ -0,0 +1,73 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	interfaces_auth "backend/internal/interfaces/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// AuthorizationMiddlewareのテスト(操作中のワークスペース)
+func TestAuthorizationMiddlewareWorkspace(t *testing.T) {
+	resetSessionMock()
+	exp := time.Now().Add(time.Hour).Unix()
+	plain := signedToken(t, jwt.MapClaims{"id": "user-1", "sid": "session-1", "role": "user", "exp": exp})
+	withWid := signedToken(t, jwt.MapClaims{"id": "user-1", "sid": "session-1", "role": "user", "wid": "ws-token", "exp": exp})
+
+	tests := map[string]struct {
+		header    map[string]string
+		workspace any
+	}{
+		"none":   {map[string]string{"Authorization": "Bearer " + plain}, nil},
+		"claim":  {map[string]string{"Authorization": "Bearer " + withWid}, "ws-token"},
+		"header": {map[string]string{"Authorization": "Bearer " + plain, interfaces_auth.WorkspaceHeader: "ws-header"}, "ws-header"},
+		// ヘッダはトークンのwidより優先する
+		"both": {map[string]string{"Authorization": "Bearer " + withWid, interfaces_auth.WorkspaceHeader: "ws-header"}, "ws-header"},
+	}
+	for name, tt := range tests {
+		response, c := callProtected(tt.header, "user")
+		assert.Equal(t, http.StatusOK, response.Code, name)
+		assert.Equal(t, tt.workspace, c.Get("workspaceId"), name)
+	}
+}
+
+// AuthorizationMiddlewareのテスト(APIキーはヘッダでワークスペースを指定する)
+func TestAuthorizationMiddlewareWorkspaceAPIKey(t *testing.T) {
+	mockAPIKeyUsecase.ExpectedCalls = nil
+	mockAPIKeyUsecase.On("Authenticate", "bk_valid").Return(domain_auth.APIKey{ID: "key-1", UserID: "user-1", Scopes: []string{"user"}}, nil)
+
+	response, c := callProtected(map[string]string{"X-API-Key": "bk_valid", interfaces_auth.WorkspaceHeader: "ws-1"}, "user")
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "ws-1", c.Get("workspaceId"))
+}
+
+// WorkspaceTokenのテスト(セッションと有効期限を引き継いでwidを設定する)
+func TestWorkspaceToken(t *testing.T) {
+	resetSessionMock()
+	exp := time.Now().Add(time.Hour).Unix()
+	request := httptest.NewRequest("POST", "/api/workspaces/ws-1/switch", nil)
+	request.Header.Set("Authorization", "Bearer "+signedToken(t, jwt.MapClaims{"id": "user-1", "sid": "session-1", "role": "user", "exp": exp}))
+	c := echo.New().NewContext(request, httptest.NewRecorder())
+
+	token, err := handler.WorkspaceToken(c, "ws-1")
+	assert.NoError(t, err)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) { return []byte("secret"), nil })
+	assert.NoError(t, err)
+	assert.Equal(t, "ws-1", claims["wid"])
+	assert.Equal(t, "session-1", claims["sid"])
+	assert.Equal(t, float64(exp), claims["exp"])
+
+	// 発行し直したトークンで認証するとワークスペースが設定される
+	response, reached := callProtected(map[string]string{"Authorization": "Bearer " + token}, "user")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "ws-1", reached.Get("workspaceId"))
+}
@@ -14,6 +14,21 @@ var (
 	logger      *pkg_logger.AppLogger
 	handler     *interfaces_auth.AuthHandler
 	mockUsecase *test_auth_usecase.MockAuthUsecase
+
+	apiKeyHandler     *interfaces_auth.APIKeyHandler
+	mockAPIKeyUsecase *test_auth_usecase.MockAPIKeyUsecase
+
+	oidcHandler     *interfaces_auth.OIDCHandler
+	mockOIDCUsecase *test_auth_usecase.MockOIDCUsecase
+
+	mfaHandler     *interfaces_auth.MFAHandler
+	mockMFAUsecase *test_auth_usecase.MockMFAUsecase
+
+	accountHandler     *interfaces_auth.AccountHandler
+	mockAccountUsecase *test_auth_usecase.MockAccountUsecase
+
+	sessionHandler     *interfaces_auth.SessionHandler
+	mockSessionUsecase *test_auth_usecase.MockSessionUsecase
 )
 
 // テストのメイン関数
@@ -28,7 +43,19 @@ func TestMain(m *testing.M) {
 
 	// モック
 	mockUsecase = new(test_auth_usecase.MockAuthUsecase)
-	handler = interfaces_auth.NewAuthHandler(logger, mockUsecase)
+	mockAPIKeyUsecase = new(test_auth_usecase.MockAPIKeyUsecase)
+	mockMFAUsecase = new(test_auth_usecase.MockMFAUsecase)
+	resetMFAMock()
+	mockSessionUsecase = new(test_auth_usecase.MockSessionUsecase)
+	resetSessionMock()
+	handler = interfaces_auth.NewAuthHandler(logger, mockUsecase, mockAPIKeyUsecase, mockMFAUsecase, mockSessionUsecase)
+	apiKeyHandler = interfaces_auth.NewAPIKeyHandler(appConfig, logger, mockAPIKeyUsecase)
+	mockOIDCUsecase = new(test_auth_usecase.MockOIDCUsecase)
+	oidcHandler = interfaces_auth.NewOIDCHandler(logger, mockOIDCUsecase, mockMFAUsecase, mockSessionUsecase)
+	mfaHandler = interfaces_auth.NewMFAHandler(logger, mockMFAUsecase, mockSessionUsecase)
+	mockAccountUsecase = new(test_auth_usecase.MockAccountUsecase)
+	accountHandler = interfaces_auth.NewAccountHandler(logger, mockAccountUsecase)
+	sessionHandler = interfaces_auth.NewSessionHandler(logger, mockSessionUsecase)
 
 	// テスト実行
 	code := m.Run()
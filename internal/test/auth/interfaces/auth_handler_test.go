@@ -22,7 +22,7 @@ func TestLogin(t *testing.T) {
 	password := "password"
 
 	// モックの挙動を設定
-	mockUsecase.On("Login", email, password).Return(userId, nil)
+	mockUsecase.On("Login", email, password, "192.0.2.1").Return(userId, nil)
 
 	// リクエストボディの作成
 	requestBody := `{"email": "test@example.com", "password": "password"}`
@@ -56,7 +56,7 @@ func TestLoginErrorEmailEmpty(t *testing.T) {
 	password := "password"
 
 	// モックの挙動を設定
-	mockUsecase.On("Login", email, password).Return("", errors.New("error"))
+	mockUsecase.On("Login", email, password, "192.0.2.1").Return("", errors.New("error"))
 
 	// リクエストボディの作成
 	requestBody := `{"email": "", "password": "password"}`
@@ -90,7 +90,7 @@ func TestLoginErrorPasswordEmpty(t *testing.T) {
 	password := ""
 
 	// モックの挙動を設定
-	mockUsecase.On("Login", email, password).Return("", errors.New("error"))
+	mockUsecase.On("Login", email, password, "192.0.2.1").Return("", errors.New("error"))
 
 	// リクエストボディの作成
 	requestBody := `{"email": "test@example.com", "password": ""}`
@@ -124,7 +124,7 @@ func TestLoginError(t *testing.T) {
 	password := "password"
 
 	// モックの挙動を設定
-	mockUsecase.On("Login", email, password).Return("", errors.New("error"))
+	mockUsecase.On("Login", email, password, "192.0.2.1").Return("", errors.New("error"))
 
 	// リクエストボディの作成
 	requestBody := `{"email": "test@example.com", "password": "password"}`
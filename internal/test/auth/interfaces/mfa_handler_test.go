@@ -0,0 +1,194 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 2要素認証のモックをリセットする
+// mfaUsersのみ2要素認証が有効なユーザーとして扱う
+func resetMFAMock(mfaUsers ...string) {
+	mockMFAUsecase.ExpectedCalls = nil
+	mockMFAUsecase.Calls = nil
+	for _, userId := range mfaUsers {
+		mockMFAUsecase.On("Required", userId).Return(true, nil)
+	}
+	mockMFAUsecase.On("Required", mock.Anything).Return(false, nil).Maybe()
+}
+
+// ログインしてレスポンスのボディを返す
+func loginBody(t *testing.T, userId string) map[string]any {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Login", "test@example.com", "password", "192.0.2.1").Return(userId, nil)
+
+	response := call("/api/auth/login", `{"email": "test@example.com", "password": "password"}`, handler.Login, nil)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	body := map[string]any{}
+	if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+		t.FailNow()
+	}
+	return body
+}
+
+// Loginのテスト(2要素認証が有効な場合はチャレンジを返す)
+func TestLoginMFARequired(t *testing.T) {
+	resetMFAMock("user-mfa")
+
+	body := loginBody(t, "user-mfa")
+	assert.Equal(t, true, body["mfa_required"])
+	assert.NotContains(t, body, "token")
+	mfaToken, _ := body["mfa_token"].(string)
+	assert.NotEmpty(t, mfaToken)
+
+	// チャレンジトークンはアクセストークンとして使えない
+	response, _ := callProtected(map[string]string{"Authorization": "Bearer " + mfaToken}, "user")
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// コードで答えるとアクセストークンを発行する
+	mockMFAUsecase.On("Verify", "user-mfa", "123456").Return(nil)
+	response = call("/api/auth/mfa/verify", `{"mfa_token": "`+mfaToken+`", "code": "123456"}`, mfaHandler.Verify, nil)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var tokenBody struct {
+		Token string `json:"token"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &tokenBody))
+
+	response, reached := callProtected(map[string]string{"Authorization": "Bearer " + tokenBody.Token}, "user")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "user-mfa", reached.Get("userId"))
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// Verifyのテスト(異常系)
+func TestMFAVerifyError(t *testing.T) {
+	resetMFAMock("user-mfa")
+	mfaToken, _ := loginBody(t, "user-mfa")["mfa_token"].(string)
+	accessToken, _ := loginBody(t, "user-1")["token"].(string)
+
+	mockMFAUsecase.On("Verify", "user-mfa", "000000").Return(errors.New("invalid code"))
+	mockMFAUsecase.On("Verify", "user-mfa", "111111").Return(&domain_auth.LockedError{RetryAt: time.Now().Add(30 * time.Second)})
+
+	cases := map[string]struct {
+		body    string
+		status  int
+		message string
+	}{
+		"invalid code":   {`{"mfa_token": "` + mfaToken + `", "code": "000000"}`, http.StatusUnauthorized, "Invalid code"},
+		"locked":         {`{"mfa_token": "` + mfaToken + `", "code": "111111"}`, http.StatusTooManyRequests, "Too many failed attempts"},
+		"access token":   {`{"mfa_token": "` + accessToken + `", "code": "123456"}`, http.StatusUnauthorized, "Invalid MFA token"},
+		"tampered token": {`{"mfa_token": "` + mfaToken + `x", "code": "123456"}`, http.StatusUnauthorized, "Invalid MFA token"},
+	}
+	for name, c := range cases {
+		response := call("/api/auth/mfa/verify", c.body, mfaHandler.Verify, nil)
+		assert.Equal(t, c.status, response.Code, name)
+		assert.JSONEq(t, `{"message":"`+c.message+`"}`, response.Body.String(), name)
+		if c.status == http.StatusTooManyRequests {
+			assert.Equal(t, "30", response.Header().Get("Retry-After"))
+		}
+	}
+
+	response := call("/api/auth/mfa/verify", `{}`, mfaHandler.Verify, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"mfa_token"`)
+	assert.Contains(t, response.Body.String(), `"code"`)
+}
+
+// Enrollのテスト
+func TestMFAEnroll(t *testing.T) {
+	resetMFAMock()
+	mockMFAUsecase.On("Enroll", "user-1", "user-1").Return("SECRET", "otpauth://totp/backend:user-1?secret=SECRET", nil)
+	mockMFAUsecase.On("Enroll", "user-2", "bob@example.com").Return("", "", errors.New("mfa is already enabled"))
+
+	response := call("/api/auth/mfa/enroll", `{}`, mfaHandler.Enroll, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"secret":"SECRET","uri":"otpauth://totp/backend:user-1?secret=SECRET"}`, response.Body.String())
+
+	response = call("/api/auth/mfa/enroll", `{"account": "bob@example.com"}`, mfaHandler.Enroll, map[string]any{"userId": "user-2"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	// APIキーでは管理できない
+	response = call("/api/auth/mfa/enroll", `{}`, mfaHandler.Enroll, map[string]any{"userId": "user-1", "apiKeyId": "key-1"})
+	assert.Equal(t, http.StatusForbidden, response.Code)
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// Confirmのテスト
+func TestMFAConfirm(t *testing.T) {
+	resetMFAMock()
+	mockMFAUsecase.On("Confirm", "user-1", "123456").Return([]string{"aaaaa-bbbbb"}, nil)
+	mockMFAUsecase.On("Confirm", "user-1", "000000").Return(nil, errors.New("invalid code"))
+	mockMFAUsecase.On("Confirm", "user-2", "123456").Return(nil, errors.New("mfa is not enrolled"))
+
+	response := call("/api/auth/mfa/confirm", `{"code": "123456"}`, mfaHandler.Confirm, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"recovery_codes":["aaaaa-bbbbb"]}`, response.Body.String())
+
+	response = call("/api/auth/mfa/confirm", `{"code": "000000"}`, mfaHandler.Confirm, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = call("/api/auth/mfa/confirm", `{"code": "123456"}`, mfaHandler.Confirm, map[string]any{"userId": "user-2"})
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	response = call("/api/auth/mfa/confirm", `{}`, mfaHandler.Confirm, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// RegenerateRecoveryCodesのテスト
+func TestMFARegenerateRecoveryCodes(t *testing.T) {
+	resetMFAMock()
+	mockMFAUsecase.On("RegenerateRecoveryCodes", "user-1", "123456").Return([]string{"ccccc-ddddd"}, nil)
+	mockMFAUsecase.On("RegenerateRecoveryCodes", "user-2", "123456").Return(nil, errors.New("mfa is not enabled"))
+
+	response := call("/api/auth/mfa/recovery-codes", `{"code": "123456"}`, mfaHandler.RegenerateRecoveryCodes, map[string]any{"userId": "user-1"})
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"recovery_codes":["ccccc-ddddd"]}`, response.Body.String())
+
+	response = call("/api/auth/mfa/recovery-codes", `{"code": "123456"}`, mfaHandler.RegenerateRecoveryCodes, map[string]any{"userId": "user-2"})
+	assert.Equal(t, http.StatusConflict, response.Code)
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// Resetのテスト(管理者)
+func TestMFAReset(t *testing.T) {
+	resetMFAMock()
+	mockMFAUsecase.On("Reset", "user-1", "admin-1").Return(nil)
+	mockMFAUsecase.On("Reset", "user-2", "admin-1").Return(errors.New("mfa is not enrolled"))
+
+	reset := func(userId string) *httptest.ResponseRecorder {
+		e := echo.New()
+		response := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest("DELETE", "/api/auth/mfa/"+userId, nil), response)
+		ctx.SetParamNames("userId")
+		ctx.SetParamValues(userId)
+		ctx.Set("userId", "admin-1")
+		mfaHandler.Reset(ctx)
+		return response
+	}
+
+	assert.Equal(t, http.StatusNoContent, reset("user-1").Code)
+	assert.Equal(t, http.StatusNotFound, reset("user-2").Code)
+	mockMFAUsecase.AssertExpectations(t)
+}
+
+// Callbackのテスト(2要素認証が有効な場合はチャレンジを返す)
+func TestOIDCCallbackMFARequired(t *testing.T) {
+	resetMFAMock("user-mfa")
+	cookie := beginOIDC(t)
+	mockOIDCUsecase.On("Complete", mock.Anything, testFlow, "code-1").Return("user-mfa", nil)
+
+	response := callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=state-1", "fake", cookie)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"mfa_required":true`)
+	assert.NotContains(t, response.Body.String(), `"token":`)
+	resetMFAMock()
+}
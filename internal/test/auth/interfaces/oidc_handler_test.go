@@ -0,0 +1,113 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// テストのフロー
+var testFlow = domain_auth.OIDCFlow{Provider: "fake", State: "state-1", Nonce: "nonce-1", Verifier: "verifier-1"}
+
+// プロバイダ名付きのリクエストを送る
+func callOIDC(h echo.HandlerFunc, target string, provider string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	e := echo.New()
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest("GET", target, nil)
+	for _, cookie := range cookies {
+		request.AddCookie(cookie)
+	}
+	ctx := e.NewContext(request, response)
+	ctx.SetParamNames("provider")
+	ctx.SetParamValues(provider)
+	h(ctx)
+	return response
+}
+
+// ログインを開始してフローのクッキーを取得する
+func beginOIDC(t *testing.T) *http.Cookie {
+	mockOIDCUsecase.ExpectedCalls = nil
+	mockOIDCUsecase.On("Begin", mock.Anything, "fake").Return(testFlow, "https://idp.example.com/authorize?state=state-1", nil)
+
+	response := callOIDC(oidcHandler.Login, "/api/auth/oidc/fake/login", "fake")
+
+	assert.Equal(t, http.StatusFound, response.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=state-1", response.Header().Get("Location"))
+	cookies := response.Result().Cookies()
+	if !assert.Len(t, cookies, 1) {
+		t.FailNow()
+	}
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, http.SameSiteLaxMode, cookies[0].SameSite)
+	return cookies[0]
+}
+
+// Callbackのテスト(正常系)
+func TestOIDCCallback(t *testing.T) {
+	cookie := beginOIDC(t)
+	mockOIDCUsecase.On("Complete", mock.Anything, testFlow, "code-1").Return("user-1", nil)
+
+	response := callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=state-1", "fake", cookie)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"token":`)
+	// クッキーを消す
+	assert.Equal(t, -1, response.Result().Cookies()[0].MaxAge)
+	mockOIDCUsecase.AssertExpectations(t)
+}
+
+// Callbackのテスト(異常系 - stateが不正)
+func TestOIDCCallbackInvalidState(t *testing.T) {
+	cookie := beginOIDC(t)
+	mockOIDCUsecase.Calls = nil
+
+	cases := map[string]*httptest.ResponseRecorder{
+		"state mismatch":   callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=other", "fake", cookie),
+		"missing state":    callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1", "fake", cookie),
+		"missing cookie":   callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=state-1", "fake"),
+		"tampered cookie":  callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=state-1", "fake", &http.Cookie{Name: cookie.Name, Value: cookie.Value + "x"}),
+		"other provider":   callOIDC(oidcHandler.Callback, "/api/auth/oidc/other/callback?code=code-1&state=state-1", "other", cookie),
+		"provider refused": callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?error=access_denied&state=state-1", "fake", cookie),
+	}
+	for name, response := range cases {
+		assert.Equal(t, http.StatusBadRequest, response.Code, name)
+	}
+	mockOIDCUsecase.AssertNotCalled(t, "Complete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Callbackのテスト(異常系 - 紐付くアカウントがない・プロバイダのエラー)
+func TestOIDCCallbackErrors(t *testing.T) {
+	statuses := map[string]int{
+		"no linked account":       http.StatusForbidden,
+		"email is not verified":   http.StatusForbidden,
+		"identity provider error": http.StatusBadGateway,
+		"invalid id token":        http.StatusBadGateway,
+		"failed to login":         http.StatusInternalServerError,
+	}
+	for message, status := range statuses {
+		cookie := beginOIDC(t)
+		mockOIDCUsecase.On("Complete", mock.Anything, testFlow, "code-1").Return("", errors.New(message))
+
+		response := callOIDC(oidcHandler.Callback, "/api/auth/oidc/fake/callback?code=code-1&state=state-1", "fake", cookie)
+
+		assert.Equal(t, status, response.Code, message)
+		assert.NotContains(t, response.Body.String(), `"token":`)
+	}
+}
+
+// Loginのテスト(異常系 - 設定されていないプロバイダ)
+func TestOIDCLoginUnknownProvider(t *testing.T) {
+	mockOIDCUsecase.ExpectedCalls = nil
+	mockOIDCUsecase.On("Begin", mock.Anything, "unknown").Return(nil, "", errors.New("unknown provider"))
+
+	response := callOIDC(oidcHandler.Login, "/api/auth/oidc/unknown/login", "unknown")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Empty(t, response.Result().Cookies())
+}
@@ -0,0 +1,162 @@
+package test_auth_handler
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// セッションのモックをリセットする
+// 既定では、どのセッションも作成・確認に成功する
+func resetSessionMock() {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.Calls = nil
+	mockSessionUsecase.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(domain_auth.Session{ID: "session-1", Role: domain_auth.RoleUser}, nil).Maybe()
+	mockSessionUsecase.On("Validate", mock.Anything, mock.Anything).Return(nil).Maybe()
+}
+
+// アクセストークンを作る
+func signedToken(t *testing.T, claims jwt.MapClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	assert.NoError(t, err)
+	return token
+}
+
+// Loginのテスト(セッションを作成し、トークンにセッションIDを含める)
+func TestLoginCreatesSession(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.Calls = nil
+	mockSessionUsecase.On("Create", "user-1", "192.0.2.1", "").Return(domain_auth.Session{ID: "session-9", UserID: "user-1", Role: domain_auth.RoleUser}, nil)
+	mockSessionUsecase.On("Validate", "session-9", "user-1").Return(nil)
+	defer resetSessionMock()
+
+	token, _ := loginBody(t, "user-1")["token"].(string)
+
+	response, c := callProtected(map[string]string{"Authorization": "Bearer " + token}, "user")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "session-9", c.Get("sessionId"))
+	mockSessionUsecase.AssertExpectations(t)
+}
+
+// Loginのテスト(異常系 - セッションを作成できない)
+func TestLoginErrorSession(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.On("Create", "user-1", mock.Anything, mock.Anything).Return(nil, errors.New("failed to create session"))
+	defer resetSessionMock()
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.On("Login", "test@example.com", "password", "192.0.2.1").Return("user-1", nil)
+
+	response := call("/api/auth/login", `{"email": "test@example.com", "password": "password"}`, handler.Login, nil)
+
+	assert.Equal(t, http.StatusInternalServerError, response.Code)
+	assert.NotContains(t, response.Body.String(), `"token"`)
+}
+
+// AuthorizationMiddlewareのテスト(異常系 - セッションIDがない・失効済み)
+func TestAuthorizationMiddlewareSession(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.On("Validate", "revoked", "user-1").Return(errors.New("invalid session"))
+	mockSessionUsecase.On("Validate", "broken", "user-1").Return(errors.New("failed to authenticate"))
+	defer resetSessionMock()
+	exp := time.Now().Add(time.Hour).Unix()
+
+	tests := map[string]struct {
+		claims  jwt.MapClaims
+		code    int
+		message string
+	}{
+		"no session": {jwt.MapClaims{"id": "user-1", "role": "user", "exp": exp}, http.StatusUnauthorized, "Invalid token"},
+		"revoked":    {jwt.MapClaims{"id": "user-1", "sid": "revoked", "role": "user", "exp": exp}, http.StatusUnauthorized, "Session has been revoked"},
+		"failure":    {jwt.MapClaims{"id": "user-1", "sid": "broken", "role": "user", "exp": exp}, http.StatusInternalServerError, "Failed to authenticate"},
+	}
+	for name, tt := range tests {
+		response, c := callProtected(map[string]string{"Authorization": "Bearer " + signedToken(t, tt.claims)}, "user")
+		assert.Equal(t, tt.code, response.Code, name)
+		assert.JSONEq(t, `{"message":"`+tt.message+`"}`, response.Body.String(), name)
+		assert.Nil(t, c, name)
+	}
+}
+
+// GetSessionsのテスト(リクエスト元のセッションにcurrentを付ける)
+func TestGetSessions(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	now := time.Now()
+	mockSessionUsecase.On("GetSessions", "user-1").Return([]domain_auth.Session{
+		{ID: "session-1", UserID: "user-1", Device: "iPhone", CreatedAt: now, LastSeenAt: now},
+		{ID: "session-2", UserID: "user-1", Device: "Mac", CreatedAt: now, LastSeenAt: now},
+	}, nil)
+	defer resetSessionMock()
+
+	response := call("/api/auth/sessions", "", sessionHandler.GetSessions, map[string]any{"userId": "user-1", "sessionId": "session-2"})
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	var body []struct {
+		ID      string `json:"id"`
+		Device  string `json:"device"`
+		Current bool   `json:"current"`
+	}
+	assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	assert.Len(t, body, 2)
+	assert.False(t, body[0].Current)
+	assert.Equal(t, "session-2", body[1].ID)
+	assert.True(t, body[1].Current)
+	mockSessionUsecase.AssertExpectations(t)
+}
+
+// APIキーではセッションを管理できない
+func TestSessionsErrorAPIKey(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.Calls = nil
+	defer resetSessionMock()
+	values := map[string]any{"userId": "user-1", "apiKeyId": "key-1"}
+
+	assert.Equal(t, http.StatusForbidden, call("/api/auth/sessions", "", sessionHandler.GetSessions, values).Code)
+	assert.Equal(t, http.StatusForbidden, call("/api/auth/sessions", "", sessionHandler.RevokeAllSessions, values).Code)
+	assert.Equal(t, http.StatusForbidden, call("/api/auth/sessions/session-1", "", sessionHandler.RevokeSession, values).Code)
+	mockSessionUsecase.AssertNotCalled(t, "GetSessions", mock.Anything)
+	mockSessionUsecase.AssertNotCalled(t, "RevokeAll", mock.Anything)
+	mockSessionUsecase.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+}
+
+// RevokeSessionのテスト
+func TestRevokeSession(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.On("Revoke", "user-1", "session-1").Return(nil)
+	mockSessionUsecase.On("Revoke", "user-1", "session-2").Return(errors.New("session not found"))
+	mockSessionUsecase.On("Revoke", "user-1", "session-3").Return(errors.New("failed to revoke session"))
+	defer resetSessionMock()
+
+	for id, status := range map[string]int{"session-1": http.StatusNoContent, "session-2": http.StatusNotFound, "session-3": http.StatusInternalServerError} {
+		e := echo.New()
+		response := httptest.NewRecorder()
+		ctx := e.NewContext(httptest.NewRequest("DELETE", "/api/auth/sessions/"+id, nil), response)
+		ctx.SetParamNames("id")
+		ctx.SetParamValues(id)
+		ctx.Set("userId", "user-1")
+		sessionHandler.RevokeSession(ctx)
+		assert.Equal(t, status, response.Code, id)
+	}
+	mockSessionUsecase.AssertExpectations(t)
+}
+
+// RevokeAllSessionsのテスト
+func TestRevokeAllSessions(t *testing.T) {
+	mockSessionUsecase.ExpectedCalls = nil
+	mockSessionUsecase.On("RevokeAll", "user-1").Return(3, nil)
+	defer resetSessionMock()
+
+	response := call("/api/auth/sessions", "", sessionHandler.RevokeAllSessions, map[string]any{"userId": "user-1"})
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"revoked":3}`, response.Body.String())
+	mockSessionUsecase.AssertExpectations(t)
+}
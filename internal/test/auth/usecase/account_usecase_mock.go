@@ -0,0 +1,41 @@
+package test_auth_usecase
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのアカウントのユースケース作成
+type MockAccountUsecase struct {
+	mock.Mock
+}
+
+// RequestPasswordResetのモック
+func (m *MockAccountUsecase) RequestPasswordReset(ctx context.Context, email string, lang string) error {
+	args := m.Called(ctx, email, lang)
+	return args.Error(0)
+}
+
+// ResetPasswordのモック
+func (m *MockAccountUsecase) ResetPassword(token string, password string) error {
+	args := m.Called(token, password)
+	return args.Error(0)
+}
+
+// RequestEmailVerificationのモック
+func (m *MockAccountUsecase) RequestEmailVerification(ctx context.Context, userId string, lang string) error {
+	args := m.Called(ctx, userId, lang)
+	return args.Error(0)
+}
+
+// VerifyEmailのモック
+func (m *MockAccountUsecase) VerifyEmail(token string) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+// Waitのモック
+func (m *MockAccountUsecase) Wait() {
+	m.Called()
+}
@@ -0,0 +1,190 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	infrastructure_auth "backend/internal/infrastructure/auth"
+	pkg_mail "backend/internal/pkg/mail"
+	test_stub "backend/internal/test/stub"
+	usecase_auth "backend/internal/usecase/auth"
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// テストのアカウント
+var testAccount = domain_auth.Account{ID: "user-1", Username: "alice", Email: "alice@example.com"}
+
+// アカウントのモックをリセットする
+func resetAccountMocks() {
+	mockAccountRepo.ExpectedCalls = nil
+	mockAccountRepo.Calls = nil
+}
+
+// 書き出されたメール
+func sentMails(t *testing.T) []test_stub.Mail {
+	mails, err := test_stub.ReadMails(mailDir)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return mails
+}
+
+// 最後に書き出されたメールとトークン
+func lastMail(t *testing.T) (test_stub.Mail, string) {
+	mails := sentMails(t)
+	if !assert.NotEmpty(t, mails) {
+		t.FailNow()
+	}
+	m := mails[len(mails)-1]
+	token, err := url.QueryUnescape(test_stub.MailToken(m))
+	assert.NoError(t, err)
+	return m, token
+}
+
+// パスワードの再設定(メールの言語はAccept-Languageから選ぶ)
+func TestPasswordReset(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountByEmail", "Alice@Example.com").Return(testAccount, nil)
+	mockAccountRepo.On("UpdatePassword", "user-1", "new-password").Return(nil)
+
+	err := accountUseCase.RequestPasswordReset(context.Background(), "Alice@Example.com", "en-US,en;q=0.9,ja;q=0.8")
+	assert.NoError(t, err)
+	// メールはリクエストの外で送る
+	accountUseCase.Wait()
+
+	m, token := lastMail(t)
+	assert.Equal(t, "alice@example.com", m.To)
+	assert.Equal(t, "no-reply@example.com", m.From)
+	assert.Equal(t, "Reset your password", m.Subject)
+	assert.Contains(t, m.Body, "Hi alice,")
+	assert.Contains(t, m.Body, "http://localhost:3000/password/reset?token=")
+
+	session, err := sessionRepo.CreateSession(domain_auth.Session{UserID: "user-1"})
+	assert.NoError(t, err)
+
+	assert.EqualError(t, accountUseCase.ResetPassword(token, "short"), "password is too short")
+	assert.NoError(t, accountUseCase.ResetPassword(token, "new-password"))
+	// 再設定すると全ての端末からログアウトする
+	session, _ = sessionRepo.GetSession(session.ID)
+	assert.NotNil(t, session.RevokedAt)
+	// 1回のみ使える
+	assert.EqualError(t, accountUseCase.ResetPassword(token, "new-password"), "invalid or expired token")
+	mockAccountRepo.AssertNumberOfCalls(t, "UpdatePassword", 1)
+}
+
+// 登録されていないメールアドレスでも成功とし、メールは送らない
+func TestPasswordResetUnknownEmail(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountByEmail", "nobody@example.com").Return(nil, errors.New("user not found"))
+	before := len(sentMails(t))
+
+	assert.NoError(t, accountUseCase.RequestPasswordReset(context.Background(), "nobody@example.com", ""))
+	accountUseCase.Wait()
+	assert.Len(t, sentMails(t), before)
+
+	assert.EqualError(t, accountUseCase.RequestPasswordReset(context.Background(), "not-an-email", ""), "invalid email format")
+}
+
+// アカウントの取得に失敗しても登録されていない場合と同じく成功とする(登録の有無が分からないように)
+func TestPasswordResetLookupError(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountByEmail", "alice@example.com").Return(nil, errors.New("connection refused"))
+	before := len(sentMails(t))
+
+	assert.NoError(t, accountUseCase.RequestPasswordReset(context.Background(), "alice@example.com", ""))
+	accountUseCase.Wait()
+	assert.Len(t, sentMails(t), before)
+	mockAccountRepo.AssertExpectations(t)
+}
+
+// 新しいトークンを発行すると、以前のトークンは使えない
+func TestPasswordResetReissue(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountByEmail", "alice@example.com").Return(testAccount, nil)
+	mockAccountRepo.On("UpdatePassword", "user-1", mock.Anything).Return(nil)
+
+	assert.NoError(t, accountUseCase.RequestPasswordReset(context.Background(), "alice@example.com", ""))
+	accountUseCase.Wait()
+	_, first := lastMail(t)
+	assert.NoError(t, accountUseCase.RequestPasswordReset(context.Background(), "alice@example.com", ""))
+	accountUseCase.Wait()
+	_, second := lastMail(t)
+
+	assert.EqualError(t, accountUseCase.ResetPassword(first, "new-password"), "invalid or expired token")
+	assert.NoError(t, accountUseCase.ResetPassword(second, "new-password"))
+}
+
+// 署名が不正・用途が違う・期限切れのトークンは使えない
+func TestPasswordResetInvalidToken(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountById", "user-1").Return(testAccount, nil)
+
+	// メールアドレスの確認のトークン
+	assert.NoError(t, accountUseCase.RequestEmailVerification(context.Background(), "user-1", ""))
+	_, token := lastMail(t)
+	assert.EqualError(t, accountUseCase.ResetPassword(token, "new-password"), "invalid or expired token")
+
+	nonce, _, _ := strings.Cut(token, ".")
+	for _, token := range []string{"", "garbage", nonce + ".forged", nonce} {
+		assert.EqualError(t, accountUseCase.ResetPassword(token, "new-password"), "invalid or expired token", token)
+	}
+
+	// 期限切れ
+	templates, _ := pkg_mail.NewTemplates("ja")
+	expired := usecase_auth.NewAccountUsecase(logger, mockAccountRepo, infrastructure_auth.NewUserTokenMemoryRepository(logger), sessionRepo, pkg_mail.NewFileMailer(logger, mailDir, "no-reply@example.com"), templates, usecase_auth.AccountOptions{
+		TokenSecret:      []byte("test-secret"),
+		PasswordResetTTL: -time.Second,
+		LinkBaseURL:      "http://localhost:3000",
+	})
+	mockAccountRepo.On("GetAccountByEmail", "alice@example.com").Return(testAccount, nil)
+	assert.NoError(t, expired.RequestPasswordReset(context.Background(), "alice@example.com", ""))
+	expired.Wait()
+	_, token = lastMail(t)
+	assert.EqualError(t, expired.ResetPassword(token, "new-password"), "invalid or expired token")
+	mockAccountRepo.AssertNotCalled(t, "UpdatePassword", mock.Anything, mock.Anything)
+}
+
+// メールアドレスの確認(言語の指定がなければ既定の日本語)
+func TestEmailVerification(t *testing.T) {
+	resetAccountMocks()
+	mockAccountRepo.On("GetAccountById", "user-1").Return(testAccount, nil)
+	mockAccountRepo.On("VerifyEmail", "user-1", "alice@example.com", mock.Anything).Return(nil)
+
+	assert.NoError(t, accountUseCase.RequestEmailVerification(context.Background(), "user-1", ""))
+
+	m, token := lastMail(t)
+	assert.Equal(t, "メールアドレスの確認", m.Subject)
+	assert.Contains(t, m.Body, "alice 様")
+	assert.Contains(t, m.Body, "http://localhost:3000/email/verify?token=")
+
+	assert.NoError(t, accountUseCase.VerifyEmail(token))
+	assert.EqualError(t, accountUseCase.VerifyEmail(token), "invalid or expired token")
+	mockAccountRepo.AssertExpectations(t)
+}
+
+// 確認済み・メールアドレスの変更後
+func TestEmailVerificationError(t *testing.T) {
+	resetAccountMocks()
+	verifiedAt := time.Now()
+	verified := testAccount
+	verified.ID = "user-2"
+	verified.EmailVerifiedAt = &verifiedAt
+	mockAccountRepo.On("GetAccountById", "user-2").Return(verified, nil)
+	mockAccountRepo.On("GetAccountById", "user-1").Return(testAccount, nil)
+	mockAccountRepo.On("GetAccountById", "user-x").Return(nil, errors.New("user not found"))
+	mockAccountRepo.On("VerifyEmail", "user-1", "alice@example.com", mock.Anything).Return(errors.New("user not found"))
+
+	assert.EqualError(t, accountUseCase.RequestEmailVerification(context.Background(), "user-2", ""), "email is already verified")
+	assert.EqualError(t, accountUseCase.RequestEmailVerification(context.Background(), "user-x", ""), "user not found")
+
+	assert.NoError(t, accountUseCase.RequestEmailVerification(context.Background(), "user-1", "en"))
+	m, token := lastMail(t)
+	assert.Equal(t, "Verify your email address", m.Subject)
+	assert.EqualError(t, accountUseCase.VerifyEmail(token), "invalid or expired token")
+}
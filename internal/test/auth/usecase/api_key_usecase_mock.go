@@ -0,0 +1,54 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのAPIキーのユースケース作成
+type MockAPIKeyUsecase struct {
+	mock.Mock
+}
+
+// CreateAPIKeyのモック
+func (m *MockAPIKeyUsecase) CreateAPIKey(userId string, req domain_auth.CreateAPIKeyRequest) (domain_auth.APIKey, string, error) {
+	args := m.Called(userId, req)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.APIKey{}, "", args.Error(2)
+	}
+
+	return args.Get(0).(domain_auth.APIKey), args.String(1), args.Error(2)
+}
+
+// GetAPIKeysのモック
+func (m *MockAPIKeyUsecase) GetAPIKeys(userId string) ([]domain_auth.APIKey, error) {
+	args := m.Called(userId)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_auth.APIKey), args.Error(1)
+}
+
+// RevokeAPIKeyのモック
+func (m *MockAPIKeyUsecase) RevokeAPIKey(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+// Authenticateのモック
+func (m *MockAPIKeyUsecase) Authenticate(key string) (domain_auth.APIKey, error) {
+	args := m.Called(key)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.APIKey{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_auth.APIKey), args.Error(1)
+}
@@ -0,0 +1,133 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	infrastructure_auth "backend/internal/infrastructure/auth"
+	test_auth_repository "backend/internal/test/auth/infrastructure"
+	usecase_auth "backend/internal/usecase/auth"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// APIキーのユースケース(ユーザーの現在のロールはrolesで指定する)
+func newAPIKeyUsecase(roles map[string]string) (usecase_auth.IAPIKeyUsecase, *test_auth_repository.MockRoleRepository) {
+	roleRepo := new(test_auth_repository.MockRoleRepository)
+	for userId, role := range roles {
+		roleRepo.On("GetRole", userId).Return(role, nil)
+	}
+	roleRepo.On("GetRole", mock.Anything).Return("", errors.New("user not found")).Maybe()
+	return usecase_auth.NewAPIKeyUsecase(logger, infrastructure_auth.NewAPIKeyMemoryRepository(logger), roleRepo), roleRepo
+}
+
+// APIキーの作成リクエスト
+func apiKeyRequest(kind domain_auth.APIKeyKind, scopes []string, expiresAt time.Time) domain_auth.CreateAPIKeyRequest {
+	return domain_auth.CreateAPIKeyRequest{Name: "ci", Kind: kind, Scopes: scopes, ExpiresAt: &expiresAt}
+}
+
+// 作成したキーで認証できる
+func TestCreateAPIKeyAndAuthenticate(t *testing.T) {
+	u, _ := newAPIKeyUsecase(map[string]string{"user-1": domain_auth.RoleUser, "admin-1": domain_auth.RoleAdmin})
+
+	key, plaintext, err := u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"user"}, time.Now().Add(time.Hour)))
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(plaintext, domain_auth.APIKeyPrefix+key.Prefix+"_"))
+	// キーそのものは保存しない
+	assert.Equal(t, domain_auth.HashAPIKey(plaintext), key.Hash)
+	assert.NotContains(t, key.Hash, plaintext)
+	assert.Nil(t, key.LastUsedAt)
+
+	authenticated, err := u.Authenticate(plaintext)
+	assert.NoError(t, err)
+	assert.Equal(t, key.ID, authenticated.ID)
+	assert.Equal(t, "user-1", authenticated.UserID)
+
+	// 最終使用日時を記録する
+	keys, err := u.GetAPIKeys("user-1")
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assert.NotNil(t, keys[0].LastUsedAt)
+}
+
+// 一致しない・失効・期限切れのキーは同じエラー
+func TestAuthenticateInvalid(t *testing.T) {
+	u, _ := newAPIKeyUsecase(map[string]string{"user-1": domain_auth.RoleUser, "admin-1": domain_auth.RoleAdmin})
+
+	valid, plaintext, _ := u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"user"}, time.Now().Add(time.Hour)))
+	_, expired, _ := u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"user"}, time.Now().Add(20*time.Millisecond)))
+	time.Sleep(30 * time.Millisecond)
+
+	for _, key := range []string{"", "not-a-key", plaintext + "x", domain_auth.APIKeyPrefix + "00000000_secret", expired} {
+		_, err := u.Authenticate(key)
+		assert.EqualError(t, err, "invalid api key", key)
+	}
+
+	assert.NoError(t, u.RevokeAPIKey("user-1", valid.ID))
+	_, err := u.Authenticate(plaintext)
+	assert.EqualError(t, err, "invalid api key")
+}
+
+// 他のユーザーのキーは失効させられない
+func TestRevokeAPIKeyOtherUser(t *testing.T) {
+	u, _ := newAPIKeyUsecase(map[string]string{"user-1": domain_auth.RoleUser, "admin-1": domain_auth.RoleAdmin})
+
+	key, plaintext, _ := u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"user"}, time.Now().Add(time.Hour)))
+	err := u.RevokeAPIKey("user-2", key.ID)
+	assert.EqualError(t, err, "api key not found")
+
+	_, err = u.Authenticate(plaintext)
+	assert.NoError(t, err)
+}
+
+// 自分のロールを超える権限とサービス用のキーは管理者のみ
+func TestCreateAPIKeyPermissions(t *testing.T) {
+	u, _ := newAPIKeyUsecase(map[string]string{"user-1": domain_auth.RoleUser, "admin-1": domain_auth.RoleAdmin})
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, _, err := u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"admin"}, expiresAt))
+	assert.EqualError(t, err, "insufficient permissions")
+	_, _, err = u.CreateAPIKey("user-1", apiKeyRequest(domain_auth.APIKeyService, []string{"user"}, expiresAt))
+	assert.EqualError(t, err, "insufficient permissions")
+
+	key, _, err := u.CreateAPIKey("admin-1", apiKeyRequest(domain_auth.APIKeyService, []string{"user", "admin"}, expiresAt))
+	assert.NoError(t, err)
+	assert.True(t, key.HasScope("admin"))
+}
+
+// 管理者でなくなったユーザーのキーは管理者の権限を使えず、新しく作成もできない
+func TestAPIKeyOwnerDemoted(t *testing.T) {
+	u, roleRepo := newAPIKeyUsecase(map[string]string{"admin-1": domain_auth.RoleAdmin})
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, plaintext, err := u.CreateAPIKey("admin-1", apiKeyRequest(domain_auth.APIKeyService, []string{"user", "admin"}, expiresAt))
+	assert.NoError(t, err)
+	key, err := u.Authenticate(plaintext)
+	assert.NoError(t, err)
+	assert.True(t, key.HasScope("admin"))
+
+	// 一般ユーザーに変更
+	roleRepo.ExpectedCalls = nil
+	roleRepo.On("GetRole", "admin-1").Return(domain_auth.RoleUser, nil)
+
+	key, err = u.Authenticate(plaintext)
+	assert.NoError(t, err)
+	assert.False(t, key.HasScope("admin"))
+	assert.True(t, key.HasScope("user"))
+
+	_, _, err = u.CreateAPIKey("admin-1", apiKeyRequest(domain_auth.APIKeyPersonal, []string{"admin"}, expiresAt))
+	assert.EqualError(t, err, "insufficient permissions")
+	_, _, err = u.CreateAPIKey("admin-1", apiKeyRequest(domain_auth.APIKeyService, []string{"user"}, expiresAt))
+	assert.EqualError(t, err, "insufficient permissions")
+
+	// 所有者が存在しないキーは使えない
+	roleRepo.ExpectedCalls = nil
+	roleRepo.On("GetRole", "admin-1").Return("", errors.New("user not found"))
+	_, err = u.Authenticate(plaintext)
+	assert.EqualError(t, err, "invalid api key")
+
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,202 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	infrastructure_auth "backend/internal/infrastructure/auth"
+	usecase_auth "backend/internal/usecase/auth"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの挙動をリセットする(認証は常に失敗、監査ログは成功)
+func resetLockoutMocks() {
+	mockRepo.ExpectedCalls = nil
+	mockRepo.Calls = nil
+	mockAuditRepo.ExpectedCalls = nil
+	mockAuditRepo.Calls = nil
+	mockRepo.On("Login", mock.Anything, mock.Anything).Return("", domain_auth.ErrInvalidCredentials)
+	mockAuditRepo.On("CreateAuditLog", mock.Anything).Return(nil)
+}
+
+// 記録された監査ログの操作
+func auditActions() []domain_auth.AuditAction {
+	actions := []domain_auth.AuditAction{}
+	for _, call := range mockAuditRepo.Calls {
+		actions = append(actions, call.Arguments.Get(0).(domain_auth.AuditLog).Action)
+	}
+	return actions
+}
+
+// 失敗回数がしきい値に達するとアカウントをロックする
+func TestLoginLockout(t *testing.T) {
+	resetLockoutMocks()
+	email := "lockout@example.com"
+
+	for i := range 3 {
+		_, err := useCase.Login(email, "wrong", "198.51.100.1")
+		assert.EqualError(t, err, "invalid email or password", "attempt %d", i+1)
+	}
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditAccountLocked}, auditActions())
+
+	// ロック中は正しいパスワードでも認証しない
+	_, err := useCase.Login(email, "password", "198.51.100.2")
+	var locked *domain_auth.LockedError
+	assert.ErrorAs(t, err, &locked)
+	assert.EqualError(t, err, "too many failed attempts")
+	assert.WithinDuration(t, time.Now().Add(accountPolicy.Duration), locked.RetryAt, time.Second)
+	mockRepo.AssertNumberOfCalls(t, "Login", 3)
+
+	// メールアドレスの大文字・小文字は区別しない
+	_, err = useCase.Login("Lockout@Example.com", "password", "198.51.100.2")
+	assert.EqualError(t, err, "too many failed attempts")
+}
+
+// 同じIPアドレスからの失敗は、アカウントが違っても数える
+func TestLoginIPLockout(t *testing.T) {
+	resetLockoutMocks()
+	ip := "198.51.100.10"
+
+	for i := range 5 {
+		_, err := useCase.Login("ip"+string(rune('a'+i))+"@example.com", "wrong", ip)
+		assert.EqualError(t, err, "invalid email or password")
+	}
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditIPLocked}, auditActions())
+
+	_, err := useCase.Login("other@example.com", "password", ip)
+	assert.EqualError(t, err, "too many failed attempts")
+
+	// 別のIPアドレスからは認証する
+	_, err = useCase.Login("other@example.com", "password", "198.51.100.11")
+	assert.EqualError(t, err, "invalid email or password")
+}
+
+// 同時の試行でも、アカウントのしきい値を超えて認証しない
+func TestLoginLockoutConcurrent(t *testing.T) {
+	resetLockoutMocks()
+	email := "concurrent@example.com"
+	// 認証に時間がかかる間に、他の試行が揃うようにする
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("Login", mock.Anything, mock.Anything).After(20*time.Millisecond).Return("", domain_auth.ErrInvalidCredentials)
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			useCase.Login(email, "wrong", fmt.Sprintf("203.0.113.%d", i))
+		}()
+	}
+	wg.Wait()
+
+	mockRepo.AssertNumberOfCalls(t, "Login", accountPolicy.Threshold)
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditAccountLocked}, auditActions())
+}
+
+// 失敗するたびに次の試行までの待ち時間を延ばす
+func TestLoginBackoff(t *testing.T) {
+	resetLockoutMocks()
+	policy := domain_auth.LockoutPolicy{Threshold: 10, Duration: time.Hour, Window: time.Hour, BaseDelay: 50 * time.Millisecond, MaxDelay: time.Second}
+	u := usecase_auth.NewAuthUsecase(logger, mockRepo, infrastructure_auth.NewLoginAttemptMemoryRepository(logger), mockAuditRepo, policy, policy)
+
+	_, err := u.Login("backoff@example.com", "wrong", "198.51.100.20")
+	assert.EqualError(t, err, "invalid email or password")
+
+	// 待ち時間中はロックと同じエラー
+	_, err = u.Login("backoff@example.com", "wrong", "198.51.100.20")
+	var locked *domain_auth.LockedError
+	assert.ErrorAs(t, err, &locked)
+	mockRepo.AssertNumberOfCalls(t, "Login", 1)
+
+	time.Sleep(time.Until(locked.RetryAt) + 10*time.Millisecond)
+	_, err = u.Login("backoff@example.com", "wrong", "198.51.100.20")
+	assert.EqualError(t, err, "invalid email or password")
+
+	// 2回目の失敗の後は2倍待つ
+	_, err = u.Login("backoff@example.com", "wrong", "198.51.100.20")
+	assert.ErrorAs(t, err, &locked)
+	assert.WithinDuration(t, time.Now().Add(100*time.Millisecond), locked.RetryAt, 30*time.Millisecond)
+}
+
+// ロックは期限が切れると自動で解除する
+func TestLoginLockoutExpires(t *testing.T) {
+	resetLockoutMocks()
+	policy := domain_auth.LockoutPolicy{Threshold: 2, Duration: 50 * time.Millisecond, Window: time.Hour}
+	u := usecase_auth.NewAuthUsecase(logger, mockRepo, infrastructure_auth.NewLoginAttemptMemoryRepository(logger), mockAuditRepo, policy, policy)
+
+	for range 2 {
+		u.Login("expires@example.com", "wrong", "198.51.100.30")
+	}
+	_, err := u.Login("expires@example.com", "wrong", "198.51.100.30")
+	assert.EqualError(t, err, "too many failed attempts")
+
+	time.Sleep(60 * time.Millisecond)
+	_, err = u.Login("expires@example.com", "wrong", "198.51.100.30")
+	assert.EqualError(t, err, "invalid email or password")
+}
+
+// ログインに成功するとアカウントの失敗を数え直す
+func TestLoginSuccessResetsFailures(t *testing.T) {
+	resetLockoutMocks()
+	email := "reset@example.com"
+
+	for range 2 {
+		useCase.Login(email, "wrong", "198.51.100.40")
+	}
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("Login", email, "password").Return("user-1", nil)
+	id, err := useCase.Login(email, "password", "198.51.100.40")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", id)
+
+	f, err := attemptRepo.Get(domain_auth.AccountKey(email))
+	assert.NoError(t, err)
+	assert.Zero(t, f.Count)
+}
+
+// 認証以外の失敗は数えない
+func TestLoginSystemErrorIsNotCounted(t *testing.T) {
+	resetLockoutMocks()
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("Login", mock.Anything, mock.Anything).Return("", errors.New("connection refused"))
+
+	for range 4 {
+		_, err := useCase.Login("system@example.com", "password", "198.51.100.50")
+		assert.EqualError(t, err, "failed to login")
+	}
+	assert.Empty(t, auditActions())
+}
+
+// 管理者はロックを解除できる
+func TestUnlock(t *testing.T) {
+	resetLockoutMocks()
+	email := "unlock@example.com"
+
+	for range 3 {
+		useCase.Login(email, "wrong", "198.51.100.60")
+	}
+	_, err := useCase.Login(email, "wrong", "198.51.100.60")
+	assert.EqualError(t, err, "too many failed attempts")
+
+	err = useCase.Unlock(email, "", "admin-1")
+	assert.NoError(t, err)
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditAccountLocked, domain_auth.AuditAccountUnlocked}, auditActions())
+	assert.Equal(t, "admin-1", mockAuditRepo.Calls[1].Arguments.Get(0).(domain_auth.AuditLog).ActorID)
+
+	_, err = useCase.Login(email, "wrong", "198.51.100.60")
+	assert.EqualError(t, err, "invalid email or password")
+}
+
+// 解除する対象がない
+func TestUnlockEmpty(t *testing.T) {
+	resetLockoutMocks()
+
+	err := useCase.Unlock("", "", "admin-1")
+	assert.EqualError(t, err, "email or ip is required")
+	assert.Empty(t, auditActions())
+}
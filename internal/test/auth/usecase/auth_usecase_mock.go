@@ -10,8 +10,8 @@ type MockAuthUsecase struct {
 }
 
 // Loginのモック
-func (m *MockAuthUsecase) Login(email string, password string) (string, error) {
-	args := m.Called(email, password)
+func (m *MockAuthUsecase) Login(email string, password string, ip string) (string, error) {
+	args := m.Called(email, password, ip)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -20,3 +20,9 @@ func (m *MockAuthUsecase) Login(email string, password string) (string, error) {
 
 	return args.Get(0).(string), args.Error(1)
 }
+
+// Unlockのモック
+func (m *MockAuthUsecase) Unlock(email string, ip string, actorId string) error {
+	args := m.Called(email, ip, actorId)
+	return args.Error(0)
+}
@@ -2,11 +2,21 @@ package test_auth_usecase
 
 import (
 	pkg_config "backend/config"
+	domain_auth "backend/internal/domain/auth"
+	infrastructure_auth "backend/internal/infrastructure/auth"
+	pkg_httpclient "backend/internal/pkg/httpclient"
 	pkg_logger "backend/internal/pkg/logger"
+	pkg_mail "backend/internal/pkg/mail"
+	pkg_oidc "backend/internal/pkg/oidc"
+	repository_auth "backend/internal/repository/auth"
 	test_auth_repository "backend/internal/test/auth/infrastructure"
+	test_stub "backend/internal/test/stub"
 	usecase_auth "backend/internal/usecase/auth"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
 )
 
 // テストの変数(グローバル用)
@@ -14,6 +24,27 @@ var (
 	logger   *pkg_logger.AppLogger
 	useCase  usecase_auth.IAuthUsecase
 	mockRepo *test_auth_repository.MockAuthRepository
+
+	attemptRepo   repository_auth.ILoginAttemptRepository
+	mockAuditRepo *test_auth_repository.MockAuditLogRepository
+	accountPolicy = domain_auth.LockoutPolicy{Threshold: 3, Duration: time.Hour, Window: time.Hour}
+	ipPolicy      = domain_auth.LockoutPolicy{Threshold: 5, Duration: time.Hour, Window: time.Hour}
+
+	oidcStub         *test_stub.OIDCProvider
+	oidcUseCase      usecase_auth.IOIDCUsecase
+	mockIdentityRepo *test_auth_repository.MockIdentityRepository
+
+	mfaRepo        repository_auth.IMFARepository
+	mfaAttemptRepo repository_auth.ILoginAttemptRepository
+	mfaUseCase     usecase_auth.IMFAUsecase
+
+	mailDir         string
+	mockAccountRepo *test_auth_repository.MockAccountRepository
+	accountUseCase  usecase_auth.IAccountUsecase
+
+	sessionRepo    repository_auth.ISessionRepository
+	mockRoleRepo   *test_auth_repository.MockRoleRepository
+	sessionUseCase usecase_auth.ISessionUsecase
 )
 
 // テストのメイン関数
@@ -28,10 +59,52 @@ func TestMain(m *testing.M) {
 
 	// モック
 	mockRepo = new(test_auth_repository.MockAuthRepository)
-	useCase = usecase_auth.NewAuthUsecase(logger, mockRepo)
+	mockAuditRepo = new(test_auth_repository.MockAuditLogRepository)
+	attemptRepo = infrastructure_auth.NewLoginAttemptMemoryRepository(logger)
+	useCase = usecase_auth.NewAuthUsecase(logger, mockRepo, attemptRepo, mockAuditRepo, accountPolicy, ipPolicy)
+
+	// OIDCのIDプロバイダのスタブサーバー
+	oidcStub = test_stub.NewOIDCProvider("client-1", "client-secret")
+	mockIdentityRepo = new(test_auth_repository.MockIdentityRepository)
+	provider := pkg_oidc.NewProvider(logger, pkg_oidc.Config{
+		Name:         "fake",
+		Issuer:       oidcStub.URL,
+		ClientID:     "client-1",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost/api/auth/oidc/fake/callback",
+	}, pkg_httpclient.NewClient(logger, pkg_httpclient.Options{Timeout: time.Second}))
+	oidcUseCase = usecase_auth.NewOIDCUsecase(logger, mockIdentityRepo, mockAuditRepo, []*pkg_oidc.Provider{provider})
+
+	// 2要素認証(失敗の記録はログインのテストと分ける)
+	mfaRepo = infrastructure_auth.NewMFAMemoryRepository(logger)
+	mfaAttemptRepo = infrastructure_auth.NewLoginAttemptMemoryRepository(logger)
+	mfaUseCase = usecase_auth.NewMFAUsecase(logger, mfaRepo, mfaAttemptRepo, mockAuditRepo, accountPolicy, "backend")
+
+	// ログインのセッション(失効がすぐに反映されるか確認するため、キャッシュは短くする)
+	// ロールは個別のテストで設定しない限り一般ユーザーとする
+	sessionRepo = infrastructure_auth.NewSessionMemoryRepository(logger)
+	mockRoleRepo = new(test_auth_repository.MockRoleRepository)
+	mockRoleRepo.On("GetRole", mock.Anything).Return(domain_auth.RoleUser, nil)
+	sessionUseCase = usecase_auth.NewSessionUsecase(logger, sessionRepo, mockRoleRepo, 50*time.Millisecond)
+
+	// パスワードの再設定・メールアドレスの確認(メールはファイルに書き出す)
+	mailDir, _ = os.MkdirTemp("", "mail")
+	templates, err := pkg_mail.NewTemplates("ja")
+	if err != nil {
+		logger.ErrorLog.Fatalf("Failed to load mail templates: %v", err)
+	}
+	mockAccountRepo = new(test_auth_repository.MockAccountRepository)
+	accountUseCase = usecase_auth.NewAccountUsecase(logger, mockAccountRepo, infrastructure_auth.NewUserTokenMemoryRepository(logger), sessionRepo, pkg_mail.NewFileMailer(logger, mailDir, "no-reply@example.com"), templates, usecase_auth.AccountOptions{
+		TokenSecret:          []byte("test-secret"),
+		PasswordResetTTL:     time.Hour,
+		EmailVerificationTTL: 24 * time.Hour,
+		LinkBaseURL:          "http://localhost:3000/",
+	})
 
 	// テスト実行
 	code := m.Run()
+	oidcStub.Close()
+	os.RemoveAll(mailDir)
 
 	// 終了コードを返す
 	os.Exit(code)
@@ -20,7 +20,7 @@ func TestLogin(t *testing.T) {
 	mockRepo.On("Login", email, password).Return(auth, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -43,7 +43,7 @@ func TestLoginEmpty(t *testing.T) {
 	mockRepo.On("Login", email, password).Return(auth, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -65,7 +65,7 @@ func TestLoginErrorEmailEmpty(t *testing.T) {
 	mockRepo.On("Login", email, password).Return("", errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.Error(t, err)
@@ -87,7 +87,7 @@ func TestLoginErrorEmailInvalid(t *testing.T) {
 	mockRepo.On("Login", email, password).Return("", errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.Error(t, err)
@@ -109,7 +109,7 @@ func TestLoginErrorPasswordEmpty(t *testing.T) {
 	mockRepo.On("Login", email, password).Return("", errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.Error(t, err)
@@ -131,7 +131,7 @@ func TestLoginError(t *testing.T) {
 	mockRepo.On("Login", email, password).Return("", errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.Login(email, password)
+	result, err := useCase.Login(email, password, "192.0.2.1")
 
 	// 検証
 	assert.Error(t, err)
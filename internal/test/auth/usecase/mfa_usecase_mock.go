@@ -0,0 +1,58 @@
+package test_auth_usecase
+
+import (
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの2要素認証のユースケース作成
+type MockMFAUsecase struct {
+	mock.Mock
+}
+
+// Enrollのモック
+func (m *MockMFAUsecase) Enroll(userId string, account string) (string, string, error) {
+	args := m.Called(userId, account)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+// Confirmのモック
+func (m *MockMFAUsecase) Confirm(userId string, code string) ([]string, error) {
+	args := m.Called(userId, code)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// RegenerateRecoveryCodesのモック
+func (m *MockMFAUsecase) RegenerateRecoveryCodes(userId string, code string) ([]string, error) {
+	args := m.Called(userId, code)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// Requiredのモック
+func (m *MockMFAUsecase) Required(userId string) (bool, error) {
+	args := m.Called(userId)
+	return args.Bool(0), args.Error(1)
+}
+
+// Verifyのモック
+func (m *MockMFAUsecase) Verify(userId string, code string) error {
+	args := m.Called(userId, code)
+	return args.Error(0)
+}
+
+// Resetのモック
+func (m *MockMFAUsecase) Reset(userId string, actorId string) error {
+	args := m.Called(userId, actorId)
+	return args.Error(0)
+}
@@ -0,0 +1,208 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	infrastructure_auth "backend/internal/infrastructure/auth"
+	pkg_totp "backend/internal/pkg/totp"
+	repository_auth "backend/internal/repository/auth"
+	usecase_auth "backend/internal/usecase/auth"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 登録して有効にし、シークレットと回復コードを返す
+func enrollMFA(t *testing.T, userId string) (string, []string) {
+	secret, _, err := mfaUseCase.Enroll(userId, userId+"@example.com")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	codes, err := mfaUseCase.Confirm(userId, totpCode(t, secret, 0))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return secret, codes
+}
+
+// 現在からoffsetステップずらしたコード
+func totpCode(t *testing.T, secret string, offset int64) string {
+	code, err := pkg_totp.Code(secret, pkg_totp.Step(time.Now())+offset)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return code
+}
+
+// 登録から有効化まで
+func TestMFAEnrollConfirm(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-enroll"
+
+	secret, uri, err := mfaUseCase.Enroll(userId, "alice@example.com")
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/backend:alice@example.com?"), uri)
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=backend")
+
+	// 確認前は要求しない
+	required, err := mfaUseCase.Required(userId)
+	assert.NoError(t, err)
+	assert.False(t, required)
+
+	_, err = mfaUseCase.Confirm(userId, "abcdef")
+	assert.EqualError(t, err, "invalid code")
+
+	codes, err := mfaUseCase.Confirm(userId, totpCode(t, secret, 0))
+	assert.NoError(t, err)
+	assert.Len(t, codes, domain_auth.RecoveryCodeCount)
+	assert.Regexp(t, `^[0-9a-f]{5}-[0-9a-f]{5}$`, codes[0])
+
+	required, err = mfaUseCase.Required(userId)
+	assert.NoError(t, err)
+	assert.True(t, required)
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditMFAEnabled}, auditActions())
+
+	// 有効になった後は登録し直せない
+	_, _, err = mfaUseCase.Enroll(userId, "alice@example.com")
+	assert.EqualError(t, err, "mfa is already enabled")
+	_, err = mfaUseCase.Confirm(userId, totpCode(t, secret, 0))
+	assert.EqualError(t, err, "mfa is already enabled")
+}
+
+// 登録していない場合
+func TestMFANotEnrolled(t *testing.T) {
+	resetLockoutMocks()
+
+	_, err := mfaUseCase.Confirm("mfa-none", "123456")
+	assert.EqualError(t, err, "mfa is not enrolled")
+	assert.EqualError(t, mfaUseCase.Verify("mfa-none", "123456"), "mfa is not enabled")
+	assert.EqualError(t, mfaUseCase.Reset("mfa-none", "admin-1"), "mfa is not enrolled")
+
+	required, err := mfaUseCase.Required("mfa-none")
+	assert.NoError(t, err)
+	assert.False(t, required)
+}
+
+// 同じステップのコードは2度使えない
+func TestMFAVerifyReplay(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-replay"
+	secret, _ := enrollMFA(t, userId)
+
+	// 確認に使ったコードは使えない
+	assert.EqualError(t, mfaUseCase.Verify(userId, totpCode(t, secret, 0)), "invalid code")
+
+	code := totpCode(t, secret, 1)
+	assert.NoError(t, mfaUseCase.Verify(userId, code))
+	assert.EqualError(t, mfaUseCase.Verify(userId, code), "invalid code")
+}
+
+// 回復コードは1度だけ使える(区切りと大文字小文字は問わない)
+func TestMFAVerifyRecoveryCode(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-recovery"
+	_, codes := enrollMFA(t, userId)
+
+	assert.NoError(t, mfaUseCase.Verify(userId, codes[0]))
+	assert.EqualError(t, mfaUseCase.Verify(userId, codes[0]), "invalid code")
+	assert.NoError(t, mfaUseCase.Verify(userId, strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))))
+}
+
+// 失敗回数がしきい値に達すると、正しいコードでもロックが解けるまで受け付けない
+func TestMFAVerifyLockout(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-lockout"
+	secret, _ := enrollMFA(t, userId)
+
+	for range accountPolicy.Threshold {
+		assert.EqualError(t, mfaUseCase.Verify(userId, "abcdef"), "invalid code")
+	}
+
+	err := mfaUseCase.Verify(userId, totpCode(t, secret, 1))
+	var locked *domain_auth.LockedError
+	assert.True(t, errors.As(err, &locked))
+	assert.WithinDuration(t, time.Now().Add(accountPolicy.Duration), locked.RetryAt, time.Minute)
+
+	// ロックを解くと使える
+	assert.NoError(t, mfaAttemptRepo.Reset(domain_auth.MFAKey(userId)))
+	assert.NoError(t, mfaUseCase.Verify(userId, totpCode(t, secret, 1)))
+}
+
+// コードの確認回数を数えるリポジトリ(確認に時間がかかる間に、他の試行が揃うようにする)
+type countingMFARepository struct {
+	repository_auth.IMFARepository
+	checked int32
+}
+
+func (r *countingMFARepository) UseRecoveryCode(userId string, hash string) (bool, error) {
+	atomic.AddInt32(&r.checked, 1)
+	time.Sleep(20 * time.Millisecond)
+	return r.IMFARepository.UseRecoveryCode(userId, hash)
+}
+
+// 同時の試行でも、しきい値を超えてコードを確認しない
+func TestMFAVerifyConcurrent(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-concurrent"
+	enrollMFA(t, userId)
+	repo := &countingMFARepository{IMFARepository: mfaRepo}
+	u := usecase_auth.NewMFAUsecase(logger, repo, infrastructure_auth.NewLoginAttemptMemoryRepository(logger), mockAuditRepo, accountPolicy, "backend")
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.Verify(userId, "wrong-code")
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&repo.checked), int32(accountPolicy.Threshold))
+	var locked *domain_auth.LockedError
+	assert.ErrorAs(t, u.Verify(userId, "wrong-code"), &locked)
+}
+
+// 回復コードを作り直すと以前のコードは使えない
+func TestMFARegenerateRecoveryCodes(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-regenerate"
+	secret, old := enrollMFA(t, userId)
+
+	_, err := mfaUseCase.RegenerateRecoveryCodes(userId, "abcdef")
+	assert.EqualError(t, err, "invalid code")
+
+	codes, err := mfaUseCase.RegenerateRecoveryCodes(userId, totpCode(t, secret, 1))
+	assert.NoError(t, err)
+	assert.Len(t, codes, domain_auth.RecoveryCodeCount)
+	assert.NotEqual(t, old, codes)
+
+	assert.EqualError(t, mfaUseCase.Verify(userId, old[0]), "invalid code")
+	assert.NoError(t, mfaUseCase.Verify(userId, codes[0]))
+}
+
+// 管理者が解除すると、パスワードのみで認証され、登録し直せる
+func TestMFAReset(t *testing.T) {
+	resetLockoutMocks()
+	userId := "mfa-reset"
+	enrollMFA(t, userId)
+
+	assert.NoError(t, mfaUseCase.Reset(userId, "admin-1"))
+
+	required, err := mfaUseCase.Required(userId)
+	assert.NoError(t, err)
+	assert.False(t, required)
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditMFAEnabled, domain_auth.AuditMFAReset}, auditActions())
+	log := mockAuditRepo.Calls[1].Arguments.Get(0).(domain_auth.AuditLog)
+	assert.Equal(t, "admin-1", log.ActorID)
+	assert.Equal(t, "user "+userId, log.Detail)
+
+	_, _, err = mfaUseCase.Enroll(userId, userId)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,37 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのOIDCログインのユースケース作成
+type MockOIDCUsecase struct {
+	mock.Mock
+}
+
+// Providersのモック
+func (m *MockOIDCUsecase) Providers() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+// Beginのモック
+func (m *MockOIDCUsecase) Begin(ctx context.Context, provider string) (domain_auth.OIDCFlow, string, error) {
+	args := m.Called(ctx, provider)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.OIDCFlow{}, "", args.Error(2)
+	}
+
+	return args.Get(0).(domain_auth.OIDCFlow), args.String(1), args.Error(2)
+}
+
+// Completeのモック
+func (m *MockOIDCUsecase) Complete(ctx context.Context, flow domain_auth.OIDCFlow, code string) (string, error) {
+	args := m.Called(ctx, flow, code)
+	return args.String(0), args.Error(1)
+}
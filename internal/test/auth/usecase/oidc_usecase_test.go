@@ -0,0 +1,147 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	test_stub "backend/internal/test/stub"
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// ブラウザの代わりに認可URLを開き、コールバックに渡される認可コードとstateを返す
+func authorize(t *testing.T, authURL string) (string, string) {
+	client := &http.Client{CheckRedirect: func(req *http.Request, via []*http.Request) error { return http.ErrUseLastResponse }}
+	res, err := client.Get(authURL)
+	if !assert.NoError(t, err) || !assert.Equal(t, http.StatusFound, res.StatusCode) {
+		t.FailNow()
+	}
+	res.Body.Close()
+	location, err := url.Parse(res.Header.Get("Location"))
+	assert.NoError(t, err)
+	assert.Equal(t, "/api/auth/oidc/fake/callback", location.Path)
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+// ログインのフローを実行する
+func oidcLogin(t *testing.T, user test_stub.OIDCUser) (string, error) {
+	oidcStub.SetUser(user)
+	flow, authURL, err := oidcUseCase.Begin(context.Background(), "fake")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	code, state := authorize(t, authURL)
+	assert.Equal(t, flow.State, state)
+	return oidcUseCase.Complete(context.Background(), flow, code)
+}
+
+// モックの挙動をリセットする
+func resetOIDCMocks() {
+	oidcStub.ModifyClaims = nil
+	mockIdentityRepo.ExpectedCalls = nil
+	mockIdentityRepo.Calls = nil
+	mockAuditRepo.ExpectedCalls = nil
+	mockAuditRepo.Calls = nil
+	mockAuditRepo.On("CreateAuditLog", mock.Anything).Return(nil)
+}
+
+// 紐付け済みのアカウントでログインする
+func TestOIDCLoginLinked(t *testing.T) {
+	resetOIDCMocks()
+	mockIdentityRepo.On("GetIdentity", "fake", "sub-1").Return(domain_auth.Identity{Provider: "fake", Subject: "sub-1", UserID: "user-1"}, nil)
+
+	userId, err := oidcLogin(t, test_stub.OIDCUser{Subject: "sub-1", Email: "linked@example.com", EmailVerified: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", userId)
+	mockIdentityRepo.AssertExpectations(t)
+	mockIdentityRepo.AssertNotCalled(t, "CreateIdentity", mock.Anything)
+}
+
+// 確認済みのメールアドレスで既存のユーザーに紐付ける
+func TestOIDCLoginLinksByVerifiedEmail(t *testing.T) {
+	resetOIDCMocks()
+	mockIdentityRepo.On("GetIdentity", "fake", "sub-2").Return(nil, errors.New("identity not found"))
+	mockIdentityRepo.On("GetUserIdByEmail", "existing@example.com").Return("user-2", nil)
+	mockIdentityRepo.On("CreateIdentity", domain_auth.Identity{Provider: "fake", Subject: "sub-2", UserID: "user-2", Email: "existing@example.com"}).Return(nil)
+
+	userId, err := oidcLogin(t, test_stub.OIDCUser{Subject: "sub-2", Email: "existing@example.com", EmailVerified: true})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-2", userId)
+	mockIdentityRepo.AssertExpectations(t)
+	assert.Equal(t, []domain_auth.AuditAction{domain_auth.AuditIdentityLinked}, auditActions())
+}
+
+// 未確認のメールアドレスでは紐付けない
+func TestOIDCLoginUnverifiedEmail(t *testing.T) {
+	resetOIDCMocks()
+	mockIdentityRepo.On("GetIdentity", "fake", "sub-3").Return(nil, errors.New("identity not found"))
+
+	_, err := oidcLogin(t, test_stub.OIDCUser{Subject: "sub-3", Email: "existing@example.com", EmailVerified: false})
+
+	assert.EqualError(t, err, "email is not verified")
+	mockIdentityRepo.AssertNotCalled(t, "GetUserIdByEmail", mock.Anything)
+	mockIdentityRepo.AssertNotCalled(t, "CreateIdentity", mock.Anything)
+}
+
+// 一致するユーザーがいない場合はユーザーを作成しない
+func TestOIDCLoginNoUser(t *testing.T) {
+	resetOIDCMocks()
+	mockIdentityRepo.On("GetIdentity", "fake", "sub-4").Return(nil, errors.New("identity not found"))
+	mockIdentityRepo.On("GetUserIdByEmail", "stranger@example.com").Return("", errors.New("user not found"))
+
+	_, err := oidcLogin(t, test_stub.OIDCUser{Subject: "sub-4", Email: "stranger@example.com", EmailVerified: true})
+
+	assert.EqualError(t, err, "no linked account")
+	mockIdentityRepo.AssertNotCalled(t, "CreateIdentity", mock.Anything)
+}
+
+// code_verifierが一致しない(PKCE)
+func TestOIDCLoginWrongVerifier(t *testing.T) {
+	resetOIDCMocks()
+	oidcStub.SetUser(test_stub.OIDCUser{Subject: "sub-1"})
+	flow, authURL, _ := oidcUseCase.Begin(context.Background(), "fake")
+	code, _ := authorize(t, authURL)
+
+	flow.Verifier = "stolen-code-without-verifier"
+	_, err := oidcUseCase.Complete(context.Background(), flow, code)
+
+	assert.EqualError(t, err, "identity provider error")
+	mockIdentityRepo.AssertNotCalled(t, "GetIdentity", mock.Anything, mock.Anything)
+}
+
+// IDトークンが不正(nonce・対象者・発行者)
+func TestOIDCLoginInvalidToken(t *testing.T) {
+	modifications := map[string]func(claims jwt.MapClaims){
+		"nonce":    func(claims jwt.MapClaims) { claims["nonce"] = "replayed" },
+		"audience": func(claims jwt.MapClaims) { claims["aud"] = "other-client" },
+		"issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://evil.example.com" },
+		"expired":  func(claims jwt.MapClaims) { claims["exp"] = int64(1) },
+	}
+	for name, modify := range modifications {
+		resetOIDCMocks()
+		oidcStub.ModifyClaims = modify
+
+		_, err := oidcLogin(t, test_stub.OIDCUser{Subject: "sub-1", Email: "linked@example.com", EmailVerified: true})
+
+		assert.EqualError(t, err, "invalid id token", name)
+		mockIdentityRepo.AssertNotCalled(t, "GetIdentity", mock.Anything, mock.Anything)
+	}
+}
+
+// 設定されていないプロバイダ
+func TestOIDCUnknownProvider(t *testing.T) {
+	_, _, err := oidcUseCase.Begin(context.Background(), "unknown")
+	assert.EqualError(t, err, "unknown provider")
+
+	_, err = oidcUseCase.Complete(context.Background(), domain_auth.OIDCFlow{Provider: "unknown"}, "code")
+	assert.EqualError(t, err, "unknown provider")
+
+	assert.Equal(t, []string{"fake"}, oidcUseCase.Providers())
+}
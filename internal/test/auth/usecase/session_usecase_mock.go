@@ -0,0 +1,54 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのログインのセッションのユースケース作成
+type MockSessionUsecase struct {
+	mock.Mock
+}
+
+// Createのモック
+func (m *MockSessionUsecase) Create(userId string, ip string, userAgent string) (domain_auth.Session, error) {
+	args := m.Called(userId, ip, userAgent)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_auth.Session{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_auth.Session), args.Error(1)
+}
+
+// Validateのモック
+func (m *MockSessionUsecase) Validate(id string, userId string) error {
+	args := m.Called(id, userId)
+	return args.Error(0)
+}
+
+// GetSessionsのモック
+func (m *MockSessionUsecase) GetSessions(userId string) ([]domain_auth.Session, error) {
+	args := m.Called(userId)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_auth.Session), args.Error(1)
+}
+
+// Revokeのモック
+func (m *MockSessionUsecase) Revoke(userId string, id string) error {
+	args := m.Called(userId, id)
+	return args.Error(0)
+}
+
+// RevokeAllのモック
+func (m *MockSessionUsecase) RevokeAll(userId string) (int, error) {
+	args := m.Called(userId)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,103 @@
+package test_auth_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	test_auth_repository "backend/internal/test/auth/infrastructure"
+	usecase_auth "backend/internal/usecase/auth"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// iPhoneのSafariのUser-Agent
+const iPhoneUserAgent = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+
+// ログインごとにセッションを作成し、一覧に表示する
+func TestSessionCreateAndList(t *testing.T) {
+	phone, err := sessionUseCase.Create("user-s1", "192.0.2.1", iPhoneUserAgent)
+	assert.NoError(t, err)
+	assert.Equal(t, "iPhone", phone.Device)
+	assert.Equal(t, "192.0.2.1", phone.IP)
+	laptop, err := sessionUseCase.Create("user-s1", "192.0.2.2", "curl/8.4.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "curl", laptop.Device)
+	_, err = sessionUseCase.Create("user-s2", "192.0.2.3", "")
+	assert.NoError(t, err)
+
+	sessions, err := sessionUseCase.GetSessions("user-s1")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	assert.NoError(t, sessionUseCase.Validate(phone.ID, "user-s1"))
+	// 別のユーザーのトークンにセッションIDを入れても通らない
+	assert.EqualError(t, sessionUseCase.Validate(phone.ID, "user-s2"), "invalid session")
+	assert.EqualError(t, sessionUseCase.Validate("unknown", "user-s1"), "invalid session")
+}
+
+// 失効させたセッションはすぐに使えなくなる
+func TestSessionRevoke(t *testing.T) {
+	s, _ := sessionUseCase.Create("user-s3", "192.0.2.1", iPhoneUserAgent)
+	other, _ := sessionUseCase.Create("user-s3", "192.0.2.2", iPhoneUserAgent)
+	assert.NoError(t, sessionUseCase.Validate(s.ID, "user-s3"))
+
+	// 別のユーザーのセッションは失効できない
+	assert.EqualError(t, sessionUseCase.Revoke("user-s4", s.ID), "session not found")
+
+	assert.NoError(t, sessionUseCase.Revoke("user-s3", s.ID))
+	assert.EqualError(t, sessionUseCase.Validate(s.ID, "user-s3"), "invalid session")
+	assert.EqualError(t, sessionUseCase.Revoke("user-s3", s.ID), "session not found")
+	assert.NoError(t, sessionUseCase.Validate(other.ID, "user-s3"))
+
+	sessions, _ := sessionUseCase.GetSessions("user-s3")
+	assert.Len(t, sessions, 1)
+}
+
+// 全ての端末からログアウト
+func TestSessionRevokeAll(t *testing.T) {
+	first, _ := sessionUseCase.Create("user-s5", "192.0.2.1", iPhoneUserAgent)
+	second, _ := sessionUseCase.Create("user-s5", "192.0.2.2", iPhoneUserAgent)
+	kept, _ := sessionUseCase.Create("user-s6", "192.0.2.3", iPhoneUserAgent)
+	assert.NoError(t, sessionUseCase.Validate(first.ID, "user-s5"))
+
+	count, err := sessionUseCase.RevokeAll("user-s5")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	assert.EqualError(t, sessionUseCase.Validate(first.ID, "user-s5"), "invalid session")
+	assert.EqualError(t, sessionUseCase.Validate(second.ID, "user-s5"), "invalid session")
+	assert.NoError(t, sessionUseCase.Validate(kept.ID, "user-s6"))
+}
+
+// 別のインスタンスでの失効は、キャッシュの期限が切れると反映される
+func TestSessionRevokeOtherInstance(t *testing.T) {
+	other := usecase_auth.NewSessionUsecase(logger, sessionRepo, mockRoleRepo, time.Hour)
+	s, _ := sessionUseCase.Create("user-s7", "192.0.2.1", iPhoneUserAgent)
+	assert.NoError(t, sessionUseCase.Validate(s.ID, "user-s7"))
+
+	assert.NoError(t, other.Revoke("user-s7", s.ID))
+	assert.Eventually(t, func() bool {
+		return sessionUseCase.Validate(s.ID, "user-s7") != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+// セッションには作成時のユーザーのロールを設定する(アクセストークンに含める)
+func TestSessionCreateRole(t *testing.T) {
+	roleRepo := new(test_auth_repository.MockRoleRepository)
+	roleRepo.On("GetRole", "user-admin").Return(domain_auth.RoleAdmin, nil)
+	roleRepo.On("GetRole", "user-x").Return("", errors.New("user not found"))
+	u := usecase_auth.NewSessionUsecase(logger, sessionRepo, roleRepo, time.Hour)
+
+	s, err := u.Create("user-admin", "192.0.2.1", iPhoneUserAgent)
+	assert.NoError(t, err)
+	assert.Equal(t, domain_auth.RoleAdmin, s.Role)
+
+	// ロールを取得できないユーザーのセッションは作成しない
+	_, err = u.Create("user-x", "192.0.2.1", iPhoneUserAgent)
+	assert.EqualError(t, err, "failed to create session")
+	sessions, _ := u.GetSessions("user-x")
+	assert.Empty(t, sessions)
+
+	roleRepo.AssertExpectations(t)
+}
@@ -0,0 +1,25 @@
+package test_fetch_repository
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのリポジトリ作成
+type MockUpstreamRepository struct {
+	mock.Mock
+}
+
+// Doのモック
+func (m *MockUpstreamRepository) Do(ctx context.Context, req domain_fetch.UpstreamRequest) (domain_fetch.UpstreamResponse, error) {
+	args := m.Called(ctx, req)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_fetch.UpstreamResponse{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_fetch.UpstreamResponse), args.Error(1)
+}
@@ -0,0 +1,57 @@
+package test_fetch_handler
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Postsのテスト(正常系)
+func TestAggregatePosts(t *testing.T) {
+	res := call(http.MethodGet, "/api/fetch/posts?fields=id,%20title,comments.id&page=3", "", aggregateHandler.Posts)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{
+		"items": [
+			{"id":5,"title":"Post 5","comments":[{"id":9},{"id":10}]},
+			{"id":6,"title":"Post 6","comments":[{"id":11},{"id":12}]}
+		],
+		"page":3,"per_page":2,"total":6,"total_pages":3
+	}`, res.Body.String())
+}
+
+// Usersのテスト(正常系)
+func TestAggregateUsers(t *testing.T) {
+	res := call(http.MethodGet, "/api/fetch/users?fields=id,albums.title&per_page=1", "", aggregateHandler.Users)
+
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `{"items":[{"id":1,"albums":[{"title":"Album 1"},{"title":"Album 2"}]}],"page":1,"per_page":1,"total":3,"total_pages":3}`, res.Body.String())
+}
+
+// 集約のテスト(入力チェック)
+func TestAggregateValidation(t *testing.T) {
+	res := call(http.MethodGet, "/api/fetch/posts?fields=id,albums&page=0&per_page=6", "", aggregateHandler.Posts)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Equal(t, []string{"fields", "page", "per_page"}, errorFields(t, res))
+	assert.Contains(t, res.Body.String(), "unknown field: albums")
+
+	res = call(http.MethodGet, "/api/fetch/users?per_page=abc", "", aggregateHandler.Users)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Equal(t, []string{"per_page"}, errorFields(t, res))
+
+	// ページ番号の上限
+	res = call(http.MethodGet, "/api/fetch/posts?page=100000000000000000&per_page=100", "", aggregateHandler.Posts)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Equal(t, []string{"page", "per_page"}, errorFields(t, res))
+}
+
+// 集約のテスト(上流APIの失敗)
+func TestAggregateUpstreamError(t *testing.T) {
+	stub.Fail("albums", http.StatusServiceUnavailable)
+	defer stub.Fail("albums", 0)
+
+	res := call(http.MethodGet, "/api/fetch/users", "", aggregateHandler.Users)
+	assert.Equal(t, http.StatusBadGateway, res.Code)
+	assert.JSONEq(t, `{"error":"upstream request failed"}`, res.Body.String())
+}
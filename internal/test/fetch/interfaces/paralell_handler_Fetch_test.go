@@ -0,0 +1,137 @@
+package test_fetch_handler
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// リクエストを送ってレスポンスを返す
+func call(method, target, body string, h echo.HandlerFunc) *httptest.ResponseRecorder {
+	e := echo.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	h(e.NewContext(req, res))
+	return res
+}
+
+// 422のエラー項目
+func errorFields(t *testing.T, res *httptest.ResponseRecorder) []string {
+	var body struct {
+		Errors []struct {
+			Field string `json:"field"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &body); err != nil {
+		t.FailNow()
+	}
+	fields := []string{}
+	for _, e := range body.Errors {
+		fields = append(fields, e.Field)
+	}
+	return fields
+}
+
+// Fetchのテスト(正常系)
+func TestFetch(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	result := domain_fetch.FetchResult{
+		Results: domain_fetch.UpstreamResults{
+			{Name: "users", Status: 200, Size: 2, Body: json.RawMessage(`[]`), Cache: "HIT"},
+			{Name: "posts", Status: 500, Error: "boom", Cache: "BYPASS"},
+		},
+		Timing: domain_fetch.FetchTiming{Mode: "parallel", Concurrency: 3},
+	}
+
+	// モックの挙動を設定
+	mockUsecase.On("Fetch", mock.Anything, []domain_fetch.UpstreamRequest{
+		{Name: "users", Path: "/users"},
+		{Name: "posts", Method: "post", Path: "/posts"},
+	}, domain_fetch.FetchOptions{Concurrency: 3, Deadline: 2 * time.Second, ErrorMode: domain_fetch.FailFast}).Return(result, nil)
+
+	// ハンドラのメソッドを呼び出し
+	res := call(http.MethodPost, "/api/fetch", `{"requests":[{"name":"users","path":"/users"},{"name":"posts","method":"post","path":"/posts"}],"concurrency":3,"deadline_ms":2000,"error_mode":"fail_fast"}`, handler.Fetch)
+
+	// 検証(名前をキーにしてリクエストの順に並ぶ)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Contains(t, res.Body.String(), `"results":{"users":{"status":200,"latency_ns":0,"cache":"HIT","size":2,"body":[]},"posts":{"status":500,"latency_ns":0,"cache":"BYPASS","size":0,"error":"boom"}}`)
+	assert.Equal(t, "users=HIT, posts=BYPASS", res.Header().Get("X-Cache"))
+	mockUsecase.AssertExpectations(t)
+}
+
+// Fetchのテスト(入力チェック)
+func TestFetchValidation(t *testing.T) {
+	// モックの挙動と呼び出し履歴をリセット
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.Calls = nil
+
+	cases := map[string][]string{
+		`{"requests":[{"name":"a","path":"/a"}],"concurrency":0}`: {"concurrency"},
+		`{"requests":[{"name":"a","path":"/a"}],"concurrency":5}`: {"concurrency"},
+		`{"requests":[]}`: {"requests"},
+		`{"requests":[{"name":"a","path":"/a"},{"name":"b","path":"/b"},{"name":"c","path":"/c"},{"name":"d","path":"/d"}]}`: {"requests"},
+		`{"requests":[{"name":"a","path":"http://evil.example/a"},{"path":"/b"}]}`:                                           {"requests[0].path", "requests[1].name"},
+		`{"requests":[{"name":"a","method":"TRACE","path":"/a"},{"name":"a","path":"/b"}]}`:                                  {"requests[0].method"},
+		`{"requests":[{"name":"a","path":"/a"},{"name":"a","path":"/b"}]}`:                                                   {"requests[1].name"},
+	}
+	for body, fields := range cases {
+		res := call(http.MethodPost, "/api/fetch", body, handler.Fetch)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.Code, body)
+		assert.Equal(t, fields, errorFields(t, res), body)
+	}
+	mockUsecase.AssertNotCalled(t, "Fetch", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Fetchのテスト(異常系 - GET/HEAD以外で比較)
+func TestFetchCompareUnsafe(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("Fetch", mock.Anything, mock.Anything, mock.MatchedBy(func(o domain_fetch.FetchOptions) bool { return o.Compare })).
+		Return(domain_fetch.FetchResult{}, errors.New("compare is only allowed for GET or HEAD"))
+
+	// ハンドラのメソッドを呼び出し
+	res := call(http.MethodPost, "/api/fetch", `{"requests":[{"name":"posts","method":"post","path":"/posts"}],"compare":true}`, handler.Fetch)
+
+	// 検証
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.JSONEq(t, `{"error": "compare is only allowed for GET or HEAD"}`, res.Body.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+// ExecParallel/ExecSeriesのテスト(同時実行数の指定)
+func TestExecParallelSeries(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("Fetch", mock.Anything, mock.Anything, domain_fetch.FetchOptions{Concurrency: 4, Compare: true, Deadline: time.Second}).
+		Return(domain_fetch.FetchResult{Timing: domain_fetch.FetchTiming{Mode: "parallel"}}, nil)
+	mockUsecase.On("Fetch", mock.Anything, mock.Anything, domain_fetch.FetchOptions{Concurrency: 1, Deadline: time.Second}).
+		Return(domain_fetch.FetchResult{Timing: domain_fetch.FetchTiming{Mode: "series"}}, nil)
+
+	// ハンドラのメソッドを呼び出し
+	res := call(http.MethodGet, "/api/fetch/parallel?concurrency=4&compare=true", "", handler.ExecParallel)
+	assert.Equal(t, http.StatusOK, res.Code)
+	res = call(http.MethodGet, "/api/fetch/series", "", handler.ExecSeries)
+	assert.Equal(t, http.StatusOK, res.Code)
+	res = call(http.MethodGet, "/api/fetch/parallel?concurrency=abc&deadline_ms=-1&error_mode=x", "", handler.ExecParallel)
+	assert.Equal(t, http.StatusUnprocessableEntity, res.Code)
+	assert.Equal(t, []string{"concurrency", "deadline_ms", "error_mode"}, errorFields(t, res))
+
+	mockUsecase.AssertExpectations(t)
+}
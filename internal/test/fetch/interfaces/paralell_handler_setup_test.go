@@ -0,0 +1,61 @@
+package test_fetch_handler
+
+import (
+	pkg_config "backend/config"
+	infrastructure_fetch "backend/internal/infrastructure/fetch"
+	interfaces_paralell "backend/internal/interfaces/paralell"
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	pkg_logger "backend/internal/pkg/logger"
+	test_fetch_usecase "backend/internal/test/fetch/usecase"
+	test_stub "backend/internal/test/stub"
+	usecase_fetch "backend/internal/usecase/fetch"
+	"os"
+	"testing"
+	"time"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger      *pkg_logger.AppLogger
+	handler     *interfaces_paralell.ParalellHandler
+	mockUsecase *test_fetch_usecase.MockFetchUsecase
+
+	stub             *test_stub.JSONPlaceholder
+	aggregateHandler *interfaces_paralell.AggregateHandler
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+	appConfig.Fetch.Concurrency = 2
+	appConfig.Fetch.MaxConcurrency = 4
+	appConfig.Fetch.MaxRequests = 3
+	appConfig.Fetch.Deadline = time.Second
+	appConfig.Fetch.MaxDeadline = 5 * time.Second
+	appConfig.Fetch.AggregatePerPage = 2
+	appConfig.Fetch.AggregateMaxPerPage = 5
+	appConfig.Fetch.AggregateMaxPage = 100
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// モック
+	mockUsecase = new(test_fetch_usecase.MockFetchUsecase)
+	handler = interfaces_paralell.NewParalellHandler(appConfig, logger, mockUsecase)
+
+	// スタブサーバー
+	stub = test_stub.NewJSONPlaceholder(3)
+	client := pkg_httpclient.NewClient(logger, pkg_httpclient.Options{Timeout: time.Second})
+	aggregateUsecase := usecase_fetch.NewAggregateUsecase(logger, infrastructure_fetch.NewUpstreamRepository(logger, stub.URL, client))
+	aggregateHandler = interfaces_paralell.NewAggregateHandler(appConfig, logger, aggregateUsecase)
+
+	// テスト実行
+	code := m.Run()
+	stub.Close()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,128 @@
+package test_fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Aggregateのテスト(投稿にコメントを結合する)
+func TestAggregatePosts(t *testing.T) {
+	result, err := aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{
+		Resource: domain_fetch.AggregatePosts,
+		Page:     2,
+		PerPage:  2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, result.Total)
+	assert.Equal(t, 3, result.TotalPages)
+	assert.Len(t, result.Items, 2)
+
+	// 型付きの構造体に戻して確認する
+	data, _ := json.Marshal(result.Items)
+	var posts []domain_fetch.PostWithComments
+	assert.NoError(t, json.Unmarshal(data, &posts))
+	assert.Equal(t, 3, posts[0].ID)
+	assert.Equal(t, 4, posts[1].ID)
+	for _, p := range posts {
+		assert.Len(t, p.Comments, 2)
+		for _, c := range p.Comments {
+			assert.Equal(t, p.ID, c.PostID)
+		}
+	}
+}
+
+// Aggregateのテスト(項目の選択)
+func TestAggregateFields(t *testing.T) {
+	result, err := aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{
+		Resource: domain_fetch.AggregatePosts,
+		Fields:   []string{"id", "comments.email"},
+		Page:     1,
+		PerPage:  1,
+	})
+
+	assert.NoError(t, err)
+	data, _ := json.Marshal(result.Items)
+	assert.JSONEq(t, `[{"id":1,"comments":[{"email":"commenter1@example.com"},{"email":"commenter2@example.com"}]}]`, string(data))
+}
+
+// Aggregateのテスト(ユーザーにアルバムを結合する)
+func TestAggregateUsers(t *testing.T) {
+	result, err := aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{
+		Resource: domain_fetch.AggregateUsers,
+		Fields:   []string{"username", "albums"},
+		Page:     1,
+		PerPage:  10,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Equal(t, 1, result.TotalPages)
+	data, _ := json.Marshal(result.Items[2])
+	assert.JSONEq(t, `{"username":"user3","albums":[{"id":5,"userId":3,"title":"Album 5"},{"id":6,"userId":3,"title":"Album 6"}]}`, string(data))
+}
+
+// Aggregateのテスト(範囲外のページは空)
+func TestAggregateOutOfRange(t *testing.T) {
+	result, err := aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{
+		Resource: domain_fetch.AggregateUsers,
+		Page:     5,
+		PerPage:  2,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{}, result.Items)
+	assert.Equal(t, 2, result.TotalPages)
+
+	// ページ番号が大きくても範囲の計算が溢れない
+	result, err = aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{
+		Resource: domain_fetch.AggregatePosts,
+		Page:     100000000000000000,
+		PerPage:  100,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{}, result.Items)
+}
+
+// Paginateのテスト
+func TestPaginate(t *testing.T) {
+	cases := []struct {
+		total, page, perPage   int
+		start, end, totalPages int
+	}{
+		{5, 1, 2, 0, 2, 3},
+		{5, 3, 2, 4, 5, 3},
+		{5, 4, 2, 5, 5, 3},
+		{0, 1, 10, 0, 0, 0},
+		{100, math.MaxInt, 100, 100, 100, 1},
+		{100, 100000000000000000, 100, 100, 100, 1},
+	}
+	for _, tc := range cases {
+		start, end, totalPages := domain_fetch.Paginate(tc.total, tc.page, tc.perPage)
+		assert.Equal(t, []int{tc.start, tc.end, tc.totalPages}, []int{start, end, totalPages}, "%+v", tc)
+	}
+}
+
+// Aggregateのテスト(異常系)
+func TestAggregateError(t *testing.T) {
+	_, err := aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{Resource: domain_fetch.AggregatePosts, Fields: []string{"comments.phone"}, Page: 1, PerPage: 1})
+	assert.EqualError(t, err, "unknown field")
+
+	_, err = aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{Resource: "photos", Page: 1, PerPage: 1})
+	assert.EqualError(t, err, "unknown resource")
+
+	_, err = aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{Resource: domain_fetch.AggregatePosts, Page: 0, PerPage: 1})
+	assert.EqualError(t, err, "page and per_page must be positive")
+
+	// 上流APIの失敗
+	stub.Fail("comments", http.StatusInternalServerError)
+	defer stub.Fail("comments", 0)
+	_, err = aggregateUseCase.Aggregate(context.Background(), domain_fetch.AggregateQuery{Resource: domain_fetch.AggregatePosts, Page: 1, PerPage: 1})
+	assert.EqualError(t, err, "upstream request failed")
+}
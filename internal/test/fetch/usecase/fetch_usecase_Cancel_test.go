@@ -0,0 +1,124 @@
+package test_fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// コンテキストが終わるまで応答しない上流API
+func hang(args mock.Arguments) {
+	<-args.Get(0).(context.Context).Done()
+}
+
+// goroutineの数が基準以下に戻るまで待つ
+func assertNoLeak(t *testing.T, base int) {
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > base {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines leaked: %d > %d", runtime.NumGoroutine(), base)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Fetchのテスト(全体の期限)
+func TestFetchDeadline(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	base := runtime.NumGoroutine()
+
+	mockRepo.On("Do", mock.Anything, named("fast")).Return(domain_fetch.UpstreamResponse{Status: 200}, nil)
+	mockRepo.On("Do", mock.Anything, named("slow")).Run(hang).Return(domain_fetch.UpstreamResponse{}, context.Canceled)
+
+	// ユースケースのメソッドを呼び出し
+	start := time.Now()
+	result, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{
+		{Name: "fast", Path: "/fast"},
+		{Name: "slow", Path: "/slow"},
+		{Name: "later", Path: "/later"},
+	}, domain_fetch.FetchOptions{Concurrency: 1, Deadline: 50 * time.Millisecond})
+
+	// 検証(期限で打ち切り、開始していないものも期限切れとする)
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, domain_fetch.OutcomeSucceeded, result.Results[0].Outcome)
+	assert.Equal(t, domain_fetch.OutcomeTimedOut, result.Results[1].Outcome)
+	assert.Equal(t, domain_fetch.OutcomeTimedOut, result.Results[2].Outcome)
+	assert.Equal(t, "not started", result.Results[2].Error)
+	assert.Equal(t, domain_fetch.FetchSummary{Succeeded: 1, TimedOut: 2, Partial: true}, result.Summary)
+	assertNoLeak(t, base)
+}
+
+// Fetchのテスト(fail_fastでは最初の失敗で残りを中断する)
+func TestFetchFailFast(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	base := runtime.NumGoroutine()
+
+	mockRepo.On("Do", mock.Anything, named("broken")).After(10*time.Millisecond).Return(domain_fetch.UpstreamResponse{Status: 500}, nil)
+	mockRepo.On("Do", mock.Anything, named("slow")).Run(hang).Return(domain_fetch.UpstreamResponse{}, context.Canceled)
+
+	// ユースケースのメソッドを呼び出し
+	start := time.Now()
+	result, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{
+		{Name: "slow", Path: "/slow"},
+		{Name: "broken", Path: "/broken"},
+	}, domain_fetch.FetchOptions{Concurrency: 2, ErrorMode: domain_fetch.FailFast})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+	assert.Equal(t, domain_fetch.OutcomeCanceled, result.Results[0].Outcome)
+	assert.Equal(t, domain_fetch.OutcomeFailed, result.Results[1].Outcome)
+	assert.Equal(t, domain_fetch.FetchSummary{Failed: 1, Canceled: 1, Partial: true}, result.Summary)
+	assertNoLeak(t, base)
+}
+
+// Fetchのテスト(collect_allでは失敗しても残りを続ける)
+func TestFetchCollectAll(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	mockRepo.On("Do", mock.Anything, named("broken")).Return(domain_fetch.UpstreamResponse{Status: 500}, nil)
+	mockRepo.On("Do", mock.Anything, named("ok")).Return(domain_fetch.UpstreamResponse{Status: 200}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{
+		{Name: "broken", Path: "/broken"},
+		{Name: "ok", Path: "/ok"},
+	}, domain_fetch.FetchOptions{Concurrency: 1})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, domain_fetch.FetchSummary{Succeeded: 1, Failed: 1, Partial: true}, result.Summary)
+}
+
+// Fetchのテスト(呼び出し元のキャンセルで全て中断する)
+func TestFetchCallerCanceled(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	base := runtime.NumGoroutine()
+
+	mockRepo.On("Do", mock.Anything, mock.Anything).Run(hang).Return(domain_fetch.UpstreamResponse{}, context.Canceled)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Fetch(ctx, []domain_fetch.UpstreamRequest{
+		{Name: "a", Path: "/a"},
+		{Name: "b", Path: "/b"},
+		{Name: "c", Path: "/c"},
+	}, domain_fetch.FetchOptions{Concurrency: 2, Deadline: time.Minute})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, domain_fetch.FetchSummary{Canceled: 3, Partial: true}, result.Summary)
+	assertNoLeak(t, base)
+}
@@ -0,0 +1,158 @@
+package test_fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 名前が一致するリクエスト
+func named(name string) interface{} {
+	return mock.MatchedBy(func(r domain_fetch.UpstreamRequest) bool { return r.Name == name })
+}
+
+// Fetchのテスト(完了順に関係なくリクエストの順に並ぶ)
+func TestFetchOrder(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// 先頭のリクエストほど遅く返す
+	for i, name := range []string{"a", "b", "c"} {
+		delay := time.Duration(3-i) * 10 * time.Millisecond
+		res := domain_fetch.UpstreamResponse{Status: 200, LatencyNs: delay.Nanoseconds()}
+		res.SetBody([]byte(`{"name":"` + name + `"}`))
+		mockRepo.On("Do", mock.Anything, named(name)).After(delay).Return(res, nil)
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{
+		{Name: "a", Path: "/a"},
+		{Name: "b", Path: "/b"},
+		{Name: "c", Path: "/c"},
+	}, domain_fetch.FetchOptions{Concurrency: 3})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "parallel", result.Timing.Mode)
+	data, _ := json.Marshal(result.Results)
+	assert.Equal(t, `{"a":{"outcome":"succeeded","status":200,"latency_ns":30000000,"size":12,"body":{"name":"a"}},"b":{"outcome":"succeeded","status":200,"latency_ns":20000000,"size":12,"body":{"name":"b"}},"c":{"outcome":"succeeded","status":200,"latency_ns":10000000,"size":12,"body":{"name":"c"}}}`, string(data))
+	assert.Equal(t, int64(60000000), result.Timing.TotalLatencyNs)
+}
+
+// Fetchのテスト(同時実行数の上限を超えない)
+func TestFetchConcurrencyLimit(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	var running, peak int32
+	mockRepo.On("Do", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}).Return(domain_fetch.UpstreamResponse{Status: 200}, nil)
+
+	reqs := []domain_fetch.UpstreamRequest{}
+	for _, name := range []string{"1", "2", "3", "4", "5", "6", "7", "8"} {
+		reqs = append(reqs, domain_fetch.UpstreamRequest{Name: name, Path: "/" + name})
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Fetch(context.Background(), reqs, domain_fetch.FetchOptions{Concurrency: 2, Compare: true})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, result.Results, 8)
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+	assert.NotNil(t, result.Timing.SeriesNs)
+	assert.NotNil(t, result.Timing.ParallelNs)
+	assert.Less(t, *result.Timing.ParallelNs, *result.Timing.SeriesNs)
+}
+
+// Fetchのテスト(失敗したリクエストは結果に記録する)
+func TestFetchUpstreamError(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	mockRepo.On("Do", mock.Anything, named("ok")).Return(domain_fetch.UpstreamResponse{Status: 404}, nil)
+	mockRepo.On("Do", mock.Anything, named("ng")).Return(domain_fetch.UpstreamResponse{}, errors.New("connection refused"))
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{
+		{Name: "ok", Path: "/missing"},
+		{Name: "ng", Path: "/down"},
+	}, domain_fetch.FetchOptions{Concurrency: 1})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "series", result.Timing.Mode)
+	assert.Equal(t, 404, result.Results[0].Status)
+	assert.Equal(t, domain_fetch.OutcomeFailed, result.Results[0].Outcome)
+	assert.Equal(t, "connection refused", result.Results[1].Error)
+	assert.Equal(t, domain_fetch.OutcomeFailed, result.Results[1].Outcome)
+	assert.Equal(t, domain_fetch.FetchSummary{Failed: 2, Partial: true}, result.Summary)
+}
+
+// Fetchのテスト(ホップバイホップ・認証情報のヘッダは転送しない)
+func TestFetchDropsHeaders(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("Do", mock.Anything, mock.MatchedBy(func(r domain_fetch.UpstreamRequest) bool {
+		return assert.ObjectsAreEqual(map[string]string{"Accept": "application/json"}, r.Headers)
+	})).Return(domain_fetch.UpstreamResponse{Status: 200}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{{Name: "a", Path: "/a", Headers: map[string]string{
+		"Accept":              "application/json",
+		"authorization":       "Bearer secret",
+		"Cookie":              "session=1",
+		"Proxy-Authorization": "Basic eA==",
+		"Connection":          "keep-alive, X-Internal",
+		"X-Internal":          "1",
+		"Transfer-Encoding":   "chunked",
+		"Host":                "evil.example",
+	}}}, domain_fetch.FetchOptions{Concurrency: 1})
+
+	// 検証
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+// Fetchのテスト(異常系)
+func TestFetchError(t *testing.T) {
+	cases := map[string][]domain_fetch.UpstreamRequest{
+		"requests is empty":     {},
+		"name is empty":         {{Path: "/a"}},
+		"method is not allowed": {{Name: "a", Method: "TRACE", Path: "/a"}},
+		"path must be relative": {{Name: "a", Path: "http://example.com/a"}},
+		"name is duplicated":    {{Name: "a", Path: "/a"}, {Name: "a", Path: "/b"}},
+	}
+	for expected, reqs := range cases {
+		_, err := useCase.Fetch(context.Background(), reqs, domain_fetch.FetchOptions{Concurrency: 1})
+		assert.EqualError(t, err, expected)
+	}
+
+	_, err := useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{{Name: "a", Path: "/a"}}, domain_fetch.FetchOptions{ErrorMode: "sometimes"})
+	assert.EqualError(t, err, "error mode is unknown")
+
+	// 比較はGET/HEAD以外では使えない(上流APIには送らない)
+	mockRepo.ExpectedCalls = nil
+	mockRepo.Calls = nil
+	_, err = useCase.Fetch(context.Background(), []domain_fetch.UpstreamRequest{{Name: "a", Path: "/a"}, {Name: "b", Method: "post", Path: "/b"}}, domain_fetch.FetchOptions{Concurrency: 2, Compare: true})
+	assert.EqualError(t, err, "compare is only allowed for GET or HEAD")
+	mockRepo.AssertNotCalled(t, "Do", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,25 @@
+package test_fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのユースケース作成
+type MockFetchUsecase struct {
+	mock.Mock
+}
+
+// Fetchのモック
+func (m *MockFetchUsecase) Fetch(ctx context.Context, reqs []domain_fetch.UpstreamRequest, opts domain_fetch.FetchOptions) (domain_fetch.FetchResult, error) {
+	args := m.Called(ctx, reqs, opts)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_fetch.FetchResult{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_fetch.FetchResult), args.Error(1)
+}
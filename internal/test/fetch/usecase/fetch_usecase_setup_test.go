@@ -0,0 +1,51 @@
+package test_fetch_usecase
+
+import (
+	pkg_config "backend/config"
+	infrastructure_fetch "backend/internal/infrastructure/fetch"
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	pkg_logger "backend/internal/pkg/logger"
+	test_fetch_repository "backend/internal/test/fetch/infrastructure"
+	test_stub "backend/internal/test/stub"
+	usecase_fetch "backend/internal/usecase/fetch"
+	"os"
+	"testing"
+	"time"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger   *pkg_logger.AppLogger
+	useCase  usecase_fetch.IFetchUsecase
+	mockRepo *test_fetch_repository.MockUpstreamRepository
+
+	stub             *test_stub.JSONPlaceholder
+	aggregateUseCase usecase_fetch.IAggregateUsecase
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// モック
+	mockRepo = new(test_fetch_repository.MockUpstreamRepository)
+	useCase = usecase_fetch.NewFetchUsecase(logger, mockRepo)
+
+	// スタブサーバー
+	stub = test_stub.NewJSONPlaceholder(3)
+	client := pkg_httpclient.NewClient(logger, pkg_httpclient.Options{Timeout: time.Second})
+	aggregateUseCase = usecase_fetch.NewAggregateUsecase(logger, infrastructure_fetch.NewUpstreamRepository(logger, stub.URL, client))
+
+	// テスト実行
+	code := m.Run()
+	stub.Close()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,278 @@
+package test_httpcache
+
+import (
+	pkg_httpcache "backend/internal/pkg/httpcache"
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 呼び出し回数を数えるサーバー
+func newServer(t *testing.T, hits *int32, handler http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(hits, 1)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// キャッシュの作成
+func newCache(opts pkg_httpcache.Options) *pkg_httpcache.Cache {
+	client := pkg_httpclient.NewClient(logger, pkg_httpclient.Options{Timeout: time.Second})
+	return pkg_httpcache.NewCache(logger, client, pkg_httpcache.NewMemoryStore(100), opts)
+}
+
+// GETリクエスト
+func get(url string) pkg_httpclient.Request {
+	return pkg_httpclient.Request{Method: http.MethodGet, URL: url}
+}
+
+// 条件が満たされるまで待つ
+func waitFor(t *testing.T, cond func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// Doのテスト(2回目はキャッシュを返す)
+func TestDoHit(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[1,2,3]`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute})
+
+	res, err := cache.Do(context.Background(), get(server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusMiss, res.Cache)
+
+	res, err = cache.Do(context.Background(), get(server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusHit, res.Cache)
+	assert.Equal(t, `[1,2,3]`, string(res.Body))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(同時のリクエストは1つにまとめる)
+func TestDoCoalesce(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{}`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute})
+
+	var wg sync.WaitGroup
+	statuses := make([]int, 10)
+	for i := range statuses {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			res, err := cache.Do(context.Background(), get(server.URL))
+			if err == nil {
+				statuses[i] = res.Status
+			}
+		}(i)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&hits) == 1 })
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	for _, s := range statuses {
+		assert.Equal(t, http.StatusOK, s)
+	}
+}
+
+// Doのテスト(鮮度が切れたら古いエントリを返し、裏で再検証する)
+func TestDoStaleWhileRevalidate(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&hits) == 1 {
+			w.Write([]byte(`"v1"`))
+			return
+		}
+		w.Write([]byte(`"v2"`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: 20 * time.Millisecond, StaleWhileRevalidate: time.Minute})
+
+	cache.Do(context.Background(), get(server.URL))
+	time.Sleep(30 * time.Millisecond)
+
+	res, err := cache.Do(context.Background(), get(server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusStale, res.Cache)
+	assert.Equal(t, `"v1"`, string(res.Body))
+
+	// 再検証が終わると新しい内容を返す
+	waitFor(t, func() bool {
+		res, _ := cache.Do(context.Background(), get(server.URL))
+		return res.Cache == pkg_httpcache.StatusHit && string(res.Body) == `"v2"`
+	})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(ETagで再検証する)
+func TestDoRevalidate(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: 10 * time.Millisecond, Retention: time.Minute})
+
+	cache.Do(context.Background(), get(server.URL))
+	time.Sleep(20 * time.Millisecond)
+
+	res, err := cache.Do(context.Background(), get(server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusRevalidated, res.Cache)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, `{"id":1}`, string(res.Body))
+
+	// 再検証で鮮度が更新される
+	res, _ = cache.Do(context.Background(), get(server.URL))
+	assert.Equal(t, pkg_httpcache.StatusHit, res.Cache)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(ヘッダが違うリクエストは別々にキャッシュする)
+func TestDoHeaders(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Header().Set("Vary", "Accept")
+		w.Write([]byte(r.Header.Get("Accept")))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute})
+
+	// 失敗で途中で終わってもサーバーを止められるようにする
+	t.Cleanup(unblock)
+
+	// 同時のリクエストでも、他のヘッダの結果を受け取らない
+	var wg sync.WaitGroup
+	bodies := map[string]string{}
+	var mu sync.Mutex
+	for _, accept := range []string{"text/csv", "application/json"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := get(server.URL)
+			req.Headers = map[string]string{"accept": accept}
+			res, err := cache.Do(context.Background(), req)
+			if err == nil {
+				mu.Lock()
+				bodies[accept] = string(res.Body)
+				mu.Unlock()
+			}
+		}()
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&hits) == 2 })
+	unblock()
+	wg.Wait()
+	assert.Equal(t, map[string]string{"text/csv": "text/csv", "application/json": "application/json"}, bodies)
+
+	// ヘッダ名の大文字・小文字は区別しない
+	req := get(server.URL)
+	req.Headers = map[string]string{"Accept": "text/csv"}
+	res, err := cache.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusHit, res.Cache)
+	assert.Equal(t, "text/csv", string(res.Body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(no-cacheは古いまま返さず、毎回再検証する)
+func TestDoNoCache(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"abc"`)
+		if r.Header.Get("If-None-Match") == `"abc"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"id":1}`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute, StaleWhileRevalidate: time.Minute, Retention: time.Minute})
+
+	res, err := cache.Do(context.Background(), get(server.URL))
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_httpcache.StatusMiss, res.Cache)
+
+	for range 2 {
+		res, err = cache.Do(context.Background(), get(server.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, pkg_httpcache.StatusRevalidated, res.Cache)
+		assert.Equal(t, `{"id":1}`, string(res.Body))
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(キャッシュの対象外)
+func TestDoBypass(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private" {
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Write([]byte(`{}`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute})
+
+	// POSTと認証情報付きのリクエストは通過させる
+	for i := 0; i < 2; i++ {
+		res, _ := cache.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodPost, URL: server.URL})
+		assert.Equal(t, pkg_httpcache.StatusBypass, res.Cache)
+		res, _ = cache.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL, Headers: map[string]string{"authorization": "Bearer x"}})
+		assert.Equal(t, pkg_httpcache.StatusBypass, res.Cache)
+	}
+	// no-storeは保存しない
+	for i := 0; i < 2; i++ {
+		res, _ := cache.Do(context.Background(), get(server.URL+"/private"))
+		assert.Equal(t, pkg_httpcache.StatusMiss, res.Cache)
+	}
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(呼び出し元がキャンセルしても取得を続けてキャッシュに残す)
+func TestDoCanceledCaller(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	})
+	cache := newCache(pkg_httpcache.Options{TTL: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := cache.Do(ctx, get(server.URL))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	waitFor(t, func() bool {
+		res, _ := cache.Do(context.Background(), get(server.URL))
+		return res.Cache == pkg_httpcache.StatusHit
+	})
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
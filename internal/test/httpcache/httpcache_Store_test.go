@@ -0,0 +1,99 @@
+package test_httpcache
+
+import (
+	pkg_httpcache "backend/internal/pkg/httpcache"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MemoryStoreのテスト(最も長く使われていないエントリから捨てる)
+func TestMemoryStoreLRU(t *testing.T) {
+	ctx := context.Background()
+	store := pkg_httpcache.NewMemoryStore(2)
+	store.Set(ctx, "a", &pkg_httpcache.Entry{Body: []byte("a")}, 0)
+	store.Set(ctx, "b", &pkg_httpcache.Entry{Body: []byte("b")}, 0)
+	store.Get(ctx, "a")
+	store.Set(ctx, "c", &pkg_httpcache.Entry{Body: []byte("c")}, 0)
+
+	a, _ := store.Get(ctx, "a")
+	b, _ := store.Get(ctx, "b")
+	c, _ := store.Get(ctx, "c")
+	assert.NotNil(t, a)
+	assert.Nil(t, b)
+	assert.NotNil(t, c)
+	assert.Equal(t, 2, store.Len())
+}
+
+// MemoryStoreのテスト(保存期限)
+func TestMemoryStoreTTL(t *testing.T) {
+	ctx := context.Background()
+	store := pkg_httpcache.NewMemoryStore(10)
+	store.Set(ctx, "a", &pkg_httpcache.Entry{}, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	e, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+	assert.Equal(t, 0, store.Len())
+}
+
+// Redis互換クライアントのフェイク
+type fakeRedis struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	if !ok {
+		return nil, pkg_httpcache.ErrNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	f.ttls[key] = ttl
+	return nil
+}
+
+func (f *fakeRedis) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+// RedisStoreのテスト
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	redis := &fakeRedis{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	store := pkg_httpcache.NewRedisStore(redis, "fetch:")
+
+	e, err := store.Get(ctx, "GET /posts")
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	fresh := time.Now().Add(time.Minute).Truncate(time.Second)
+	store.Set(ctx, "GET /posts", &pkg_httpcache.Entry{Status: 200, Body: []byte(`[]`), FreshUntil: fresh}, time.Hour)
+	assert.Equal(t, time.Hour, redis.ttls["fetch:GET /posts"])
+
+	e, err = store.Get(ctx, "GET /posts")
+	assert.NoError(t, err)
+	assert.Equal(t, 200, e.Status)
+	assert.Equal(t, `[]`, string(e.Body))
+	assert.True(t, fresh.Equal(e.FreshUntil))
+
+	store.Delete(ctx, "GET /posts")
+	e, _ = store.Get(ctx, "GET /posts")
+	assert.Nil(t, e)
+}
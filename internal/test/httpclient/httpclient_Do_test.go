@@ -0,0 +1,203 @@
+package test_httpclient
+
+import (
+	pkg_httpclient "backend/internal/pkg/httpclient"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 呼び出し回数を数え、statusesの順にステータスを返すサーバー(最後のステータスを繰り返す)
+func newServer(t *testing.T, hits *int32, statuses ...int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(hits, 1))
+		status := statuses[min(n, len(statuses))-1]
+		w.WriteHeader(status)
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// テスト用の設定(待ち時間は短くする)
+func options() pkg_httpclient.Options {
+	return pkg_httpclient.Options{
+		Timeout:     time.Second,
+		MaxRetries:  2,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  5 * time.Millisecond,
+	}
+}
+
+// Doのテスト(正常系)
+func TestDo(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, http.StatusOK)
+	client := pkg_httpclient.NewClient(logger, options())
+
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, `{"ok":true}`, string(res.Body))
+	assert.Equal(t, 1, res.Attempts)
+}
+
+// Doのテスト(冪等なリクエストは再試行する)
+func TestDoRetry(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	client := pkg_httpclient.NewClient(logger, options())
+
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.Status)
+	assert.Equal(t, 3, res.Attempts)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(再試行しても失敗した場合は最後のレスポンスを返す)
+func TestDoRetryExhausted(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, http.StatusServiceUnavailable)
+	client := pkg_httpclient.NewClient(logger, options())
+
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Status)
+	assert.Equal(t, 3, res.Attempts)
+}
+
+// Doのテスト(冪等でないリクエストは再試行しない)
+func TestDoNoRetryForPost(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, http.StatusServiceUnavailable, http.StatusOK)
+	client := pkg_httpclient.NewClient(logger, options())
+
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodPost, URL: server.URL, Body: []byte(`{}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, res.Status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+}
+
+// Doのテスト(1回の試行のタイムアウト)
+func TestDoTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+	opts := options()
+	opts.Timeout = 20 * time.Millisecond
+	opts.MaxRetries = 1
+	client := pkg_httpclient.NewClient(logger, opts)
+
+	start := time.Now()
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.Error(t, err)
+	assert.Nil(t, res)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+// Doのテスト(呼び出し側のキャンセルでは再試行しない)
+func TestDoCanceled(t *testing.T) {
+	var hits int32
+	server := newServer(t, &hits, http.StatusServiceUnavailable)
+	opts := options()
+	opts.BaseBackoff = time.Second
+	opts.MaxBackoff = time.Second
+	client := pkg_httpclient.NewClient(logger, opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Do(ctx, pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+// Doのテスト(レスポンスサイズの上限)
+func TestDoResponseTooLarge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Content-Lengthを付けずに送る
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer server.Close()
+	opts := options()
+	opts.MaxResponseSize = 10
+	client := pkg_httpclient.NewClient(logger, opts)
+
+	res, err := client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+
+	assert.ErrorIs(t, err, pkg_httpclient.ErrResponseTooLarge)
+	assert.Equal(t, 1, res.Attempts)
+	assert.Nil(t, res.Body)
+
+	// 上限ちょうどは読める
+	opts.MaxResponseSize = 100
+	client = pkg_httpclient.NewClient(logger, opts)
+	res, err = client.Do(context.Background(), pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL})
+	assert.NoError(t, err)
+	assert.Len(t, res.Body, 100)
+}
+
+// Doのテスト(サーキットブレーカー)
+func TestDoCircuitBreaker(t *testing.T) {
+	var hits int32
+	var healthy int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+	opts := options()
+	opts.MaxRetries = 0
+	opts.BreakerThreshold = 2
+	opts.BreakerCooldown = 50 * time.Millisecond
+	client := pkg_httpclient.NewClient(logger, opts)
+	req := pkg_httpclient.Request{Method: http.MethodGet, URL: server.URL}
+
+	// 連続して失敗すると回路を開く
+	for i := 0; i < 2; i++ {
+		res, err := client.Do(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, res.Status)
+	}
+	_, err := client.Do(context.Background(), req)
+	assert.ErrorIs(t, err, pkg_httpclient.ErrCircuitOpen)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	// cooldown後の試行が失敗すると再び開く
+	time.Sleep(60 * time.Millisecond)
+	res, err := client.Do(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, res.Status)
+	_, err = client.Do(context.Background(), req)
+	assert.ErrorIs(t, err, pkg_httpclient.ErrCircuitOpen)
+
+	// cooldown後の試行が成功すると閉じる
+	atomic.StoreInt32(&healthy, 1)
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		res, err = client.Do(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, res.Status)
+	}
+	assert.Equal(t, int32(6), atomic.LoadInt32(&hits))
+}
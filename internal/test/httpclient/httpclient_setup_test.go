@@ -0,0 +1,30 @@
+package test_httpclient
+
+import (
+	pkg_config "backend/config"
+	pkg_logger "backend/internal/pkg/logger"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger *pkg_logger.AppLogger
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
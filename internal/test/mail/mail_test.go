@@ -0,0 +1,86 @@
+package test_mail
+
+import (
+	pkg_mail "backend/internal/pkg/mail"
+	test_stub "backend/internal/test/stub"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 希望する言語からテンプレートのある言語を選ぶ
+func TestTemplatesLanguage(t *testing.T) {
+	templates, err := pkg_mail.NewTemplates("ja")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	cases := map[string]string{
+		"":                        "ja",
+		"en":                      "en",
+		"EN-us":                   "en",
+		"fr-FR,en;q=0.8,ja;q=0.5": "en",
+		"de":                      "ja",
+		"ja-JP,en;q=0.9":          "ja",
+	}
+	for preferred, expected := range cases {
+		assert.Equal(t, expected, templates.Language(preferred), preferred)
+	}
+
+	_, err = pkg_mail.NewTemplates("fr")
+	assert.Error(t, err)
+}
+
+// テンプレートから件名と本文を作る
+func TestTemplatesRender(t *testing.T) {
+	templates, _ := pkg_mail.NewTemplates("en")
+	data := map[string]any{
+		"Name":      "alice",
+		"Email":     "alice@example.com",
+		"Link":      "http://localhost:3000/password/reset?token=abc",
+		"ExpiresAt": time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC),
+	}
+
+	subject, body, err := templates.Render("password_reset", "ja", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "パスワードの再設定", subject)
+	assert.Contains(t, body, "alice 様")
+	assert.Contains(t, body, "2026-01-02 03:04 UTC")
+
+	// 対応していない言語は既定の言語
+	subject, _, err = templates.Render("password_reset", "de", data)
+	assert.NoError(t, err)
+	assert.Equal(t, "Reset your password", subject)
+
+	_, _, err = templates.Render("unknown", "en", data)
+	assert.Error(t, err)
+}
+
+// ファイルに書き出したメールを読み戻せる
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := pkg_mail.NewFileMailer(logger, dir+"/out", "no-reply@example.com")
+
+	body := "こんにちは\nhttp://localhost:3000/email/verify?token=abc.def\n"
+	assert.NoError(t, mailer.Send(context.Background(), pkg_mail.Message{To: "alice@example.com", Subject: "メールアドレスの確認", Body: body}))
+	assert.NoError(t, mailer.Send(context.Background(), pkg_mail.Message{To: "bob@example.com", Subject: "Second", Body: "2"}))
+
+	mails, err := test_stub.ReadMails(dir + "/out")
+	assert.NoError(t, err)
+	if assert.Len(t, mails, 2) {
+		assert.Equal(t, test_stub.Mail{From: "no-reply@example.com", To: "alice@example.com", Subject: "メールアドレスの確認", Body: body}, mails[0])
+		assert.Equal(t, "abc.def", test_stub.MailToken(mails[0]))
+		assert.Equal(t, "bob@example.com", mails[1].To)
+	}
+
+	// ヘッダの差し込みは拒否する
+	assert.Error(t, mailer.Send(context.Background(), pkg_mail.Message{To: "alice@example.com\r\nBcc: eve@example.com", Subject: "x"}))
+
+	// キャンセル済み
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, mailer.Send(ctx, pkg_mail.Message{To: "alice@example.com"}), context.Canceled)
+	assert.ErrorIs(t, pkg_mail.NewLogMailer(logger).Send(ctx, pkg_mail.Message{To: "alice@example.com"}), context.Canceled)
+}
@@ -0,0 +1,100 @@
+package test_rank
+
+import (
+	pkg_rank "backend/internal/pkg/rank"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 先頭・末尾・間のキー
+func TestBetween(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected string
+	}{
+		{"", "", "V"},
+		{"V", "", "W"},
+		{"z", "", "zV"},
+		{"", "V", "U"},
+		{"", "1", "0V"},
+		{"", "01", "00V"},
+		{"V", "W", "VV"},
+		{"V", "X", "W"},
+		{"V3", "W", "VW"},
+		{"a", "a1", "a0V"},
+		{"zz", "", "zzV"},
+	}
+	for _, tc := range cases {
+		key, err := pkg_rank.Between(tc.a, tc.b)
+		assert.NoError(t, err, tc)
+		assert.Equal(t, tc.expected, key, tc)
+	}
+}
+
+// 順序が逆・不正なキーはエラー
+func TestBetweenError(t *testing.T) {
+	for _, tc := range [][2]string{{"W", "V"}, {"V", "V"}, {"V0", ""}, {"", "a-b"}} {
+		_, err := pkg_rank.Between(tc[0], tc[1])
+		assert.Error(t, err, tc)
+	}
+}
+
+// ランダムな位置に挿入し続けても順序が保たれる
+func TestBetweenRandomInsert(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	keys := []string{}
+	for i := 0; i < 2000; i++ {
+		pos := r.Intn(len(keys) + 1)
+		a, b := "", ""
+		if pos > 0 {
+			a = keys[pos-1]
+		}
+		if pos < len(keys) {
+			b = keys[pos]
+		}
+		key, err := pkg_rank.Between(a, b)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, (a == "" || a < key) && (b == "" || key < b), "%q < %q < %q", a, key, b)
+		assert.False(t, strings.HasSuffix(key, "0"))
+		keys = append(keys[:pos], append([]string{key}, keys[pos:]...)...)
+	}
+	assert.True(t, sort.StringsAreSorted(keys))
+}
+
+// 末尾に追加し続けても桁はゆっくり増える
+func TestBetweenAppend(t *testing.T) {
+	key := ""
+	for i := 0; i < 300; i++ {
+		next, err := pkg_rank.Between(key, "")
+		assert.NoError(t, err)
+		key = next
+	}
+	assert.LessOrEqual(t, len(key), 10)
+}
+
+// 等間隔のキー
+func TestSpread(t *testing.T) {
+	assert.Equal(t, []string{"V"}, pkg_rank.Spread(1))
+	assert.Equal(t, []string{}, pkg_rank.Spread(0))
+	for _, n := range []int{3, 61, 62, 1000, 5000} {
+		keys := pkg_rank.Spread(n)
+		assert.Len(t, keys, n)
+		assert.True(t, sort.StringsAreSorted(keys), n)
+		for i, key := range keys {
+			assert.NotEmpty(t, key)
+			assert.False(t, strings.HasSuffix(key, "0"), key)
+			if i > 0 {
+				assert.NotEqual(t, keys[i-1], key)
+			}
+		}
+		// 並び直した後も間に挿入できる
+		_, err := pkg_rank.Between(keys[0], keys[len(keys)-1])
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,144 @@
+package test_ratelimit_handler
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	interfaces_ratelimit "backend/internal/interfaces/ratelimit"
+	usecase_ratelimit "backend/internal/usecase/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// ミドルウェアを通したルーター
+func newServer(h *interfaces_ratelimit.RateLimitHandler) *echo.Echo {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	ok := func(c echo.Context) error { return c.String(http.StatusOK, "ok") }
+	api := e.Group("/api", h.Middleware)
+	api.POST("/login", ok)
+	api.GET("/login", ok)
+	api.POST("/search/:algorithm", ok)
+	api.GET("/keys", ok)
+	api.GET("/invalid", ok)
+	api.GET("/free", ok)
+	return e
+}
+
+// リクエストを送ってレスポンスを返す
+func call(e *echo.Echo, method, target, ip string, header map[string]string) *httptest.ResponseRecorder {
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(method, target, nil)
+	req.RemoteAddr = ip + ":12345"
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	e.ServeHTTP(res, req)
+	return res
+}
+
+// 制限を超えると429とRetry-Afterを返す
+func TestMiddleware_TooManyRequests(t *testing.T) {
+	e := newServer(handler)
+
+	res := call(e, http.MethodPost, "/api/login", "10.0.0.1", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "2", res.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", res.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2;w=3600", res.Header().Get("RateLimit-Policy"))
+	assert.NotEmpty(t, res.Header().Get("RateLimit-Reset"))
+
+	res = call(e, http.MethodPost, "/api/login", "10.0.0.1", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "0", res.Header().Get("RateLimit-Remaining"))
+
+	res = call(e, http.MethodPost, "/api/login", "10.0.0.1", nil)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	assert.JSONEq(t, `{"message":"Too many requests"}`, res.Body.String())
+	assert.NotEmpty(t, res.Header().Get("Retry-After"))
+	assert.NotEqual(t, "0", res.Header().Get("Retry-After"))
+	assert.Equal(t, "0", res.Header().Get("RateLimit-Remaining"))
+
+	// 別のIPアドレスは制限されない
+	res = call(e, http.MethodPost, "/api/login", "10.0.0.2", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	// メソッドが違うルートは制限されない
+	res = call(e, http.MethodGet, "/api/login", "10.0.0.1", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+}
+
+// ユーザーごとに制限し、最も残りの少ないポリシーを返す
+func TestMiddleware_KeyByUser(t *testing.T) {
+	e := newServer(handler)
+	alice := map[string]string{"X-User-Id": "alice"}
+
+	for i := range 3 {
+		res := call(e, http.MethodPost, "/api/search/bfs", "10.0.1.1", alice)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Equal(t, "3", res.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, []string{"2", "1", "0"}[i], res.Header().Get("RateLimit-Remaining"))
+	}
+	// 前方一致のルートは同じポリシーで数える
+	res := call(e, http.MethodPost, "/api/search/dfs", "10.0.1.2", alice)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+
+	// 同じIPアドレスでも別のユーザーは制限されない
+	res = call(e, http.MethodPost, "/api/search/bfs", "10.0.1.1", map[string]string{"X-User-Id": "bob"})
+	assert.Equal(t, http.StatusOK, res.Code)
+}
+
+// APIキーごとに制限し、キーがない場合はIPアドレスで制限する
+func TestMiddleware_KeyByAPIKey(t *testing.T) {
+	e := newServer(handler)
+
+	res := call(e, http.MethodGet, "/api/keys", "10.0.2.1", map[string]string{"X-API-Key": "key-1"})
+	assert.Equal(t, http.StatusOK, res.Code)
+	res = call(e, http.MethodGet, "/api/keys", "10.0.2.1", map[string]string{"X-API-Key": "key-1"})
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+	res = call(e, http.MethodGet, "/api/keys", "10.0.2.1", map[string]string{"X-API-Key": "key-2"})
+	assert.Equal(t, http.StatusOK, res.Code)
+
+	res = call(e, http.MethodGet, "/api/keys", "10.0.2.1", nil)
+	assert.Equal(t, http.StatusOK, res.Code)
+	res = call(e, http.MethodGet, "/api/keys", "10.0.2.1", nil)
+	assert.Equal(t, http.StatusTooManyRequests, res.Code)
+}
+
+// ポリシーのないルートと不正なポリシーは制限しない
+func TestMiddleware_NoPolicy(t *testing.T) {
+	e := newServer(handler)
+
+	for _, target := range []string{"/api/free", "/api/invalid"} {
+		for range 3 {
+			res := call(e, http.MethodGet, target, "10.0.3.1", nil)
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+		}
+	}
+}
+
+// 状態を保存できないリポジトリ
+type failingRepository struct{}
+
+func (failingRepository) Update(ctx context.Context, key string, ttl time.Duration, fn func(s domain_ratelimit.State) domain_ratelimit.State) (domain_ratelimit.State, error) {
+	return domain_ratelimit.State{}, errors.New("connection refused")
+}
+
+// 状態の保存に失敗した場合は制限しない
+func TestMiddleware_FailOpen(t *testing.T) {
+	h := interfaces_ratelimit.NewRateLimitHandler(appConfig, logger, usecase_ratelimit.NewRateLimitUsecase(logger, failingRepository{}), nil)
+	e := newServer(h)
+
+	for range 3 {
+		res := call(e, http.MethodPost, "/api/login", "10.0.4.1", nil)
+		assert.Equal(t, http.StatusOK, res.Code)
+		assert.Empty(t, res.Header().Get("RateLimit-Limit"))
+	}
+}
@@ -0,0 +1,53 @@
+package test_ratelimit_handler
+
+import (
+	pkg_config "backend/config"
+	infrastructure_ratelimit "backend/internal/infrastructure/ratelimit"
+	interfaces_ratelimit "backend/internal/interfaces/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_ratelimit "backend/internal/usecase/ratelimit"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger    *pkg_logger.AppLogger
+	appConfig *pkg_config.AppConfig
+	handler   *interfaces_ratelimit.RateLimitHandler
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig = pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+	appConfig.RateLimit.Policies = []pkg_config.RateLimitPolicyConfig{
+		{Name: "login", Method: "POST", Path: "/api/login", Algorithm: "sliding_window", KeyBy: "ip", Limit: 2, Window: time.Hour},
+		{Name: "search", Path: "/api/search/*", Algorithm: "token_bucket", KeyBy: "user", Limit: 3, Window: time.Hour},
+		{Name: "search_burst", Path: "/api/search/*", Algorithm: "token_bucket", KeyBy: "user", Limit: 10, Window: time.Hour},
+		{Name: "keys", Path: "/api/keys", Algorithm: "sliding_window", KeyBy: "api_key", Limit: 1, Window: time.Hour},
+		{Name: "invalid", Path: "/api/invalid", Algorithm: "leaky_bucket", KeyBy: "ip", Limit: 1, Window: time.Hour},
+	}
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// ユーザーIDはX-User-Idヘッダから取得する
+	userID := func(c echo.Context) (string, bool) {
+		id := c.Request().Header.Get("X-User-Id")
+		return id, id != ""
+	}
+	useCase := usecase_ratelimit.NewRateLimitUsecase(logger, infrastructure_ratelimit.NewRateLimitMemoryRepository(logger))
+	handler = interfaces_ratelimit.NewRateLimitHandler(appConfig, logger, useCase, userID)
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
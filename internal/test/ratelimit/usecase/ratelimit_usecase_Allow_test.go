@@ -0,0 +1,103 @@
+package test_ratelimit_usecase
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// n回リクエストして許可された回数を返す
+func allowN(t *testing.T, p domain_ratelimit.Policy, key string, n int) (int, domain_ratelimit.Decision) {
+	allowed := 0
+	var last domain_ratelimit.Decision
+	for range n {
+		d, err := useCase.Allow(context.Background(), p, key)
+		assert.NoError(t, err)
+		if d.Allowed {
+			allowed++
+		}
+		last = d
+	}
+	return allowed, last
+}
+
+// トークンバケットは容量までのバーストを許し、補充された分だけ再び許可する
+func TestAllow_TokenBucket(t *testing.T) {
+	p := domain_ratelimit.Policy{Name: "bucket", Path: "/", Algorithm: domain_ratelimit.TokenBucket, KeyBy: domain_ratelimit.KeyByIP, Limit: 3, Window: 300 * time.Millisecond}
+
+	allowed, last := allowN(t, p, "a", 4)
+	assert.Equal(t, 3, allowed)
+	assert.False(t, last.Allowed)
+	assert.Equal(t, 3, last.Limit)
+	assert.Equal(t, 0, last.Remaining)
+	// 1トークンの補充は100ms
+	assert.Greater(t, last.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, last.RetryAfter, 100*time.Millisecond)
+
+	time.Sleep(last.RetryAfter + 20*time.Millisecond)
+	allowed, _ = allowN(t, p, "a", 2)
+	assert.Equal(t, 1, allowed)
+}
+
+// 残りの件数を返す
+func TestAllow_TokenBucketRemaining(t *testing.T) {
+	p := domain_ratelimit.Policy{Name: "remaining", Path: "/", Algorithm: domain_ratelimit.TokenBucket, KeyBy: domain_ratelimit.KeyByIP, Limit: 5, Window: time.Hour}
+
+	d, err := useCase.Allow(context.Background(), p, "a")
+	assert.NoError(t, err)
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 4, d.Remaining)
+	assert.Greater(t, d.Reset, time.Duration(0))
+}
+
+// スライディングウィンドウは期間内のリクエスト数をLimitまで許可する
+func TestAllow_SlidingWindow(t *testing.T) {
+	p := domain_ratelimit.Policy{Name: "window", Path: "/", Algorithm: domain_ratelimit.SlidingWindow, KeyBy: domain_ratelimit.KeyByIP, Limit: 2, Window: time.Hour}
+
+	allowed, last := allowN(t, p, "a", 3)
+	assert.Equal(t, 2, allowed)
+	assert.False(t, last.Allowed)
+	assert.Equal(t, 0, last.Remaining)
+	assert.Greater(t, last.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, last.RetryAfter, 2*time.Hour)
+	assert.LessOrEqual(t, last.Reset, time.Hour)
+}
+
+// 前の窓の件数を按分して数える
+func TestAllow_SlidingWindowCarriesPreviousWindow(t *testing.T) {
+	window := 200 * time.Millisecond
+	p := domain_ratelimit.Policy{Name: "carry", Path: "/", Algorithm: domain_ratelimit.SlidingWindow, KeyBy: domain_ratelimit.KeyByIP, Limit: 4, Window: window}
+
+	// 窓の始めに合わせる
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window)))
+	allowed, _ := allowN(t, p, "a", 4)
+	assert.Equal(t, 4, allowed)
+
+	// 次の窓の始めでは前の窓の4件がほぼそのまま数えられる
+	time.Sleep(time.Until(time.Now().Truncate(window).Add(window)))
+	allowed, last := allowN(t, p, "a", 1)
+	assert.Equal(t, 0, allowed)
+	assert.Greater(t, last.RetryAfter, time.Duration(0))
+	assert.LessOrEqual(t, last.RetryAfter, window)
+}
+
+// キーごとに独立して制限する
+func TestAllow_KeysAreIndependent(t *testing.T) {
+	p := domain_ratelimit.Policy{Name: "keys", Path: "/", Algorithm: domain_ratelimit.SlidingWindow, KeyBy: domain_ratelimit.KeyByIP, Limit: 1, Window: time.Hour}
+
+	allowed, _ := allowN(t, p, "a", 2)
+	assert.Equal(t, 1, allowed)
+	allowed, _ = allowN(t, p, "b", 2)
+	assert.Equal(t, 1, allowed)
+}
+
+// 未知のアルゴリズム
+func TestAllow_UnknownAlgorithm(t *testing.T) {
+	p := domain_ratelimit.Policy{Name: "unknown", Path: "/", Algorithm: "leaky_bucket", KeyBy: domain_ratelimit.KeyByIP, Limit: 1, Window: time.Hour}
+
+	_, err := useCase.Allow(context.Background(), p, "a")
+	assert.EqualError(t, err, "unknown algorithm")
+}
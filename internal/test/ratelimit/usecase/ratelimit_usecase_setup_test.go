@@ -0,0 +1,36 @@
+package test_ratelimit_usecase
+
+import (
+	pkg_config "backend/config"
+	infrastructure_ratelimit "backend/internal/infrastructure/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_ratelimit "backend/internal/usecase/ratelimit"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger  *pkg_logger.AppLogger
+	useCase usecase_ratelimit.IRateLimitUsecase
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// インメモリの状態を使う(テストごとにポリシー名を変えて状態を分ける)
+	useCase = usecase_ratelimit.NewRateLimitUsecase(logger, infrastructure_ratelimit.NewRateLimitMemoryRepository(logger))
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,65 @@
+package test_rls
+
+import (
+	pkg_config "backend/config"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_supabase "backend/internal/pkg/supabase"
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger *pkg_logger.AppLogger
+	// TEST_DATABASE_URLが未設定の場合はnil(テストをスキップする)
+	client *pkg_supabase.SupabaseClient
+)
+
+// テストのメイン関数
+// TEST_DATABASE_URLにはmigrationsを適用したPostgresを指定する(テストで作成したユーザーは終了時に削除する)
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// DBの接続
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		config, err := pgxpool.ParseConfig(url)
+		if err != nil {
+			logger.ErrorLog.Fatalf("Unable to parse TEST_DATABASE_URL: %v", err)
+		}
+		// 本番と同じくSimple Protocolを使い、接続を1つにして設定が次のクエリに残らないことを確認する
+		config.MaxConns = 1
+		config.ConnConfig.PreferSimpleProtocol = true
+		client = pkg_supabase.NewSupabaseClient()
+		client.Pool, err = pgxpool.ConnectConfig(context.Background(), config)
+		if err != nil {
+			logger.ErrorLog.Fatalf("Unable to connect to test database: %v", err)
+		}
+	}
+
+	// テスト実行
+	code := m.Run()
+
+	if client != nil {
+		client.ClosePool(logger)
+	}
+
+	// 終了コードを返す
+	os.Exit(code)
+}
+
+// DBが無い場合はスキップする
+func requireDB(t *testing.T) {
+	t.Helper()
+	if client == nil {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+}
@@ -0,0 +1,197 @@
+package test_rls
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	domain_workspace "backend/internal/domain/workspace"
+	infrastructure_todo "backend/internal/infrastructure/todo"
+	infrastructure_user "backend/internal/infrastructure/user"
+	infrastructure_workspace "backend/internal/infrastructure/workspace"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// テスト用のユーザーを作成する(個人用のワークスペースはトリガーで作成される)
+func createUser(t *testing.T, name string) string {
+	email := fmt.Sprintf("%s-%d@rls.example.com", name, time.Now().UnixNano())
+	var id string
+	err := client.Pool.QueryRow(client.Ctx, `
+		INSERT INTO users (username, email, password) VALUES ($1, $2, 'password')
+		RETURNING id::text
+	`, name, email).Scan(&id)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() {
+		client.Pool.Exec(client.Ctx, `DELETE FROM todos WHERE user_id = $1`, id)
+		client.Pool.Exec(client.Ctx, `DELETE FROM users WHERE id = $1`, id)
+	})
+	return id
+}
+
+// ユーザーの個人用のワークスペースにTodoを作成する
+func createTodo(t *testing.T, userId string, description string) domain_todo.Todo {
+	repo := infrastructure_todo.NewTodoRepository(logger, client)
+	todo, err := repo.CreateTodo(domain_todo.Scope{UserID: userId}, domain_todo.Todo{Description: description, UserId: userId})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return todo
+}
+
+// ユーザーとしてクエリを実行し、1列目を文字列のリストで返す
+// リポジトリの条件を通さない(Go側の確認を迂回した)クエリとして使う
+func queryAs(t *testing.T, userId string, query string, args ...any) []string {
+	values := []string{}
+	err := client.AsUser(client.Ctx, userId, func(tx pgx.Tx) error {
+		rows, err := tx.Query(client.Ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var v string
+			if err := rows.Scan(&v); err != nil {
+				return err
+			}
+			values = append(values, v)
+		}
+		return rows.Err()
+	})
+	assert.NoError(t, err)
+	return values
+}
+
+// ユーザーとしてクエリを実行し、変更した行数を返す
+func execAs(userId string, query string, args ...any) (int64, error) {
+	var affected int64
+	err := client.AsUser(client.Ctx, userId, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(client.Ctx, query, args...)
+		affected = tag.RowsAffected()
+		return err
+	})
+	return affected, err
+}
+
+// 権限のエラー(insufficient_privilege)か
+func isPermissionError(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "42501"
+}
+
+// 他のユーザーのTodoは条件無しのクエリでも参照・変更できない
+func TestTodosIsolation(t *testing.T) {
+	requireDB(t)
+	alice := createUser(t, "alice")
+	bob := createUser(t, "bob")
+	aliceTodo := createTodo(t, alice, "alice's todo")
+	bobTodo := createTodo(t, bob, "bob's todo")
+
+	ids := queryAs(t, alice, `SELECT id::text FROM todos`)
+	assert.Contains(t, ids, aliceTodo.ID)
+	assert.NotContains(t, ids, bobTodo.ID)
+
+	affected, err := execAs(alice, `UPDATE todos SET description = 'hacked' WHERE id = $1`, bobTodo.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, affected)
+
+	affected, err = execAs(alice, `DELETE FROM todos WHERE id = $1`, bobTodo.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, affected)
+
+	// 他のユーザーのワークスペースには作成できない
+	_, err = execAs(alice, `INSERT INTO todos (description, completed, user_id, workspace_id, position) VALUES ('x', false, $1, $2, 'x')`, alice, bobTodo.WorkspaceID)
+	assert.True(t, isPermissionError(err), "%v", err)
+
+	// 接続プールのロールから見て変更されていない
+	var description string
+	assert.NoError(t, client.Pool.QueryRow(client.Ctx, `SELECT description FROM todos WHERE id = $1`, bobTodo.ID).Scan(&description))
+	assert.Equal(t, "bob's todo", description)
+
+	// リポジトリからも参照できない
+	_, err = infrastructure_todo.NewTodoRepository(logger, client).GetTodoById(domain_todo.Scope{UserID: alice}, bobTodo.ID)
+	assert.EqualError(t, err, "todo not found")
+}
+
+// 共有されたワークスペースのTodoは役割に応じて参照・変更できる
+func TestTodosSharedWorkspace(t *testing.T) {
+	requireDB(t)
+	alice := createUser(t, "alice")
+	bob := createUser(t, "bob")
+	bobTodo := createTodo(t, bob, "shared todo")
+
+	// アリスを閲覧者として追加する
+	workspaceRepo := infrastructure_workspace.NewWorkspaceRepository(logger, client)
+	_, err := workspaceRepo.AddMember(domain_workspace.Member{WorkspaceID: bobTodo.WorkspaceID, UserID: alice, Role: domain_workspace.RoleViewer})
+	assert.NoError(t, err)
+
+	assert.Contains(t, queryAs(t, alice, `SELECT id::text FROM todos`), bobTodo.ID)
+	affected, err := execAs(alice, `UPDATE todos SET description = 'edited' WHERE id = $1`, bobTodo.ID)
+	assert.NoError(t, err)
+	assert.Zero(t, affected)
+
+	// 編集者は変更できる
+	assert.NoError(t, workspaceRepo.UpdateMemberRole(bobTodo.WorkspaceID, alice, domain_workspace.RoleEditor))
+	affected, err = execAs(alice, `UPDATE todos SET description = 'edited' WHERE id = $1`, bobTodo.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, affected)
+
+	// 編集者でも他のユーザーを作成者にしたTodoは作成できず、作成者も変更できない
+	_, err = execAs(alice, `INSERT INTO todos (description, completed, user_id, workspace_id, position) VALUES ('x', false, $1, $2, 'x')`, bob, bobTodo.WorkspaceID)
+	assert.True(t, isPermissionError(err), "%v", err)
+	_, err = execAs(alice, `UPDATE todos SET user_id = $1 WHERE id = $2`, alice, bobTodo.ID)
+	assert.True(t, isPermissionError(err), "%v", err)
+
+	// 同じワークスペースのメンバーは参照できる
+	assert.ElementsMatch(t, []string{alice, bob}, queryAs(t, alice, `SELECT id::text FROM users`))
+}
+
+// 他のユーザーは条件無しのクエリでも参照できない
+func TestUsersIsolation(t *testing.T) {
+	requireDB(t)
+	alice := createUser(t, "alice")
+	createUser(t, "bob")
+
+	assert.Equal(t, []string{alice}, queryAs(t, alice, `SELECT id::text FROM users`))
+
+	// パスワードの列は自分のものでも参照できない
+	_, err := execAs(alice, `SELECT password FROM users WHERE id = $1`, alice)
+	assert.True(t, isPermissionError(err), "%v", err)
+
+	// リポジトリからも同じ結果になる
+	users, err := infrastructure_user.NewUserRepository(logger, client).GetAllUsers(alice)
+	assert.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, alice, users[0].ID)
+	}
+}
+
+// ユーザーが無い場合は実行せず、設定はトランザクションの後に残らない
+func TestClaimsScope(t *testing.T) {
+	requireDB(t)
+	alice := createUser(t, "alice")
+
+	err := client.AsUser(client.Ctx, "", func(tx pgx.Tx) error { return nil })
+	assert.EqualError(t, err, "user is not authenticated")
+	_, err = infrastructure_todo.NewTodoRepository(logger, client).GetAllTodos(domain_todo.Scope{})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"authenticated " + alice}, queryAs(t, alice, `SELECT current_user || ' ' || app_user_id()::text`))
+
+	var role, claims string
+	err = client.Pool.QueryRow(client.Ctx, `SELECT current_user::text, COALESCE(current_setting('request.jwt.claims', true), '')`).Scan(&role, &claims)
+	assert.NoError(t, err)
+	assert.NotEqual(t, "authenticated", role)
+	assert.Empty(t, claims)
+
+	// 設定が無い場合はポリシーでどの行も参照できない
+	var count int
+	err = client.Pool.QueryRow(client.Ctx, `SELECT count(*) FROM todos WHERE workspace_id IN (SELECT app_workspace_ids())`).Scan(&count)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
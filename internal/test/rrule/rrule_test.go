@@ -0,0 +1,235 @@
+package test_rrule
+
+import (
+	pkg_rrule "backend/internal/pkg/rrule"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// タイムゾーンの日時
+func at(t *testing.T, timezone string, value string) time.Time {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+// ルールを読み込み、startから最初のn件の予定をRFC3339で返す
+func occurrences(t *testing.T, rrule string, timezone string, start string, n int) []string {
+	rule, err := pkg_rrule.Parse(rrule)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := pkg_rrule.NewSchedule(rule, at(t, timezone, start), timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := []string{}
+	after := at(t, timezone, start).Add(-time.Second)
+	for len(values) < n {
+		next, ok := schedule.Next(after)
+		if !ok {
+			break
+		}
+		values = append(values, next.Format(time.RFC3339))
+		after = next
+	}
+	return values
+}
+
+// 対応していない・不正なルールはエラー
+func TestParseError(t *testing.T) {
+	for _, rrule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;BYSETPOS=1",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20240101",
+		"FREQ=DAILY;UNTIL=2024-01-01",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=YEARLY;BYDAY=1MO",
+		"FREQ=DAILY;WKST=XX",
+	} {
+		_, err := pkg_rrule.Parse(rrule)
+		assert.Error(t, err, rrule)
+	}
+}
+
+// "RRULE:"の接頭辞・小文字も受け付ける
+func TestParse(t *testing.T) {
+	rule, err := pkg_rrule.Parse("RRULE:freq=monthly;interval=2;byday=mo,-1fr;bymonth=12,3")
+	assert.NoError(t, err)
+	assert.Equal(t, pkg_rrule.Monthly, rule.Freq)
+	assert.Equal(t, 2, rule.Interval)
+	assert.Equal(t, []pkg_rrule.WeekdayNum{{N: 0, Weekday: time.Monday}, {N: -1, Weekday: time.Friday}}, rule.ByDay)
+	assert.Equal(t, []int{3, 12}, rule.ByMonth)
+	assert.Equal(t, time.Monday, rule.WeekStart)
+}
+
+// サーバーによって変わる・存在しないタイムゾーンはエラー
+func TestLoadLocationError(t *testing.T) {
+	for _, timezone := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := pkg_rrule.LoadLocation(timezone)
+		assert.Error(t, err, timezone)
+	}
+}
+
+// 夏時間の開始の前後でも現地の同じ時刻になる(UTCでは1時間ずれる)
+func TestDailyAcrossSpringForward(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-03-29T09:00:00+01:00",
+		"2024-03-30T09:00:00+01:00",
+		"2024-03-31T09:00:00+02:00",
+		"2024-04-01T09:00:00+02:00",
+	}, occurrences(t, "FREQ=DAILY", "Europe/Berlin", "2024-03-29 09:00", 4))
+}
+
+// 夏時間の開始で存在しない時刻は、切り替えの分だけ後ろにずらす(翌日からは元の時刻)
+func TestDailySpringForwardGap(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-03-30T02:30:00+01:00",
+		"2024-03-31T03:30:00+02:00",
+		"2024-04-01T02:30:00+02:00",
+	}, occurrences(t, "FREQ=DAILY", "Europe/Berlin", "2024-03-30 02:30", 3))
+}
+
+// 夏時間の終了で2回ある時刻は、早い方(夏時間)にする
+func TestDailyFallBackOverlap(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-10-26T02:30:00+02:00",
+		"2024-10-27T02:30:00+02:00",
+		"2024-10-28T02:30:00+01:00",
+	}, occurrences(t, "FREQ=DAILY", "Europe/Berlin", "2024-10-26 02:30", 3))
+}
+
+// 毎週の予定も夏時間の切り替えの前後で現地の同じ時刻になる
+func TestWeeklyAcrossTransitions(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-03-04T09:00:00-05:00",
+		"2024-03-06T09:00:00-05:00",
+		"2024-03-11T09:00:00-04:00",
+		"2024-03-13T09:00:00-04:00",
+	}, occurrences(t, "FREQ=WEEKLY;BYDAY=MO,WE", "America/New_York", "2024-03-04 09:00", 4))
+
+	assert.Equal(t, []string{
+		"2024-10-28T09:00:00-04:00",
+		"2024-11-04T09:00:00-05:00",
+	}, occurrences(t, "FREQ=WEEKLY", "America/New_York", "2024-10-28 09:00", 2))
+}
+
+// 南半球(夏時間が年をまたぐ)でも同じ
+func TestWeeklySouthernHemisphere(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-03-31T08:00:00+11:00",
+		"2024-04-07T08:00:00+10:00",
+		"2024-04-14T08:00:00+10:00",
+		"2024-04-21T08:00:00+10:00",
+		"2024-04-28T08:00:00+10:00",
+		"2024-10-06T08:00:00+11:00",
+	}, occurrences(t, "FREQ=WEEKLY;BYMONTH=3,4,10;BYDAY=SU", "Australia/Sydney", "2024-03-31 08:00", 6))
+}
+
+// 週の始まり(WKST)で隔週の数え方が変わる(RFC 5545 の例)
+func TestWeeklyIntervalWeekStart(t *testing.T) {
+	assert.Equal(t, []string{
+		"1997-08-05T09:00:00-04:00",
+		"1997-08-10T09:00:00-04:00",
+		"1997-08-19T09:00:00-04:00",
+		"1997-08-24T09:00:00-04:00",
+	}, occurrences(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO", "America/New_York", "1997-08-05 09:00", 10))
+
+	assert.Equal(t, []string{
+		"1997-08-05T09:00:00-04:00",
+		"1997-08-17T09:00:00-04:00",
+		"1997-08-19T09:00:00-04:00",
+		"1997-08-31T09:00:00-04:00",
+	}, occurrences(t, "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU", "America/New_York", "1997-08-05 09:00", 10))
+}
+
+// 31日の毎月の予定は、31日が無い月を飛ばす
+func TestMonthlySkipsMissingDays(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-01-31T10:00:00+09:00",
+		"2024-03-31T10:00:00+09:00",
+		"2024-05-31T10:00:00+09:00",
+		"2024-07-31T10:00:00+09:00",
+	}, occurrences(t, "FREQ=MONTHLY;COUNT=4", "Asia/Tokyo", "2024-01-31 10:00", 10))
+}
+
+// 月末(負のBYMONTHDAY)
+func TestMonthlyLastDay(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-01-31T10:00:00+09:00",
+		"2024-02-29T10:00:00+09:00",
+		"2024-03-31T10:00:00+09:00",
+	}, occurrences(t, "FREQ=MONTHLY;BYMONTHDAY=-1", "Asia/Tokyo", "2024-01-01 10:00", 3))
+}
+
+// 序数付きの曜日(最終金曜日・11月の第4木曜日)
+func TestByDayOrdinal(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-01-26T18:00:00+09:00",
+		"2024-02-23T18:00:00+09:00",
+		"2024-03-29T18:00:00+09:00",
+	}, occurrences(t, "FREQ=MONTHLY;BYDAY=-1FR", "Asia/Tokyo", "2024-01-01 18:00", 3))
+
+	assert.Equal(t, []string{
+		"2024-11-28T12:00:00-05:00",
+		"2025-11-27T12:00:00-05:00",
+		"2026-11-26T12:00:00-05:00",
+	}, occurrences(t, "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", "America/New_York", "2024-01-01 12:00", 3))
+}
+
+// 2月29日の毎年の予定は、うるう年のみ
+func TestYearlyLeapDay(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-02-29T09:00:00Z",
+		"2028-02-29T09:00:00Z",
+	}, occurrences(t, "FREQ=YEARLY", "UTC", "2024-02-29 09:00", 2))
+}
+
+// UNTILは終了日時を含む。タイムゾーンの無い日付はスケジュールのタイムゾーンのその日の終わりまで
+func TestUntil(t *testing.T) {
+	assert.Equal(t, []string{
+		"2024-01-01T09:00:00Z",
+		"2024-01-02T09:00:00Z",
+		"2024-01-03T09:00:00Z",
+	}, occurrences(t, "FREQ=DAILY;UNTIL=20240103T090000Z", "UTC", "2024-01-01 09:00", 10))
+
+	assert.Equal(t, []string{
+		"2024-01-01T23:00:00+09:00",
+		"2024-01-02T23:00:00+09:00",
+	}, occurrences(t, "FREQ=DAILY;UNTIL=20240102", "Asia/Tokyo", "2024-01-01 23:00", 10))
+}
+
+// 範囲の予定(fromを含み、untilを含まない)
+func TestBetween(t *testing.T) {
+	rule, err := pkg_rrule.Parse("FREQ=DAILY;INTERVAL=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := pkg_rrule.NewSchedule(rule, at(t, "UTC", "2024-01-01 09:00"), "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	times := schedule.Between(at(t, "UTC", "2024-01-03 09:00"), at(t, "UTC", "2024-01-07 09:00"))
+	assert.Equal(t, []time.Time{at(t, "UTC", "2024-01-03 09:00"), at(t, "UTC", "2024-01-05 09:00")}, times)
+}
+
+// 一致する日が無いルールも終わる
+func TestNoOccurrences(t *testing.T) {
+	assert.Empty(t, occurrences(t, "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", "UTC", "2024-01-01 09:00", 1))
+}
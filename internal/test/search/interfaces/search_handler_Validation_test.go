@@ -0,0 +1,298 @@
+package test_search_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// ハンドラにJSONをPOSTし、ステータスコードとレスポンスを返す
+func post(t *testing.T, fn echo.HandlerFunc, body string) (int, map[string]interface{}) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/api/search", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+
+	// ハンドラのメソッドを呼び出し
+	if err := fn(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// JSONレスポンスのデコード
+	var resBody map[string]interface{}
+	if err := json.Unmarshal(res.Body.Bytes(), &resBody); err != nil {
+		t.Fatal(err)
+	}
+	return res.Code, resBody
+}
+
+// 入力エラーの項目名を取得する
+func errorFields(resBody map[string]interface{}) []string {
+	fields := []string{}
+	errs, _ := resBody["errors"].([]interface{})
+	for _, e := range errs {
+		fields = append(fields, e.(map[string]interface{})["field"].(string))
+	}
+	return fields
+}
+
+// BFSのテスト(異常系 - 空のグリッド)
+func TestBFSErrorEmptyGrid(t *testing.T) {
+	code, resBody := post(t, handler.BFS, `{"graph": []}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph"}, errorFields(resBody))
+}
+
+// DFSのテスト(異常系 - 行の長さが不揃い)
+func TestDFSErrorRaggedGrid(t *testing.T) {
+	code, resBody := post(t, handler.DFS, `{"graph": [[0, 0], [0], [0, 0]]}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph[1]"}, errorFields(resBody))
+}
+
+// BFSのテスト(異常系 - 0/1以外の値)
+func TestBFSErrorInvalidCell(t *testing.T) {
+	code, resBody := post(t, handler.BFS, `{"graph": [[0, 2], [0, 0]]}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph[0][1]"}, errorFields(resBody))
+}
+
+// BFSのテスト(異常系 - マス数の上限)
+func TestBFSErrorTooManyCells(t *testing.T) {
+	row := "[" + strings.TrimSuffix(strings.Repeat("0,", 11), ",") + "]"
+	grid := "[" + strings.TrimSuffix(strings.Repeat(row+",", 10), ",") + "]"
+	code, resBody := post(t, handler.BFS, `{"graph": `+grid+`}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph"}, errorFields(resBody))
+}
+
+// DFSのテスト(正常系 - 経路付き)
+func TestDFSReturnPath(t *testing.T) {
+	code, resBody := post(t, handler.DFS, `{"graph": [[0, 1], [0, 0]], "return_path": true}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, resBody["result"])
+	assert.Len(t, resBody["path"], 3)
+}
+
+// LinearSearchのテスト(異常系 - 配列の上限)
+func TestLinearSearchErrorTooLong(t *testing.T) {
+	code, resBody := post(t, handler.LinearSearch, `{"arr": [1,2,3,4,5,6,7,8,9,10,11], "target": 1}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"arr"}, errorFields(resBody))
+}
+
+// BinarySearchのテスト(異常系 - 未ソート)
+func TestBinarySearchErrorUnsorted(t *testing.T) {
+	code, resBody := post(t, handler.BinarySearch, `{"arr": [5, 1, 3], "target": 3}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"arr"}, errorFields(resBody))
+}
+
+// BinarySearchのテスト(正常系 - ソートして探索)
+func TestBinarySearchSort(t *testing.T) {
+	code, resBody := post(t, handler.BinarySearch, `{"arr": [5, 1, 3], "target": 5, "sort": true}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), resBody["index"])
+	assert.Equal(t, float64(0), resBody["original_index"])
+}
+
+// BinarySearchのテスト(正常系 - ソート済み)
+func TestBinarySearchSorted(t *testing.T) {
+	code, resBody := post(t, handler.BinarySearch, `{"arr": [1, 3, 5], "target": 3}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(1), resBody["index"])
+}
+
+// Dijkstraのテスト(異常系 - スタートがグリッド外、ゴールが壁)
+func TestDijkstraErrorPoints(t *testing.T) {
+	code, resBody := post(t, handler.Dijkstra, `{"grid": [[0, 0], [0, 1]], "start": {"row": 3, "col": 0}}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"start", "goal"}, errorFields(resBody))
+}
+
+// AStarのテスト(異常系 - 不明なヒューリスティック)
+func TestAStarErrorHeuristic(t *testing.T) {
+	code, resBody := post(t, handler.AStar, `{"grid": [[0, 0]], "heuristic": "unknown"}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"heuristic"}, errorFields(resBody))
+}
+
+// AStarのテスト(異常系 - 斜め移動でマンハッタン距離)
+func TestAStarErrorManhattanDiagonal(t *testing.T) {
+	code, resBody := post(t, handler.AStar, `{"grid": [[0, 0], [0, 0]], "diagonal": true, "heuristic": "manhattan"}`)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"heuristic"}, errorFields(resBody))
+}
+
+// Dijkstraのテスト(異常系 - 0/1以外のマス、不正なコスト)
+func TestDijkstraErrorCells(t *testing.T) {
+	code, resBody := post(t, handler.Dijkstra, `{"grid": [[0, -1], [0, 0]]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"grid[0][1]"}, errorFields(resBody))
+
+	code, resBody = post(t, handler.Dijkstra, `{"grid": [[0, 0], [0, 0]], "costs": [[1, 1]]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"costs"}, errorFields(resBody))
+
+	code, resBody = post(t, handler.Dijkstra, `{"grid": [[0, 0], [0, 0]], "costs": [[1, 1], [-1, 1]]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"costs[1][0]"}, errorFields(resBody))
+}
+
+// Dijkstraのテスト(正常系 - コストの小さい迂回路を選ぶ)
+func TestDijkstraCosts(t *testing.T) {
+	code, resBody := post(t, handler.Dijkstra, `{"grid": [[0, 0, 0], [0, 0, 0], [0, 0, 0]], "costs": [[1, 9, 1], [1, 9, 1], [1, 1, 1]], "goal": {"row": 0, "col": 2}}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(6), resBody["cost"])
+}
+
+// グラフ探索のテスト(異常系 - 空のグラフ、ノード数の上限、閉路)
+func TestGraphValidation(t *testing.T) {
+	code, resBody := post(t, graphHandler.BFS, `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph"}, errorFields(resBody))
+
+	code, resBody = post(t, graphHandler.BFS, `{"nodes": ["a", "b", "c", "d", "e", "f"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"nodes"}, errorFields(resBody))
+
+	code, resBody = post(t, graphHandler.TopologicalSort, `{"directed": true, "edges": [{"from": "a", "to": "b"}, {"from": "b", "to": "a"}]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"graph"}, errorFields(resBody))
+
+	code, _ = post(t, graphHandler.TopologicalSort, `{"directed": true, "edges": [{"from": "a", "to": "b"}]}`)
+	assert.Equal(t, http.StatusOK, code)
+}
+
+// Benchmarkのテスト(正常系)
+func TestBenchmark(t *testing.T) {
+	code, resBody := post(t, benchHandler.Benchmark, `{"dataset": {"size": 10, "sorted": true, "seed": 1}, "algorithms": ["linear", "binary"], "iterations": 5}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(10), resBody["size"])
+	assert.Len(t, resBody["results"], 2)
+}
+
+// Benchmarkのテスト(異常系 - 未ソート、不明なアルゴリズム、作業量の上限)
+func TestBenchmarkValidation(t *testing.T) {
+	code, resBody := post(t, benchHandler.Benchmark, `{"data": [3, 1, 2], "algorithms": ["binary", "ternary"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"data", "algorithms[1]"}, errorFields(resBody))
+
+	code, resBody = post(t, benchHandler.Benchmark, `{"dataset": {"size": 10}, "algorithms": ["linear"], "target_count": 1000, "iterations": 100}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"iterations"}, errorFields(resBody))
+
+	code, resBody = post(t, benchHandler.Benchmark, `{"algorithms": ["linear"]}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"dataset"}, errorFields(resBody))
+}
+
+// LinearSearchのテスト(途中経過)
+func TestLinearSearchTrace(t *testing.T) {
+	code, resBody := post(t, handler.LinearSearch, `{"arr": [5, 1, 3], "target": 3, "trace": true}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), resBody["index"])
+	trace := resBody["trace"].(map[string]interface{})
+	assert.Len(t, trace["events"], 5)
+	assert.Equal(t, false, trace["truncated"])
+}
+
+// BFSのテスト(途中経過をNDJSONでストリーミング)
+func TestBFSTraceNDJSON(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/api/search/bfs?format=ndjson", strings.NewReader(`{"graph": [[0, 0], [1, 0]]}`))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+
+	// ハンドラのメソッドを呼び出し
+	if err := handler.BFS(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// 検証
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "application/x-ndjson", res.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(res.Body.String()), "\n")
+	assert.Greater(t, len(lines), 2)
+	for i, line := range lines[:len(lines)-1] {
+		var event map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &event))
+		assert.Equal(t, float64(i+1), event["step"])
+	}
+	var result map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &result))
+	assert.Equal(t, "result", result["type"])
+	assert.Equal(t, float64(3), result["steps"])
+}
+
+// 迷路のテスト(生成したテキストを読み込み、BFSに渡す)
+func TestMazeGenerateImport(t *testing.T) {
+	code, resBody := post(t, mazeHandler.Generate, `{"rows": 9, "cols": 9, "algorithm": "prim", "seed": 7}`)
+	assert.Equal(t, http.StatusOK, code)
+
+	text, _ := json.Marshal(map[string]interface{}{"text": resBody["text"]})
+	code, resBody = post(t, mazeHandler.Import, string(text))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(9), resBody["rows"])
+
+	graph, _ := json.Marshal(map[string]interface{}{"graph": resBody["grid"]})
+	code, resBody = post(t, handler.BFS, string(graph))
+	assert.Equal(t, http.StatusOK, code)
+	assert.Greater(t, resBody["steps"], float64(0))
+}
+
+// 迷路のテスト(異常系)
+func TestMazeValidation(t *testing.T) {
+	code, resBody := post(t, mazeHandler.Generate, `{"rows": 8, "cols": 21, "algorithm": "wilson", "density": 2}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"rows", "algorithm", "density"}, errorFields(resBody))
+
+	code, resBody = post(t, mazeHandler.Generate, `{"rows": 11, "cols": 11}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"rows"}, errorFields(resBody))
+
+	code, resBody = post(t, mazeHandler.Import, `{"text": "..\n.?"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+	assert.Equal(t, []string{"text[2]"}, errorFields(resBody))
+
+	code, resBody = post(t, mazeHandler.Export, `{"grid": [[0, 1], [0, 0]], "format": "binary"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "01\n00\n", resBody["text"])
+}
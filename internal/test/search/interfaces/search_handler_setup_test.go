@@ -0,0 +1,48 @@
+package test_search_handler
+
+import (
+	pkg_config "backend/config"
+	interfaces_search "backend/internal/interfaces/search"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_search "backend/internal/usecase/search"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger       *pkg_logger.AppLogger
+	handler      *interfaces_search.SearchHandler
+	graphHandler *interfaces_search.GraphHandler
+	benchHandler *interfaces_search.BenchmarkHandler
+	mazeHandler  *interfaces_search.MazeHandler
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+	// 上限値のテストのため小さくする
+	appConfig.SearchLimits.MaxArrayLength = 10
+	appConfig.SearchLimits.MaxGridCells = 100
+	appConfig.SearchLimits.MaxGraphNodes = 5
+	appConfig.SearchLimits.MaxGraphEdges = 5
+	appConfig.SearchLimits.MaxBenchmarkWork = 100000
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// 探索は純粋な計算のため、モックではなく実際のユースケースを使う
+	handler = interfaces_search.NewSearchHandler(appConfig, logger, usecase_search.NewSearchUsecase(logger))
+	graphHandler = interfaces_search.NewGraphHandler(appConfig, logger, usecase_search.NewGraphUsecase(logger))
+	benchHandler = interfaces_search.NewBenchmarkHandler(appConfig, logger, usecase_search.NewBenchmarkUsecase(logger))
+	mazeHandler = interfaces_search.NewMazeHandler(appConfig, logger, usecase_search.NewMazeUsecase(logger))
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
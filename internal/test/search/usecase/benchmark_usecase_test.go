@@ -0,0 +1,170 @@
+package test_search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 全てのアルゴリズム
+var allAlgorithms = []domain_search.SearchAlgorithm{
+	domain_search.AlgorithmLinear,
+	domain_search.AlgorithmBinary,
+	domain_search.AlgorithmJump,
+	domain_search.AlgorithmInterpolation,
+	domain_search.AlgorithmExponential,
+}
+
+// GenerateDatasetのテスト(同じseedからは同じデータ)
+func TestGenerateDatasetDeterministic(t *testing.T) {
+	for _, d := range []domain_search.Distribution{
+		domain_search.DistributionUniform,
+		domain_search.DistributionSequential,
+		domain_search.DistributionNormal,
+		domain_search.DistributionFewUnique,
+	} {
+		spec := domain_search.DatasetSpec{Size: 500, Distribution: d, Seed: 42}
+
+		// ユースケースのメソッドを呼び出し
+		a, err := benchUseCase.GenerateDataset(spec)
+		assert.NoError(t, err)
+		b, err := benchUseCase.GenerateDataset(spec)
+		assert.NoError(t, err)
+		spec.Seed = 43
+		c, err := benchUseCase.GenerateDataset(spec)
+		assert.NoError(t, err)
+
+		// 検証
+		assert.Len(t, a, 500)
+		assert.Equal(t, a, b, "distribution: %s", d)
+		assert.NotEqual(t, a, c, "distribution: %s", d)
+		for _, v := range a {
+			assert.True(t, v >= 0 && v <= 5000, "distribution: %s value: %d", d, v)
+		}
+	}
+}
+
+// GenerateDatasetのテスト(ソート済み、範囲指定)
+func TestGenerateDatasetSorted(t *testing.T) {
+	// ユースケースのメソッドを呼び出し
+	data, err := benchUseCase.GenerateDataset(domain_search.DatasetSpec{Size: 100, Sorted: true, Seed: 1, Min: -50, Max: 50})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.True(t, sort.IntsAreSorted(data))
+	assert.GreaterOrEqual(t, data[0], -50)
+	assert.LessOrEqual(t, data[len(data)-1], 50)
+
+	// 不明な分布
+	_, err = benchUseCase.GenerateDataset(domain_search.DatasetSpec{Size: 10, Distribution: "zipf"})
+	assert.EqualError(t, err, "unknown distribution")
+}
+
+// GenerateDatasetのテスト(範囲がintの全体でも桁あふれしない)
+func TestGenerateDatasetFullRange(t *testing.T) {
+	for _, d := range []domain_search.Distribution{
+		domain_search.DistributionUniform,
+		domain_search.DistributionSequential,
+		domain_search.DistributionNormal,
+		domain_search.DistributionFewUnique,
+	} {
+		spec := domain_search.DatasetSpec{Size: 200, Distribution: d, Sorted: true, Seed: 5, Min: math.MinInt64, Max: math.MaxInt64}
+
+		// ユースケースのメソッドを呼び出し
+		data, err := benchUseCase.GenerateDataset(spec)
+
+		// 検証
+		assert.NoError(t, err, "distribution: %s", d)
+		assert.Len(t, data, 200)
+		assert.True(t, sort.IntsAreSorted(data), "distribution: %s", d)
+
+		// 全アルゴリズムで探索できる
+		result, err := benchUseCase.Run(domain_search.BenchmarkRequest{Dataset: &spec, TargetCount: 50, Algorithms: allAlgorithms, Iterations: 1})
+		assert.NoError(t, err, "distribution: %s", d)
+		for _, s := range result.Results {
+			assert.Equal(t, result.Results[0].Found, s.Found, "distribution: %s algorithm: %s", d, s.Algorithm)
+		}
+	}
+}
+
+// Runのテスト(全アルゴリズムが同じtargetを見つけ、統計値の大小関係が正しい)
+func TestBenchmarkRun(t *testing.T) {
+	req := domain_search.BenchmarkRequest{
+		Dataset:     &domain_search.DatasetSpec{Size: 1000, Distribution: domain_search.DistributionSequential, Sorted: true, Seed: 7},
+		TargetCount: 50,
+		Algorithms:  allAlgorithms,
+		Iterations:  10,
+		Warmup:      2,
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := benchUseCase.Run(req)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, result.Size)
+	assert.True(t, result.Sorted)
+	assert.Equal(t, 50, result.Targets)
+	assert.Len(t, result.Results, len(allAlgorithms))
+	for _, s := range result.Results {
+		assert.Equal(t, 10, s.Runs)
+		assert.Equal(t, result.Results[0].Found, s.Found, "algorithm: %s", s.Algorithm)
+		assert.LessOrEqual(t, s.MinNs, s.MedianNs)
+		assert.LessOrEqual(t, s.MedianNs, s.P95Ns)
+		assert.Positive(t, s.Comparisons)
+	}
+
+	// 二分探索は線形探索より比較回数が少ない
+	assert.Less(t, result.Results[1].Comparisons, result.Results[0].Comparisons)
+
+	// 同じseedなら比較回数も再現できる
+	again, err := benchUseCase.Run(req)
+	assert.NoError(t, err)
+	for i := range again.Results {
+		assert.Equal(t, result.Results[i].Comparisons, again.Results[i].Comparisons)
+		assert.Equal(t, result.Results[i].Found, again.Results[i].Found)
+	}
+}
+
+// Runのテスト(明示したデータとtarget)
+func TestBenchmarkRunExplicitData(t *testing.T) {
+	// ユースケースのメソッドを呼び出し
+	result, err := benchUseCase.Run(domain_search.BenchmarkRequest{
+		Data:       []int{1, 3, 5, 7, 9},
+		Targets:    []int{1, 9, 4},
+		Algorithms: allAlgorithms,
+		Iterations: 3,
+	})
+
+	// 検証
+	assert.NoError(t, err)
+	for _, s := range result.Results {
+		assert.Equal(t, 2, s.Found, "algorithm: %s", s.Algorithm)
+	}
+	// 線形探索: 1(1回) + 9(5回) + 4(5回)
+	assert.Equal(t, int64(11), result.Results[0].Comparisons)
+}
+
+// Runのテスト(異常系)
+func TestBenchmarkRunError(t *testing.T) {
+	// 未ソートのデータで二分探索
+	_, err := benchUseCase.Run(domain_search.BenchmarkRequest{
+		Data:       []int{5, 1, 3},
+		Algorithms: []domain_search.SearchAlgorithm{domain_search.AlgorithmBinary},
+	})
+	assert.EqualError(t, err, "algorithm requires sorted data: binary")
+
+	// 不明なアルゴリズム
+	_, err = benchUseCase.Run(domain_search.BenchmarkRequest{
+		Data:       []int{1},
+		Algorithms: []domain_search.SearchAlgorithm{"ternary"},
+	})
+	assert.EqualError(t, err, "unknown algorithm: ternary")
+
+	// データが無い
+	_, err = benchUseCase.Run(domain_search.BenchmarkRequest{Algorithms: allAlgorithms})
+	assert.EqualError(t, err, "dataset or data is required")
+}
@@ -0,0 +1,150 @@
+package test_search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// JSONからグラフの入力を作成する
+func graphInput(t *testing.T, s string) domain_search.GraphInput {
+	var input domain_search.GraphInput
+	if err := json.Unmarshal([]byte(s), &input); err != nil {
+		t.Fatal(err)
+	}
+	return input
+}
+
+// 隣接リストの読み込みのテスト(文字列とオブジェクトの混在)
+func TestGraphInputAdjacency(t *testing.T) {
+	input := graphInput(t, `{"directed": true, "adjacency": {"a": ["b", {"to": "c", "weight": 2.5}]}}`)
+
+	// グラフを作成
+	g, err := domain_search.NewGraph(input)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, g.Nodes)
+	assert.Equal(t, 2, g.EdgeCount())
+	assert.Equal(t, 2.5, g.Adj[0][1].Weight)
+}
+
+// 空のグラフのテスト
+func TestGraphEmpty(t *testing.T) {
+	_, err := graphUseCase.BFS(domain_search.GraphInput{}, "")
+
+	// 検証
+	assert.EqualError(t, err, "graph is empty")
+}
+
+// BFS/DFSの訪問順のテスト
+func TestGraphTraversalOrder(t *testing.T) {
+	input := graphInput(t, `{"edges": [
+		{"from": "a", "to": "b"}, {"from": "a", "to": "c"},
+		{"from": "b", "to": "d"}, {"from": "c", "to": "e"}
+	]}`)
+
+	// BFS
+	bfs, err := graphUseCase.BFS(input, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, bfs.Order)
+	assert.Equal(t, 5, bfs.Visited)
+
+	// DFS
+	dfs, err := graphUseCase.DFS(input, "a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "d", "c", "e"}, dfs.Order)
+
+	// 存在しない開始ノード
+	_, err = graphUseCase.BFS(input, "z")
+	assert.EqualError(t, err, "start node not found")
+}
+
+// 連結成分のテスト
+func TestGraphConnectedComponents(t *testing.T) {
+	input := graphInput(t, `{"nodes": ["x"], "edges": [
+		{"from": "a", "to": "b"}, {"from": "c", "to": "d"}, {"from": "d", "to": "e"}
+	]}`)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := graphUseCase.ConnectedComponents(input)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"x"}, {"a", "b"}, {"c", "d", "e"}}, result.Components)
+}
+
+// 閉路検出のテスト
+func TestGraphDetectCycle(t *testing.T) {
+	// 有向グラフ(閉路あり)
+	result, err := graphUseCase.DetectCycle(graphInput(t, `{"directed": true, "edges": [
+		{"from": "a", "to": "b"}, {"from": "b", "to": "c"}, {"from": "c", "to": "a"}
+	]}`))
+	assert.NoError(t, err)
+	assert.True(t, *result.HasCycle)
+	assert.Equal(t, []string{"a", "b", "c", "a"}, result.Cycle)
+
+	// 有向グラフ(閉路なし、合流あり)
+	result, err = graphUseCase.DetectCycle(graphInput(t, `{"directed": true, "edges": [
+		{"from": "a", "to": "b"}, {"from": "a", "to": "c"}, {"from": "b", "to": "c"}
+	]}`))
+	assert.NoError(t, err)
+	assert.False(t, *result.HasCycle)
+
+	// 無向グラフ(木は閉路なし)
+	result, err = graphUseCase.DetectCycle(graphInput(t, `{"edges": [
+		{"from": "a", "to": "b"}, {"from": "b", "to": "c"}
+	]}`))
+	assert.NoError(t, err)
+	assert.False(t, *result.HasCycle)
+
+	// 無向グラフ(閉路あり)
+	result, err = graphUseCase.DetectCycle(graphInput(t, `{"edges": [
+		{"from": "a", "to": "b"}, {"from": "b", "to": "c"}, {"from": "c", "to": "a"}
+	]}`))
+	assert.NoError(t, err)
+	assert.True(t, *result.HasCycle)
+	assert.Len(t, result.Cycle, 4)
+}
+
+// トポロジカルソートのテスト
+func TestGraphTopologicalSort(t *testing.T) {
+	input := graphInput(t, `{"directed": true, "adjacency": {
+		"shirt": ["tie", "belt"], "tie": ["jacket"], "pants": ["shoes", "belt"], "belt": ["jacket"]
+	}}`)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := graphUseCase.TopologicalSort(input)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pants", "shoes", "shirt", "belt", "tie", "jacket"}, result.Order)
+
+	// 閉路がある場合
+	_, err = graphUseCase.TopologicalSort(graphInput(t, `{"directed": true, "edges": [
+		{"from": "a", "to": "b"}, {"from": "b", "to": "a"}
+	]}`))
+	assert.EqualError(t, err, "graph has a cycle")
+
+	// 無向グラフの場合
+	_, err = graphUseCase.TopologicalSort(graphInput(t, `{"edges": [{"from": "a", "to": "b"}]}`))
+	assert.EqualError(t, err, "graph must be directed")
+}
+
+// 強連結成分のテスト
+func TestGraphStronglyConnectedComponents(t *testing.T) {
+	input := graphInput(t, `{"directed": true, "edges": [
+		{"from": "a", "to": "b"}, {"from": "b", "to": "c"}, {"from": "c", "to": "a"},
+		{"from": "c", "to": "d"}, {"from": "d", "to": "e"}, {"from": "e", "to": "d"}
+	]}`)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := graphUseCase.StronglyConnectedComponents(input)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"e", "d"}, {"c", "b", "a"}}, result.Components)
+	assert.Equal(t, 5, result.Visited)
+}
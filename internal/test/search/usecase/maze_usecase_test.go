@@ -0,0 +1,132 @@
+package test_search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 全ての生成アルゴリズム
+var mazeAlgorithms = []domain_search.MazeAlgorithm{
+	domain_search.MazeBacktracker,
+	domain_search.MazePrim,
+	domain_search.MazeKruskal,
+}
+
+// 通路のマス数を数える
+func countPaths(grid [][]int) int {
+	n := 0
+	for _, row := range grid {
+		for _, cell := range row {
+			if cell == domain_search.MazePath {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Generateのテスト(完全迷路で、左上から右下へ到達できる)
+func TestMazeGenerate(t *testing.T) {
+	for _, a := range mazeAlgorithms {
+		// ユースケースのメソッドを呼び出し
+		result, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 21, Cols: 31, Algorithm: a, Seed: 1})
+
+		// 検証
+		assert.NoError(t, err)
+		assert.Len(t, result.Grid, 21)
+		assert.Len(t, result.Grid[0], 31)
+		// 完全迷路は部屋の数 + (部屋の数 - 1)本の通路を持つ(木になる)
+		rooms := 11 * 16
+		assert.Equal(t, rooms+rooms-1, countPaths(result.Grid), "algorithm: %s", a)
+		assert.True(t, useCase.DFS(result.Grid), "algorithm: %s", a)
+		assert.Greater(t, useCase.BFS(result.Grid), 0, "algorithm: %s", a)
+	}
+}
+
+// Generateのテスト(同じseedからは同じ迷路)
+func TestMazeGenerateDeterministic(t *testing.T) {
+	for _, a := range mazeAlgorithms {
+		spec := domain_search.MazeSpec{Rows: 15, Cols: 15, Algorithm: a, Seed: 42}
+
+		// ユースケースのメソッドを呼び出し
+		first, err := mazeUseCase.Generate(spec)
+		assert.NoError(t, err)
+		second, err := mazeUseCase.Generate(spec)
+		assert.NoError(t, err)
+		spec.Seed = 43
+		other, err := mazeUseCase.Generate(spec)
+		assert.NoError(t, err)
+
+		// 検証
+		assert.Equal(t, first.Text, second.Text, "algorithm: %s", a)
+		assert.NotEqual(t, first.Text, other.Text, "algorithm: %s", a)
+	}
+}
+
+// Generateのテスト(密度を下げるとループができる)
+func TestMazeGenerateDensity(t *testing.T) {
+	half, zero := 0.5, 0.0
+
+	perfect, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 11, Cols: 11, Seed: 3})
+	assert.NoError(t, err)
+	braided, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 11, Cols: 11, Seed: 3, Density: &half})
+	assert.NoError(t, err)
+	open, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 11, Cols: 11, Seed: 3, Density: &zero})
+	assert.NoError(t, err)
+
+	// 検証
+	assert.Greater(t, countPaths(braided.Grid), countPaths(perfect.Grid))
+	// 柱(奇数の座標)以外は全て通路
+	assert.Equal(t, 11*11-5*5, countPaths(open.Grid))
+}
+
+// Generateのテスト(異常系)
+func TestMazeGenerateError(t *testing.T) {
+	_, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 10, Cols: 11})
+	assert.EqualError(t, err, "rows and cols must be odd")
+
+	_, err = mazeUseCase.Generate(domain_search.MazeSpec{Rows: 11, Cols: 11, Algorithm: "wilson"})
+	assert.EqualError(t, err, "unknown maze algorithm")
+
+	density := 1.5
+	_, err = mazeUseCase.Generate(domain_search.MazeSpec{Rows: 11, Cols: 11, Density: &density})
+	assert.EqualError(t, err, "density must be between 0 and 1")
+}
+
+// Import・Exportのテスト(往復で元に戻る)
+func TestMazeImportExport(t *testing.T) {
+	maze, err := mazeUseCase.Generate(domain_search.MazeSpec{Rows: 9, Cols: 13, Algorithm: domain_search.MazeKruskal, Seed: 5})
+	assert.NoError(t, err)
+
+	// ユースケースのメソッドを呼び出し
+	grid, _, err := mazeUseCase.Import(maze.Text)
+	assert.NoError(t, err)
+	assert.Equal(t, maze.Grid, grid)
+
+	binary, err := mazeUseCase.Export(grid, domain_search.MazeFormatBinary)
+	assert.NoError(t, err)
+	grid, _, err = mazeUseCase.Import(binary)
+	assert.NoError(t, err)
+	assert.Equal(t, maze.Grid, grid)
+}
+
+// Importのテスト(S/G、末尾の空白の省略、異常系)
+func TestMazeImport(t *testing.T) {
+	// 末尾の空白が省略された行は通路で補う
+	grid, _, err := mazeUseCase.Import("S #\n#\n  G\r\n")
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{0, 0, 1}, {1, 0, 0}, {0, 0, 0}}, grid)
+
+	_, line, err := mazeUseCase.Import("..#\n.?#\n")
+	assert.EqualError(t, err, "invalid maze character")
+	assert.Equal(t, 2, line)
+
+	_, line, err = mazeUseCase.Import("..#\n..#.\n")
+	assert.EqualError(t, err, "maze rows must have the same length")
+	assert.Equal(t, 2, line)
+
+	_, _, err = mazeUseCase.Import("\n\n")
+	assert.EqualError(t, err, "maze text is empty")
+}
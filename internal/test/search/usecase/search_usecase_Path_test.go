@@ -0,0 +1,281 @@
+package test_search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 経路が隣接マスの連続になっているかを検証する
+func assertContinuousPath(t *testing.T, path []domain_search.Point, diagonal bool) {
+	for i := 1; i < len(path); i++ {
+		dr := path[i].Row - path[i-1].Row
+		dc := path[i].Col - path[i-1].Col
+		if dr < 0 {
+			dr = -dr
+		}
+		if dc < 0 {
+			dc = -dc
+		}
+		if diagonal {
+			assert.True(t, dr <= 1 && dc <= 1 && dr+dc > 0, "not adjacent: %v -> %v", path[i-1], path[i])
+		} else {
+			assert.Equal(t, 1, dr+dc, "not adjacent: %v -> %v", path[i-1], path[i])
+		}
+	}
+}
+
+// BFSPathのテスト
+func TestBFSPath(t *testing.T) {
+	graph := [][]int{
+		{0, 1, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	steps, path := useCase.BFSPath(graph)
+
+	// 検証
+	assert.Equal(t, 5, steps)
+	assert.Len(t, path, steps)
+	assert.Equal(t, domain_search.Point{Row: 0, Col: 0}, path[0])
+	assert.Equal(t, domain_search.Point{Row: 2, Col: 2}, path[len(path)-1])
+	assertContinuousPath(t, path, false)
+}
+
+// BFSPathのテスト(到達不可)
+func TestBFSPathUnreachable(t *testing.T) {
+	graph := [][]int{
+		{0, 1},
+		{1, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	steps, path := useCase.BFSPath(graph)
+
+	// 検証
+	assert.Equal(t, -1, steps)
+	assert.Nil(t, path)
+}
+
+// DFSPathのテスト
+func TestDFSPath(t *testing.T) {
+	graph := [][]int{
+		{0, 0, 0},
+		{1, 1, 0},
+		{0, 0, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, path := useCase.DFSPath(graph)
+
+	// 検証
+	assert.True(t, result)
+	assert.Equal(t, domain_search.Point{Row: 0, Col: 0}, path[0])
+	assert.Equal(t, domain_search.Point{Row: 2, Col: 2}, path[len(path)-1])
+	assertContinuousPath(t, path, false)
+}
+
+// Dijkstraのテスト(コストの小さい迂回路を選ぶ)
+func TestDijkstra(t *testing.T) {
+	grid := [][]int{
+		{0, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+	costs := [][]int{
+		{1, 9, 1},
+		{1, 9, 1},
+		{1, 1, 1},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Dijkstra(grid, costs, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 0, Col: 2}, false)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.True(t, result.Found)
+	assert.Equal(t, float64(6), result.Cost)
+	assert.Len(t, result.Path, 7)
+	assertContinuousPath(t, result.Path, false)
+}
+
+// Dijkstraのテスト(斜め移動)
+func TestDijkstraDiagonal(t *testing.T) {
+	grid := [][]int{
+		{0, 0, 0},
+		{0, 0, 0},
+		{0, 0, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Dijkstra(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 2, Col: 2}, true)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*math.Sqrt2, result.Cost, 1e-9)
+	assert.Len(t, result.Path, 3)
+	assertContinuousPath(t, result.Path, true)
+}
+
+// Dijkstraのテスト(壁で到達不可)
+func TestDijkstraUnreachable(t *testing.T) {
+	grid := [][]int{
+		{0, 1, 0},
+		{0, 1, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.Dijkstra(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 1, Col: 2}, true)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.False(t, result.Found)
+	assert.Equal(t, float64(-1), result.Cost)
+	assert.Empty(t, result.Path)
+}
+
+// Dijkstraのテスト(異常系)
+func TestDijkstraError(t *testing.T) {
+	grid := [][]int{
+		{0, 1},
+		{0, 0},
+	}
+
+	// スタートが範囲外
+	_, err := useCase.Dijkstra(grid, nil, domain_search.Point{Row: 5, Col: 0}, domain_search.Point{Row: 1, Col: 1}, false)
+	assert.EqualError(t, err, "start is out of range")
+
+	// ゴールが壁
+	_, err = useCase.Dijkstra(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 0, Col: 1}, false)
+	assert.EqualError(t, err, "goal is a wall")
+
+	// グリッドが空
+	_, err = useCase.Dijkstra([][]int{}, nil, domain_search.Point{}, domain_search.Point{}, false)
+	assert.EqualError(t, err, "grid is empty")
+
+	// 0/1以外の値
+	_, err = useCase.Dijkstra([][]int{{0, -1}, {0, 2}}, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 1, Col: 0}, false)
+	assert.EqualError(t, err, "grid must contain only 0 or 1")
+
+	// コストの形がグリッドと違う
+	_, err = useCase.Dijkstra(grid, [][]int{{1, 1}}, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 1, Col: 1}, false)
+	assert.EqualError(t, err, "costs must have the same shape as grid")
+
+	// 負のコスト
+	_, err = useCase.Dijkstra(grid, [][]int{{1, 1}, {-1, 1}}, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 1, Col: 1}, false)
+	assert.EqualError(t, err, "costs must not be negative")
+}
+
+// AStarのテスト(ダイクストラ法と同じコストで、展開ノード数が少ない)
+func TestAStar(t *testing.T) {
+	// 20x20の一様なグリッド
+	grid := make([][]int, 20)
+	for i := range grid {
+		grid[i] = make([]int, 20)
+	}
+	start := domain_search.Point{Row: 0, Col: 0}
+	goal := domain_search.Point{Row: 19, Col: 10}
+
+	for _, h := range []domain_search.Heuristic{
+		domain_search.HeuristicManhattan,
+		domain_search.HeuristicEuclidean,
+		domain_search.HeuristicChebyshev,
+		domain_search.HeuristicOctile,
+		"",
+	} {
+		// ユースケースのメソッドを呼び出し
+		dijkstra, err := useCase.Dijkstra(grid, nil, start, goal, false)
+		assert.NoError(t, err)
+		astar, err := useCase.AStar(grid, nil, start, goal, false, h)
+		assert.NoError(t, err)
+
+		// 検証
+		assert.Equal(t, dijkstra.Cost, astar.Cost, "heuristic: %s", h)
+		assert.Less(t, astar.Expanded, dijkstra.Expanded, "heuristic: %s", h)
+		assertContinuousPath(t, astar.Path, false)
+	}
+}
+
+// AStarのテスト(斜め移動 + octile)
+func TestAStarDiagonal(t *testing.T) {
+	grid := [][]int{
+		{0, 0, 0, 0},
+		{0, 1, 1, 0},
+		{0, 0, 0, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	dijkstra, err := useCase.Dijkstra(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 2, Col: 3}, true)
+	assert.NoError(t, err)
+	astar, err := useCase.AStar(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 2, Col: 3}, true, domain_search.HeuristicOctile)
+	assert.NoError(t, err)
+
+	// 検証
+	assert.InDelta(t, dijkstra.Cost, astar.Cost, 1e-9)
+	assertContinuousPath(t, astar.Path, true)
+}
+
+// AStarのテスト(異常系 - 不明なヒューリスティック)
+func TestAStarUnknownHeuristic(t *testing.T) {
+	grid := [][]int{{0, 0}}
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.AStar(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 0, Col: 1}, false, "unknown")
+
+	// 検証
+	assert.EqualError(t, err, "unknown heuristic")
+}
+
+// AStarのテスト(異常系 - 斜め移動でマンハッタン距離)
+func TestAStarManhattanDiagonal(t *testing.T) {
+	grid := [][]int{
+		{0, 0},
+		{0, 0},
+	}
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.AStar(grid, nil, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: 1, Col: 1}, true, domain_search.HeuristicManhattan)
+
+	// 検証
+	assert.EqualError(t, err, "manhattan heuristic does not support diagonal moves")
+}
+
+// DFSのテスト(大きな迷路でもスタックが溢れない)
+func TestDFSLargeMaze(t *testing.T) {
+	// 999x999の蛇行する迷路(経路長は約50万マス)
+	const n = 999
+	graph := make([][]int, n)
+	for i := range graph {
+		graph[i] = make([]int, n)
+		if i%2 == 1 {
+			for j := range graph[i] {
+				graph[i][j] = 1
+			}
+			// 行ごとに左右交互に通路を開ける
+			if i%4 == 1 {
+				graph[i][n-1] = 0
+			} else {
+				graph[i][0] = 0
+			}
+		}
+	}
+
+	// ユースケースのメソッドを呼び出し
+	result, path := useCase.DFSPath(graph)
+
+	// 検証
+	assert.True(t, result)
+	assert.Equal(t, domain_search.Point{Row: n - 1, Col: n - 1}, path[len(path)-1])
+	assert.True(t, useCase.DFS(graph))
+}
+
+// 空のグリッドのテスト(パニックしない)
+func TestSearchEmptyGrid(t *testing.T) {
+	assert.Equal(t, -1, useCase.BFS([][]int{}))
+	assert.False(t, useCase.DFS([][]int{}))
+	assert.False(t, useCase.DFS([][]int{{}}))
+}
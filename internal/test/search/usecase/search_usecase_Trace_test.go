@@ -0,0 +1,106 @@
+package test_search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// イベントの種類の一覧を取得する
+func eventTypes(events []domain_search.TraceEvent) []domain_search.TraceEventType {
+	types := []domain_search.TraceEventType{}
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	return types
+}
+
+// LinearSearchTraceのテスト
+func TestLinearSearchTrace(t *testing.T) {
+	log, tracer := domain_search.NewTraceRecorder(100)
+
+	// ユースケースのメソッドを呼び出し
+	index := useCase.LinearSearchTrace([]int{4, 2, 9}, 2, tracer)
+
+	// 検証
+	assert.Equal(t, 1, index)
+	assert.Equal(t, []domain_search.TraceEventType{"compare", "compare", "found", "done"}, eventTypes(log.Events))
+	assert.Equal(t, []int{1, 2}, log.Events[1].Args)
+	assert.Equal(t, 4, log.Events[3].Step)
+}
+
+// BinarySearchTraceのテスト
+func TestBinarySearchTrace(t *testing.T) {
+	log, tracer := domain_search.NewTraceRecorder(100)
+
+	// ユースケースのメソッドを呼び出し
+	index := useCase.BinarySearchTrace([]int{1, 3, 5, 7, 9, 11, 13}, 11, tracer)
+
+	// 検証
+	assert.Equal(t, 5, index)
+	assert.Equal(t, domain_search.TraceBounds, log.Events[0].Type)
+	assert.Equal(t, []int{0, 6, 3}, log.Events[0].Args)
+	assert.Equal(t, []int{4, 6, 5}, log.Events[2].Args)
+	assert.Equal(t, []int{5}, log.Events[len(log.Events)-2].Args)
+}
+
+// BFSTraceのテスト(フロンティアが深さごとに記録される)
+func TestBFSTrace(t *testing.T) {
+	graph := [][]int{
+		{0, 0},
+		{0, 0},
+	}
+	log, tracer := domain_search.NewTraceRecorder(100)
+
+	// ユースケースのメソッドを呼び出し
+	steps, path := useCase.BFSTrace(graph, tracer)
+
+	// 検証
+	assert.Equal(t, 3, steps)
+	assert.Len(t, path, 3)
+	frontiers := [][]domain_search.Point{}
+	for _, e := range log.Events {
+		if e.Type == domain_search.TraceFrontier {
+			frontiers = append(frontiers, e.Cells)
+		}
+	}
+	assert.Equal(t, [][]domain_search.Point{
+		{{Row: 0, Col: 0}},
+		{{Row: 1, Col: 0}, {Row: 0, Col: 1}},
+		{{Row: 1, Col: 1}},
+	}, frontiers)
+	assert.Equal(t, domain_search.TraceDone, log.Events[len(log.Events)-1].Type)
+}
+
+// DFSTraceのテスト(打ち切り)
+func TestDFSTraceTruncated(t *testing.T) {
+	graph := make([][]int, 10)
+	for i := range graph {
+		graph[i] = make([]int, 10)
+	}
+	log, tracer := domain_search.NewTraceRecorder(5)
+
+	// ユースケースのメソッドを呼び出し
+	result, _ := useCase.DFSTrace(graph, tracer)
+
+	// 検証
+	assert.True(t, result)
+	assert.Len(t, log.Events, 5)
+	assert.True(t, log.Truncated)
+	assert.Equal(t, domain_search.TracePush, log.Events[0].Type)
+}
+
+// 途中経過を記録しない場合はアロケーションが発生しない
+func TestTraceDisabledNoAllocs(t *testing.T) {
+	arr := make([]int, 1000)
+	for i := range arr {
+		arr[i] = i * 2
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		useCase.LinearSearch(arr, 1500)
+		useCase.BinarySearch(arr, 1500)
+	})
+	assert.Zero(t, allocs)
+}
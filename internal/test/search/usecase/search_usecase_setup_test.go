@@ -0,0 +1,41 @@
+package test_search_usecase
+
+import (
+	pkg_config "backend/config"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_search "backend/internal/usecase/search"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger       *pkg_logger.AppLogger
+	useCase      usecase_search.ISearchUsecase
+	graphUseCase usecase_search.IGraphUsecase
+	benchUseCase usecase_search.IBenchmarkUsecase
+	mazeUseCase  usecase_search.IMazeUsecase
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// 探索はリポジトリを持たないため、モックは不要
+	useCase = usecase_search.NewSearchUsecase(logger)
+	graphUseCase = usecase_search.NewGraphUsecase(logger)
+	benchUseCase = usecase_search.NewBenchmarkUsecase(logger)
+	mazeUseCase = usecase_search.NewMazeUsecase(logger)
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,106 @@
+package test_sort_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// ハンドラにJSONをPOSTし、ステータスコードとレスポンスを返す
+func post(t *testing.T, fn echo.HandlerFunc, body string) (int, map[string]interface{}) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/api/sort", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	res := httptest.NewRecorder()
+	c := e.NewContext(req, res)
+
+	// ハンドラのメソッドを呼び出し
+	if err := fn(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// JSONレスポンスのデコード
+	var resBody map[string]interface{}
+	if err := json.Unmarshal(res.Body.Bytes(), &resBody); err != nil {
+		t.Fatal(err)
+	}
+	return res.Code, resBody
+}
+
+// 入力エラーの項目名を取得する
+func errorFields(resBody map[string]interface{}) []string {
+	fields := []string{}
+	errs, _ := resBody["errors"].([]interface{})
+	for _, e := range errs {
+		fields = append(fields, e.(map[string]interface{})["field"].(string))
+	}
+	return fields
+}
+
+// QuickSortのテスト(正常系)
+func TestQuickSort(t *testing.T) {
+	code, resBody := post(t, handler.QuickSort, `{"values": [3, -1, 2.5, 10]}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{float64(-1), 2.5, float64(3), float64(10)}, resBody["sorted"])
+	assert.Equal(t, float64(4), resBody["count"])
+	assert.Equal(t, false, resBody["stable"])
+	assert.Nil(t, resBody["steps"])
+}
+
+// MergeSortのテスト(オブジェクトのキー、キーが値より後ろにある)
+func TestMergeSortObjects(t *testing.T) {
+	code, resBody := post(t, handler.MergeSort, `{"values": [{"name": "b", "n": {"v": 2}}, {"name": "a", "n": {"v": 1}}], "desc": true, "key": "n.v"}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	sorted := resBody["sorted"].([]interface{})
+	assert.Equal(t, "b", sorted[0].(map[string]interface{})["name"])
+	assert.Equal(t, true, resBody["desc"])
+	assert.Equal(t, true, resBody["stable"])
+}
+
+// HeapSortのテスト(文字列、途中経過)
+func TestHeapSortTrace(t *testing.T) {
+	code, resBody := post(t, handler.HeapSort, `{"values": ["c", "a", "b"], "trace": true, "stable": true}`)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, []interface{}{"a", "b", "c"}, resBody["sorted"])
+	assert.Equal(t, true, resBody["stable"])
+	assert.NotEmpty(t, resBody["steps"])
+}
+
+// ソートのテスト(異常系)
+func TestSortValidation(t *testing.T) {
+	cases := []struct {
+		fn     echo.HandlerFunc
+		body   string
+		fields []string
+	}{
+		{handler.QuickSort, `{}`, []string{"values"}},
+		{handler.QuickSort, `{"values": [1, true]}`, []string{"values[1]"}},
+		{handler.QuickSort, `{"values": [1, "a"]}`, []string{"values[1]"}},
+		{handler.QuickSort, `{"values": [{"a": 1}]}`, []string{"key"}},
+		{handler.QuickSort, `{"values": [{"a": 1}, {"b": 1}], "key": "a"}`, []string{"values[1]"}},
+		{handler.RadixSort, `{"values": [1, 2.5]}`, []string{"values"}},
+		{handler.TimSort, `{"values": [` + strings.TrimSuffix(strings.Repeat("1,", 21), ",") + `]}`, []string{"values"}},
+		{handler.InsertionSort, `{"values": [` + strings.TrimSuffix(strings.Repeat("1,", 11), ",") + `]}`, []string{"values"}},
+		{handler.TimSort, `{"values": [1, 2, 3, 4, 5, 6], "trace": true}`, []string{"values"}},
+	}
+	for _, tc := range cases {
+		code, resBody := post(t, tc.fn, tc.body)
+		assert.Equal(t, http.StatusUnprocessableEntity, code, tc.body)
+		assert.Equal(t, tc.fields, errorFields(resBody), tc.body)
+	}
+
+	// JSONとして不正
+	code, _ := post(t, handler.QuickSort, `{"values": [1, 2`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,40 @@
+package test_sort_handler
+
+import (
+	pkg_config "backend/config"
+	interfaces_sort "backend/internal/interfaces/sort"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_sort "backend/internal/usecase/sort"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger  *pkg_logger.AppLogger
+	handler *interfaces_sort.SortHandler
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+	// 上限値のテストのため小さくする
+	appConfig.SortLimits.MaxValues = 20
+	appConfig.SortLimits.MaxQuadraticValues = 10
+	appConfig.SortLimits.MaxTraceValues = 5
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// ソートは純粋な計算のため、モックではなく実際のユースケースを使う
+	handler = interfaces_sort.NewSortHandler(appConfig, logger, usecase_sort.NewSortUsecase(logger))
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
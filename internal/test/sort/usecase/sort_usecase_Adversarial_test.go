@@ -0,0 +1,114 @@
+package test_sort_usecase
+
+import (
+	domain_sort "backend/internal/domain/sort"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 両端と中央の中央値をピボットにするクイックソートを最悪にする入力
+// McIlroyの"A Killer Adversary for Quicksort"の方法で、比較のたびに値を決めながら作る
+func medianOf3Killer(n int) []int {
+	gas := n
+	values := make([]int, n)
+	for i := range values {
+		values[i] = gas
+	}
+	solid, candidate := 0, 0
+	compare := func(x, y int) int {
+		if values[x] == gas && values[y] == gas {
+			// 未定の値同士は、候補でない方を小さい値に決める
+			if x == candidate {
+				values[x] = solid
+			} else {
+				values[y] = solid
+			}
+			solid++
+		}
+		if values[x] == gas {
+			candidate = x
+		} else if values[y] == gas {
+			candidate = y
+		}
+		return values[x] - values[y]
+	}
+
+	// 位置の並びを、値を決めながらソートする(3-way分割、両端と中央の中央値)
+	ptr := make([]int, n)
+	for i := range ptr {
+		ptr[i] = i
+	}
+	var sortRange func(lo, hi int)
+	sortRange = func(lo, hi int) {
+		less := func(i, j int) bool { return compare(ptr[i], ptr[j]) < 0 }
+		swap := func(i, j int) { ptr[i], ptr[j] = ptr[j], ptr[i] }
+		for lo < hi {
+			mid := lo + (hi-lo)/2
+			if less(mid, lo) {
+				swap(mid, lo)
+			}
+			if less(hi, lo) {
+				swap(hi, lo)
+			}
+			if less(hi, mid) {
+				swap(hi, mid)
+			}
+			swap(lo, mid)
+			pivot := ptr[lo]
+			lt, i, gt := lo, lo+1, hi
+			for i <= gt {
+				switch c := compare(ptr[i], pivot); {
+				case c < 0:
+					swap(lt, i)
+					lt++
+					i++
+				case c > 0:
+					swap(i, gt)
+					gt--
+				default:
+					i++
+				}
+			}
+			if lt-lo < hi-gt {
+				sortRange(lo, lt-1)
+				lo = gt + 1
+			} else {
+				sortRange(gt+1, hi)
+				hi = lt - 1
+			}
+		}
+	}
+	sortRange(0, n-1)
+	return values
+}
+
+// Sortのテスト(クイックソートは意図的な入力でもO(n log n)の比較回数に収まる)
+func TestQuickSortAdversarial(t *testing.T) {
+	const n = 5000
+	inputs := map[string][]int{
+		"median-of-3 killer": medianOf3Killer(n),
+		"organ pipe":         make([]int, n),
+	}
+	for i := 0; i < n; i++ {
+		inputs["organ pipe"][i] = min(i, n-1-i)
+	}
+
+	// 上限はn·log₂nの数倍(2乗になるとおよそn²/4回)
+	limit := int64(4 * n * math.Log2(n))
+	for name, values := range inputs {
+		// ユースケースのメソッドを呼び出し
+		items := intItems(values)
+		result, err := useCase.Sort(domain_sort.AlgorithmQuick, items, domain_sort.SortOptions{})
+
+		// 検証
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, result.Comparisons, limit, name)
+		for i := 1; i < n; i++ {
+			if items[i-1].Key.Int > items[i].Key.Int {
+				t.Fatalf("%s: not sorted at %d", name, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package test_sort_usecase
+
+import (
+	domain_sort "backend/internal/domain/sort"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 全てのアルゴリズム
+var algorithms = []domain_sort.Algorithm{
+	domain_sort.AlgorithmQuick,
+	domain_sort.AlgorithmMerge,
+	domain_sort.AlgorithmHeap,
+	domain_sort.AlgorithmInsertion,
+	domain_sort.AlgorithmRadix,
+	domain_sort.AlgorithmTim,
+}
+
+// 整数の要素を作成する
+func intItems(values []int) []domain_sort.Item {
+	items := make([]domain_sort.Item, len(values))
+	for i, v := range values {
+		items[i] = domain_sort.Item{Key: domain_sort.Key{Kind: domain_sort.KindInt, Int: int64(v)}, Index: i}
+	}
+	return items
+}
+
+// 要素の整数値を取得する
+func intValues(items []domain_sort.Item) []int {
+	values := make([]int, len(items))
+	for i, it := range items {
+		values[i] = int(it.Key.Int)
+	}
+	return values
+}
+
+// Sortのテスト(全アルゴリズムで昇順・降順に並ぶ)
+func TestSort(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 31, 100, 1000} {
+		values := make([]int, n)
+		for i := range values {
+			values[i] = rng.Intn(200) - 100
+		}
+		asc := make([]int, n)
+		copy(asc, values)
+		sort.Ints(asc)
+		desc := make([]int, n)
+		copy(desc, asc)
+		sort.Sort(sort.Reverse(sort.IntSlice(desc)))
+
+		for _, a := range algorithms {
+			// ユースケースのメソッドを呼び出し
+			items := intItems(values)
+			result, err := useCase.Sort(a, items, domain_sort.SortOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, asc, intValues(items), "algorithm: %s n: %d", a, n)
+			assert.Equal(t, n, result.Count)
+
+			items = intItems(values)
+			_, err = useCase.Sort(a, items, domain_sort.SortOptions{Desc: true})
+			assert.NoError(t, err)
+			assert.Equal(t, desc, intValues(items), "algorithm: %s n: %d (desc)", a, n)
+		}
+	}
+}
+
+// Sortのテスト(部分的にソート済み、全て同じ値)
+func TestSortPresorted(t *testing.T) {
+	inputs := map[string][]int{}
+	inputs["ascending"] = make([]int, 500)
+	inputs["descending"] = make([]int, 500)
+	inputs["same"] = make([]int, 500)
+	inputs["sawtooth"] = make([]int, 500)
+	for i := 0; i < 500; i++ {
+		inputs["ascending"][i] = i
+		inputs["descending"][i] = 500 - i
+		inputs["same"][i] = 7
+		inputs["sawtooth"][i] = i % 37
+	}
+
+	for name, values := range inputs {
+		expected := append([]int(nil), values...)
+		sort.Ints(expected)
+		for _, a := range algorithms {
+			items := intItems(values)
+			_, err := useCase.Sort(a, items, domain_sort.SortOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, intValues(items), "algorithm: %s input: %s", a, name)
+		}
+	}
+}
+
+// Sortのテスト(安定性)
+func TestSortStable(t *testing.T) {
+	// キーが重複する要素(Indexで元の順を確認する)
+	rng := rand.New(rand.NewSource(2))
+	values := make([]int, 300)
+	for i := range values {
+		values[i] = rng.Intn(5)
+	}
+
+	for _, a := range algorithms {
+		// ユースケースのメソッドを呼び出し
+		items := intItems(values)
+		result, err := useCase.Sort(a, items, domain_sort.SortOptions{Stable: true})
+
+		// 検証
+		assert.NoError(t, err)
+		assert.True(t, result.Stable, "algorithm: %s", a)
+		for i := 1; i < len(items); i++ {
+			if items[i].Key.Int == items[i-1].Key.Int {
+				assert.Less(t, items[i-1].Index, items[i].Index, "algorithm: %s", a)
+			}
+		}
+	}
+
+	// 不安定なソートはstableを指定しない場合、安定性を保証しない
+	result, err := useCase.Sort(domain_sort.AlgorithmHeap, intItems(values), domain_sort.SortOptions{})
+	assert.NoError(t, err)
+	assert.False(t, result.Stable)
+}
+
+// Sortのテスト(文字列、小数、オブジェクトのキー)
+func TestSortKinds(t *testing.T) {
+	raws := []string{`"pear"`, `"apple"`, `"fig"`}
+	items := make([]domain_sort.Item, len(raws))
+	for i, r := range raws {
+		items[i], _ = domain_sort.NewItem(json.RawMessage(r), i)
+	}
+	_, err := useCase.Sort(domain_sort.AlgorithmTim, items, domain_sort.SortOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"apple", "fig", "pear"}, []string{items[0].Key.Str, items[1].Key.Str, items[2].Key.Str})
+
+	// 整数と小数の混在
+	raws = []string{`2.5`, `-1`, `2`, `1e3`}
+	items = make([]domain_sort.Item, len(raws))
+	for i, r := range raws {
+		items[i], _ = domain_sort.NewItem(json.RawMessage(r), i)
+	}
+	_, err = useCase.Sort(domain_sort.AlgorithmMerge, items, domain_sort.SortOptions{})
+	assert.NoError(t, err)
+	out := []string{}
+	for _, it := range items {
+		out = append(out, string(it.AppendJSON(nil)))
+	}
+	assert.Equal(t, []string{"-1", "2", "2.5", "1000"}, out)
+
+	// オブジェクト
+	raws = []string{`{"user": {"age": 30}, "id": 1}`, `{"user": {"age": 20}, "id": 2}`}
+	items = make([]domain_sort.Item, len(raws))
+	for i, r := range raws {
+		items[i], _ = domain_sort.NewItem(json.RawMessage(r), i)
+	}
+	_, err = domain_sort.ApplyKey(items, "user.age")
+	assert.NoError(t, err)
+	_, err = useCase.Sort(domain_sort.AlgorithmQuick, items, domain_sort.SortOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, items[0].Index)
+}
+
+// Sortのテスト(途中経過の記録)
+func TestSortTrace(t *testing.T) {
+	for _, a := range algorithms {
+		// ユースケースのメソッドを呼び出し
+		result, err := useCase.Sort(a, intItems([]int{3, 1, 2}), domain_sort.SortOptions{Trace: true, MaxTraceSteps: 1000})
+
+		// 検証
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Steps, "algorithm: %s", a)
+		assert.False(t, result.Truncated)
+	}
+
+	// 上限で打ち切り
+	result, err := useCase.Sort(domain_sort.AlgorithmInsertion, intItems([]int{5, 4, 3, 2, 1}), domain_sort.SortOptions{Trace: true, MaxTraceSteps: 3})
+	assert.NoError(t, err)
+	assert.Len(t, result.Steps, 3)
+	assert.True(t, result.Truncated)
+
+	// 記録しない場合は空
+	result, err = useCase.Sort(domain_sort.AlgorithmInsertion, intItems([]int{2, 1}), domain_sort.SortOptions{})
+	assert.NoError(t, err)
+	assert.Nil(t, result.Steps)
+}
+
+// Sortのテスト(異常系)
+func TestSortError(t *testing.T) {
+	item, _ := domain_sort.NewItem(json.RawMessage(`"a"`), 0)
+	_, err := useCase.Sort(domain_sort.AlgorithmRadix, []domain_sort.Item{item}, domain_sort.SortOptions{})
+	assert.EqualError(t, err, "radix sort supports integers only")
+
+	_, err = useCase.Sort("bogo", nil, domain_sort.SortOptions{})
+	assert.EqualError(t, err, "unknown algorithm")
+}
+
+// NewItem・ApplyKeyのテスト(異常系)
+func TestApplyKeyError(t *testing.T) {
+	_, err := domain_sort.NewItem(json.RawMessage(`true`), 0)
+	assert.Error(t, err)
+
+	cases := map[string][]string{
+		"values must have the same type": {`1`, `"a"`},
+		"key not found":                  {`{"a": 1}`, `{"b": 1}`},
+		"key must be a number or string": {`{"a": [1]}`},
+	}
+	for expected, raws := range cases {
+		items := make([]domain_sort.Item, len(raws))
+		for i, r := range raws {
+			items[i], _ = domain_sort.NewItem(json.RawMessage(r), i)
+		}
+		_, err := domain_sort.ApplyKey(items, "a")
+		assert.EqualError(t, err, expected, fmt.Sprint(raws))
+	}
+}
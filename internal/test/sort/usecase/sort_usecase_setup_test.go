@@ -0,0 +1,35 @@
+package test_sort_usecase
+
+import (
+	pkg_config "backend/config"
+	pkg_logger "backend/internal/pkg/logger"
+	usecase_sort "backend/internal/usecase/sort"
+	"os"
+	"testing"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger  *pkg_logger.AppLogger
+	useCase usecase_sort.ISortUsecase
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// ソートはリポジトリを持たないため、モックは不要
+	useCase = usecase_sort.NewSortUsecase(logger)
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,151 @@
+package test_stub
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+// JSONPlaceholder互換のスタブサーバー
+// /posts /comments /albums /users と ?postId= ?userId= での絞り込み、/{resource}/{id} に応答する
+// ユーザーごとに投稿2件・アルバム2件、投稿ごとにコメント2件のデータを持つ
+type JSONPlaceholder struct {
+	*httptest.Server
+	Users    []domain_fetch.User
+	Posts    []domain_fetch.Post
+	Comments []domain_fetch.Comment
+	Albums   []domain_fetch.Album
+
+	mu       sync.Mutex
+	hits     map[string]int
+	failures map[string]int
+}
+
+// スタブサーバーの起動(終了時はCloseを呼ぶ)
+func NewJSONPlaceholder(users int) *JSONPlaceholder {
+	s := &JSONPlaceholder{hits: map[string]int{}, failures: map[string]int{}}
+	for u := 1; u <= users; u++ {
+		s.Users = append(s.Users, domain_fetch.User{
+			ID:       u,
+			Name:     fmt.Sprintf("User %d", u),
+			Username: fmt.Sprintf("user%d", u),
+			Email:    fmt.Sprintf("user%d@example.com", u),
+			Phone:    fmt.Sprintf("000-0000-%04d", u),
+			Website:  fmt.Sprintf("user%d.example.com", u),
+		})
+		for i := 0; i < 2; i++ {
+			postID := len(s.Posts) + 1
+			s.Posts = append(s.Posts, domain_fetch.Post{ID: postID, UserID: u, Title: fmt.Sprintf("Post %d", postID), Body: fmt.Sprintf("Body of post %d", postID)})
+			s.Albums = append(s.Albums, domain_fetch.Album{ID: len(s.Albums) + 1, UserID: u, Title: fmt.Sprintf("Album %d", len(s.Albums)+1)})
+			for j := 0; j < 2; j++ {
+				id := len(s.Comments) + 1
+				s.Comments = append(s.Comments, domain_fetch.Comment{ID: id, PostID: postID, Name: fmt.Sprintf("Comment %d", id), Email: fmt.Sprintf("commenter%d@example.com", id), Body: fmt.Sprintf("Body of comment %d", id)})
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users", s.list("users", func(r *http.Request) any { return s.Users }))
+	mux.HandleFunc("GET /posts", s.list("posts", func(r *http.Request) any {
+		return filter(s.Posts, r.URL.Query().Get("userId"), func(p domain_fetch.Post) int { return p.UserID })
+	}))
+	mux.HandleFunc("GET /comments", s.list("comments", func(r *http.Request) any {
+		return filter(s.Comments, r.URL.Query().Get("postId"), func(c domain_fetch.Comment) int { return c.PostID })
+	}))
+	mux.HandleFunc("GET /albums", s.list("albums", func(r *http.Request) any {
+		return filter(s.Albums, r.URL.Query().Get("userId"), func(a domain_fetch.Album) int { return a.UserID })
+	}))
+	mux.HandleFunc("GET /users/{id}", s.item("users", func(id int) any { return find(s.Users, id, func(u domain_fetch.User) int { return u.ID }) }))
+	mux.HandleFunc("GET /posts/{id}", s.item("posts", func(id int) any { return find(s.Posts, id, func(p domain_fetch.Post) int { return p.ID }) }))
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// 指定したリソースへのリクエストをstatusで失敗させる(0で解除)
+func (s *JSONPlaceholder) Fail(resource string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures[resource] = status
+}
+
+// リソースへのリクエスト回数
+func (s *JSONPlaceholder) Hits(resource string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits[resource]
+}
+
+// 回数を数え、失敗させる指定があればそのステータスを返す
+func (s *JSONPlaceholder) record(w http.ResponseWriter, resource string) bool {
+	s.mu.Lock()
+	s.hits[resource]++
+	status := s.failures[resource]
+	s.mu.Unlock()
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return false
+	}
+	return true
+}
+
+// 一覧のハンドラ
+func (s *JSONPlaceholder) list(resource string, get func(r *http.Request) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.record(w, resource) {
+			writeJSON(w, get(r))
+		}
+	}
+}
+
+// 1件のハンドラ
+func (s *JSONPlaceholder) item(resource string, get func(id int) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !s.record(w, resource) {
+			return
+		}
+		id, _ := strconv.Atoi(r.PathValue("id"))
+		v := get(id)
+		if v == nil {
+			// JSONPlaceholderは存在しないIDに空のオブジェクトを返す
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("{}"))
+			return
+		}
+		writeJSON(w, v)
+	}
+}
+
+// JSONを書き出す
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
+
+// キーで絞り込む(空の場合は全件)
+func filter[T any](items []T, value string, key func(T) int) []T {
+	if value == "" {
+		return items
+	}
+	id, _ := strconv.Atoi(value)
+	result := []T{}
+	for _, item := range items {
+		if key(item) == id {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// IDで探す
+func find[T any](items []T, id int, key func(T) int) any {
+	for _, item := range items {
+		if key(item) == id {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package test_stub
+
+import (
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// 書き出されたメール
+type Mail struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// メールの書き出し先の .eml ファイルを送信順に読み込む
+func ReadMails(dir string) ([]Mail, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	mails := []Mail{}
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := mail.ReadMessage(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil {
+			return nil, err
+		}
+		mails = append(mails, Mail{
+			From:    msg.Header.Get("From"),
+			To:      msg.Header.Get("To"),
+			Subject: subject,
+			Body:    strings.ReplaceAll(string(body), "\r\n", "\n"), // 改行はCRLFで書き出される
+		})
+	}
+	return mails, nil
+}
+
+// メールの本文のリンクのトークン
+var tokenPattern = regexp.MustCompile(`[?&]token=([^&\s]+)`)
+
+// メールの本文のリンクからトークンを取り出す
+func MailToken(m Mail) string {
+	match := tokenPattern.FindStringSubmatch(m.Body)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
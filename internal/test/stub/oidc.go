@@ -0,0 +1,164 @@
+package test_stub
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// OIDCのIDプロバイダのユーザー
+type OIDCUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// 発行した認可コード
+type oidcCode struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	user        OIDCUser
+}
+
+// OIDCのIDプロバイダのスタブサーバー
+// ディスカバリ・認可(ログイン画面なしでUserとして即座に認可する)・トークン(PKCE S256のみ)・JWKSに応答する
+type OIDCProvider struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	// IDトークンのクレームを書き換える(不正なトークンのテスト用)
+	ModifyClaims func(claims jwt.MapClaims)
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	user  OIDCUser
+	codes map[string]oidcCode
+}
+
+// スタブサーバーの起動(終了時はCloseを呼ぶ)
+func NewOIDCProvider(clientID, clientSecret string) *OIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	s := &OIDCProvider{ClientID: clientID, ClientSecret: clientSecret, key: key, codes: map[string]oidcCode{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 s.URL,
+			"authorization_endpoint": s.URL + "/authorize",
+			"token_endpoint":         s.URL + "/token",
+			"jwks_uri":               s.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("GET /authorize", s.authorize)
+	mux.HandleFunc("POST /token", s.token)
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "test-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}}})
+	})
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// 認可するユーザーを設定する
+func (s *OIDCProvider) SetUser(user OIDCUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.user = user
+}
+
+// 認可エンドポイント
+func (s *OIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.ClientID || q.Get("response_type") != "code" || q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+		http.Error(w, "invalid_request", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	s.mu.Lock()
+	s.codes[code] = oidcCode{challenge: q.Get("code_challenge"), nonce: q.Get("nonce"), redirectURI: q.Get("redirect_uri"), user: s.user}
+	s.mu.Unlock()
+
+	redirect, _ := url.Parse(q.Get("redirect_uri"))
+	rq := redirect.Query()
+	rq.Set("code", code)
+	rq.Set("state", q.Get("state"))
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+// トークンエンドポイント
+func (s *OIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != s.ClientID || secret != s.ClientSecret {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+		return
+	}
+	r.ParseForm()
+
+	// 認可コードは1回だけ使える
+	s.mu.Lock()
+	c, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" || r.PostForm.Get("redirect_uri") != c.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != c.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	claims := jwt.MapClaims{
+		"iss":            s.URL,
+		"sub":            c.user.Subject,
+		"aud":            s.ClientID,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          c.nonce,
+		"email":          c.user.Email,
+		"email_verified": c.user.EmailVerified,
+		"name":           c.user.Name,
+	}
+	if s.ModifyClaims != nil {
+		s.ModifyClaims(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"access_token": randomString(), "token_type": "Bearer", "id_token": idToken})
+}
+
+// ランダムな文字列
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,85 @@
+package test_todo_repository
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの繰り返しのTodoリポジトリ作成
+type MockTodoRecurrenceRepository struct {
+	mock.Mock
+}
+
+// CreateRecurrenceのモック
+func (m *MockTodoRecurrenceRepository) CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, error) {
+	args := m.Called(scope, recurrence)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Recurrence{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Recurrence), args.Error(1)
+}
+
+// GetRecurrenceByIdのモック
+func (m *MockTodoRecurrenceRepository) GetRecurrenceById(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error) {
+	args := m.Called(scope, id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Recurrence{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Recurrence), args.Error(1)
+}
+
+// GetPendingRecurrencesのモック
+func (m *MockTodoRecurrenceRepository) GetPendingRecurrences(until time.Time, after domain_todo.Recurrence, limit int) ([]domain_todo.Recurrence, error) {
+	args := m.Called(until, after, limit)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Recurrence), args.Error(1)
+}
+
+// MaterializeOccurrencesのモック
+func (m *MockTodoRecurrenceRepository) MaterializeOccurrences(recurrence domain_todo.Recurrence, occurrences []time.Time, until time.Time) ([]domain_todo.Todo, error) {
+	args := m.Called(recurrence, occurrences, until)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
+
+// UpdateOccurrenceのモック
+func (m *MockTodoRecurrenceRepository) UpdateOccurrence(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	args := m.Called(scope, todo)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Todo{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Todo), args.Error(1)
+}
+
+// SplitRecurrenceのモック
+func (m *MockTodoRecurrenceRepository) SplitRecurrence(scope domain_todo.Scope, todoId string, next domain_todo.Recurrence) (domain_todo.Recurrence, domain_todo.Todo, error) {
+	args := m.Called(scope, todoId, next)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Recurrence{}, domain_todo.Todo{}, args.Error(2)
+	}
+
+	return args.Get(0).(domain_todo.Recurrence), args.Get(1).(domain_todo.Todo), args.Error(2)
+}
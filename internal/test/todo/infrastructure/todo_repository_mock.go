@@ -12,8 +12,8 @@ type MockTodoRepository struct {
 }
 
 // GetAllTodosのモック
-func (m *MockTodoRepository) GetAllTodos() ([]domain_todo.Todo, error) {
-	args := m.Called()
+func (m *MockTodoRepository) GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error) {
+	args := m.Called(scope)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -24,8 +24,8 @@ func (m *MockTodoRepository) GetAllTodos() ([]domain_todo.Todo, error) {
 }
 
 // GetTodoByIdのモック
-func (m *MockTodoRepository) GetTodoById(id string) (domain_todo.Todo, error) {
-	args := m.Called(id)
+func (m *MockTodoRepository) GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error) {
+	args := m.Called(scope, id)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -36,8 +36,8 @@ func (m *MockTodoRepository) GetTodoById(id string) (domain_todo.Todo, error) {
 }
 
 // GetTodoByUserIdのモック
-func (m *MockTodoRepository) GetTodoByUserId(userId string) ([]domain_todo.Todo, error) {
-	args := m.Called(userId)
+func (m *MockTodoRepository) GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, userId)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -48,8 +48,8 @@ func (m *MockTodoRepository) GetTodoByUserId(userId string) ([]domain_todo.Todo,
 }
 
 // CreateTodoのモック
-func (m *MockTodoRepository) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
-	args := m.Called(todo)
+func (m *MockTodoRepository) CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	args := m.Called(scope, todo)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -60,8 +60,8 @@ func (m *MockTodoRepository) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo
 }
 
 // UpdateTodoのモック
-func (m *MockTodoRepository) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
-	args := m.Called(todo)
+func (m *MockTodoRepository) UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	args := m.Called(scope, todo)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -72,8 +72,8 @@ func (m *MockTodoRepository) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo
 }
 
 // DeleteTodoのモック
-func (m *MockTodoRepository) DeleteTodo(id string) error {
-	args := m.Called(id)
+func (m *MockTodoRepository) DeleteTodo(scope domain_todo.Scope, id string) error {
+	args := m.Called(scope, id)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -82,3 +82,51 @@ func (m *MockTodoRepository) DeleteTodo(id string) error {
 
 	return args.Error(0)
 }
+
+// GetTodoSubtreeのモック
+func (m *MockTodoRepository) GetTodoSubtree(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
+
+// GetTodoAncestorsのモック
+func (m *MockTodoRepository) GetTodoAncestors(scope domain_todo.Scope, id string) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
+
+// MoveTodoのモック
+func (m *MockTodoRepository) MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error) {
+	args := m.Called(scope, id, move)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Todo{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Todo), args.Error(1)
+}
+
+// SetTodosCompletedのモック
+func (m *MockTodoRepository) SetTodosCompleted(scope domain_todo.Scope, ids []string, completed bool) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, ids, completed)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
@@ -31,7 +31,7 @@ func TestCreateTodo(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (時間の影響を受けないように比較)
-	mockUsecase.On("CreateTodo", mock.MatchedBy(func(t domain_todo.Todo) bool {
+	mockUsecase.On("CreateTodo", domain_todo.Scope{}, mock.MatchedBy(func(t domain_todo.Todo) bool {
 		return t.Description == todo.Description && t.UserId == todo.UserId
 	})).Return(todo, nil)
 
@@ -81,7 +81,7 @@ func TestCreateTodoErrorDescriptionEmpty(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("CreateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("description is empty"))
+	mockUsecase.On("CreateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("description is empty"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -124,7 +124,7 @@ func TestCreateTodoErrorUserIdEmpty(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("CreateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("user_id is empty"))
+	mockUsecase.On("CreateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("user_id is empty"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -167,7 +167,7 @@ func TestCreateTodoErrorUsecase(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("CreateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("error"))
+	mockUsecase.On("CreateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
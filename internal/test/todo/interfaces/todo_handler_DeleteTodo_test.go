@@ -21,7 +21,7 @@ func TestDeleteTodo(t *testing.T) {
 	id := "1"
 
 	// モックの挙動を設定 (時間の影響を受けないように比較)
-	mockUsecase.On("DeleteTodo", id).Return(nil)
+	mockUsecase.On("DeleteTodo", domain_todo.Scope{}, id).Return(nil)
 
 	// リクエストの作成
 	req := httptest.NewRequest("DELETE", "/api/todo/"+id, nil)
@@ -59,7 +59,7 @@ func TestDeleteTodoErrorIdEmpty(t *testing.T) {
 	id := ""
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("DeleteTodo", id).Return(errors.New("id is empty"))
+	mockUsecase.On("DeleteTodo", domain_todo.Scope{}, id).Return(errors.New("id is empty"))
 
 	// リクエストの作成
 	req := httptest.NewRequest("DELETE", "/api/todo/"+id, nil)
@@ -98,7 +98,7 @@ func TestDeleteTodoErrorUsecase(t *testing.T) {
 	id := "1"
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("DeleteTodo", id).Return(errors.New("error"))
+	mockUsecase.On("DeleteTodo", domain_todo.Scope{}, id).Return(errors.New("error"))
 
 	// リクエストの作成
 	req := httptest.NewRequest("DELETE", "/api/todo/"+id, nil)
@@ -21,12 +21,12 @@ func TestGetAllUsers(t *testing.T) {
 	// テストデータ
 	fixedTime := "2021-01-01T00:00:00Z"
 	todos := []domain_todo.Todo{
-		{ID: "1", Description: "alice@example.com", Completed: false, UserId: "1", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
-		{ID: "2", Description: "bob@example.com", Completed: false, UserId: "2", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "1", Description: "alice@example.com", Completed: false, UserId: "1", WorkspaceID: "w1", Position: "V", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: "2", Description: "bob@example.com", Completed: false, UserId: "2", WorkspaceID: "w1", Position: "W", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
 	}
 
 	// モックの挙動を設定
-	mockUsecase.On("GetAllTodos").Return(todos, nil)
+	mockUsecase.On("GetAllTodos", domain_todo.Scope{}).Return(todos, nil)
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
@@ -37,8 +37,8 @@ func TestGetAllUsers(t *testing.T) {
 	assert.Equal(t, http.StatusOK, response.Code)
 	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
 	assert.JSONEq(t, `[
-		{"id": "1", "description": "alice@example.com", "completed": false, "user_id": "1", "created_at": "`+fixedTime+`", "updated_at": "`+fixedTime+`"},
-		{"id": "2", "description": "bob@example.com", "completed": false, "user_id": "2", "created_at": "`+fixedTime+`", "updated_at": "`+fixedTime+`"}
+		{"id": "1", "description": "alice@example.com", "completed": false, "user_id": "1", "workspace_id": "w1", "parent_id": "", "position": "V", "created_at": "`+fixedTime+`", "updated_at": "`+fixedTime+`"},
+		{"id": "2", "description": "bob@example.com", "completed": false, "user_id": "2", "workspace_id": "w1", "parent_id": "", "position": "W", "created_at": "`+fixedTime+`", "updated_at": "`+fixedTime+`"}
 	]`, response.Body.String())
 	// モックのメソッドが期待通りに呼ばれたことを確認
 	mockUsecase.AssertExpectations(t)
@@ -53,7 +53,7 @@ func TestGetAllTodosEmpty(t *testing.T) {
 	todos := []domain_todo.Todo{}
 
 	// モックの挙動を設定
-	mockUsecase.On("GetAllTodos").Return(todos, nil)
+	mockUsecase.On("GetAllTodos", domain_todo.Scope{}).Return(todos, nil)
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
@@ -74,7 +74,7 @@ func TestGetAllTodosError(t *testing.T) {
 	mockUsecase.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockUsecase.On("GetAllTodos").Return(nil, errors.New("error"))
+	mockUsecase.On("GetAllTodos", domain_todo.Scope{}).Return(nil, errors.New("error"))
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
@@ -30,7 +30,7 @@ func TestGetTodoById(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoById", id).Return(todo, nil)
+	mockUsecase.On("GetTodoById", domain_todo.Scope{}, id).Return(todo, nil)
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
@@ -73,7 +73,7 @@ func TestGetTodoByIdErrorIdEmpty(t *testing.T) {
 	id := ""
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoById", id).Return(domain_todo.Todo{}, errors.New("id is empty"))
+	mockUsecase.On("GetTodoById", domain_todo.Scope{}, id).Return(domain_todo.Todo{}, errors.New("id is empty"))
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
@@ -109,7 +109,7 @@ func TestGetTodoByIdError(t *testing.T) {
 	id := "1"
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoById", id).Return(domain_todo.Todo{}, errors.New("error"))
+	mockUsecase.On("GetTodoById", domain_todo.Scope{}, id).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
@@ -39,7 +39,7 @@ func TestGetTodoByUserId(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoByUserId", "1").Return(todos, nil)
+	mockUsecase.On("GetTodoByUserId", domain_todo.Scope{UserID: "1"}, "1").Return(todos, nil)
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
@@ -84,7 +84,7 @@ func TestGetTodoByUserIdErrorUserIdEmpty(t *testing.T) {
 	mockUsecase.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoByUserId", "").Return(nil, errors.New("user_id is empty"))
+	mockUsecase.On("GetTodoByUserId", domain_todo.Scope{}, "").Return(nil, errors.New("user_id is empty"))
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
@@ -123,7 +123,7 @@ func TestGetTodoByUserIdError(t *testing.T) {
 	userId := "1"
 
 	// モックの挙動を設定
-	mockUsecase.On("GetTodoByUserId", userId).Return(nil, errors.New("error"))
+	mockUsecase.On("GetTodoByUserId", domain_todo.Scope{UserID: userId}, userId).Return(nil, errors.New("error"))
 
 	// ハンドラのメソッドを呼び出し
 	e := echo.New()
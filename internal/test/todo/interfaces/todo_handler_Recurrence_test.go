@@ -0,0 +1,189 @@
+package test_todo_handler
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// CreateRecurrenceのテスト(正常系)
+func TestCreateRecurrence(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	start := time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+	created := domain_todo.Recurrence{ID: "r1", WorkspaceID: "w1", Description: "日報", RRule: "FREQ=DAILY", Timezone: "Asia/Tokyo", StartAt: start}
+	todos := []domain_todo.Todo{{ID: "t1", RecurrenceID: "r1", OccurrenceAt: &start}}
+
+	// モックの挙動を設定
+	mockRecurrenceUsecase.On("CreateRecurrence", domain_todo.Scope{}, mock.MatchedBy(func(r domain_todo.Recurrence) bool {
+		return r.Description == "日報" && r.RRule == "FREQ=DAILY" && r.Timezone == "Asia/Tokyo" && r.StartAt.Equal(start)
+	})).Return(created, todos, nil)
+
+	// ハンドラのメソッドを呼び出し
+	body := `{"description": "日報", "rrule": "FREQ=DAILY", "timezone": "Asia/Tokyo", "start_at": "2100-01-01T09:00:00+09:00"}`
+	c, rec := subtaskContext("POST", "/api/todo/recurrences", body, "")
+	recurrenceHandler.CreateRecurrence(c)
+
+	// 検証
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"recurrence":{"id":"r1"`)
+	assert.Contains(t, rec.Body.String(), `"occurrence_at":"2100-01-01T00:00:00Z"`)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceUsecase.AssertExpectations(t)
+}
+
+// CreateRecurrenceのテスト(異常系 - 入力エラー)
+func TestCreateRecurrenceValidation(t *testing.T) {
+	// モックの挙動をリセット(ユースケースは呼ばれない)
+	mockRecurrenceUsecase.ExpectedCalls = nil
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("POST", "/api/todo/recurrences", `{"rrule": "FREQ=HOURLY", "timezone": "Mars/Olympus"}`, "")
+	recurrenceHandler.CreateRecurrence(c)
+
+	// 検証
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	for _, field := range []string{`"description"`, `"start_at"`, `"rrule"`, `"timezone"`} {
+		assert.Contains(t, rec.Body.String(), field)
+	}
+}
+
+// CreateRecurrenceのテスト(異常系 - ユースケースのエラー)
+func TestCreateRecurrenceError(t *testing.T) {
+	cases := []struct {
+		err    string
+		status int
+	}{
+		{"workspace not found", http.StatusNotFound},
+		{"permission denied", http.StatusForbidden},
+		{"error", http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		// モックの挙動をリセット
+		mockRecurrenceUsecase.ExpectedCalls = nil
+
+		// モックの挙動を設定
+		mockRecurrenceUsecase.On("CreateRecurrence", domain_todo.Scope{}, mock.Anything).Return(nil, nil, errors.New(tc.err))
+
+		// ハンドラのメソッドを呼び出し
+		body := `{"description": "日報", "rrule": "FREQ=DAILY", "timezone": "UTC", "start_at": "2100-01-01T09:00:00Z"}`
+		c, rec := subtaskContext("POST", "/api/todo/recurrences", body, "")
+		recurrenceHandler.CreateRecurrence(c)
+
+		// 検証
+		assert.Equal(t, tc.status, rec.Code, tc.err)
+		assert.JSONEq(t, `{"message": "`+tc.err+`"}`, rec.Body.String())
+
+		// モックのメソッドが期待通りに呼ばれたことを確認
+		mockRecurrenceUsecase.AssertExpectations(t)
+	}
+}
+
+// GetRecurrenceのテスト(異常系 - 参照できない)
+func TestGetRecurrenceNotFound(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRecurrenceUsecase.On("GetRecurrence", domain_todo.Scope{}, "x").Return(nil, errors.New("recurrence not found"))
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("GET", "/api/todo/recurrences/x", "", "x")
+	recurrenceHandler.GetRecurrence(c)
+
+	// 検証
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"message": "recurrence not found"}`, rec.Body.String())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceUsecase.AssertExpectations(t)
+}
+
+// EditOccurrenceのテスト(正常系)
+func TestEditOccurrence(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	edit := domain_todo.OccurrenceEdit{Scope: domain_todo.OccurrenceScopeFuture, Description: "定例(隔週)", RRule: "FREQ=WEEKLY;INTERVAL=2"}
+	mockRecurrenceUsecase.On("EditOccurrence", domain_todo.Scope{}, "t1", edit).
+		Return([]domain_todo.Todo{{ID: "t1", RecurrenceID: "r2"}, {ID: "t2", RecurrenceID: "r2"}}, nil)
+
+	// ハンドラのメソッドを呼び出し
+	body := `{"scope": "future", "description": "定例(隔週)", "rrule": "FREQ=WEEKLY;INTERVAL=2"}`
+	c, rec := subtaskContext("PUT", "/api/todo/t1/occurrence", body, "t1")
+	recurrenceHandler.EditOccurrence(c)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"t2"`)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceUsecase.AssertExpectations(t)
+}
+
+// EditOccurrenceのテスト(異常系 - 入力エラー)
+func TestEditOccurrenceValidation(t *testing.T) {
+	// モックの挙動をリセット(ユースケースは呼ばれない)
+	mockRecurrenceUsecase.ExpectedCalls = nil
+
+	cases := []struct {
+		body  string
+		field string
+	}{
+		{`{"scope": "all"}`, `"scope"`},
+		{`{"scope": "this", "rrule": "FREQ=DAILY"}`, `"rrule"`},
+		{`{"scope": "this", "timezone": "UTC"}`, `"timezone"`},
+		{`{"scope": "future", "rrule": "FREQ=DAILY;COUNT=2;UNTIL=21000101"}`, `"rrule"`},
+		{`{"scope": "future", "timezone": "Local"}`, `"timezone"`},
+	}
+	for _, tc := range cases {
+		// ハンドラのメソッドを呼び出し
+		c, rec := subtaskContext("PUT", "/api/todo/t1/occurrence", tc.body, "t1")
+		recurrenceHandler.EditOccurrence(c)
+
+		// 検証
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code, tc.body)
+		assert.Contains(t, rec.Body.String(), tc.field, tc.body)
+	}
+}
+
+// EditOccurrenceのテスト(異常系 - ユースケースのエラー)
+func TestEditOccurrenceError(t *testing.T) {
+	cases := []struct {
+		err    string
+		status int
+	}{
+		{"todo not found", http.StatusNotFound},
+		{"recurrence not found", http.StatusNotFound},
+		{"todo is not recurring", http.StatusBadRequest},
+		{"permission denied", http.StatusForbidden},
+		{"error", http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		// モックの挙動をリセット
+		mockRecurrenceUsecase.ExpectedCalls = nil
+
+		// モックの挙動を設定
+		mockRecurrenceUsecase.On("EditOccurrence", domain_todo.Scope{}, "t1", mock.Anything).Return(nil, errors.New(tc.err))
+
+		// ハンドラのメソッドを呼び出し
+		c, rec := subtaskContext("PUT", "/api/todo/t1/occurrence", `{"scope": "this", "description": "日報"}`, "t1")
+		recurrenceHandler.EditOccurrence(c)
+
+		// 検証
+		assert.Equal(t, tc.status, rec.Code, tc.err)
+		assert.JSONEq(t, `{"message": "`+tc.err+`"}`, rec.Body.String())
+
+		// モックのメソッドが期待通りに呼ばれたことを確認
+		mockRecurrenceUsecase.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,179 @@
+package test_todo_handler
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// パスパラメータidを設定したコンテキストを作成
+func subtaskContext(method string, path string, body string, id string) (echo.Context, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+	return c, rec
+}
+
+// GetTodoTreeのテスト(正常系)
+func TestGetTodoTree(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	tree, _ := domain_todo.BuildTodoTree([]domain_todo.Todo{
+		{ID: "p1", WorkspaceID: "w1"},
+		{ID: "c1", WorkspaceID: "w1", ParentID: "p1", Completed: true},
+	}, "p1")
+
+	// モックの挙動を設定
+	mockUsecase.On("GetTodoTree", domain_todo.Scope{}, "p1").Return(tree, nil)
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("GET", "/api/todo/p1/tree", "", "p1")
+	handler.GetTodoTree(c)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"id": "p1", "description": "", "completed": false, "user_id": "", "workspace_id": "w1", "parent_id": "", "position": "",
+		"created_at": "0001-01-01T00:00:00Z", "updated_at": "0001-01-01T00:00:00Z", "progress": 100,
+		"children": [{
+			"id": "c1", "description": "", "completed": true, "user_id": "", "workspace_id": "w1", "parent_id": "p1", "position": "",
+			"created_at": "0001-01-01T00:00:00Z", "updated_at": "0001-01-01T00:00:00Z", "progress": 100, "children": []
+		}]
+	}`, rec.Body.String())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// GetTodoTreeのテスト(異常系 - 参照できない)
+func TestGetTodoTreeNotFound(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("GetTodoTree", domain_todo.Scope{}, "x").Return(nil, errors.New("todo not found"))
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("GET", "/api/todo/x/tree", "", "x")
+	handler.GetTodoTree(c)
+
+	// 検証
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.JSONEq(t, `{"message": "todo not found"}`, rec.Body.String())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(正常系)
+func TestMoveTodo(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	move := domain_todo.TodoMove{AfterID: "c0", BeforeID: "c2"}
+	moved := domain_todo.Todo{ID: "c1", ParentID: "p2", Position: "VV"}
+
+	// モックの挙動を設定
+	mockUsecase.On("MoveTodo", domain_todo.Scope{}, "c1", move).Return(moved, nil)
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("POST", "/api/todo/c1/move", `{"after_id": "c0", "before_id": "c2"}`, "c1")
+	handler.MoveTodo(c)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"parent_id":"p2","position":"VV"`)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(異常系 - エラーとステータスコード)
+func TestMoveTodoErrors(t *testing.T) {
+	cases := []struct {
+		err    string
+		status int
+	}{
+		{"cycle detected", http.StatusConflict},
+		{"max depth exceeded", http.StatusConflict},
+		{"neighbours are not adjacent", http.StatusConflict},
+		{"parent not found", http.StatusNotFound},
+		{"neighbour not found", http.StatusNotFound},
+		{"permission denied", http.StatusForbidden},
+		{"error", http.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		// モックの挙動をリセット
+		mockUsecase.ExpectedCalls = nil
+
+		// モックの挙動を設定
+		mockUsecase.On("MoveTodo", domain_todo.Scope{}, "c1", domain_todo.TodoMove{ParentID: "g1"}).Return(nil, errors.New(tc.err))
+
+		// ハンドラのメソッドを呼び出し
+		c, rec := subtaskContext("POST", "/api/todo/c1/move", `{"parent_id": "g1"}`, "c1")
+		handler.MoveTodo(c)
+
+		// 検証
+		assert.Equal(t, tc.status, rec.Code, tc.err)
+		assert.JSONEq(t, `{"message": "`+tc.err+`"}`, rec.Body.String())
+
+		// モックのメソッドが期待通りに呼ばれたことを確認
+		mockUsecase.AssertExpectations(t)
+	}
+}
+
+// CompleteTodoのテスト(正常系)
+func TestCompleteTodo(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	completion := domain_todo.TodoCompletion{Completed: true, Descendants: true, Ancestors: true}
+	todos := []domain_todo.Todo{{ID: "c1", Completed: true}, {ID: "g1", ParentID: "c1", Completed: true}}
+
+	// モックの挙動を設定
+	mockUsecase.On("CompleteTodo", domain_todo.Scope{}, "c1", completion).Return(todos, nil)
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("PUT", "/api/todo/c1/complete", `{"completed": true, "descendants": true, "ancestors": true}`, "c1")
+	handler.CompleteTodo(c)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), `"completed":true`))
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(異常系 - 編集できない)
+func TestCompleteTodoPermissionDenied(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("CompleteTodo", domain_todo.Scope{}, "c1", domain_todo.TodoCompletion{}).Return(nil, errors.New("permission denied"))
+
+	// ハンドラのメソッドを呼び出し
+	c, rec := subtaskContext("PUT", "/api/todo/c1/complete", `{"completed": false}`, "c1")
+	handler.CompleteTodo(c)
+
+	// 検証
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.JSONEq(t, `{"message": "permission denied"}`, rec.Body.String())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
@@ -31,7 +31,7 @@ func TestUpdateTodo(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (時間の影響を受けないように比較)
-	mockUsecase.On("UpdateTodo", mock.MatchedBy(func(t domain_todo.Todo) bool {
+	mockUsecase.On("UpdateTodo", domain_todo.Scope{}, mock.MatchedBy(func(t domain_todo.Todo) bool {
 		return t.ID == todo.ID && t.Description == todo.Description && t.UserId == todo.UserId
 	})).Return(todo, nil)
 
@@ -85,7 +85,7 @@ func TestUpdateTodoErrorIdEmpty(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("UpdateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("id is empty"))
+	mockUsecase.On("UpdateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("id is empty"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -132,7 +132,7 @@ func TestUpdateTodoErrorDescriptionEmpty(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("UpdateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("description is empty"))
+	mockUsecase.On("UpdateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("description is empty"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -179,7 +179,7 @@ func TestUpdateTodoErrorUserIdEmpty(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("UpdateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("user_id is empty"))
+	mockUsecase.On("UpdateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("user_id is empty"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -226,7 +226,7 @@ func TestUpdateTodoErrorUsecase(t *testing.T) {
 	}
 
 	// モックの挙動を設定 (エラーを返す)
-	mockUsecase.On("UpdateTodo", mock.Anything).Return(domain_todo.Todo{}, errors.New("error"))
+	mockUsecase.On("UpdateTodo", domain_todo.Scope{}, mock.Anything).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// リクエストの作成
 	body, _ := json.Marshal(todo)
@@ -0,0 +1,132 @@
+package test_todo_handler
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 認証ミドルウェアが設定したユーザー・ワークスペースをスコープとして渡すことのテスト
+func TestTodoScopeFromContext(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	scope := domain_todo.Scope{UserID: "1", WorkspaceID: "w1"}
+	mockUsecase.On("GetAllTodos", scope).Return([]domain_todo.Todo{{ID: "1", WorkspaceID: "w1"}}, nil)
+
+	// ハンドラのメソッドを呼び出し
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo", nil)
+	c := echo.New().NewContext(req, res)
+	c.Set("userId", "1")
+	c.Set("workspaceId", "w1")
+	handler.GetAllTodos(c)
+
+	// 検証
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.JSONEq(t, `[{"id": "1", "description": "", "completed": false, "user_id": "", "workspace_id": "w1", "parent_id": "", "position": "", "created_at": "0001-01-01T00:00:00Z", "updated_at": "0001-01-01T00:00:00Z"}]`, res.Body.String())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// 所属していないワークスペースのTodoは404を返すことのテスト
+func TestGetTodoByIdOtherWorkspace(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("GetTodoById", domain_todo.Scope{UserID: "2"}, "1").Return(domain_todo.Todo{}, errors.New("todo not found"))
+
+	// ハンドラのメソッドを呼び出し
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo/1", nil)
+	c := echo.New().NewContext(req, res)
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+	c.Set("userId", "2")
+	handler.GetTodoById(c)
+
+	// 検証
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Contains(t, res.Body.String(), "todo not found")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// 閲覧者が作成・更新・削除すると403を返すことのテスト
+func TestTodoPermissionDenied(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	scope := domain_todo.Scope{UserID: "3", WorkspaceID: "w1"}
+	mockUsecase.On("CreateTodo", scope, mock.Anything).Return(domain_todo.Todo{}, errors.New("permission denied"))
+	mockUsecase.On("UpdateTodo", scope, mock.Anything).Return(domain_todo.Todo{}, errors.New("permission denied"))
+	mockUsecase.On("DeleteTodo", scope, "1").Return(errors.New("permission denied"))
+
+	body, _ := json.Marshal(domain_todo.Todo{ID: "1", Description: "Todo 1", UserId: "3"})
+	requests := []struct {
+		method string
+		call   func(c echo.Context) error
+	}{
+		{"POST", handler.CreateTodo},
+		{"PUT", handler.UpdateTodo},
+		{"DELETE", handler.DeleteTodo},
+	}
+	for _, r := range requests {
+		res := httptest.NewRecorder()
+		req := httptest.NewRequest(r.method, "/api/todo/1", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		c := echo.New().NewContext(req, res)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set("userId", "3")
+		c.Set("workspaceId", "w1")
+		r.call(c)
+
+		// 検証
+		assert.Equal(t, http.StatusForbidden, res.Code, r.method)
+		assert.Contains(t, res.Body.String(), "permission denied", r.method)
+	}
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
+
+// 所属していないワークスペースに作成すると404を返すことのテスト
+func TestCreateTodoWorkspaceNotFound(t *testing.T) {
+	// モックの挙動をリセット
+	mockUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockUsecase.On("CreateTodo", domain_todo.Scope{UserID: "1"}, mock.MatchedBy(func(t domain_todo.Todo) bool {
+		return t.WorkspaceID == "w9"
+	})).Return(domain_todo.Todo{}, errors.New("workspace not found"))
+
+	// ハンドラのメソッドを呼び出し
+	body, _ := json.Marshal(domain_todo.Todo{Description: "Todo 1", UserId: "1", WorkspaceID: "w9"})
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/api/todo", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	c := echo.New().NewContext(req, res)
+	c.Set("userId", "1")
+	handler.CreateTodo(c)
+
+	// 検証
+	assert.Equal(t, http.StatusNotFound, res.Code)
+	assert.Contains(t, res.Body.String(), "workspace not found")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockUsecase.AssertExpectations(t)
+}
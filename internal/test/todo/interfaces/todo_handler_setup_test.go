@@ -11,9 +11,14 @@ import (
 
 // テストの変数(グローバル用)
 var (
-	logger      *pkg_logger.AppLogger
-	handler     *interfaces_todo.TodoHandler
-	mockUsecase *test_todo_usecase.MockTodoUsecase
+	logger            *pkg_logger.AppLogger
+	handler           *interfaces_todo.TodoHandler
+	mockUsecase       *test_todo_usecase.MockTodoUsecase
+	searchHandler     *interfaces_todo.TodoSearchHandler
+	mockSearchUsecase *test_todo_usecase.MockTodoSearchUsecase
+	// 繰り返しのTodo
+	recurrenceHandler     *interfaces_todo.TodoRecurrenceHandler
+	mockRecurrenceUsecase *test_todo_usecase.MockTodoRecurrenceUsecase
 )
 
 // テストのメイン関数
@@ -29,6 +34,10 @@ func TestMain(m *testing.M) {
 	// モック
 	mockUsecase = new(test_todo_usecase.MockTodoUsecase)
 	handler = interfaces_todo.NewTodoHandler(logger, mockUsecase)
+	mockSearchUsecase = new(test_todo_usecase.MockTodoSearchUsecase)
+	searchHandler = interfaces_todo.NewTodoSearchHandler(logger, mockSearchUsecase)
+	mockRecurrenceUsecase = new(test_todo_usecase.MockTodoRecurrenceUsecase)
+	recurrenceHandler = interfaces_todo.NewTodoRecurrenceHandler(logger, mockRecurrenceUsecase)
 
 	// テスト実行
 	code := m.Run()
@@ -0,0 +1,133 @@
+package test_todo_handler
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// SearchTodosのテスト(正常系)
+func TestSearchTodos(t *testing.T) {
+	// モックの挙動をリセット
+	mockSearchUsecase.ExpectedCalls = nil
+
+	// テストデータ
+	results := []domain_todo.TodoSearchResult{
+		{
+			Todo:      domain_todo.Todo{ID: "1", Description: "Buy milk", UserId: "1"},
+			Rank:      0.5,
+			Highlight: "Buy <b>milk</b>",
+		},
+	}
+
+	// モックの挙動を設定
+	mockSearchUsecase.On("SearchTodos", domain_todo.Scope{UserID: "1"}, "milk", 5).Return(results, nil)
+
+	// ハンドラのメソッドを呼び出し
+	e := echo.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo/search?q=milk&limit=5", nil)
+	c := e.NewContext(req, res)
+
+	// コンテキストの設定
+	c.Set("userId", "1")
+
+	// ハンドラのメソッドを呼び出し
+	searchHandler.SearchTodos(c)
+
+	// JSONレスポンスのデコード
+	var resResults []domain_todo.TodoSearchResult
+	err := json.Unmarshal(res.Body.Bytes(), &resResults)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// 検証
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, results[0].Todo.ID, resResults[0].Todo.ID)
+	assert.Equal(t, results[0].Rank, resResults[0].Rank)
+	assert.Equal(t, results[0].Highlight, resResults[0].Highlight)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockSearchUsecase.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(異常系 - limitが不正)
+func TestSearchTodosErrorInvalidLimit(t *testing.T) {
+	// モックの挙動をリセット
+	mockSearchUsecase.ExpectedCalls = nil
+
+	// ハンドラのメソッドを呼び出し
+	e := echo.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo/search?q=milk&limit=abc", nil)
+	c := e.NewContext(req, res)
+	c.Set("userId", "1")
+	searchHandler.SearchTodos(c)
+
+	// 検証
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+
+	// モックのメソッドが呼ばれていないことを確認
+	mockSearchUsecase.AssertNotCalled(t, "SearchTodos")
+}
+
+// SearchTodosのテスト(異常系 - クエリが空)
+func TestSearchTodosErrorQueryEmpty(t *testing.T) {
+	// モックの挙動をリセット
+	mockSearchUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockSearchUsecase.On("SearchTodos", domain_todo.Scope{UserID: "1"}, "", 0).Return(nil, errors.New("query is empty"))
+
+	// ハンドラのメソッドを呼び出し
+	e := echo.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo/search", nil)
+	c := e.NewContext(req, res)
+	c.Set("userId", "1")
+	searchHandler.SearchTodos(c)
+
+	// JSONレスポンスのデコード
+	var errRes map[string]interface{}
+	err := json.Unmarshal(res.Body.Bytes(), &errRes)
+	if err != nil {
+		t.FailNow()
+	}
+
+	// 検証
+	assert.Equal(t, http.StatusBadRequest, res.Code)
+	assert.Contains(t, errRes["message"], "query is empty")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockSearchUsecase.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(異常系 - Usecase異常)
+func TestSearchTodosError(t *testing.T) {
+	// モックの挙動をリセット
+	mockSearchUsecase.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockSearchUsecase.On("SearchTodos", domain_todo.Scope{UserID: "1"}, "milk", 0).Return(nil, errors.New("error"))
+
+	// ハンドラのメソッドを呼び出し
+	e := echo.New()
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/api/todo/search?q=milk", nil)
+	c := e.NewContext(req, res)
+	c.Set("userId", "1")
+	searchHandler.SearchTodos(c)
+
+	// 検証
+	assert.Equal(t, http.StatusInternalServerError, res.Code)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockSearchUsecase.AssertExpectations(t)
+}
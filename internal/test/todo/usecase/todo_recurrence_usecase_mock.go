@@ -0,0 +1,55 @@
+package test_todo_usecase
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックの繰り返しのTodoユースケース作成
+type MockTodoRecurrenceUsecase struct {
+	mock.Mock
+}
+
+// CreateRecurrenceのモック
+func (m *MockTodoRecurrenceUsecase) CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, []domain_todo.Todo, error) {
+	args := m.Called(scope, recurrence)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Recurrence{}, nil, args.Error(2)
+	}
+
+	return args.Get(0).(domain_todo.Recurrence), args.Get(1).([]domain_todo.Todo), args.Error(2)
+}
+
+// GetRecurrenceのモック
+func (m *MockTodoRecurrenceUsecase) GetRecurrence(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error) {
+	args := m.Called(scope, id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Recurrence{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Recurrence), args.Error(1)
+}
+
+// EditOccurrenceのモック
+func (m *MockTodoRecurrenceUsecase) EditOccurrence(scope domain_todo.Scope, id string, edit domain_todo.OccurrenceEdit) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, id, edit)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
+
+// MaterializeDueのモック
+func (m *MockTodoRecurrenceUsecase) MaterializeDue(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
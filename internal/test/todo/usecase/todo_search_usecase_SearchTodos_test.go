@@ -0,0 +1,196 @@
+package test_todo_usecase
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// 検索用のテストデータ
+func searchTestTodos() []domain_todo.Todo {
+	now := time.Now()
+	return []domain_todo.Todo{
+		{ID: "1", Description: "Buy milk and bread", UserId: "1", UpdatedAt: now},
+		{ID: "2", Description: "Write the weekly report", UserId: "1", UpdatedAt: now.Add(-time.Hour)},
+		{ID: "3", Description: "Report bug in milk-tracker", UserId: "1", UpdatedAt: now.Add(-2 * time.Hour)},
+	}
+}
+
+// SearchTodosのテスト(単語検索)
+func TestSearchTodosWord(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return(searchTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, "MILK", 0)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "Buy <b>milk</b> and bread", results[0].Highlight)
+	assert.Equal(t, "Report bug in <b>milk</b>-tracker", results[1].Highlight)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(説明文のマークアップはエスケープする)
+func TestSearchTodosEscape(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return([]domain_todo.Todo{
+		{ID: "1", Description: `<script>alert("milk")</script> & milk` + domain_todo.HighlightStartMark, UserId: "1"},
+	}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, "milk", 0)
+
+	// 検証(ハイライトのタグ以外はエスケープし、入力された目印はタグにしない)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, `&lt;script&gt;alert(&#34;<b>milk</b>&#34;)&lt;/script&gt; &amp; <b>milk</b>`, results[0].Highlight)
+
+	// 目印で囲んだ説明文(Postgresのts_headlineの結果)も同じようにエスケープする
+	marked := "<img src=x onerror=alert(1)> " + domain_todo.HighlightStartMark + "milk" + domain_todo.HighlightStopMark
+	assert.Equal(t, "&lt;img src=x onerror=alert(1)&gt; <b>milk</b>", domain_todo.RenderHighlight(marked))
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(前方一致)
+func TestSearchTodosPrefix(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return(searchTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, "rep*", 0)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	// 単語数の少ないTodoほど関連度が高い
+	assert.Equal(t, "2", results[0].Todo.ID)
+	assert.Equal(t, "3", results[1].Todo.ID)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(フレーズ検索)
+func TestSearchTodosPhrase(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return(searchTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, `"weekly report"`, 0)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Write the <b>weekly</b> <b>report</b>", results[0].Highlight)
+
+	// 語順が異なる場合は一致しない
+	results, err = searchUseCase.SearchTodos(scope, `"report weekly"`, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(AND検索と件数制限)
+func TestSearchTodosLimit(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return(searchTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, "milk bug", 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "3", results[0].Todo.ID)
+
+	results, err = searchUseCase.SearchTodos(scope, "milk", 1)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// SearchTodosのテスト(異常系 - クエリが空)
+func TestSearchTodosEmptyQuery(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(domain_todo.Scope{UserID: "2"}, ` "" * `, 0)
+
+	// 検証
+	assert.EqualError(t, err, "query is empty")
+	assert.Nil(t, results)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertNotCalled(t, "GetAllTodos", domain_todo.Scope{UserID: "2"})
+}
+
+// SearchTodosのテスト(異常系 - user_idが空)
+func TestSearchTodosEmptyUserId(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(domain_todo.Scope{}, "milk", 0)
+
+	// 検証
+	assert.EqualError(t, err, "user_id is empty")
+	assert.Nil(t, results)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertNotCalled(t, "GetAllTodos", domain_todo.Scope{})
+}
+
+// SearchTodosのテスト(異常系 - リポジトリ異常)
+func TestSearchTodosError(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetAllTodos", scope).Return(nil, errors.New("error"))
+
+	// ユースケースのメソッドを呼び出し
+	results, err := searchUseCase.SearchTodos(scope, "milk", 0)
+
+	// 検証
+	assert.Error(t, err)
+	assert.Nil(t, results)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// ToTsQueryのテスト
+func TestSearchQueryToTsQuery(t *testing.T) {
+	query, err := domain_todo.ParseTodoSearchQuery(`buy "weekly report" rep* it's`, 20)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "'buy' & ('weekly' <-> 'report') & 'rep':* & 'it' & 's'", query.ToTsQuery())
+}
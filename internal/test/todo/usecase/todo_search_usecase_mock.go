@@ -0,0 +1,24 @@
+package test_todo_usecase
+
+import (
+	domain_todo "backend/internal/domain/todo"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのTodo検索ユースケース作成
+type MockTodoSearchUsecase struct {
+	mock.Mock
+}
+
+// SearchTodosのモック
+func (m *MockTodoSearchUsecase) SearchTodos(scope domain_todo.Scope, q string, limit int) ([]domain_todo.TodoSearchResult, error) {
+	args := m.Called(scope, q, limit)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.TodoSearchResult), args.Error(1)
+}
@@ -25,10 +25,10 @@ func TestCreateTodo(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("CreateTodo", todo).Return(todo, nil)
+	mockRepo.On("CreateTodo", scope, todo).Return(todo, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.CreateTodo(todo)
+	result, err := useCase.CreateTodo(scope, todo)
 
 	// 検証
 	assert.NoError(t, err)
@@ -53,10 +53,10 @@ func TestCreateTodoEmpty(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("CreateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("CreateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.CreateTodo(todo)
+	result, err := useCase.CreateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
@@ -66,9 +66,9 @@ func TestCreateTodoEmpty(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "CreateTodo", todo)
 }
 
-// CreateTodoのテスト(異常系 - UserIdが空)
+// CreateTodoのテスト(異常系 - リクエストのユーザーが空)
 func TestCreateTodoUserIdEmpty(t *testing.T) {
-	// モックの挙動をリセット
+	// モックの挙動をリセット(リポジトリは呼ばれない)
 	mockRepo.ExpectedCalls = nil
 
 	// テストデータ
@@ -76,22 +76,40 @@ func TestCreateTodoUserIdEmpty(t *testing.T) {
 		ID:          "1",
 		Description: "Todo 1",
 		Completed:   false,
-		UserId:      "",
+		UserId:      "1",
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-	// モックの挙動を設定
-	mockRepo.On("CreateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.CreateTodo(todo)
+	result, err := useCase.CreateTodo(domain_todo.Scope{}, todo)
 
 	// 検証
-	assert.Error(t, err)
+	assert.EqualError(t, err, "user_id is empty")
 	assert.Equal(t, domain_todo.Todo{}, result)
+}
+
+// CreateTodoのテスト(リクエストボディのuser_idは使わず、リクエストのユーザーを作成者にする)
+func TestCreateTodoOwner(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	todo := domain_todo.Todo{Description: "Todo 1", UserId: "2"}
+	expected := domain_todo.Todo{Description: "Todo 1", UserId: "1"}
+
+	// モックの挙動を設定
+	mockRepo.On("CreateTodo", scope, expected).Return(expected, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.CreateTodo(scope, todo)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "1", result.UserId)
 
 	// モックのメソッドが期待通りに呼ばれたことを確認
-	mockRepo.AssertNotCalled(t, "CreateTodo", todo)
+	mockRepo.AssertExpectations(t)
 }
 
 // CreateTodoのテスト(異常系 - リポジトリでエラーが発生)
@@ -109,10 +127,10 @@ func TestCreateTodoError(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("CreateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("CreateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.CreateTodo(todo)
+	result, err := useCase.CreateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
@@ -121,3 +139,35 @@ func TestCreateTodoError(t *testing.T) {
 	// モックのメソッドが期待通りに呼ばれたことを確認
 	mockRepo.AssertExpectations(t)
 }
+
+// CreateTodoのテスト(作成先の指定が無ければ操作中のワークスペースに作成する)
+func TestCreateTodoDefaultWorkspace(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	workspaceScope := domain_todo.Scope{UserID: "1", WorkspaceID: "w1"}
+	todo := domain_todo.Todo{Description: "Todo 1", UserId: "1"}
+	expected := todo
+	expected.WorkspaceID = "w1"
+
+	// モックの挙動を設定
+	mockRepo.On("CreateTodo", workspaceScope, expected).Return(expected, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.CreateTodo(workspaceScope, todo)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "w1", result.WorkspaceID)
+
+	// 指定がある場合はそのワークスペースに作成する
+	todo.WorkspaceID = "w2"
+	mockRepo.On("CreateTodo", workspaceScope, todo).Return(todo, nil)
+	result, err = useCase.CreateTodo(workspaceScope, todo)
+	assert.NoError(t, err)
+	assert.Equal(t, "w2", result.WorkspaceID)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
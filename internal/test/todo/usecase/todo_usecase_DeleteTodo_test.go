@@ -16,10 +16,10 @@ func TestDeleteTodo(t *testing.T) {
 	id := "1"
 
 	// モックの挙動を設定
-	mockRepo.On("DeleteTodo", id).Return(nil)
+	mockRepo.On("DeleteTodo", scope, id).Return(nil)
 
 	// ユースケースのメソッドを呼び出し
-	err := useCase.DeleteTodo(id)
+	err := useCase.DeleteTodo(scope, id)
 
 	// 検証
 	assert.NoError(t, err)
@@ -37,10 +37,10 @@ func TestDeleteTodoIdEmpty(t *testing.T) {
 	id := ""
 
 	// モックの挙動を設定
-	mockRepo.On("DeleteTodo", id).Return(errors.New("error"))
+	mockRepo.On("DeleteTodo", scope, id).Return(errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	err := useCase.DeleteTodo(id)
+	err := useCase.DeleteTodo(scope, id)
 
 	// 検証
 	assert.Error(t, err)
@@ -58,10 +58,10 @@ func TestDeleteTodoError(t *testing.T) {
 	id := "1"
 
 	// モックの挙動を設定
-	mockRepo.On("DeleteTodo", id).Return(errors.New("error"))
+	mockRepo.On("DeleteTodo", scope, id).Return(errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	err := useCase.DeleteTodo(id)
+	err := useCase.DeleteTodo(scope, id)
 
 	// 検証
 	assert.Error(t, err)
@@ -21,10 +21,10 @@ func TestGetAllTodos(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllTodos").Return(todos, nil)
+	mockRepo.On("GetAllTodos", scope).Return(todos, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllTodos()
+	result, err := useCase.GetAllTodos(scope)
 
 	// 検証
 	assert.NoError(t, err)
@@ -44,10 +44,10 @@ func TestGetAllTodosEmpty(t *testing.T) {
 	todos := []domain_todo.Todo{}
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllTodos").Return(todos, nil)
+	mockRepo.On("GetAllTodos", scope).Return(todos, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllTodos()
+	result, err := useCase.GetAllTodos(scope)
 
 	// 検証
 	assert.NoError(t, err)
@@ -63,10 +63,10 @@ func TestGetAllTodosError(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllTodos").Return(([]domain_todo.Todo)(nil), errors.New("error"))
+	mockRepo.On("GetAllTodos", scope).Return(([]domain_todo.Todo)(nil), errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllTodos()
+	result, err := useCase.GetAllTodos(scope)
 
 	// 検証
 	assert.Error(t, err)
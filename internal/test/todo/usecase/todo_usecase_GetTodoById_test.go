@@ -25,10 +25,10 @@ func TestGetTodoById(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoById", "1").Return(todo, nil)
+	mockRepo.On("GetTodoById", scope, "1").Return(todo, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoById("1")
+	result, err := useCase.GetTodoById(scope, "1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -44,10 +44,10 @@ func TestGetTodoByIdEmpty(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoById", "").Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("GetTodoById", scope, "").Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoById("")
+	result, err := useCase.GetTodoById(scope, "")
 
 	// 検証
 	assert.Error(t, err)
@@ -63,10 +63,10 @@ func TestGetTodoByIdError(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoById", "1").Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("GetTodoById", scope, "1").Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoById("1")
+	result, err := useCase.GetTodoById(scope, "1")
 
 	// 検証
 	assert.Error(t, err)
@@ -35,10 +35,10 @@ func TestGetTodoByUserId(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoByUserId", "1").Return(todos, nil)
+	mockRepo.On("GetTodoByUserId", scope, "1").Return(todos, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoByUserId("1")
+	result, err := useCase.GetTodoByUserId(scope, "1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -54,10 +54,10 @@ func TestGetTodoByUserIdEmpty(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoByUserId", "").Return(nil, errors.New("error"))
+	mockRepo.On("GetTodoByUserId", scope, "").Return(nil, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoByUserId("")
+	result, err := useCase.GetTodoByUserId(scope, "")
 
 	// 検証
 	assert.Error(t, err)
@@ -73,10 +73,10 @@ func TestGetTodoByUserIdError(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetTodoByUserId", "1").Return(nil, errors.New("error"))
+	mockRepo.On("GetTodoByUserId", scope, "1").Return(nil, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetTodoByUserId("1")
+	result, err := useCase.GetTodoByUserId(scope, "1")
 
 	// 検証
 	assert.Error(t, err)
@@ -0,0 +1,424 @@
+package test_todo_usecase
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// 日時のリストが一致するか(タイムゾーンに依らず時刻で比べる)
+func sameTimes(expected ...time.Time) interface{} {
+	return mock.MatchedBy(func(actual []time.Time) bool {
+		if len(actual) != len(expected) {
+			return false
+		}
+		for i := range expected {
+			if !actual[i].Equal(expected[i]) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// 日時が一致するか
+func sameTime(expected time.Time) interface{} {
+	return mock.MatchedBy(func(actual time.Time) bool {
+		return actual.Equal(expected)
+	})
+}
+
+// UTCの日時
+func utc(year int, month time.Month, day int, hour int) time.Time {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC)
+}
+
+// CreateRecurrenceのテスト(正常系 - 先の期間の予定を作成する)
+func TestCreateRecurrence(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(24時間後から毎日、先の期間は7日)
+	start := time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour)
+	input := domain_todo.Recurrence{Description: "日報", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: start.Add(500 * time.Millisecond)}
+	expected := domain_todo.Recurrence{UserId: "1", Description: "日報", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: start}
+	created := expected
+	created.ID, created.WorkspaceID, created.MaterializedUntil = "r1", "w1", start
+	todos := []domain_todo.Todo{{ID: "t1", RecurrenceID: "r1"}}
+
+	// モックの挙動を設定
+	mockRecurrenceRepo.On("CreateRecurrence", scope, expected).Return(created, nil)
+	mockRecurrenceRepo.On("MaterializeOccurrences", created, mock.MatchedBy(func(occurrences []time.Time) bool {
+		// 開始から7日後の現在時刻より前まで(7日分)
+		return len(occurrences) == 7 && occurrences[0].Equal(start) && occurrences[6].Equal(start.Add(6*24*time.Hour))
+	}), mock.Anything).Return(todos, nil)
+
+	// テスト対象のメソッドを呼び出し
+	result, resultTodos, err := recurrenceUseCase.CreateRecurrence(scope, input)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, created, result)
+	assert.Equal(t, todos, resultTodos)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// CreateRecurrenceのテスト(正常系 - 最初の予定が先の期間より後でも作成する)
+func TestCreateRecurrenceFirstOccurrence(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ
+	start := utc(2100, 1, 1, 9)
+	expected := domain_todo.Recurrence{UserId: "1", Description: "更新", RRule: "FREQ=YEARLY", Timezone: "UTC", StartAt: start}
+	created := expected
+	created.ID, created.MaterializedUntil = "r1", start
+
+	// モックの挙動を設定
+	mockRecurrenceRepo.On("CreateRecurrence", scope, expected).Return(created, nil)
+	mockRecurrenceRepo.On("MaterializeOccurrences", created, sameTimes(start), sameTime(start.Add(time.Second))).Return([]domain_todo.Todo{{ID: "t1"}}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	_, todos, err := recurrenceUseCase.CreateRecurrence(scope, expected)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Len(t, todos, 1)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// CreateRecurrenceのテスト(正常系 - 開始日時が過去でも、過去の予定は作成しない)
+func TestCreateRecurrencePastStart(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(1年前から毎日。作成済みの日時はリポジトリが現在にする)
+	now := time.Now().UTC().Truncate(time.Second)
+	start := now.Add(-365 * 24 * time.Hour).Add(time.Hour)
+	expected := domain_todo.Recurrence{UserId: "1", Description: "日報", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: start}
+	created := expected
+	created.ID, created.MaterializedUntil = "r1", now
+
+	// モックの挙動を設定
+	mockRecurrenceRepo.On("CreateRecurrence", scope, expected).Return(created, nil)
+	mockRecurrenceRepo.On("MaterializeOccurrences", created, mock.MatchedBy(func(occurrences []time.Time) bool {
+		// 現在から7日分(過去の予定を含まない)
+		return len(occurrences) == 7 && !occurrences[0].Before(now) && occurrences[0].Before(now.Add(24*time.Hour))
+	}), mock.Anything).Return([]domain_todo.Todo{{ID: "t1"}}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	_, _, err := recurrenceUseCase.CreateRecurrence(scope, expected)
+
+	// 検証
+	assert.NoError(t, err)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// CreateRecurrenceのテスト(異常系 - 入力エラー)
+func TestCreateRecurrenceInvalid(t *testing.T) {
+	cases := []struct {
+		recurrence domain_todo.Recurrence
+		err        string
+	}{
+		{domain_todo.Recurrence{RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9)}, "description is empty"},
+		{domain_todo.Recurrence{Description: "d", RRule: "FREQ=DAILY", Timezone: "UTC"}, "start_at is empty"},
+		{domain_todo.Recurrence{Description: "d", RRule: "FREQ=HOURLY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9)}, "invalid rrule"},
+		{domain_todo.Recurrence{Description: "d", RRule: "FREQ=DAILY", Timezone: "Local", StartAt: utc(2100, 1, 1, 9)}, "invalid timezone"},
+	}
+	for _, tc := range cases {
+		// モックの挙動をリセット(リポジトリは呼ばれない)
+		mockRecurrenceRepo.ExpectedCalls = nil
+
+		// テスト対象のメソッドを呼び出し
+		_, _, err := recurrenceUseCase.CreateRecurrence(scope, tc.recurrence)
+
+		// 検証
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+// EditOccurrenceのテスト(正常系 - この予定のみ)
+func TestEditOccurrenceThis(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ
+	occurrenceAt := utc(2100, 1, 4, 9)
+	dueAt := utc(2100, 1, 5, 12)
+	todo := domain_todo.Todo{ID: "t1", Description: "日報", RecurrenceID: "r1", OccurrenceAt: &occurrenceAt, DueAt: &occurrenceAt}
+	edited := todo
+	edited.Description, edited.DueAt = "日報(延期)", &dueAt
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "t1").Return(todo, nil)
+	mockRecurrenceRepo.On("UpdateOccurrence", scope, edited).Return(edited, nil)
+
+	// テスト対象のメソッドを呼び出し
+	todos, err := recurrenceUseCase.EditOccurrence(scope, "t1", domain_todo.OccurrenceEdit{
+		Scope: domain_todo.OccurrenceScopeThis, Description: "日報(延期)", DueAt: &dueAt,
+	})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []domain_todo.Todo{edited}, todos)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// EditOccurrenceのテスト(正常系 - 以降の全て)
+// 新しい繰り返しは現地の時刻で展開するため、夏時間の開始後もUTCでは1時間早くなる
+func TestEditOccurrenceFuture(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(2100年3月28日に夏時間が始まる)
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	occurrenceAt := time.Date(2100, 3, 22, 9, 0, 0, 0, berlin)
+	todo := domain_todo.Todo{ID: "t1", Description: "定例", RecurrenceID: "r1", OccurrenceAt: &occurrenceAt}
+	recurrence := domain_todo.Recurrence{
+		ID: "r1", WorkspaceID: "w1", UserId: "u1", Description: "定例", RRule: "FREQ=WEEKLY", Timezone: "Europe/Berlin",
+		StartAt: time.Date(2100, 3, 1, 9, 0, 0, 0, berlin),
+	}
+	next := domain_todo.Recurrence{UserId: "u1", Description: "定例(隔週)", RRule: "FREQ=WEEKLY;INTERVAL=2", Timezone: "Europe/Berlin", StartAt: occurrenceAt}
+	created := next
+	created.ID, created.WorkspaceID, created.MaterializedUntil = "r2", "w1", occurrenceAt
+	moved := domain_todo.Todo{ID: "t1", Description: "定例(隔週)", RecurrenceID: "r2", OccurrenceAt: &occurrenceAt}
+	following := utc(2100, 4, 5, 7)
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "t1").Return(todo, nil)
+	mockRecurrenceRepo.On("GetRecurrenceById", scope, "r1").Return(recurrence, nil)
+	mockRecurrenceRepo.On("SplitRecurrence", scope, "t1", next).Return(created, moved, nil)
+	mockRecurrenceRepo.On("MaterializeOccurrences", created, sameTimes(utc(2100, 3, 22, 8), following), sameTime(following.Add(time.Second))).
+		Return([]domain_todo.Todo{{ID: "t2", RecurrenceID: "r2"}}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	todos, err := recurrenceUseCase.EditOccurrence(scope, "t1", domain_todo.OccurrenceEdit{
+		Scope: domain_todo.OccurrenceScopeFuture, Description: "定例(隔週)", RRule: "FREQ=WEEKLY;INTERVAL=2",
+	})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []domain_todo.Todo{moved, {ID: "t2", RecurrenceID: "r2"}}, todos)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// EditOccurrenceのテスト(異常系 - 繰り返しでないTodo)
+func TestEditOccurrenceNotRecurring(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "t1").Return(domain_todo.Todo{ID: "t1"}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	_, err := recurrenceUseCase.EditOccurrence(scope, "t1", domain_todo.OccurrenceEdit{Scope: domain_todo.OccurrenceScopeFuture})
+
+	// 検証
+	assert.EqualError(t, err, "todo is not recurring")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// EditOccurrenceのテスト(異常系 - 変更範囲の指定が不正)
+func TestEditOccurrenceInvalidScope(t *testing.T) {
+	// モックの挙動をリセット(リポジトリは呼ばれない)
+	mockRepo.ExpectedCalls = nil
+
+	// テスト対象のメソッドを呼び出し
+	_, err := recurrenceUseCase.EditOccurrence(scope, "t1", domain_todo.OccurrenceEdit{Scope: "all"})
+
+	// 検証
+	assert.EqualError(t, err, "invalid scope")
+}
+
+// CompleteTodoのテスト(正常系 - 繰り返しの次の予定を作成する)
+func TestCompleteTodoRecurring(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(2100年1月4日の予定まで作成済み)
+	occurrenceAt := utc(2100, 1, 4, 9)
+	todo := domain_todo.Todo{ID: "t1", RecurrenceID: "r1", OccurrenceAt: &occurrenceAt}
+	completed := todo
+	completed.Completed = true
+	recurrence := domain_todo.Recurrence{
+		ID: "r1", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9), MaterializedUntil: occurrenceAt.Add(time.Second),
+	}
+	nextAt := utc(2100, 1, 5, 9)
+	next := domain_todo.Todo{ID: "t2", RecurrenceID: "r1", OccurrenceAt: &nextAt}
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "t1").Return([]domain_todo.Todo{todo}, nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"t1"}, true).Return([]domain_todo.Todo{completed}, nil)
+	mockRecurrenceRepo.On("GetRecurrenceById", scope, "r1").Return(recurrence, nil)
+	mockRecurrenceRepo.On("MaterializeOccurrences", recurrence, sameTimes(nextAt), sameTime(nextAt.Add(time.Second))).Return([]domain_todo.Todo{next}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	todos, err := useCase.CompleteTodo(scope, "t1", domain_todo.TodoCompletion{Completed: true})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, []domain_todo.Todo{completed, next}, todos)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// UpdateTodoのテスト(正常系 - 次の予定が作成済みの場合は作成しない)
+func TestUpdateTodoRecurringMaterialized(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(先の期間まで作成済み)
+	occurrenceAt := utc(2100, 1, 4, 9)
+	todo := domain_todo.Todo{ID: "t1", Description: "日報", UserId: "1", Completed: true, RecurrenceID: "r1", OccurrenceAt: &occurrenceAt}
+	recurrence := domain_todo.Recurrence{
+		ID: "r1", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9), MaterializedUntil: utc(2100, 1, 10, 0),
+	}
+
+	// モックの挙動を設定(MaterializeOccurrencesは呼ばれない)
+	mockRepo.On("UpdateTodo", scope, todo).Return(todo, nil)
+	mockRecurrenceRepo.On("GetRecurrenceById", scope, "r1").Return(recurrence, nil)
+
+	// テスト対象のメソッドを呼び出し
+	result, err := useCase.UpdateTodo(scope, todo)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, todo, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// UpdateTodoのテスト(正常系 - 繰り返しの取得に失敗しても更新は成功)
+func TestUpdateTodoRecurringError(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ
+	occurrenceAt := utc(2100, 1, 4, 9)
+	todo := domain_todo.Todo{ID: "t1", Description: "日報", UserId: "1", Completed: true, RecurrenceID: "r1", OccurrenceAt: &occurrenceAt}
+
+	// モックの挙動を設定
+	mockRepo.On("UpdateTodo", scope, todo).Return(todo, nil)
+	mockRecurrenceRepo.On("GetRecurrenceById", scope, "r1").Return(nil, errors.New("error"))
+
+	// テスト対象のメソッドを呼び出し
+	result, err := useCase.UpdateTodo(scope, todo)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, todo, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// MaterializeDueのテスト(正常系 - 全ての繰り返しを処理する)
+func TestMaterializeDue(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(先の期間は7日、1回に2件ずつ取得する)
+	now := utc(2100, 1, 1, 0)
+	until := utc(2100, 1, 8, 0)
+	endAt := utc(2100, 1, 3, 0)
+	daily := domain_todo.Recurrence{ID: "r1", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9), MaterializedUntil: utc(2100, 1, 5, 9)}
+	invalid := domain_todo.Recurrence{ID: "r2", RRule: "FREQ=SECONDLY", Timezone: "UTC", StartAt: utc(2100, 1, 1, 9)}
+	ended := domain_todo.Recurrence{ID: "r3", RRule: "FREQ=WEEKLY", Timezone: "UTC", StartAt: utc(2099, 12, 27, 9), EndAt: &endAt, MaterializedUntil: now}
+
+	// モックの挙動を設定
+	mockRecurrenceRepo.On("GetPendingRecurrences", until, domain_todo.Recurrence{}, 2).Return([]domain_todo.Recurrence{daily, invalid}, nil).Once()
+	mockRecurrenceRepo.On("GetPendingRecurrences", until, invalid, 2).Return([]domain_todo.Recurrence{ended}, nil).Once()
+	mockRecurrenceRepo.On("MaterializeOccurrences", daily, sameTimes(utc(2100, 1, 5, 9), utc(2100, 1, 6, 9), utc(2100, 1, 7, 9)), until).
+		Return([]domain_todo.Todo{{ID: "t5"}, {ID: "t6"}, {ID: "t7"}}, nil)
+	// 終了した繰り返しも作成済みの日時は進める
+	mockRecurrenceRepo.On("MaterializeOccurrences", ended, sameTimes(), until).Return([]domain_todo.Todo{}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	count, err := recurrenceUseCase.MaterializeDue(now)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// MaterializeDueのテスト(正常系 - 失敗し続ける繰り返しが1回の取得数以上あっても、後の繰り返しを処理する)
+func TestMaterializeDueFailing(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// テストデータ(作成済みの日時が古い2件は作成に失敗し続ける)
+	now := utc(2100, 1, 1, 0)
+	until := utc(2100, 1, 8, 0)
+	failing1 := domain_todo.Recurrence{ID: "r1", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2099, 12, 1, 9), MaterializedUntil: utc(2099, 12, 1, 9)}
+	failing2 := domain_todo.Recurrence{ID: "r2", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2099, 12, 1, 9), MaterializedUntil: utc(2099, 12, 1, 9)}
+	healthy := domain_todo.Recurrence{ID: "r3", RRule: "FREQ=DAILY", Timezone: "UTC", StartAt: utc(2100, 1, 7, 9), MaterializedUntil: utc(2100, 1, 7, 9)}
+
+	// モックの挙動を設定
+	mockRecurrenceRepo.On("GetPendingRecurrences", until, domain_todo.Recurrence{}, 2).Return([]domain_todo.Recurrence{failing1, failing2}, nil).Once()
+	mockRecurrenceRepo.On("GetPendingRecurrences", until, failing2, 2).Return([]domain_todo.Recurrence{healthy}, nil).Once()
+	mockRecurrenceRepo.On("MaterializeOccurrences", failing1, mock.Anything, until).Return(nil, errors.New("error"))
+	mockRecurrenceRepo.On("MaterializeOccurrences", failing2, mock.Anything, until).Return(nil, errors.New("error"))
+	mockRecurrenceRepo.On("MaterializeOccurrences", healthy, sameTimes(utc(2100, 1, 7, 9)), until).Return([]domain_todo.Todo{{ID: "t1"}}, nil)
+
+	// テスト対象のメソッドを呼び出し
+	count, err := recurrenceUseCase.MaterializeDue(now)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
+
+// MaterializeDueのテスト(異常系 - 取得に失敗)
+func TestMaterializeDueError(t *testing.T) {
+	// モックの挙動をリセット
+	mockRecurrenceRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	now := utc(2100, 1, 1, 0)
+	mockRecurrenceRepo.On("GetPendingRecurrences", utc(2100, 1, 8, 0), domain_todo.Recurrence{}, 2).Return(nil, errors.New("error"))
+
+	// テスト対象のメソッドを呼び出し
+	_, err := recurrenceUseCase.MaterializeDue(now)
+
+	// 検証
+	assert.EqualError(t, err, "error")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRecurrenceRepo.AssertExpectations(t)
+}
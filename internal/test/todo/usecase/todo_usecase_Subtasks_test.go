@@ -0,0 +1,406 @@
+package test_todo_usecase
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// サブタスクのテストデータ
+// p1の子はc1(完了)とc2、c1の子はg1(完了)、c2の子はg2(完了)とg3
+func subtaskTestTodos() []domain_todo.Todo {
+	return []domain_todo.Todo{
+		{ID: "p1", Description: "Release", UserId: "1", WorkspaceID: "w1", Position: "V"},
+		{ID: "c2", Description: "Docs", UserId: "1", WorkspaceID: "w1", ParentID: "p1", Position: "W"},
+		{ID: "c1", Description: "Build", UserId: "1", WorkspaceID: "w1", ParentID: "p1", Position: "V", Completed: true},
+		{ID: "g1", Description: "Compile", UserId: "1", WorkspaceID: "w1", ParentID: "c1", Position: "V", Completed: true},
+		{ID: "g3", Description: "Changelog", UserId: "1", WorkspaceID: "w1", ParentID: "c2", Position: "W"},
+		{ID: "g2", Description: "README", UserId: "1", WorkspaceID: "w1", ParentID: "c2", Position: "V", Completed: true},
+	}
+}
+
+// idsのTodoを順に取り出す
+func subtaskTestSubtree(ids ...string) []domain_todo.Todo {
+	todos := []domain_todo.Todo{}
+	for _, id := range ids {
+		for _, todo := range subtaskTestTodos() {
+			if todo.ID == id {
+				todos = append(todos, todo)
+			}
+		}
+	}
+	return todos
+}
+
+// GetTodoTreeのテスト(並び順と完了率)
+func TestGetTodoTree(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "p1").Return(subtaskTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	tree, err := useCase.GetTodoTree(scope, "p1")
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", tree.ID)
+	if assert.Len(t, tree.Children, 2) {
+		assert.Equal(t, "c1", tree.Children[0].ID)
+		assert.Equal(t, "c2", tree.Children[1].ID)
+		assert.Equal(t, 100, tree.Children[0].Progress)
+		assert.Equal(t, 50, tree.Children[1].Progress)
+		assert.Equal(t, []string{"g2", "g3"}, []string{tree.Children[1].Children[0].ID, tree.Children[1].Children[1].ID})
+	}
+	// 末端のTodo3つのうち2つが完了
+	assert.Equal(t, 66, tree.Progress)
+	assert.Equal(t, 3, tree.Height())
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// GetTodoTreeのテスト(異常系 - 参照できない)
+func TestGetTodoTreeNotFound(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "x").Return(nil, errors.New("todo not found"))
+
+	// ユースケースのメソッドを呼び出し
+	tree, err := useCase.GetTodoTree(scope, "x")
+
+	// 検証
+	assert.EqualError(t, err, "todo not found")
+	assert.Nil(t, tree)
+
+	// idが空の場合はリポジトリを呼ばない
+	_, err = useCase.GetTodoTree(scope, "")
+	assert.EqualError(t, err, "id is empty")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CreateTodoのテスト(サブタスクは親のワークスペースに作成する)
+func TestCreateTodoSubtask(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	todo := domain_todo.Todo{Description: "Tests", UserId: "1", ParentID: "c1"}
+	expected := todo
+	expected.WorkspaceID = "w1"
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "c1").Return(subtaskTestSubtree("c1")[0], nil)
+	mockRepo.On("GetTodoAncestors", scope, "c1").Return(subtaskTestSubtree("p1"), nil)
+	mockRepo.On("CreateTodo", scope, expected).Return(expected, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.CreateTodo(scope, todo)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, expected, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CreateTodoのテスト(異常系 - 親が参照できない・階層が深すぎる)
+func TestCreateTodoSubtaskError(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "x").Return(nil, errors.New("todo not found"))
+	mockRepo.On("GetTodoById", scope, "g1").Return(subtaskTestSubtree("g1")[0], nil)
+	// g1の深さがMaxTodoDepthの場合
+	ancestors := make([]domain_todo.Todo, domain_todo.MaxTodoDepth-1)
+	mockRepo.On("GetTodoAncestors", scope, "g1").Return(ancestors, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.CreateTodo(scope, domain_todo.Todo{Description: "Tests", UserId: "1", ParentID: "x"})
+	assert.EqualError(t, err, "parent not found")
+
+	_, err = useCase.CreateTodo(scope, domain_todo.Todo{Description: "Tests", UserId: "1", ParentID: "g1"})
+	assert.EqualError(t, err, "max depth exceeded")
+
+	// 親と異なるワークスペースには作成できない
+	mockRepo.On("GetTodoAncestors", scope, "c1").Return(subtaskTestSubtree("p1"), nil)
+	mockRepo.On("GetTodoById", scope, "c1").Return(subtaskTestSubtree("c1")[0], nil)
+	_, err = useCase.CreateTodo(scope, domain_todo.Todo{Description: "Tests", UserId: "1", ParentID: "c1", WorkspaceID: "w2"})
+	assert.EqualError(t, err, "parent not found")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト
+func TestMoveTodo(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	move := domain_todo.TodoMove{ParentID: "c2"}
+	moved := subtaskTestSubtree("c1")[0]
+	moved.ParentID = "c2"
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "c1").Return(subtaskTestSubtree("c1", "g1"), nil)
+	mockRepo.On("GetTodoById", scope, "c2").Return(subtaskTestSubtree("c2")[0], nil)
+	mockRepo.On("GetTodoAncestors", scope, "c2").Return(subtaskTestSubtree("p1"), nil)
+	mockRepo.On("MoveTodo", scope, "c1", move).Return(moved, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.MoveTodo(scope, "c1", move)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, moved, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(ルートへの移動は親を確認しない)
+func TestMoveTodoToRoot(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	move := domain_todo.TodoMove{}
+	moved := subtaskTestSubtree("c2")[0]
+	moved.ParentID = ""
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "c2").Return(subtaskTestSubtree("c2", "g2", "g3"), nil)
+	mockRepo.On("MoveTodo", scope, "c2", move).Return(moved, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.MoveTodo(scope, "c2", move)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, moved, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(隣のTodoの親に移動する)
+func TestMoveTodoNeighbours(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ(g1をg2とg3の間に移動する)
+	move := domain_todo.TodoMove{AfterID: "g2", BeforeID: "g3"}
+	resolved := domain_todo.TodoMove{ParentID: "c2", AfterID: "g2", BeforeID: "g3"}
+	moved := subtaskTestSubtree("g1")[0]
+	moved.ParentID, moved.Position = "c2", "VV"
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "g1").Return(subtaskTestSubtree("g1"), nil)
+	mockRepo.On("GetTodoById", scope, "g2").Return(subtaskTestSubtree("g2")[0], nil)
+	mockRepo.On("GetTodoById", scope, "g3").Return(subtaskTestSubtree("g3")[0], nil)
+	mockRepo.On("GetTodoById", scope, "c2").Return(subtaskTestSubtree("c2")[0], nil)
+	mockRepo.On("GetTodoAncestors", scope, "c2").Return(subtaskTestSubtree("p1"), nil)
+	mockRepo.On("MoveTodo", scope, "g1", resolved).Return(moved, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.MoveTodo(scope, "g1", move)
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, moved, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(異常系 - 隣のTodoが無い・親が異なる)
+func TestMoveTodoNeighbourNotFound(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoById", scope, "x").Return(nil, errors.New("todo not found"))
+	mockRepo.On("GetTodoById", scope, "g2").Return(subtaskTestSubtree("g2")[0], nil)
+	mockRepo.On("GetTodoById", scope, "c1").Return(subtaskTestSubtree("c1")[0], nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.MoveTodo(scope, "g1", domain_todo.TodoMove{AfterID: "x"})
+	assert.EqualError(t, err, "neighbour not found")
+
+	// 自身は隣に指定できない
+	_, err = useCase.MoveTodo(scope, "g1", domain_todo.TodoMove{BeforeID: "g1"})
+	assert.EqualError(t, err, "neighbour not found")
+
+	// 親の指定と隣のTodoの親が異なる
+	_, err = useCase.MoveTodo(scope, "g1", domain_todo.TodoMove{ParentID: "c1", AfterID: "g2"})
+	assert.EqualError(t, err, "neighbour not found")
+
+	// 隣のTodo同士の親が異なる
+	_, err = useCase.MoveTodo(scope, "g1", domain_todo.TodoMove{AfterID: "g2", BeforeID: "c1"})
+	assert.EqualError(t, err, "neighbour not found")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(異常系 - 自身・子孫の下に移動)
+func TestMoveTodoCycle(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "p1").Return(subtaskTestTodos(), nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.MoveTodo(scope, "p1", domain_todo.TodoMove{ParentID: "p1"})
+	assert.EqualError(t, err, "cycle detected")
+
+	_, err = useCase.MoveTodo(scope, "p1", domain_todo.TodoMove{ParentID: "g3"})
+	assert.EqualError(t, err, "cycle detected")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// MoveTodoのテスト(異常系 - 移動後の階層が深すぎる)
+func TestMoveTodoMaxDepth(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ(深さMaxTodoDepth-2の親の下に高さ3の階層を移動する)
+	other := domain_todo.Todo{ID: "o1", UserId: "1", WorkspaceID: "w1"}
+	ancestors := make([]domain_todo.Todo, domain_todo.MaxTodoDepth-3)
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "p1").Return(subtaskTestTodos(), nil)
+	mockRepo.On("GetTodoById", scope, "o1").Return(other, nil)
+	mockRepo.On("GetTodoAncestors", scope, "o1").Return(ancestors, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.MoveTodo(scope, "p1", domain_todo.TodoMove{ParentID: "o1"})
+
+	// 検証
+	assert.EqualError(t, err, "max depth exceeded")
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(子孫にも反映する)
+func TestCompleteTodoDescendants(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ
+	updated := subtaskTestSubtree("c2", "g2", "g3")
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "c2").Return(subtaskTestSubtree("c2", "g2", "g3"), nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"c2", "g2", "g3"}, true).Return(updated, nil)
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.CompleteTodo(scope, "c2", domain_todo.TodoCompletion{Completed: true, Descendants: true})
+
+	// 検証
+	assert.NoError(t, err)
+	assert.Equal(t, updated, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(全ての子が完了した祖先を完了にする)
+func TestCompleteTodoAncestors(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "g3").Return(subtaskTestSubtree("g3"), nil)
+	mockRepo.On("GetTodoAncestors", scope, "g3").Return(subtaskTestSubtree("c2", "p1"), nil)
+	mockRepo.On("GetTodoSubtree", scope, "p1").Return(subtaskTestTodos(), nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"g3", "c2", "p1"}, true).Return([]domain_todo.Todo{}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.CompleteTodo(scope, "g3", domain_todo.TodoCompletion{Completed: true, Ancestors: true})
+
+	// 検証
+	assert.NoError(t, err)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(未完了の兄弟が残る場合は親を完了にしない)
+func TestCompleteTodoAncestorsIncompleteSibling(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// テストデータ(g3を未完了のまま、g2を完了にし直す)
+	todos := subtaskTestTodos()
+	todos[5].Completed = false
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "g2").Return(todos[5:], nil)
+	mockRepo.On("GetTodoAncestors", scope, "g2").Return(subtaskTestSubtree("c2", "p1"), nil)
+	mockRepo.On("GetTodoSubtree", scope, "p1").Return(todos, nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"g2"}, true).Return([]domain_todo.Todo{}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.CompleteTodo(scope, "g2", domain_todo.TodoCompletion{Completed: true, Ancestors: true})
+
+	// 検証
+	assert.NoError(t, err)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(未完了にする場合は全ての祖先を未完了にする)
+func TestUncompleteTodoAncestors(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "g1").Return(subtaskTestSubtree("g1"), nil)
+	mockRepo.On("GetTodoAncestors", scope, "g1").Return(subtaskTestSubtree("c1", "p1"), nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"g1", "c1", "p1"}, false).Return([]domain_todo.Todo{}, nil)
+
+	// ユースケースのメソッドを呼び出し
+	_, err := useCase.CompleteTodo(scope, "g1", domain_todo.TodoCompletion{Completed: false, Ancestors: true})
+
+	// 検証
+	assert.NoError(t, err)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
+
+// CompleteTodoのテスト(異常系 - 編集できない)
+func TestCompleteTodoPermissionDenied(t *testing.T) {
+	// モックの挙動をリセット
+	mockRepo.ExpectedCalls = nil
+
+	// モックの挙動を設定
+	mockRepo.On("GetTodoSubtree", scope, "g1").Return(subtaskTestSubtree("g1"), nil)
+	mockRepo.On("SetTodosCompleted", scope, []string{"g1"}, true).Return(nil, errors.New("permission denied"))
+
+	// ユースケースのメソッドを呼び出し
+	result, err := useCase.CompleteTodo(scope, "g1", domain_todo.TodoCompletion{Completed: true})
+
+	// 検証
+	assert.EqualError(t, err, "permission denied")
+	assert.Nil(t, result)
+
+	// モックのメソッドが期待通りに呼ばれたことを確認
+	mockRepo.AssertExpectations(t)
+}
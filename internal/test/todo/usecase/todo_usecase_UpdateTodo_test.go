@@ -25,10 +25,10 @@ func TestUpdateTodo(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("UpdateTodo", todo).Return(todo, nil)
+	mockRepo.On("UpdateTodo", scope, todo).Return(todo, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.UpdateTodo(todo)
+	result, err := useCase.UpdateTodo(scope, todo)
 
 	// 検証
 	assert.NoError(t, err)
@@ -53,10 +53,10 @@ func TestUpdateTodoIdEmpty(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("UpdateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("UpdateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.UpdateTodo(todo)
+	result, err := useCase.UpdateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
@@ -81,10 +81,10 @@ func TestUpdateTodoEmpty(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("UpdateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("UpdateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.UpdateTodo(todo)
+	result, err := useCase.UpdateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
@@ -109,10 +109,10 @@ func TestUpdateTodoUserIdEmpty(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("UpdateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("UpdateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.UpdateTodo(todo)
+	result, err := useCase.UpdateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
@@ -137,10 +137,10 @@ func TestUpdateTodoError(t *testing.T) {
 		UpdatedAt:   time.Now(),
 	}
 	// モックの挙動を設定
-	mockRepo.On("UpdateTodo", todo).Return(domain_todo.Todo{}, errors.New("error"))
+	mockRepo.On("UpdateTodo", scope, todo).Return(domain_todo.Todo{}, errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.UpdateTodo(todo)
+	result, err := useCase.UpdateTodo(scope, todo)
 
 	// 検証
 	assert.Error(t, err)
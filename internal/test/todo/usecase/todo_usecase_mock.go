@@ -12,8 +12,8 @@ type MockTodoUsecase struct {
 }
 
 // GetAllTodosのモック
-func (m *MockTodoUsecase) GetAllTodos() ([]domain_todo.Todo, error) {
-	args := m.Called()
+func (m *MockTodoUsecase) GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error) {
+	args := m.Called(scope)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -24,8 +24,8 @@ func (m *MockTodoUsecase) GetAllTodos() ([]domain_todo.Todo, error) {
 }
 
 // GetTodoByIdのモック
-func (m *MockTodoUsecase) GetTodoById(id string) (domain_todo.Todo, error) {
-	args := m.Called(id)
+func (m *MockTodoUsecase) GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error) {
+	args := m.Called(scope, id)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -36,8 +36,8 @@ func (m *MockTodoUsecase) GetTodoById(id string) (domain_todo.Todo, error) {
 }
 
 // GetTodoByUserIdのモック
-func (m *MockTodoUsecase) GetTodoByUserId(userId string) ([]domain_todo.Todo, error) {
-	args := m.Called(userId)
+func (m *MockTodoUsecase) GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, userId)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -48,8 +48,8 @@ func (m *MockTodoUsecase) GetTodoByUserId(userId string) ([]domain_todo.Todo, er
 }
 
 // CreateTodoのモック
-func (m *MockTodoUsecase) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
-	args := m.Called(todo)
+func (m *MockTodoUsecase) CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	args := m.Called(scope, todo)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -60,8 +60,8 @@ func (m *MockTodoUsecase) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, e
 }
 
 // UpdateTodoのモック
-func (m *MockTodoUsecase) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
-	args := m.Called(todo)
+func (m *MockTodoUsecase) UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
+	args := m.Called(scope, todo)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -72,8 +72,8 @@ func (m *MockTodoUsecase) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, e
 }
 
 // DeleteTodoのモック
-func (m *MockTodoUsecase) DeleteTodo(id string) error {
-	args := m.Called(id)
+func (m *MockTodoUsecase) DeleteTodo(scope domain_todo.Scope, id string) error {
+	args := m.Called(scope, id)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -82,3 +82,39 @@ func (m *MockTodoUsecase) DeleteTodo(id string) error {
 
 	return args.Error(0)
 }
+
+// GetTodoTreeのモック
+func (m *MockTodoUsecase) GetTodoTree(scope domain_todo.Scope, id string) (*domain_todo.TodoNode, error) {
+	args := m.Called(scope, id)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).(*domain_todo.TodoNode), args.Error(1)
+}
+
+// MoveTodoのモック
+func (m *MockTodoUsecase) MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error) {
+	args := m.Called(scope, id, move)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_todo.Todo{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_todo.Todo), args.Error(1)
+}
+
+// CompleteTodoのモック
+func (m *MockTodoUsecase) CompleteTodo(scope domain_todo.Scope, id string, completion domain_todo.TodoCompletion) ([]domain_todo.Todo, error) {
+	args := m.Called(scope, id, completion)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_todo.Todo), args.Error(1)
+}
@@ -2,18 +2,27 @@ package test_todo_usecase
 
 import (
 	pkg_config "backend/config"
+	domain_todo "backend/internal/domain/todo"
+	infrastructure_todo "backend/internal/infrastructure/todo"
 	pkg_logger "backend/internal/pkg/logger"
 	test_todo_repository "backend/internal/test/todo/infrastructure"
 	usecase_todo "backend/internal/usecase/todo"
 	"os"
 	"testing"
+	"time"
 )
 
 // テストの変数(グローバル用)
 var (
-	logger   *pkg_logger.AppLogger
-	useCase  usecase_todo.ITodoUsecase
-	mockRepo *test_todo_repository.MockTodoRepository
+	logger        *pkg_logger.AppLogger
+	useCase       usecase_todo.ITodoUsecase
+	searchUseCase usecase_todo.ITodoSearchUsecase
+	mockRepo      *test_todo_repository.MockTodoRepository
+	// 繰り返しのTodo
+	recurrenceUseCase  usecase_todo.ITodoRecurrenceUsecase
+	mockRecurrenceRepo *test_todo_repository.MockTodoRecurrenceRepository
+	// 操作するユーザー(ワークスペースは指定しない)
+	scope = domain_todo.Scope{UserID: "1"}
 )
 
 // テストのメイン関数
@@ -28,7 +37,14 @@ func TestMain(m *testing.M) {
 
 	// モック
 	mockRepo = new(test_todo_repository.MockTodoRepository)
-	useCase = usecase_todo.NewTodoUsecase(logger, mockRepo)
+	mockRecurrenceRepo = new(test_todo_repository.MockTodoRecurrenceRepository)
+	useCase = usecase_todo.NewTodoUsecase(logger, mockRepo, mockRecurrenceRepo)
+	recurrenceUseCase = usecase_todo.NewTodoRecurrenceUsecase(logger, mockRepo, mockRecurrenceRepo, usecase_todo.RecurrenceOptions{
+		Horizon:   7 * 24 * time.Hour,
+		BatchSize: 2,
+	})
+	// 検索はDBを使わないインメモリ実装で検証する
+	searchUseCase = usecase_todo.NewTodoSearchUsecase(logger, infrastructure_todo.NewTodoSearchMemoryRepository(logger, mockRepo))
 
 	// テスト実行
 	code := m.Run()
@@ -0,0 +1,66 @@
+package test_totp
+
+import (
+	pkg_totp "backend/internal/pkg/totp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 付録Bのシークレット("12345678901234567890")
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// RFC 6238 のテストベクタ(SHA-1, 8桁の下6桁)
+func TestCode(t *testing.T) {
+	cases := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1111111111: "050471",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, expected := range cases {
+		code, err := pkg_totp.Code(rfcSecret, pkg_totp.Step(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, unix)
+	}
+}
+
+// 前後1ステップのずれまで受け付け、一致したステップを返す
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := pkg_totp.Step(now)
+
+	for _, offset := range []int64{-1, 0, 1} {
+		code, _ := pkg_totp.Code(rfcSecret, step+offset)
+		matched, ok := pkg_totp.Validate(rfcSecret, code, now)
+		assert.True(t, ok, offset)
+		assert.Equal(t, step+offset, matched, offset)
+	}
+
+	code, _ := pkg_totp.Code(rfcSecret, step+2)
+	_, ok := pkg_totp.Validate(rfcSecret, code, now)
+	assert.False(t, ok)
+	_, ok = pkg_totp.Validate(rfcSecret, "12345", now)
+	assert.False(t, ok)
+	_, ok = pkg_totp.Validate("not base32!", "123456", now)
+	assert.False(t, ok)
+}
+
+// シークレットの生成と登録用のURI
+func TestProvisioningURI(t *testing.T) {
+	secret, err := pkg_totp.GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+	_, err = pkg_totp.Code(secret, 1)
+	assert.NoError(t, err)
+
+	uri := pkg_totp.ProvisioningURI("My App", "alice@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/My%20App:alice@example.com?"), uri)
+	assert.Contains(t, uri, "secret="+secret)
+	assert.Contains(t, uri, "issuer=My+App")
+	assert.Contains(t, uri, "digits=6")
+	assert.Contains(t, uri, "period=30")
+}
@@ -12,8 +12,8 @@ type MockUserRepository struct {
 }
 
 // GetAllUsersのモック
-func (m *MockUserRepository) GetAllUsers() ([]domain_user.Users, error) {
-	args := m.Called()
+func (m *MockUserRepository) GetAllUsers(userId string) ([]domain_user.Users, error) {
+	args := m.Called(userId)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
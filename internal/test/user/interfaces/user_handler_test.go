@@ -25,13 +25,15 @@ func TestGetAllUsers(t *testing.T) {
 		{ID: "2", Username: "Bob", Email: "bob@example.com", Password: "", CreatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
 	}
 
-	// モックの挙動を設定
-	mockUsecase.On("GetAllUsers").Return(users, nil)
+	// モックの挙動を設定(リクエストのユーザーを渡す)
+	mockUsecase.On("GetAllUsers", "1").Return(users, nil)
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
 	request := httptest.NewRequest("GET", "/api/users", nil)
-	handler.GetAllUsers(echo.New().NewContext(request, response))
+	c := echo.New().NewContext(request, response)
+	c.Set("userId", "1")
+	handler.GetAllUsers(c)
 
 	// 検証
 	assert.Equal(t, http.StatusOK, response.Code)
@@ -53,7 +55,7 @@ func TestGetAllUsersEmpty(t *testing.T) {
 	users := []domain_user.Users{}
 
 	// モックの挙動を設定
-	mockUsecase.On("GetAllUsers").Return(users, nil)
+	mockUsecase.On("GetAllUsers", "").Return(users, nil)
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
@@ -74,7 +76,7 @@ func TestGetAllUsersError(t *testing.T) {
 	mockUsecase.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockUsecase.On("GetAllUsers").Return(nil, errors.New("error"))
+	mockUsecase.On("GetAllUsers", "").Return(nil, errors.New("error"))
 
 	// ハンドラのメソッドを呼び出し
 	response := httptest.NewRecorder()
@@ -12,8 +12,8 @@ type MockUserUsecase struct {
 }
 
 // GetAllUsersのモック
-func (m *MockUserUsecase) GetAllUsers() ([]domain_user.Users, error) {
-	args := m.Called()
+func (m *MockUserUsecase) GetAllUsers(userId string) ([]domain_user.Users, error) {
+	args := m.Called(userId)
 
 	// `nil` チェックを追加
 	if args.Get(0) == nil {
@@ -20,10 +20,10 @@ func TestGetAllUsers(t *testing.T) {
 	}
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllUsers").Return(users, nil)
+	mockRepo.On("GetAllUsers", "1").Return(users, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllUsers()
+	result, err := useCase.GetAllUsers("1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -43,10 +43,10 @@ func TestGetAllUsersEmpty(t *testing.T) {
 	users := []domain_user.Users{}
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllUsers").Return(users, nil)
+	mockRepo.On("GetAllUsers", "1").Return(users, nil)
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllUsers()
+	result, err := useCase.GetAllUsers("1")
 
 	// 検証
 	assert.NoError(t, err)
@@ -62,10 +62,10 @@ func TestGetAllUsersError(t *testing.T) {
 	mockRepo.ExpectedCalls = nil
 
 	// モックの挙動を設定
-	mockRepo.On("GetAllUsers").Return(([]domain_user.Users)(nil), errors.New("error"))
+	mockRepo.On("GetAllUsers", "1").Return(([]domain_user.Users)(nil), errors.New("error"))
 
 	// ユースケースのメソッドを呼び出し
-	result, err := useCase.GetAllUsers()
+	result, err := useCase.GetAllUsers("1")
 
 	// 検証
 	assert.Error(t, err)
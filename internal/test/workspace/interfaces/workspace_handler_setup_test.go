@@ -0,0 +1,42 @@
+package test_workspace_handler
+
+import (
+	pkg_config "backend/config"
+	interfaces_workspace "backend/internal/interfaces/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	test_workspace_usecase "backend/internal/test/workspace/usecase"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger      *pkg_logger.AppLogger
+	handler     *interfaces_workspace.WorkspaceHandler
+	mockUsecase *test_workspace_usecase.MockWorkspaceUsecase
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// モック(トークンはワークスペースIDを含む固定の文字列を返す)
+	mockUsecase = new(test_workspace_usecase.MockWorkspaceUsecase)
+	handler = interfaces_workspace.NewWorkspaceHandler(logger, mockUsecase, func(c echo.Context, workspaceId string) (string, error) {
+		return "token-" + workspaceId, nil
+	})
+
+	// テスト実行
+	code := m.Run()
+
+	// 終了コードを返す
+	os.Exit(code)
+}
@@ -0,0 +1,210 @@
+package test_workspace_handler
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// ハンドラを呼び出す(パスパラメータはid, userIdの順に設定する)
+func call(method string, body string, h echo.HandlerFunc, values map[string]any, params ...string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(method, "/api/workspaces", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept-Language", "en-US")
+	ctx := echo.New().NewContext(request, response)
+	ctx.SetParamNames([]string{"id", "userId"}[:len(params)]...)
+	ctx.SetParamValues(params...)
+	for k, v := range values {
+		ctx.Set(k, v)
+	}
+	h(ctx)
+	return response
+}
+
+// ログイン中のユーザー
+var owner = map[string]any{"userId": "user-1"}
+
+// モックをリセットする
+func resetMock() {
+	mockUsecase.ExpectedCalls = nil
+	mockUsecase.Calls = nil
+}
+
+// GetWorkspacesのテスト
+func TestGetWorkspaces(t *testing.T) {
+	resetMock()
+	mockUsecase.On("GetWorkspaces", "user-1").Return([]domain_workspace.Membership{
+		{Workspace: domain_workspace.Workspace{ID: "ws-1", Name: "Personal", Personal: true}, Role: domain_workspace.RoleOwner},
+	}, nil)
+
+	response := call("GET", "", handler.GetWorkspaces, owner)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"id":"ws-1"`)
+	assert.Contains(t, response.Body.String(), `"role":"owner"`)
+	mockUsecase.AssertExpectations(t)
+}
+
+// CreateWorkspaceのテスト
+func TestCreateWorkspace(t *testing.T) {
+	resetMock()
+	mockUsecase.On("CreateWorkspace", "user-1", "Team").Return(domain_workspace.Workspace{ID: "ws-2", Name: "Team"}, nil)
+
+	response := call("POST", `{"name": "Team"}`, handler.CreateWorkspace, owner)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Contains(t, response.Body.String(), `"id":"ws-2"`)
+	mockUsecase.AssertExpectations(t)
+}
+
+// CreateWorkspaceのテスト(異常系 - 名前が空)
+func TestCreateWorkspaceErrorValidation(t *testing.T) {
+	resetMock()
+
+	response := call("POST", `{"name": "  "}`, handler.CreateWorkspace, owner)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"name"`)
+	mockUsecase.AssertNotCalled(t, "CreateWorkspace")
+}
+
+// GetMembersのテスト(所属していない場合は404)
+func TestGetMembersErrorNotFound(t *testing.T) {
+	resetMock()
+	mockUsecase.On("GetMembers", "user-1", "ws-9").Return(nil, errors.New("workspace not found"))
+
+	response := call("GET", "", handler.GetMembers, owner, "ws-9")
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.JSONEq(t, `{"message":"Workspace not found"}`, response.Body.String())
+	mockUsecase.AssertExpectations(t)
+}
+
+// UpdateMemberのテスト
+func TestUpdateMember(t *testing.T) {
+	resetMock()
+	mockUsecase.On("UpdateMemberRole", "user-1", "ws-1", "user-2", domain_workspace.RoleViewer).Return(nil)
+	mockUsecase.On("UpdateMemberRole", "user-2", "ws-1", "user-3", domain_workspace.RoleViewer).Return(errors.New("permission denied"))
+	mockUsecase.On("UpdateMemberRole", "user-1", "ws-1", "user-1", domain_workspace.RoleViewer).Return(errors.New("workspace must have an owner"))
+
+	response := call("PUT", `{"role": "viewer"}`, handler.UpdateMember, owner, "ws-1", "user-2")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = call("PUT", `{"role": "viewer"}`, handler.UpdateMember, map[string]any{"userId": "user-2"}, "ws-1", "user-3")
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = call("PUT", `{"role": "viewer"}`, handler.UpdateMember, owner, "ws-1", "user-1")
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	// 不正な役割
+	response = call("PUT", `{"role": "admin"}`, handler.UpdateMember, owner, "ws-1", "user-2")
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"role"`)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+// RemoveMemberのテスト
+func TestRemoveMember(t *testing.T) {
+	resetMock()
+	mockUsecase.On("RemoveMember", "user-1", "ws-1", "user-2").Return(nil)
+	mockUsecase.On("RemoveMember", "user-1", "ws-1", "user-9").Return(errors.New("member not found"))
+
+	response := call("DELETE", "", handler.RemoveMember, owner, "ws-1", "user-2")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+
+	response = call("DELETE", "", handler.RemoveMember, owner, "ws-1", "user-9")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.JSONEq(t, `{"message":"Member not found"}`, response.Body.String())
+
+	mockUsecase.AssertExpectations(t)
+}
+
+// Inviteのテスト(役割の既定は編集者、言語はAccept-Language)
+func TestInvite(t *testing.T) {
+	resetMock()
+	mockUsecase.On("Invite", "user-1", "ws-1", "bob@example.com", domain_workspace.RoleEditor, "en-US").
+		Return(domain_workspace.Invitation{ID: "inv-1", WorkspaceID: "ws-1", Email: "bob@example.com", Role: domain_workspace.RoleEditor, TokenHash: "hash"}, nil)
+
+	response := call("POST", `{"email": "bob@example.com"}`, handler.Invite, owner, "ws-1")
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+	assert.Contains(t, response.Body.String(), `"id":"inv-1"`)
+	// トークンのハッシュはレスポンスに含めない
+	assert.NotContains(t, response.Body.String(), "hash")
+	mockUsecase.AssertExpectations(t)
+}
+
+// Inviteのテスト(異常系)
+func TestInviteErrors(t *testing.T) {
+	resetMock()
+	mockUsecase.On("Invite", "user-1", "ws-1", "bad", domain_workspace.RoleViewer, "ja").Return(nil, errors.New("invalid email format"))
+	mockUsecase.On("Invite", "user-2", "ws-1", "bob@example.com", domain_workspace.RoleViewer, "ja").Return(nil, errors.New("permission denied"))
+
+	response := call("POST", `{"email": "bad", "role": "viewer", "lang": "ja"}`, handler.Invite, owner, "ws-1")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = call("POST", `{"email": "bob@example.com", "role": "viewer", "lang": "ja"}`, handler.Invite, map[string]any{"userId": "user-2"}, "ws-1")
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = call("POST", `{"role": "viewer"}`, handler.Invite, owner, "ws-1")
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	assert.Contains(t, response.Body.String(), `"email"`)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+// AcceptInvitationのテスト
+func TestAcceptInvitation(t *testing.T) {
+	resetMock()
+	mockUsecase.On("AcceptInvitation", "user-2", "good").Return(domain_workspace.Membership{Workspace: domain_workspace.Workspace{ID: "ws-1"}, Role: domain_workspace.RoleViewer}, nil)
+	mockUsecase.On("AcceptInvitation", "user-2", "used").Return(nil, errors.New("invalid or expired invitation"))
+	mockUsecase.On("AcceptInvitation", "user-1", "good").Return(nil, errors.New("already a member"))
+	mockUsecase.On("AcceptInvitation", "user-2", "forwarded").Return(nil, errors.New("permission denied"))
+	member := map[string]any{"userId": "user-2"}
+
+	response := call("POST", `{"token": "good"}`, handler.AcceptInvitation, member)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"role":"viewer"`)
+
+	response = call("POST", `{"token": "used"}`, handler.AcceptInvitation, member)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = call("POST", `{"token": "good"}`, handler.AcceptInvitation, owner)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	response = call("POST", `{"token": "forwarded"}`, handler.AcceptInvitation, member)
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = call("POST", `{}`, handler.AcceptInvitation, member)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	mockUsecase.AssertExpectations(t)
+}
+
+// Switchのテスト(所属するワークスペースのトークンを返す)
+func TestSwitch(t *testing.T) {
+	resetMock()
+	mockUsecase.On("Authorize", "user-1", "ws-1").Return(domain_workspace.RoleEditor, nil)
+	mockUsecase.On("Authorize", "user-1", "ws-9").Return(domain_workspace.Role(""), errors.New("workspace not found"))
+
+	response := call("POST", "", handler.Switch, owner, "ws-1")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"token":"token-ws-1","workspace_id":"ws-1","role":"editor"}`, response.Body.String())
+
+	response = call("POST", "", handler.Switch, owner, "ws-9")
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// APIキーはトークンを発行し直せない
+	response = call("POST", "", handler.Switch, map[string]any{"userId": "user-1", "apiKeyId": "key-1"}, "ws-1")
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	mockUsecase.AssertExpectations(t)
+}
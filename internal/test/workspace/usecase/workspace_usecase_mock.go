@@ -0,0 +1,91 @@
+package test_workspace_usecase
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// モックのワークスペースユースケース作成
+type MockWorkspaceUsecase struct {
+	mock.Mock
+}
+
+// CreateWorkspaceのモック
+func (m *MockWorkspaceUsecase) CreateWorkspace(userId string, name string) (domain_workspace.Workspace, error) {
+	args := m.Called(userId, name)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_workspace.Workspace{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_workspace.Workspace), args.Error(1)
+}
+
+// GetWorkspacesのモック
+func (m *MockWorkspaceUsecase) GetWorkspaces(userId string) ([]domain_workspace.Membership, error) {
+	args := m.Called(userId)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_workspace.Membership), args.Error(1)
+}
+
+// Authorizeのモック
+func (m *MockWorkspaceUsecase) Authorize(userId string, workspaceId string) (domain_workspace.Role, error) {
+	args := m.Called(userId, workspaceId)
+	return args.Get(0).(domain_workspace.Role), args.Error(1)
+}
+
+// GetMembersのモック
+func (m *MockWorkspaceUsecase) GetMembers(userId string, workspaceId string) ([]domain_workspace.Member, error) {
+	args := m.Called(userId, workspaceId)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+
+	return args.Get(0).([]domain_workspace.Member), args.Error(1)
+}
+
+// UpdateMemberRoleのモック
+func (m *MockWorkspaceUsecase) UpdateMemberRole(actorId string, workspaceId string, userId string, role domain_workspace.Role) error {
+	args := m.Called(actorId, workspaceId, userId, role)
+	return args.Error(0)
+}
+
+// RemoveMemberのモック
+func (m *MockWorkspaceUsecase) RemoveMember(actorId string, workspaceId string, userId string) error {
+	args := m.Called(actorId, workspaceId, userId)
+	return args.Error(0)
+}
+
+// Inviteのモック
+func (m *MockWorkspaceUsecase) Invite(ctx context.Context, actorId string, workspaceId string, email string, role domain_workspace.Role, lang string) (domain_workspace.Invitation, error) {
+	args := m.Called(actorId, workspaceId, email, role, lang)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_workspace.Invitation{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_workspace.Invitation), args.Error(1)
+}
+
+// AcceptInvitationのモック
+func (m *MockWorkspaceUsecase) AcceptInvitation(userId string, token string) (domain_workspace.Membership, error) {
+	args := m.Called(userId, token)
+
+	// `nil` チェックを追加
+	if args.Get(0) == nil {
+		return domain_workspace.Membership{}, args.Error(1)
+	}
+
+	return args.Get(0).(domain_workspace.Membership), args.Error(1)
+}
@@ -0,0 +1,61 @@
+package test_workspace_usecase
+
+import (
+	pkg_config "backend/config"
+	infrastructure_workspace "backend/internal/infrastructure/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_mail "backend/internal/pkg/mail"
+	repository_workspace "backend/internal/repository/workspace"
+	test_auth_infrastructure "backend/internal/test/auth/infrastructure"
+	usecase_workspace "backend/internal/usecase/workspace"
+	"os"
+	"testing"
+	"time"
+)
+
+// テストの変数(グローバル用)
+var (
+	logger        *pkg_logger.AppLogger
+	useCase       usecase_workspace.IWorkspaceUsecase
+	workspaceRepo repository_workspace.IWorkspaceRepository
+	accountRepo   *test_auth_infrastructure.MockAccountRepository
+	mailer        pkg_mail.Mailer
+	templates     *pkg_mail.Templates
+	mailDir       string
+)
+
+// テストのメイン関数
+func TestMain(m *testing.M) {
+	// 設定
+	appConfig := pkg_config.NewAppConfig()
+	appConfig.SetUpEnv()
+
+	// ログ
+	logger = pkg_logger.NewAppLogger()
+	logger.SetUpLogger()
+
+	// メールはファイルに書き出して検証する
+	mailDir, _ = os.MkdirTemp("", "mail")
+	var err error
+	templates, err = pkg_mail.NewTemplates("ja")
+	if err != nil {
+		logger.ErrorLog.Fatalf("Failed to load mail templates: %v", err)
+	}
+	mailer = pkg_mail.NewFileMailer(logger, mailDir, "no-reply@example.com")
+
+	// リポジトリはDBを使わないインメモリ実装で検証する
+	workspaceRepo = infrastructure_workspace.NewWorkspaceMemoryRepository(logger)
+	accountRepo = new(test_auth_infrastructure.MockAccountRepository)
+	useCase = usecase_workspace.NewWorkspaceUsecase(logger, workspaceRepo, accountRepo, mailer, templates, usecase_workspace.WorkspaceOptions{
+		InvitationTTL: time.Hour,
+		LinkBaseURL:   "http://localhost:3000",
+	})
+
+	// テスト実行
+	code := m.Run()
+
+	os.RemoveAll(mailDir)
+
+	// 終了コードを返す
+	os.Exit(code)
+}
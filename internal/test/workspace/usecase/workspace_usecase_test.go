@@ -0,0 +1,301 @@
+package test_workspace_usecase
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	domain_workspace "backend/internal/domain/workspace"
+	test_stub "backend/internal/test/stub"
+	usecase_workspace "backend/internal/usecase/workspace"
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// テスト用のワークスペースを作成する(ownerIdがオーナー)
+func newWorkspace(t *testing.T, ownerId string, members map[string]domain_workspace.Role) domain_workspace.Workspace {
+	workspace, err := useCase.CreateWorkspace(ownerId, "Team "+ownerId)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	for userId, role := range members {
+		_, err := workspaceRepo.AddMember(domain_workspace.Member{WorkspaceID: workspace.ID, UserID: userId, Role: role})
+		assert.NoError(t, err)
+	}
+	return workspace
+}
+
+// 最後に書き出されたメールとトークン
+func lastMail(t *testing.T) (test_stub.Mail, string) {
+	mails, err := test_stub.ReadMails(mailDir)
+	if !assert.NoError(t, err) || !assert.NotEmpty(t, mails) {
+		t.FailNow()
+	}
+	m := mails[len(mails)-1]
+	token, err := url.QueryUnescape(test_stub.MailToken(m))
+	assert.NoError(t, err)
+	return m, token
+}
+
+// テスト用のアカウントを登録する(verifiedならメールアドレスを確認済みにする)
+func newAccount(userId string, email string, verified bool) {
+	account := domain_auth.Account{ID: userId, Email: email}
+	if verified {
+		now := time.Now()
+		account.EmailVerifiedAt = &now
+	}
+	accountRepo.On("GetAccountById", userId).Return(account, nil)
+}
+
+// CreateWorkspaceのテスト(作成したユーザーがオーナーになる)
+func TestCreateWorkspace(t *testing.T) {
+	workspace, err := useCase.CreateWorkspace("user-c1", "  Project X  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "Project X", workspace.Name)
+	assert.NotEmpty(t, workspace.ID)
+	assert.False(t, workspace.Personal)
+
+	memberships, err := useCase.GetWorkspaces("user-c1")
+	assert.NoError(t, err)
+	if assert.Len(t, memberships, 1) {
+		assert.Equal(t, workspace.ID, memberships[0].ID)
+		assert.Equal(t, domain_workspace.RoleOwner, memberships[0].Role)
+	}
+
+	role, err := useCase.Authorize("user-c1", workspace.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain_workspace.RoleOwner, role)
+}
+
+// CreateWorkspaceのテスト(異常系 - 名前が不正)
+func TestCreateWorkspaceInvalidName(t *testing.T) {
+	_, err := useCase.CreateWorkspace("user-c2", "   ")
+	assert.EqualError(t, err, "name is empty")
+
+	_, err = useCase.CreateWorkspace("user-c2", strings.Repeat("あ", domain_workspace.MaxNameLength+1))
+	assert.EqualError(t, err, "name is too long")
+
+	memberships, err := useCase.GetWorkspaces("user-c2")
+	assert.NoError(t, err)
+	assert.Empty(t, memberships)
+}
+
+// Authorizeのテスト(所属していないワークスペースは存在しないものとして扱う)
+func TestAuthorizeNotMember(t *testing.T) {
+	workspace := newWorkspace(t, "user-a1", nil)
+
+	_, err := useCase.Authorize("user-a2", workspace.ID)
+	assert.EqualError(t, err, "workspace not found")
+	_, err = useCase.GetMembers("user-a2", workspace.ID)
+	assert.EqualError(t, err, "workspace not found")
+
+	_, err = useCase.Authorize("user-a1", "missing")
+	assert.EqualError(t, err, "workspace not found")
+}
+
+// Inviteのテスト(招待のメールを送り、リンクのトークンで参加する)
+func TestInviteAndAccept(t *testing.T) {
+	workspace := newWorkspace(t, "user-i1", nil)
+
+	invitation, err := useCase.Invite(context.Background(), "user-i1", workspace.ID, "Bob@Example.com", domain_workspace.RoleViewer, "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "bob@example.com", invitation.Email)
+	assert.Equal(t, domain_workspace.RoleViewer, invitation.Role)
+	assert.Equal(t, "user-i1", invitation.InvitedBy)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), invitation.ExpiresAt, time.Minute)
+
+	m, token := lastMail(t)
+	assert.Equal(t, "bob@example.com", m.To)
+	assert.Equal(t, `You're invited to the workspace "Team user-i1"`, m.Subject)
+	assert.Contains(t, m.Body, "http://localhost:3000/workspaces/join?token=")
+	assert.NotEmpty(t, token)
+
+	// メールアドレスは大文字小文字を区別しない
+	newAccount("user-i2", "Bob@Example.com", true)
+	membership, err := useCase.AcceptInvitation("user-i2", token)
+	assert.NoError(t, err)
+	assert.Equal(t, workspace.ID, membership.ID)
+	assert.Equal(t, domain_workspace.RoleViewer, membership.Role)
+
+	role, err := useCase.Authorize("user-i2", workspace.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain_workspace.RoleViewer, role)
+
+	// 招待は1回のみ使える
+	_, err = useCase.AcceptInvitation("user-i3", token)
+	assert.EqualError(t, err, "invalid or expired invitation")
+}
+
+// Inviteのテスト(異常系)
+func TestInviteErrors(t *testing.T) {
+	workspace := newWorkspace(t, "user-i4", map[string]domain_workspace.Role{"user-i5": domain_workspace.RoleEditor})
+	ctx := context.Background()
+
+	_, err := useCase.Invite(ctx, "user-i4", workspace.ID, "not-an-email", domain_workspace.RoleEditor, "ja")
+	assert.EqualError(t, err, "invalid email format")
+
+	_, err = useCase.Invite(ctx, "user-i4", workspace.ID, "carol@example.com", "admin", "ja")
+	assert.EqualError(t, err, "invalid role")
+
+	// オーナー以外は招待できない
+	_, err = useCase.Invite(ctx, "user-i5", workspace.ID, "carol@example.com", domain_workspace.RoleEditor, "ja")
+	assert.EqualError(t, err, "permission denied")
+
+	_, err = useCase.Invite(ctx, "user-i6", workspace.ID, "carol@example.com", domain_workspace.RoleEditor, "ja")
+	assert.EqualError(t, err, "workspace not found")
+}
+
+// AcceptInvitationのテスト(異常系)
+func TestAcceptInvitationErrors(t *testing.T) {
+	workspace := newWorkspace(t, "user-i7", nil)
+
+	_, err := useCase.AcceptInvitation("user-i8", "unknown")
+	assert.EqualError(t, err, "invalid or expired invitation")
+
+	// 既に所属している場合
+	_, err = useCase.Invite(context.Background(), "user-i7", workspace.ID, "owner@example.com", domain_workspace.RoleEditor, "ja")
+	assert.NoError(t, err)
+	_, token := lastMail(t)
+	newAccount("user-i7", "owner@example.com", true)
+	_, err = useCase.AcceptInvitation("user-i7", token)
+	assert.EqualError(t, err, "already a member")
+
+	// 期限切れの招待
+	expired := usecase_workspace.NewWorkspaceUsecase(logger, workspaceRepo, accountRepo, mailer, templates, usecase_workspace.WorkspaceOptions{
+		InvitationTTL: -time.Minute,
+		LinkBaseURL:   "http://localhost:3000",
+	})
+	_, err = expired.Invite(context.Background(), "user-i7", workspace.ID, "late@example.com", domain_workspace.RoleEditor, "ja")
+	assert.NoError(t, err)
+	_, token = lastMail(t)
+	_, err = useCase.AcceptInvitation("user-i8", token)
+	assert.EqualError(t, err, "invalid or expired invitation")
+}
+
+// AcceptInvitationのテスト(追加に失敗した場合は招待を使用済みにしない)
+func TestAcceptInvitationKeepsInvitation(t *testing.T) {
+	workspace := newWorkspace(t, "user-k1", map[string]domain_workspace.Role{"user-k2": domain_workspace.RoleViewer})
+	_, err := useCase.Invite(context.Background(), "user-k1", workspace.ID, "dave@example.com", domain_workspace.RoleEditor, "ja")
+	assert.NoError(t, err)
+	_, token := lastMail(t)
+
+	newAccount("user-k2", "dave@example.com", true)
+	_, err = useCase.AcceptInvitation("user-k2", token)
+	assert.EqualError(t, err, "already a member")
+
+	// 脱退後は同じ招待で参加できる
+	assert.NoError(t, useCase.RemoveMember("user-k2", workspace.ID, "user-k2"))
+	membership, err := useCase.AcceptInvitation("user-k2", token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain_workspace.RoleEditor, membership.Role)
+}
+
+// AcceptInvitationのテスト(異常系 - 招待先と異なるユーザー)
+func TestAcceptInvitationEmailMismatch(t *testing.T) {
+	workspace := newWorkspace(t, "user-a1", nil)
+	_, err := useCase.Invite(context.Background(), "user-a1", workspace.ID, "carol@example.com", domain_workspace.RoleOwner, "ja")
+	assert.NoError(t, err)
+	_, token := lastMail(t)
+
+	// 転送されたリンクでは参加できない
+	newAccount("user-a2", "mallory@example.com", true)
+	_, err = useCase.AcceptInvitation("user-a2", token)
+	assert.EqualError(t, err, "permission denied")
+
+	// メールアドレスが確認済みでない場合も参加できない
+	newAccount("user-a3", "carol@example.com", false)
+	_, err = useCase.AcceptInvitation("user-a3", token)
+	assert.EqualError(t, err, "permission denied")
+
+	_, err = useCase.Authorize("user-a2", workspace.ID)
+	assert.EqualError(t, err, "workspace not found")
+
+	// 招待は使用済みにならず、本人は参加できる
+	newAccount("user-a4", "carol@example.com", true)
+	membership, err := useCase.AcceptInvitation("user-a4", token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain_workspace.RoleOwner, membership.Role)
+}
+
+// UpdateMemberRoleのテスト
+func TestUpdateMemberRole(t *testing.T) {
+	workspace := newWorkspace(t, "user-r1", map[string]domain_workspace.Role{
+		"user-r2": domain_workspace.RoleEditor,
+		"user-r3": domain_workspace.RoleViewer,
+	})
+
+	assert.NoError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r3", domain_workspace.RoleEditor))
+	role, _ := useCase.Authorize("user-r3", workspace.ID)
+	assert.Equal(t, domain_workspace.RoleEditor, role)
+
+	// オーナー以外は変更できない
+	assert.EqualError(t, useCase.UpdateMemberRole("user-r2", workspace.ID, "user-r3", domain_workspace.RoleViewer), "permission denied")
+	assert.EqualError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r3", "admin"), "invalid role")
+	assert.EqualError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r9", domain_workspace.RoleViewer), "member not found")
+
+	// 最後のオーナーは降格できない
+	assert.EqualError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r1", domain_workspace.RoleEditor), "workspace must have an owner")
+
+	// 別のオーナーが居れば降格できる
+	assert.NoError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r2", domain_workspace.RoleOwner))
+	assert.NoError(t, useCase.UpdateMemberRole("user-r1", workspace.ID, "user-r1", domain_workspace.RoleEditor))
+}
+
+// RemoveMemberのテスト
+func TestRemoveMember(t *testing.T) {
+	workspace := newWorkspace(t, "user-m1", map[string]domain_workspace.Role{
+		"user-m2": domain_workspace.RoleEditor,
+		"user-m3": domain_workspace.RoleViewer,
+	})
+
+	// オーナー以外は他のメンバーを削除できない
+	assert.EqualError(t, useCase.RemoveMember("user-m2", workspace.ID, "user-m3"), "permission denied")
+
+	// 本人は脱退できる
+	assert.NoError(t, useCase.RemoveMember("user-m3", workspace.ID, "user-m3"))
+	_, err := useCase.Authorize("user-m3", workspace.ID)
+	assert.EqualError(t, err, "workspace not found")
+
+	// 最後のオーナーは脱退できない
+	assert.EqualError(t, useCase.RemoveMember("user-m1", workspace.ID, "user-m1"), "workspace must have an owner")
+
+	assert.NoError(t, useCase.RemoveMember("user-m1", workspace.ID, "user-m2"))
+	assert.EqualError(t, useCase.RemoveMember("user-m1", workspace.ID, "user-m2"), "member not found")
+
+	members, err := useCase.GetMembers("user-m1", workspace.ID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+}
+
+// UpdateMemberRoleのテスト(2人のオーナーが同時に降格しあってもオーナーが残る)
+func TestUpdateMemberRoleConcurrent(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		workspace := newWorkspace(t, "user-c1", map[string]domain_workspace.Role{"user-c2": domain_workspace.RoleOwner})
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		for j, pair := range [][2]string{{"user-c1", "user-c2"}, {"user-c2", "user-c1"}} {
+			wg.Add(1)
+			go func(j int, actorId string, userId string) {
+				defer wg.Done()
+				errs[j] = useCase.UpdateMemberRole(actorId, workspace.ID, userId, domain_workspace.RoleEditor)
+			}(j, pair[0], pair[1])
+		}
+		wg.Wait()
+
+		members, err := useCase.GetMembers("user-c1", workspace.ID)
+		assert.NoError(t, err)
+		owners := 0
+		for _, m := range members {
+			if m.Role == domain_workspace.RoleOwner {
+				owners++
+			}
+		}
+		if !assert.Equal(t, 1, owners, "errors: %v", errs) {
+			return
+		}
+	}
+}
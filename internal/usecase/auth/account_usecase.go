@@ -0,0 +1,236 @@
+package usecase_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_mail "backend/internal/pkg/mail"
+	repository_auth "backend/internal/repository/auth"
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// アカウントユースケースの設定
+type AccountOptions struct {
+	TokenSecret          []byte        // トークンの署名の鍵
+	PasswordResetTTL     time.Duration // パスワードの再設定のトークンの有効期間
+	EmailVerificationTTL time.Duration // メールアドレスの確認のトークンの有効期間
+	LinkBaseURL          string        // メールのリンク先(フロントエンド)のURL
+}
+
+// アカウントユースケース(IF)
+type IAccountUsecase interface {
+	// パスワードの再設定のメールを送る(送信はリクエストの外で行う)
+	RequestPasswordReset(ctx context.Context, email string, lang string) error
+	// トークンを確認してパスワードを再設定する
+	ResetPassword(token string, password string) error
+	// メールアドレスの確認のメールを送る
+	RequestEmailVerification(ctx context.Context, userId string, lang string) error
+	// トークンを確認してメールアドレスを確認済みにする
+	VerifyEmail(token string) error
+	// 送信中のパスワードの再設定のメールを待つ(終了時に呼ぶ)
+	Wait()
+}
+
+// アカウントユースケース(Impl)
+type AccountUsecase struct {
+	Logger              *pkg_logger.AppLogger
+	accountRepository   repository_auth.IAccountRepository
+	userTokenRepository repository_auth.IUserTokenRepository
+	sessionRepository   repository_auth.ISessionRepository
+	mailer              pkg_mail.Mailer
+	templates           *pkg_mail.Templates
+	options             AccountOptions
+
+	// 送信中のパスワードの再設定のメール
+	sending sync.WaitGroup
+}
+
+// アカウントユースケースのインスタンス化
+func NewAccountUsecase(l *pkg_logger.AppLogger, ar repository_auth.IAccountRepository, tr repository_auth.IUserTokenRepository, sr repository_auth.ISessionRepository, mailer pkg_mail.Mailer, templates *pkg_mail.Templates, opts AccountOptions) IAccountUsecase {
+	return &AccountUsecase{
+		Logger:              l,
+		accountRepository:   ar,
+		userTokenRepository: tr,
+		sessionRepository:   sr,
+		mailer:              mailer,
+		templates:           templates,
+		options:             opts,
+	}
+}
+
+// メールの本文に渡す値
+type tokenMail struct {
+	Name      string
+	Email     string
+	Link      string
+	ExpiresAt time.Time
+}
+
+// パスワードの再設定のメールを送る
+// メールアドレスが登録されているかどうかは返さない(存在しない場合も、送信に失敗した場合も成功とする)
+// 応答時間で登録の有無が分からないよう、アカウントの取得とメールの送信はリクエストの外で行う
+func (u *AccountUsecase) RequestPasswordReset(ctx context.Context, email string, lang string) error {
+	u.Logger.InfoLog.Println("RequestPasswordReset called")
+
+	matched, err := regexp.MatchString(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`, email)
+	if err != nil || !matched {
+		u.Logger.ErrorLog.Println("Invalid email format")
+		return errors.New("invalid email format")
+	}
+
+	u.sending.Add(1)
+	go func() {
+		defer u.sending.Done()
+		u.sendPasswordReset(context.WithoutCancel(ctx), email, lang)
+	}()
+	return nil
+}
+
+// パスワードの再設定のメールを送る(失敗はログに残すのみ)
+func (u *AccountUsecase) sendPasswordReset(ctx context.Context, email string, lang string) {
+	account, err := u.accountRepository.GetAccountByEmail(email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			u.Logger.WarnLog.Println("Password reset requested for unknown email")
+			return
+		}
+		u.Logger.ErrorLog.Printf("Failed to fetch account for password reset: %v", err)
+		return
+	}
+
+	if err := u.sendToken(ctx, account, domain_auth.PurposePasswordReset, u.options.PasswordResetTTL, "/password/reset", lang); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to send password reset mail: user %s: %v", account.ID, err)
+	}
+}
+
+// 送信中のパスワードの再設定のメールを待つ
+func (u *AccountUsecase) Wait() {
+	u.sending.Wait()
+}
+
+// トークンを確認してパスワードを再設定する
+// 再設定したユーザーのセッションは全て失効させる
+func (u *AccountUsecase) ResetPassword(token string, password string) error {
+	u.Logger.InfoLog.Println("ResetPassword called")
+
+	if len([]rune(password)) < domain_auth.MinPasswordLength {
+		return errors.New("password is too short")
+	}
+
+	t, err := u.consume(domain_auth.PurposePasswordReset, token)
+	if err != nil {
+		return err
+	}
+	if err := u.accountRepository.UpdatePassword(t.UserID, password); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to update password: %v", err)
+		return errors.New("failed to reset password")
+	}
+	// 漏れたパスワードでログインされている可能性があるため、全ての端末からログアウトさせる
+	if _, err := u.sessionRepository.RevokeSessions(t.UserID, time.Now()); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to revoke sessions: %v", err)
+	}
+
+	u.Logger.InfoLog.Printf("Password reset: user %s", t.UserID)
+	return nil
+}
+
+// メールアドレスの確認のメールを送る
+func (u *AccountUsecase) RequestEmailVerification(ctx context.Context, userId string, lang string) error {
+	u.Logger.InfoLog.Println("RequestEmailVerification called")
+
+	account, err := u.accountRepository.GetAccountById(userId)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return err
+		}
+		return errors.New("failed to send email")
+	}
+	if account.EmailVerifiedAt != nil {
+		return errors.New("email is already verified")
+	}
+
+	return u.sendToken(ctx, account, domain_auth.PurposeEmailVerification, u.options.EmailVerificationTTL, "/email/verify", lang)
+}
+
+// トークンを確認してメールアドレスを確認済みにする
+// トークンを発行した後にメールアドレスが変わった場合は確認しない
+func (u *AccountUsecase) VerifyEmail(token string) error {
+	u.Logger.InfoLog.Println("VerifyEmail called")
+
+	t, err := u.consume(domain_auth.PurposeEmailVerification, token)
+	if err != nil {
+		return err
+	}
+	if err := u.accountRepository.VerifyEmail(t.UserID, t.Email, time.Now()); err != nil {
+		if err.Error() == "user not found" {
+			u.Logger.WarnLog.Printf("Email changed after verification was requested: user %s", t.UserID)
+			return errors.New("invalid or expired token")
+		}
+		return errors.New("failed to verify email")
+	}
+
+	u.Logger.InfoLog.Printf("Email verified: user %s", t.UserID)
+	return nil
+}
+
+// トークンを発行してメールで送る
+func (u *AccountUsecase) sendToken(ctx context.Context, account domain_auth.Account, purpose domain_auth.TokenPurpose, ttl time.Duration, path string, lang string) error {
+	token, hash, err := domain_auth.GenerateUserToken(u.options.TokenSecret, purpose)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate token: %v", err)
+		return errors.New("failed to send email")
+	}
+	expiresAt := time.Now().Add(ttl)
+	if err := u.userTokenRepository.CreateUserToken(domain_auth.UserToken{
+		UserID:    account.ID,
+		Purpose:   purpose,
+		Hash:      hash,
+		Email:     account.Email,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return errors.New("failed to send email")
+	}
+
+	link := strings.TrimSuffix(u.options.LinkBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+	subject, body, err := u.templates.Render(string(purpose), lang, tokenMail{
+		Name:      account.Username,
+		Email:     account.Email,
+		Link:      link,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to render %s mail: %v", purpose, err)
+		return errors.New("failed to send email")
+	}
+	if err := u.mailer.Send(ctx, pkg_mail.Message{To: account.Email, Subject: subject, Body: body}); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to send %s mail: %v", purpose, err)
+		return errors.New("failed to send email")
+	}
+
+	u.Logger.InfoLog.Printf("Sent %s mail: user %s", purpose, account.ID)
+	return nil
+}
+
+// トークンを使用済みにする
+// 署名が一致しない・未発行・使用済み・期限切れは区別しない
+func (u *AccountUsecase) consume(purpose domain_auth.TokenPurpose, token string) (domain_auth.UserToken, error) {
+	hash, ok := domain_auth.VerifyUserToken(u.options.TokenSecret, purpose, token)
+	if !ok {
+		u.Logger.WarnLog.Printf("Invalid %s token signature", purpose)
+		return domain_auth.UserToken{}, errors.New("invalid or expired token")
+	}
+	t, err := u.userTokenRepository.ConsumeUserToken(purpose, hash, time.Now())
+	if err != nil {
+		if err.Error() == "token not found" {
+			u.Logger.WarnLog.Printf("Unknown, used or expired %s token", purpose)
+			return domain_auth.UserToken{}, errors.New("invalid or expired token")
+		}
+		return domain_auth.UserToken{}, errors.New("failed to verify token")
+	}
+	return t, nil
+}
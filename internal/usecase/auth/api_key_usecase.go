@@ -0,0 +1,163 @@
+package usecase_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"time"
+)
+
+// 最終使用日時を更新する間隔(リクエストごとに書き込まない)
+const apiKeyTouchInterval = time.Minute
+
+// APIキーのユースケース(IF)
+type IAPIKeyUsecase interface {
+	// APIキーを作成し、キーそのもの(この時だけ返す)と合わせて返す
+	CreateAPIKey(userId string, req domain_auth.CreateAPIKeyRequest) (domain_auth.APIKey, string, error)
+	// ユーザーのAPIキーを取得
+	GetAPIKeys(userId string) ([]domain_auth.APIKey, error)
+	// ユーザーのAPIキーを失効させる
+	RevokeAPIKey(userId string, id string) error
+	// APIキーを照合する(Scopesは所有者の現在のロールで許可されるもののみ返す)
+	Authenticate(key string) (domain_auth.APIKey, error)
+}
+
+// APIキーのユースケース(Impl)
+type APIKeyUsecase struct {
+	Logger           *pkg_logger.AppLogger
+	apiKeyRepository repository_auth.IAPIKeyRepository
+	roleRepository   repository_auth.IRoleRepository
+}
+
+// APIキーのユースケースのインスタンス化
+func NewAPIKeyUsecase(l *pkg_logger.AppLogger, ar repository_auth.IAPIKeyRepository, rr repository_auth.IRoleRepository) IAPIKeyUsecase {
+	return &APIKeyUsecase{
+		Logger:           l,
+		apiKeyRepository: ar,
+		roleRepository:   rr,
+	}
+}
+
+// APIキーを作成
+// 自分の現在のロールより強い権限は付けられず、サービス用のキーは管理者のみ作成できる
+// ロールはトークンではなく保存先から取得する(トークンの発行後に変更されている場合があるため)
+func (u *APIKeyUsecase) CreateAPIKey(userId string, req domain_auth.CreateAPIKeyRequest) (domain_auth.APIKey, string, error) {
+	u.Logger.InfoLog.Println("CreateAPIKey called")
+
+	role, err := u.roleRepository.GetRole(userId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to fetch role: %v", err)
+		return domain_auth.APIKey{}, "", errors.New("failed to create api key")
+	}
+	if req.Kind == domain_auth.APIKeyService && role != domain_auth.RoleAdmin {
+		u.Logger.ErrorLog.Println("Only admins can create service keys")
+		return domain_auth.APIKey{}, "", errors.New("insufficient permissions")
+	}
+	for _, scope := range req.Scopes {
+		if !domain_auth.RoleAllows(role, scope) {
+			u.Logger.ErrorLog.Printf("Scope exceeds role: %s", scope)
+			return domain_auth.APIKey{}, "", errors.New("insufficient permissions")
+		}
+	}
+
+	plaintext, prefix, hash, err := domain_auth.GenerateAPIKey()
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate api key: %v", err)
+		return domain_auth.APIKey{}, "", errors.New("failed to create api key")
+	}
+	key, err := u.apiKeyRepository.CreateAPIKey(domain_auth.APIKey{
+		UserID:    userId,
+		Name:      req.Name,
+		Kind:      req.Kind,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    req.Scopes,
+		ExpiresAt: *req.ExpiresAt,
+	})
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to create api key: %v", err)
+		return domain_auth.APIKey{}, "", errors.New("failed to create api key")
+	}
+
+	u.Logger.InfoLog.Printf("Created api key %s for user %s", key.Prefix, userId)
+	return key, plaintext, nil
+}
+
+// ユーザーのAPIキーを取得
+func (u *APIKeyUsecase) GetAPIKeys(userId string) ([]domain_auth.APIKey, error) {
+	u.Logger.InfoLog.Println("GetAPIKeys called")
+
+	keys, err := u.apiKeyRepository.GetAPIKeysByUserId(userId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to fetch api keys: %v", err)
+		return nil, errors.New("failed to get api keys")
+	}
+	return keys, nil
+}
+
+// ユーザーのAPIキーを失効させる
+func (u *APIKeyUsecase) RevokeAPIKey(userId string, id string) error {
+	u.Logger.InfoLog.Println("RevokeAPIKey called")
+
+	err := u.apiKeyRepository.RevokeAPIKey(id, userId, time.Now())
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to revoke api key: %v", err)
+		if err.Error() == "api key not found" {
+			return err
+		}
+		return errors.New("failed to revoke api key")
+	}
+
+	u.Logger.InfoLog.Printf("Revoked api key %s", id)
+	return nil
+}
+
+// APIキーを照合する
+// 存在しない・一致しない・失効・期限切れは区別せず"invalid api key"を返す
+func (u *APIKeyUsecase) Authenticate(plaintext string) (domain_auth.APIKey, error) {
+	prefix, ok := domain_auth.ParseAPIKey(plaintext)
+	if !ok {
+		return domain_auth.APIKey{}, errors.New("invalid api key")
+	}
+	key, err := u.apiKeyRepository.GetAPIKeyByPrefix(prefix)
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return domain_auth.APIKey{}, errors.New("invalid api key")
+		}
+		u.Logger.ErrorLog.Printf("Failed to fetch api key: %v", err)
+		return domain_auth.APIKey{}, errors.New("failed to authenticate")
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(domain_auth.HashAPIKey(plaintext))) != 1 || !key.Valid(now) {
+		u.Logger.WarnLog.Printf("Invalid api key: %s", prefix)
+		return domain_auth.APIKey{}, errors.New("invalid api key")
+	}
+
+	// 所有者のロールが変更されている場合、現在のロールを超える権限は使えない
+	role, err := u.roleRepository.GetRole(key.UserID)
+	if err != nil {
+		if err.Error() == "user not found" {
+			u.Logger.WarnLog.Printf("Api key owner not found: %s", prefix)
+			return domain_auth.APIKey{}, errors.New("invalid api key")
+		}
+		u.Logger.ErrorLog.Printf("Failed to fetch role: %v", err)
+		return domain_auth.APIKey{}, errors.New("failed to authenticate")
+	}
+	key.Scopes = slices.DeleteFunc(slices.Clone(key.Scopes), func(scope string) bool {
+		return !domain_auth.RoleAllows(role, scope)
+	})
+
+	// 最終使用日時の更新に失敗しても認証は通す
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval {
+		if err := u.apiKeyRepository.TouchAPIKey(key.ID, now); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to touch api key: %v", err)
+		} else {
+			key.LastUsedAt = &now
+		}
+	}
+	return key, nil
+}
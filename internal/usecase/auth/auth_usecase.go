@@ -1,34 +1,47 @@
 package usecase_auth
 
 import (
+	domain_auth "backend/internal/domain/auth"
 	pkg_logger "backend/internal/pkg/logger"
 	repository_auth "backend/internal/repository/auth"
 	"errors"
 	"regexp"
+	"time"
 )
 
 // 認証ユースケース(IF)
 type IAuthUsecase interface {
 	// ログイン
-	Login(email string, password string) (string, error)
+	Login(email string, password string, ip string) (string, error)
+	// ロックの解除(管理者)
+	Unlock(email string, ip string, actorId string) error
 }
 
 // 認証ユースケース(Impl)
 type AuthUsecase struct {
-	Logger         *pkg_logger.AppLogger
-	authRepository repository_auth.IAuthRepository
+	Logger                 *pkg_logger.AppLogger
+	authRepository         repository_auth.IAuthRepository
+	loginAttemptRepository repository_auth.ILoginAttemptRepository
+	auditLogRepository     repository_auth.IAuditLogRepository
+	accountPolicy          domain_auth.LockoutPolicy
+	ipPolicy               domain_auth.LockoutPolicy
 }
 
 // 認証ユースケースのインスタンス化
-func NewAuthUsecase(l *pkg_logger.AppLogger, ar repository_auth.IAuthRepository) IAuthUsecase {
+// アカウントごと・IPアドレスごとにログインの失敗を数え、ポリシーに従って待ち時間とロックを設ける
+func NewAuthUsecase(l *pkg_logger.AppLogger, ar repository_auth.IAuthRepository, lr repository_auth.ILoginAttemptRepository, alr repository_auth.IAuditLogRepository, accountPolicy domain_auth.LockoutPolicy, ipPolicy domain_auth.LockoutPolicy) IAuthUsecase {
 	return &AuthUsecase{
-		Logger:         l,
-		authRepository: ar,
+		Logger:                 l,
+		authRepository:         ar,
+		loginAttemptRepository: lr,
+		auditLogRepository:     alr,
+		accountPolicy:          accountPolicy,
+		ipPolicy:               ipPolicy,
 	}
 }
 
 // ログイン
-func (u *AuthUsecase) Login(email string, password string) (string, error) {
+func (u *AuthUsecase) Login(email string, password string, ip string) (string, error) {
 	u.Logger.InfoLog.Println("Login called")
 
 	// バリデーション
@@ -43,13 +56,155 @@ func (u *AuthUsecase) Login(email string, password string) (string, error) {
 		return "", errors.New("invalid email format")
 	}
 
+	// 試行を失敗として先に数えてから認証する
+	// ロック中・待ち時間中は認証しない(メールアドレスが存在しない場合も同じ)
+	now := time.Now()
+	reserved, err := u.reserve(now, email, ip)
+	if err != nil {
+		return "", err
+	}
+
 	// 認証リポジトリからログイン(repository層)
 	id, err := u.authRepository.Login(email, password)
+	if errors.Is(err, domain_auth.ErrInvalidCredentials) {
+		u.auditLocked(now, email, ip, reserved)
+		return "", errors.New("invalid email or password")
+	}
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to login: %v", err)
+		u.release(reserved)
 		return "", errors.New("failed to login")
 	}
 
+	// 成功したらアカウントの失敗を数え直す
+	// (IPアドレスは別のアカウントでのログインで数え直させないため、この試行の分だけ取り消す)
+	for _, r := range reserved {
+		if r.target.key != domain_auth.AccountKey(email) {
+			u.release([]reservation{r})
+			continue
+		}
+		if err := u.loginAttemptRepository.Reset(r.target.key); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to reset login attempts: %v", err)
+		}
+	}
+
 	u.Logger.InfoLog.Println("Login successful. 1 user found")
 	return id, nil
 }
+
+// ロックの解除(管理者)
+func (u *AuthUsecase) Unlock(email string, ip string, actorId string) error {
+	u.Logger.InfoLog.Println("Unlock called")
+
+	if email == "" && ip == "" {
+		u.Logger.ErrorLog.Println("Email or IP is required")
+		return errors.New("email or ip is required")
+	}
+
+	keys := []string{}
+	if email != "" {
+		keys = append(keys, domain_auth.AccountKey(email))
+	}
+	if ip != "" {
+		keys = append(keys, domain_auth.IPKey(ip))
+	}
+	for _, key := range keys {
+		if err := u.loginAttemptRepository.Reset(key); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to reset login attempts: %v", err)
+			return errors.New("failed to unlock")
+		}
+	}
+
+	u.audit(domain_auth.AuditLog{Action: domain_auth.AuditAccountUnlocked, ActorID: actorId, Email: email, IP: ip})
+	u.Logger.InfoLog.Printf("Unlocked: email=%s ip=%s", email, ip)
+	return nil
+}
+
+// アカウントとIPアドレスの試行を予約する
+// どちらかがロック中・待ち時間中であれば、予約した分を取り消してエラーを返す
+// 記録を更新できない場合は認証を止めない
+func (u *AuthUsecase) reserve(now time.Time, email string, ip string) ([]reservation, error) {
+	reserved := []reservation{}
+	var retryAt time.Time
+	for _, target := range u.targets(email, ip) {
+		var rejectedUntil time.Time
+		f, err := u.loginAttemptRepository.Update(target.key, func(f domain_auth.LoginFailures) domain_auth.LoginFailures {
+			f, rejectedUntil = f.Reserve(now, target.policy)
+			return f
+		})
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to reserve login attempt: %v", err)
+			continue
+		}
+		if !rejectedUntil.IsZero() {
+			retryAt = maxTime(retryAt, rejectedUntil)
+			continue
+		}
+		reserved = append(reserved, reservation{target: target, failures: f})
+	}
+	if !retryAt.IsZero() {
+		u.release(reserved)
+		u.Logger.WarnLog.Printf("Login rejected until %v: email=%s ip=%s", retryAt, email, ip)
+		return nil, &domain_auth.LockedError{RetryAt: retryAt}
+	}
+	return reserved, nil
+}
+
+// 予約した試行を取り消す
+func (u *AuthUsecase) release(reserved []reservation) {
+	for _, r := range reserved {
+		if _, err := u.loginAttemptRepository.Update(r.target.key, func(f domain_auth.LoginFailures) domain_auth.LoginFailures {
+			return f.Release(r.target.policy)
+		}); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to release login attempt: %v", err)
+		}
+	}
+}
+
+// 失敗した試行でロックした場合は監査ログに残す
+func (u *AuthUsecase) auditLocked(now time.Time, email string, ip string, reserved []reservation) {
+	for _, r := range reserved {
+		// ロックした回の失敗のみ記録する
+		if r.failures.Locked(now) && r.failures.Count == max(1, r.target.policy.Threshold) {
+			u.Logger.WarnLog.Printf("Locked %s until %v", r.target.key, r.failures.LockedUntil)
+			u.audit(domain_auth.AuditLog{Action: r.target.action, Email: email, IP: ip, Detail: "locked until " + r.failures.LockedUntil.Format(time.RFC3339)})
+		}
+	}
+}
+
+// 予約した試行(予約後の記録)
+type reservation struct {
+	target   lockoutTarget
+	failures domain_auth.LoginFailures
+}
+
+// 失敗を数える対象
+type lockoutTarget struct {
+	key    string
+	policy domain_auth.LockoutPolicy
+	action domain_auth.AuditAction
+}
+
+// アカウントとIPアドレス(不明な場合はアカウントのみ)
+func (u *AuthUsecase) targets(email string, ip string) []lockoutTarget {
+	targets := []lockoutTarget{{key: domain_auth.AccountKey(email), policy: u.accountPolicy, action: domain_auth.AuditAccountLocked}}
+	if ip != "" {
+		targets = append(targets, lockoutTarget{key: domain_auth.IPKey(ip), policy: u.ipPolicy, action: domain_auth.AuditIPLocked})
+	}
+	return targets
+}
+
+// 遅い方の時刻
+func maxTime(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// 監査ログを記録する(失敗しても処理は続ける)
+func (u *AuthUsecase) audit(log domain_auth.AuditLog) {
+	if err := u.auditLogRepository.CreateAuditLog(log); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to create audit log: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package usecase_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_totp "backend/internal/pkg/totp"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"time"
+)
+
+// 2要素認証ユースケース(IF)
+type IMFAUsecase interface {
+	// 登録を始め、シークレットと認証アプリに登録するURIを返す
+	Enroll(userId string, account string) (string, string, error)
+	// コードで登録を確認して有効にし、回復コードを返す
+	Confirm(userId string, code string) ([]string, error)
+	// 回復コードを作り直す(以前のコードは使えなくなる)
+	RegenerateRecoveryCodes(userId string, code string) ([]string, error)
+	// ログイン時に2要素認証が必要か
+	Required(userId string) (bool, error)
+	// ログイン時のコード(TOTPまたは回復コード)を検証する
+	Verify(userId string, code string) error
+	// 2要素認証を解除する(管理者)
+	Reset(userId string, actorId string) error
+}
+
+// 2要素認証ユースケース(Impl)
+type MFAUsecase struct {
+	Logger                 *pkg_logger.AppLogger
+	mfaRepository          repository_auth.IMFARepository
+	loginAttemptRepository repository_auth.ILoginAttemptRepository
+	auditLogRepository     repository_auth.IAuditLogRepository
+	policy                 domain_auth.LockoutPolicy
+	issuer                 string
+}
+
+// 2要素認証ユースケースのインスタンス化
+// コードの失敗はユーザーごとに数え、ポリシーに従って待ち時間とロックを設ける
+func NewMFAUsecase(l *pkg_logger.AppLogger, mr repository_auth.IMFARepository, lr repository_auth.ILoginAttemptRepository, alr repository_auth.IAuditLogRepository, policy domain_auth.LockoutPolicy, issuer string) IMFAUsecase {
+	return &MFAUsecase{
+		Logger:                 l,
+		mfaRepository:          mr,
+		loginAttemptRepository: lr,
+		auditLogRepository:     alr,
+		policy:                 policy,
+		issuer:                 issuer,
+	}
+}
+
+// 登録を始める
+// 確認前であれば何度でもやり直せる(シークレットは作り直す)
+func (u *MFAUsecase) Enroll(userId string, account string) (string, string, error) {
+	u.Logger.InfoLog.Println("Enroll MFA called")
+
+	if _, err := u.enabled(userId); err == nil {
+		return "", "", errors.New("mfa is already enabled")
+	} else if err.Error() != "mfa is not enabled" {
+		return "", "", err
+	}
+
+	secret, err := pkg_totp.GenerateSecret()
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate totp secret: %v", err)
+		return "", "", errors.New("failed to enroll mfa")
+	}
+	if err := u.mfaRepository.SaveMFA(domain_auth.MFA{UserID: userId, Secret: secret}); err != nil {
+		return "", "", errors.New("failed to enroll mfa")
+	}
+
+	u.Logger.InfoLog.Printf("MFA enrollment started: user %s", userId)
+	return secret, pkg_totp.ProvisioningURI(u.issuer, account, secret), nil
+}
+
+// 登録を確認する
+func (u *MFAUsecase) Confirm(userId string, code string) ([]string, error) {
+	u.Logger.InfoLog.Println("Confirm MFA called")
+
+	m, err := u.mfaRepository.GetMFA(userId)
+	if err != nil {
+		if err.Error() == "mfa not found" {
+			return nil, errors.New("mfa is not enrolled")
+		}
+		return nil, errors.New("failed to confirm mfa")
+	}
+	if m.Enabled() {
+		return nil, errors.New("mfa is already enabled")
+	}
+
+	now := time.Now()
+	step, ok := pkg_totp.Validate(m.Secret, code, now)
+	if !ok {
+		u.Logger.WarnLog.Printf("Invalid mfa confirmation code: user %s", userId)
+		return nil, errors.New("invalid code")
+	}
+
+	codes, hashes, err := domain_auth.GenerateRecoveryCodes()
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate recovery codes: %v", err)
+		return nil, errors.New("failed to confirm mfa")
+	}
+	m.ConfirmedAt = &now
+	m.LastUsedStep = step
+	m.RecoveryCodes = hashes
+	if err := u.mfaRepository.SaveMFA(m); err != nil {
+		return nil, errors.New("failed to confirm mfa")
+	}
+
+	u.audit(domain_auth.AuditLog{Action: domain_auth.AuditMFAEnabled, ActorID: userId})
+	u.Logger.InfoLog.Printf("MFA enabled: user %s", userId)
+	return codes, nil
+}
+
+// 回復コードを作り直す
+// 本人確認のため、有効なコードを必要とする
+func (u *MFAUsecase) RegenerateRecoveryCodes(userId string, code string) ([]string, error) {
+	u.Logger.InfoLog.Println("RegenerateRecoveryCodes called")
+
+	if err := u.Verify(userId, code); err != nil {
+		return nil, err
+	}
+	m, err := u.mfaRepository.GetMFA(userId)
+	if err != nil {
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+
+	codes, hashes, err := domain_auth.GenerateRecoveryCodes()
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate recovery codes: %v", err)
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+	m.RecoveryCodes = hashes
+	if err := u.mfaRepository.SaveMFA(m); err != nil {
+		return nil, errors.New("failed to regenerate recovery codes")
+	}
+
+	u.Logger.InfoLog.Printf("Regenerated recovery codes: user %s", userId)
+	return codes, nil
+}
+
+// ログイン時に2要素認証が必要か
+func (u *MFAUsecase) Required(userId string) (bool, error) {
+	_, err := u.enabled(userId)
+	if err == nil {
+		return true, nil
+	}
+	if err.Error() == "mfa is not enabled" {
+		return false, nil
+	}
+	return false, err
+}
+
+// ログイン時のコードを検証する
+// TOTPは同じステップのコードを2度使えない。回復コードは1度だけ使える
+func (u *MFAUsecase) Verify(userId string, code string) error {
+	u.Logger.InfoLog.Println("Verify MFA called")
+
+	m, err := u.enabled(userId)
+	if err != nil {
+		return err
+	}
+
+	// 試行を失敗として先に数えてからコードを確認する(同時の試行でもしきい値を超えて確認しない)
+	// 待ち時間中・ロック中はコードを確認しない
+	now := time.Now()
+	key := domain_auth.MFAKey(userId)
+	var retryAt time.Time
+	if _, err := u.loginAttemptRepository.Update(key, func(f domain_auth.LoginFailures) domain_auth.LoginFailures {
+		f, retryAt = f.Reserve(now, u.policy)
+		return f
+	}); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to reserve mfa attempt: %v", err)
+	} else if !retryAt.IsZero() {
+		u.Logger.WarnLog.Printf("MFA rejected until %v: user %s", retryAt, userId)
+		return &domain_auth.LockedError{RetryAt: retryAt}
+	}
+
+	ok, err := u.use(m, code, now)
+	if err != nil {
+		if _, err := u.loginAttemptRepository.Update(key, func(f domain_auth.LoginFailures) domain_auth.LoginFailures {
+			return f.Release(u.policy)
+		}); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to release mfa attempt: %v", err)
+		}
+		return errors.New("failed to verify mfa")
+	}
+	if !ok {
+		u.Logger.WarnLog.Printf("Invalid mfa code: user %s", userId)
+		return errors.New("invalid code")
+	}
+
+	if err := u.loginAttemptRepository.Reset(key); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to reset mfa attempts: %v", err)
+	}
+	u.Logger.InfoLog.Printf("MFA verified: user %s", userId)
+	return nil
+}
+
+// 2要素認証を解除する(管理者)
+// ユーザーは次回のログインからパスワードのみで認証され、改めて登録できる
+func (u *MFAUsecase) Reset(userId string, actorId string) error {
+	u.Logger.InfoLog.Println("Reset MFA called")
+
+	if _, err := u.mfaRepository.GetMFA(userId); err != nil {
+		if err.Error() == "mfa not found" {
+			return errors.New("mfa is not enrolled")
+		}
+		return errors.New("failed to reset mfa")
+	}
+	if err := u.mfaRepository.DeleteMFA(userId); err != nil {
+		return errors.New("failed to reset mfa")
+	}
+	if err := u.loginAttemptRepository.Reset(domain_auth.MFAKey(userId)); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to reset mfa attempts: %v", err)
+	}
+
+	u.audit(domain_auth.AuditLog{Action: domain_auth.AuditMFAReset, ActorID: actorId, Detail: "user " + userId})
+	u.Logger.InfoLog.Printf("MFA reset: user %s by %s", userId, actorId)
+	return nil
+}
+
+// 有効な2要素認証を取得する(無い場合・確認前は"mfa is not enabled")
+func (u *MFAUsecase) enabled(userId string) (domain_auth.MFA, error) {
+	m, err := u.mfaRepository.GetMFA(userId)
+	if err != nil {
+		if err.Error() == "mfa not found" {
+			return domain_auth.MFA{}, errors.New("mfa is not enabled")
+		}
+		u.Logger.ErrorLog.Printf("Failed to fetch mfa: %v", err)
+		return domain_auth.MFA{}, errors.New("failed to fetch mfa")
+	}
+	if !m.Enabled() {
+		return domain_auth.MFA{}, errors.New("mfa is not enabled")
+	}
+	return m, nil
+}
+
+// コードを使用済みにする
+// 桁数が合えばTOTP、それ以外は回復コードとして扱う
+func (u *MFAUsecase) use(m domain_auth.MFA, code string, now time.Time) (bool, error) {
+	if len(code) == pkg_totp.Digits {
+		step, ok := pkg_totp.Validate(m.Secret, code, now)
+		if !ok {
+			return false, nil
+		}
+		return u.mfaRepository.UseStep(m.UserID, step)
+	}
+	return u.mfaRepository.UseRecoveryCode(m.UserID, domain_auth.HashRecoveryCode(code))
+}
+
+// 監査ログを記録する(失敗しても処理は続ける)
+func (u *MFAUsecase) audit(log domain_auth.AuditLog) {
+	if err := u.auditLogRepository.CreateAuditLog(log); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to create audit log: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package usecase_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_oidc "backend/internal/pkg/oidc"
+	repository_auth "backend/internal/repository/auth"
+	"context"
+	"errors"
+	"sort"
+)
+
+// OIDCログインのユースケース(IF)
+type IOIDCUsecase interface {
+	// 設定されたプロバイダ名
+	Providers() []string
+	// 認可リクエストを開始し、コールバックまで保持する値と認可URLを返す
+	Begin(ctx context.Context, provider string) (domain_auth.OIDCFlow, string, error)
+	// 認可コードを交換し、紐付いたユーザーIDを返す
+	Complete(ctx context.Context, flow domain_auth.OIDCFlow, code string) (string, error)
+}
+
+// OIDCログインのユースケース(Impl)
+type OIDCUsecase struct {
+	Logger             *pkg_logger.AppLogger
+	identityRepository repository_auth.IIdentityRepository
+	auditLogRepository repository_auth.IAuditLogRepository
+	providers          map[string]*pkg_oidc.Provider
+}
+
+// OIDCログインのユースケースのインスタンス化
+func NewOIDCUsecase(l *pkg_logger.AppLogger, ir repository_auth.IIdentityRepository, alr repository_auth.IAuditLogRepository, providers []*pkg_oidc.Provider) IOIDCUsecase {
+	m := map[string]*pkg_oidc.Provider{}
+	for _, p := range providers {
+		m[p.Name()] = p
+	}
+	return &OIDCUsecase{
+		Logger:             l,
+		identityRepository: ir,
+		auditLogRepository: alr,
+		providers:          m,
+	}
+}
+
+// 設定されたプロバイダ名(名前順)
+func (u *OIDCUsecase) Providers() []string {
+	names := []string{}
+	for name := range u.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 認可リクエストを開始する
+func (u *OIDCUsecase) Begin(ctx context.Context, provider string) (domain_auth.OIDCFlow, string, error) {
+	u.Logger.InfoLog.Printf("OIDC login started: %s", provider)
+
+	p, ok := u.providers[provider]
+	if !ok {
+		return domain_auth.OIDCFlow{}, "", errors.New("unknown provider")
+	}
+
+	flow := domain_auth.OIDCFlow{Provider: provider}
+	for _, v := range []*string{&flow.State, &flow.Nonce, &flow.Verifier} {
+		s, err := pkg_oidc.RandomString()
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to generate random string: %v", err)
+			return domain_auth.OIDCFlow{}, "", errors.New("failed to start login")
+		}
+		*v = s
+	}
+
+	url, err := p.AuthCodeURL(ctx, flow.State, flow.Nonce, pkg_oidc.CodeChallenge(flow.Verifier))
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to build authorization url: %v", err)
+		return domain_auth.OIDCFlow{}, "", errors.New("identity provider error")
+	}
+	return flow, url, nil
+}
+
+// 認可コードを交換し、紐付いたユーザーIDを返す
+// 紐付けがない場合は、確認済みのメールアドレスが一致するユーザーに紐付ける(ユーザーは作成しない)
+func (u *OIDCUsecase) Complete(ctx context.Context, flow domain_auth.OIDCFlow, code string) (string, error) {
+	u.Logger.InfoLog.Printf("OIDC callback: %s", flow.Provider)
+
+	p, ok := u.providers[flow.Provider]
+	if !ok {
+		return "", errors.New("unknown provider")
+	}
+
+	claims, err := p.Exchange(ctx, code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to exchange code: %v", err)
+		if errors.Is(err, pkg_oidc.ErrInvalidToken) {
+			return "", errors.New("invalid id token")
+		}
+		return "", errors.New("identity provider error")
+	}
+
+	// 紐付け済み
+	identity, err := u.identityRepository.GetIdentity(flow.Provider, claims.Subject)
+	if err == nil {
+		u.Logger.InfoLog.Printf("OIDC login successful: user %s", identity.UserID)
+		return identity.UserID, nil
+	}
+	if err.Error() != "identity not found" {
+		u.Logger.ErrorLog.Printf("Failed to fetch identity: %v", err)
+		return "", errors.New("failed to login")
+	}
+
+	// 確認済みのメールアドレスで既存のユーザーに紐付ける
+	if claims.Email == "" || !claims.EmailVerified {
+		u.Logger.WarnLog.Printf("Email of %s identity is not verified", flow.Provider)
+		return "", errors.New("email is not verified")
+	}
+	userId, err := u.identityRepository.GetUserIdByEmail(claims.Email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			u.Logger.WarnLog.Printf("No user for %s identity", flow.Provider)
+			return "", errors.New("no linked account")
+		}
+		u.Logger.ErrorLog.Printf("Failed to fetch user: %v", err)
+		return "", errors.New("failed to login")
+	}
+	err = u.identityRepository.CreateIdentity(domain_auth.Identity{Provider: flow.Provider, Subject: claims.Subject, UserID: userId, Email: claims.Email})
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to link identity: %v", err)
+		return "", errors.New("failed to login")
+	}
+	if err := u.auditLogRepository.CreateAuditLog(domain_auth.AuditLog{Action: domain_auth.AuditIdentityLinked, ActorID: userId, Email: claims.Email, Detail: flow.Provider}); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to create audit log: %v", err)
+	}
+
+	u.Logger.InfoLog.Printf("OIDC login successful: linked user %s", userId)
+	return userId, nil
+}
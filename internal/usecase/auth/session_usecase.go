@@ -0,0 +1,204 @@
+package usecase_auth
+
+import (
+	domain_auth "backend/internal/domain/auth"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_auth "backend/internal/repository/auth"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 最終使用日時を更新する間隔(リクエストごとに書き込まない)
+const sessionTouchInterval = time.Minute
+
+// ログインのセッションのユースケース(IF)
+type ISessionUsecase interface {
+	// セッションを作成
+	Create(userId string, ip string, userAgent string) (domain_auth.Session, error)
+	// アクセストークンのセッションが有効か確認する
+	Validate(id string, userId string) error
+	// ユーザーの有効なセッションを取得
+	GetSessions(userId string) ([]domain_auth.Session, error)
+	// ユーザーのセッションを失効させる
+	Revoke(userId string, id string) error
+	// ユーザーの全てのセッションを失効させる(全ての端末からログアウト)
+	RevokeAll(userId string) (int, error)
+}
+
+// キャッシュしたセッション
+type cachedSession struct {
+	session   domain_auth.Session
+	fetchedAt time.Time
+}
+
+// ログインのセッションのユースケース(Impl)
+type SessionUsecase struct {
+	Logger            *pkg_logger.AppLogger
+	sessionRepository repository_auth.ISessionRepository
+	roleRepository    repository_auth.IRoleRepository
+	cacheTTL          time.Duration
+
+	mu        sync.Mutex
+	cache     map[string]cachedSession
+	lastSweep time.Time
+}
+
+// ログインのセッションのユースケースのインスタンス化
+// 認証のたびに保存先を参照しないよう、セッションをcacheTTLの間キャッシュする
+// 他のインスタンスでの失効は、最大でcacheTTL遅れて反映される
+func NewSessionUsecase(l *pkg_logger.AppLogger, sr repository_auth.ISessionRepository, rr repository_auth.IRoleRepository, cacheTTL time.Duration) ISessionUsecase {
+	return &SessionUsecase{
+		Logger:            l,
+		sessionRepository: sr,
+		roleRepository:    rr,
+		cacheTTL:          cacheTTL,
+		cache:             map[string]cachedSession{},
+	}
+}
+
+// セッションを作成
+// アクセストークンに含めるため、ユーザーの現在のロールをRoleに設定する
+func (u *SessionUsecase) Create(userId string, ip string, userAgent string) (domain_auth.Session, error) {
+	role, err := u.roleRepository.GetRole(userId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to fetch role: %v", err)
+		return domain_auth.Session{}, errors.New("failed to create session")
+	}
+
+	session, err := u.sessionRepository.CreateSession(domain_auth.Session{
+		UserID:    userId,
+		Device:    domain_auth.DeviceName(userAgent),
+		IP:        ip,
+		UserAgent: userAgent,
+	})
+	if err != nil {
+		return domain_auth.Session{}, errors.New("failed to create session")
+	}
+	session.Role = role
+	u.put(session, time.Now())
+	return session, nil
+}
+
+// アクセストークンのセッションが有効か確認する
+// 失効・期限切れ・別のユーザーのセッションは"invalid session"
+func (u *SessionUsecase) Validate(id string, userId string) error {
+	now := time.Now()
+	session, ok := u.get(id, now)
+	if !ok {
+		var err error
+		session, err = u.sessionRepository.GetSession(id)
+		if err != nil {
+			if err.Error() == "session not found" {
+				return errors.New("invalid session")
+			}
+			u.Logger.ErrorLog.Printf("Failed to fetch session: %v", err)
+			return errors.New("failed to authenticate")
+		}
+		u.put(session, now)
+	}
+
+	if session.UserID != userId || !session.Active(now) {
+		u.Logger.WarnLog.Printf("Invalid session %s for user %s", id, userId)
+		return errors.New("invalid session")
+	}
+
+	// 最終使用日時の更新に失敗しても認証は通す
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := u.sessionRepository.TouchSession(id, now); err != nil {
+			u.Logger.ErrorLog.Printf("Failed to touch session: %v", err)
+		} else {
+			u.touch(id, now)
+		}
+	}
+	return nil
+}
+
+// ユーザーの有効なセッションを取得
+func (u *SessionUsecase) GetSessions(userId string) ([]domain_auth.Session, error) {
+	u.Logger.InfoLog.Println("GetSessions called")
+
+	sessions, err := u.sessionRepository.GetActiveSessions(userId, time.Now())
+	if err != nil {
+		return nil, errors.New("failed to get sessions")
+	}
+	return sessions, nil
+}
+
+// ユーザーのセッションを失効させる
+func (u *SessionUsecase) Revoke(userId string, id string) error {
+	u.Logger.InfoLog.Println("Revoke session called")
+
+	if err := u.sessionRepository.RevokeSession(id, userId, time.Now()); err != nil {
+		if err.Error() == "session not found" {
+			return err
+		}
+		return errors.New("failed to revoke session")
+	}
+	u.evict(func(s domain_auth.Session) bool { return s.ID == id })
+
+	u.Logger.InfoLog.Printf("Revoked session %s for user %s", id, userId)
+	return nil
+}
+
+// ユーザーの全てのセッションを失効させる
+func (u *SessionUsecase) RevokeAll(userId string) (int, error) {
+	u.Logger.InfoLog.Println("RevokeAll sessions called")
+
+	count, err := u.sessionRepository.RevokeSessions(userId, time.Now())
+	if err != nil {
+		return 0, errors.New("failed to revoke sessions")
+	}
+	u.evict(func(s domain_auth.Session) bool { return s.UserID == userId })
+
+	u.Logger.InfoLog.Printf("Revoked %d sessions for user %s", count, userId)
+	return count, nil
+}
+
+// キャッシュから取得する(期限切れの場合はfalse)
+func (u *SessionUsecase) get(id string, now time.Time) (domain_auth.Session, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	c, ok := u.cache[id]
+	if !ok || now.Sub(c.fetchedAt) >= u.cacheTTL {
+		return domain_auth.Session{}, false
+	}
+	return c.session, true
+}
+
+// キャッシュに保存する
+// 1分ごとに期限切れのものを削除する
+func (u *SessionUsecase) put(session domain_auth.Session, now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if now.Sub(u.lastSweep) >= time.Minute {
+		for id, c := range u.cache {
+			if now.Sub(c.fetchedAt) >= u.cacheTTL {
+				delete(u.cache, id)
+			}
+		}
+		u.lastSweep = now
+	}
+	u.cache[session.ID] = cachedSession{session: session, fetchedAt: now}
+}
+
+// キャッシュしたセッションの最終使用日時を更新する
+func (u *SessionUsecase) touch(id string, at time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if c, ok := u.cache[id]; ok {
+		c.session.LastSeenAt = at
+		u.cache[id] = c
+	}
+}
+
+// 条件に一致するセッションをキャッシュから削除する
+func (u *SessionUsecase) evict(match func(s domain_auth.Session) bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for id, c := range u.cache {
+		if match(c.session) {
+			delete(u.cache, id)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_fetch "backend/internal/repository/fetch"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// 集約ユースケース(IF)
+type IAggregateUsecase interface {
+	// 上流APIのリソースを結合し、指定したページを返す
+	Aggregate(ctx context.Context, q domain_fetch.AggregateQuery) (domain_fetch.AggregateResult, error)
+}
+
+// 集約ユースケース(Impl)
+type AggregateUsecase struct {
+	Logger             *pkg_logger.AppLogger
+	upstreamRepository repository_fetch.IUpstreamRepository
+}
+
+// 集約ユースケースのインスタンス化
+func NewAggregateUsecase(l *pkg_logger.AppLogger, ur repository_fetch.IUpstreamRepository) IAggregateUsecase {
+	return &AggregateUsecase{
+		Logger:             l,
+		upstreamRepository: ur,
+	}
+}
+
+// 上流APIのリソースを結合し、指定したページを返す
+// 結合に必要なリソースは並行して取得し、1つでも失敗した場合は残りを中断する
+func (u *AggregateUsecase) Aggregate(ctx context.Context, q domain_fetch.AggregateQuery) (domain_fetch.AggregateResult, error) {
+	u.Logger.InfoLog.Printf("Aggregate called: resource=%s fields=%v page=%d per_page=%d", q.Resource, q.Fields, q.Page, q.PerPage)
+
+	// 入力チェック
+	if field, err := domain_fetch.ValidateFields(q.Resource, q.Fields); err != nil {
+		u.Logger.ErrorLog.Printf("Invalid fields %q: %v", field, err)
+		return domain_fetch.AggregateResult{}, err
+	}
+	if q.Page < 1 || q.PerPage < 1 {
+		return domain_fetch.AggregateResult{}, errors.New("page and per_page must be positive")
+	}
+
+	var items any
+	var total int
+	g, gctx := errgroup.WithContext(ctx)
+	switch q.Resource {
+	case domain_fetch.AggregatePosts:
+		var posts []domain_fetch.Post
+		var comments []domain_fetch.Comment
+		g.Go(func() error { return u.getJSON(gctx, "posts", "/posts", &posts) })
+		g.Go(func() error { return u.getJSON(gctx, "comments", "/comments", &comments) })
+		if err := g.Wait(); err != nil {
+			return domain_fetch.AggregateResult{}, err
+		}
+		total = len(posts)
+		start, end, _ := domain_fetch.Paginate(total, q.Page, q.PerPage)
+		// 結合は表示するページの投稿だけに行う
+		items = domain_fetch.JoinPosts(posts[start:end], comments)
+	case domain_fetch.AggregateUsers:
+		var users []domain_fetch.User
+		var albums []domain_fetch.Album
+		g.Go(func() error { return u.getJSON(gctx, "users", "/users", &users) })
+		g.Go(func() error { return u.getJSON(gctx, "albums", "/albums", &albums) })
+		if err := g.Wait(); err != nil {
+			return domain_fetch.AggregateResult{}, err
+		}
+		total = len(users)
+		start, end, _ := domain_fetch.Paginate(total, q.Page, q.PerPage)
+		items = domain_fetch.JoinUsers(users[start:end], albums)
+	}
+
+	selected, err := domain_fetch.SelectFields(items, q.Fields)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to select fields: %v", err)
+		return domain_fetch.AggregateResult{}, err
+	}
+	_, _, totalPages := domain_fetch.Paginate(total, q.Page, q.PerPage)
+
+	u.Logger.InfoLog.Printf("Aggregate completed: total=%d items=%d", total, len(selected))
+	return domain_fetch.AggregateResult{
+		Items:      selected,
+		Page:       q.Page,
+		PerPage:    q.PerPage,
+		Total:      total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// 上流APIからJSONを取得してdstに変換する
+func (u *AggregateUsecase) getJSON(ctx context.Context, name, path string, dst any) error {
+	res, err := u.upstreamRepository.Do(ctx, domain_fetch.UpstreamRequest{Name: name, Method: http.MethodGet, Path: path})
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to fetch %s: %v", name, err)
+		return errors.New("upstream request failed")
+	}
+	if res.Status != http.StatusOK {
+		u.Logger.ErrorLog.Printf("Unexpected status of %s: %d", name, res.Status)
+		return errors.New("upstream request failed")
+	}
+	if err := json.Unmarshal(res.Body, dst); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to decode %s: %v", name, err)
+		return errors.New("upstream response is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package fetch_usecase
+
+import (
+	domain_fetch "backend/internal/domain/fetch"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_fetch "backend/internal/repository/fetch"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// 一括取得ユースケース(IF)
+type IFetchUsecase interface {
+	// 複数の上流APIにリクエストを送り、結果をリクエストの順に返す
+	Fetch(ctx context.Context, reqs []domain_fetch.UpstreamRequest, opts domain_fetch.FetchOptions) (domain_fetch.FetchResult, error)
+}
+
+// 一括取得ユースケース(Impl)
+type FetchUsecase struct {
+	Logger             *pkg_logger.AppLogger
+	upstreamRepository repository_fetch.IUpstreamRepository
+}
+
+// 一括取得ユースケースのインスタンス化
+func NewFetchUsecase(l *pkg_logger.AppLogger, ur repository_fetch.IUpstreamRepository) IFetchUsecase {
+	return &FetchUsecase{
+		Logger:             l,
+		upstreamRepository: ur,
+	}
+}
+
+// 複数の上流APIにリクエストを送り、結果をリクエストの順に返す
+// 個々のリクエストの失敗は結果のoutcome/errorに記録し、全体のエラーにはしない
+func (u *FetchUsecase) Fetch(ctx context.Context, reqs []domain_fetch.UpstreamRequest, opts domain_fetch.FetchOptions) (domain_fetch.FetchResult, error) {
+	u.Logger.InfoLog.Printf("Fetch called: requests=%d concurrency=%d compare=%v deadline=%v mode=%s", len(reqs), opts.Concurrency, opts.Compare, opts.Deadline, opts.ErrorMode)
+
+	// 入力チェック
+	if len(reqs) == 0 {
+		return domain_fetch.FetchResult{}, errors.New("requests is empty")
+	}
+	names := map[string]bool{}
+	for i := range reqs {
+		if err := reqs[i].Normalize(); err != nil {
+			u.Logger.ErrorLog.Printf("Invalid request %q: %v", reqs[i].Name, err)
+			return domain_fetch.FetchResult{}, err
+		}
+		if names[reqs[i].Name] {
+			u.Logger.ErrorLog.Printf("Duplicate name: %s", reqs[i].Name)
+			return domain_fetch.FetchResult{}, errors.New("name is duplicated")
+		}
+		names[reqs[i].Name] = true
+		// 比較では同じリクエストを2回送るため、GET/HEAD以外は許可しない
+		if opts.Compare && !reqs[i].IsSafe() {
+			u.Logger.ErrorLog.Printf("Compare with unsafe method: %s %s", reqs[i].Method, reqs[i].Name)
+			return domain_fetch.FetchResult{}, errors.New("compare is only allowed for GET or HEAD")
+		}
+	}
+	switch opts.ErrorMode {
+	case "":
+		opts.ErrorMode = domain_fetch.CollectAll
+	case domain_fetch.CollectAll, domain_fetch.FailFast:
+	default:
+		return domain_fetch.FetchResult{}, errors.New("error mode is unknown")
+	}
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	timing := domain_fetch.FetchTiming{Mode: "parallel", Concurrency: concurrency}
+	if concurrency == 1 {
+		timing.Mode = "series"
+	}
+
+	// 比較する場合は先に逐次実行する
+	if opts.Compare {
+		_, seriesNs := u.run(ctx, reqs, 1, opts)
+		timing.SeriesNs = &seriesNs
+	}
+
+	results, elapsed := u.run(ctx, reqs, concurrency, opts)
+	timing.ElapsedNs = elapsed
+	for _, r := range results {
+		timing.TotalLatencyNs += r.LatencyNs
+	}
+	if elapsed > 0 {
+		timing.Speedup = float64(timing.TotalLatencyNs) / float64(elapsed)
+	}
+	if opts.Compare {
+		timing.ParallelNs = &elapsed
+	}
+
+	summary := results.Summary()
+	u.Logger.InfoLog.Printf("Fetch completed: elapsed=%dns total latency=%dns summary=%+v", timing.ElapsedNs, timing.TotalLatencyNs, summary)
+	return domain_fetch.FetchResult{Results: results, Summary: summary, Timing: timing}, nil
+}
+
+// 全体の期限切れを表す原因
+var errDeadline = errors.New("deadline exceeded")
+
+// 最大concurrency件ずつ並行してリクエストを送る
+// 結果はリクエストと同じ位置に格納するため、完了順に関係なく順序は安定する
+// 期限切れ・fail_fastでの失敗・呼び出し元のキャンセルでは実行中のリクエストを中断し、全てのgoroutineの終了を待ってから返す
+func (u *FetchUsecase) run(ctx context.Context, reqs []domain_fetch.UpstreamRequest, concurrency int, opts domain_fetch.FetchOptions) (domain_fetch.UpstreamResults, int64) {
+	start := time.Now()
+	results := make(domain_fetch.UpstreamResults, len(reqs))
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, opts.Deadline, errDeadline)
+		defer cancel()
+	}
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(concurrency)
+
+	for i, req := range reqs {
+		g.Go(func() error {
+			// 空きを待つ間に中断された場合は、リクエストを開始しない
+			if gctx.Err() != nil {
+				results[i] = domain_fetch.UpstreamResponse{Name: req.Name, Outcome: abortOutcome(gctx), Error: "not started"}
+				return nil
+			}
+			res, err := u.upstreamRepository.Do(gctx, req)
+			res.Name = req.Name
+			res.Outcome = outcome(gctx, res, err)
+			if err != nil {
+				res.Error = err.Error()
+			}
+			results[i] = res
+
+			if opts.ErrorMode == domain_fetch.FailFast && (res.Outcome == domain_fetch.OutcomeFailed || res.Outcome == domain_fetch.OutcomeTimedOut) {
+				u.Logger.WarnLog.Printf("Fail fast: %s %s", req.Name, res.Outcome)
+				return fmt.Errorf("%s %s", req.Name, res.Outcome)
+			}
+			return nil
+		})
+	}
+	g.Wait()
+
+	return results, time.Since(start).Nanoseconds()
+}
+
+// 1件の結果の分類
+func outcome(ctx context.Context, res domain_fetch.UpstreamResponse, err error) domain_fetch.Outcome {
+	switch {
+	case err == nil && res.Status < 400:
+		return domain_fetch.OutcomeSucceeded
+	case err == nil:
+		return domain_fetch.OutcomeFailed
+	case errors.Is(err, context.DeadlineExceeded):
+		// 1回の試行のタイムアウトを含む
+		return domain_fetch.OutcomeTimedOut
+	case ctx.Err() != nil:
+		return abortOutcome(ctx)
+	default:
+		return domain_fetch.OutcomeFailed
+	}
+}
+
+// 中断された理由の分類
+func abortOutcome(ctx context.Context) domain_fetch.Outcome {
+	if errors.Is(context.Cause(ctx), errDeadline) {
+		return domain_fetch.OutcomeTimedOut
+	}
+	return domain_fetch.OutcomeCanceled
+}
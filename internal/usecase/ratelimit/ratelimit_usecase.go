@@ -0,0 +1,140 @@
+package usecase_ratelimit
+
+import (
+	domain_ratelimit "backend/internal/domain/ratelimit"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_ratelimit "backend/internal/repository/ratelimit"
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// レート制限ユースケース(IF)
+type IRateLimitUsecase interface {
+	// キーのリクエストを1件許可するか判定する
+	Allow(ctx context.Context, policy domain_ratelimit.Policy, key string) (domain_ratelimit.Decision, error)
+}
+
+// レート制限ユースケース(Impl)
+type RateLimitUsecase struct {
+	Logger              *pkg_logger.AppLogger
+	rateLimitRepository repository_ratelimit.IRateLimitRepository
+}
+
+// レート制限ユースケースのインスタンス化
+func NewRateLimitUsecase(l *pkg_logger.AppLogger, rr repository_ratelimit.IRateLimitRepository) IRateLimitUsecase {
+	return &RateLimitUsecase{
+		Logger:              l,
+		rateLimitRepository: rr,
+	}
+}
+
+// キーのリクエストを1件許可するか判定する
+// 状態はポリシー名とキーごとに保存する
+func (u *RateLimitUsecase) Allow(ctx context.Context, policy domain_ratelimit.Policy, key string) (domain_ratelimit.Decision, error) {
+	var decision domain_ratelimit.Decision
+	var fn func(s domain_ratelimit.State) domain_ratelimit.State
+	ttl := policy.Window
+
+	switch policy.Algorithm {
+	case domain_ratelimit.TokenBucket:
+		fn = func(s domain_ratelimit.State) domain_ratelimit.State {
+			s, decision = tokenBucket(s, policy, time.Now())
+			return s
+		}
+	case domain_ratelimit.SlidingWindow:
+		// 前の窓の件数も使うため、2窓分残す
+		ttl = 2 * policy.Window
+		fn = func(s domain_ratelimit.State) domain_ratelimit.State {
+			s, decision = slidingWindow(s, policy, time.Now())
+			return s
+		}
+	default:
+		return domain_ratelimit.Decision{}, errors.New("unknown algorithm")
+	}
+
+	if _, err := u.rateLimitRepository.Update(ctx, policy.Name+":"+key, ttl, fn); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to update rate limit %s: %v", policy.Name, err)
+		return domain_ratelimit.Decision{}, err
+	}
+	if !decision.Allowed {
+		u.Logger.WarnLog.Printf("Rate limited: policy=%s key=%s retry after %v", policy.Name, key, decision.RetryAfter)
+	}
+	return decision, nil
+}
+
+// トークンバケット
+// 容量はLimitで、Window当たりLimit個の速度で補充する
+func tokenBucket(s domain_ratelimit.State, p domain_ratelimit.Policy, now time.Time) (domain_ratelimit.State, domain_ratelimit.Decision) {
+	capacity := float64(p.Limit)
+	rate := capacity / p.Window.Seconds() // 1秒当たりの補充数
+
+	if s.Stamp.IsZero() {
+		s.Tokens = capacity
+	} else if elapsed := now.Sub(s.Stamp).Seconds(); elapsed > 0 {
+		s.Tokens = math.Min(capacity, s.Tokens+elapsed*rate)
+	}
+	s.Stamp = now
+
+	d := domain_ratelimit.Decision{Limit: p.Limit}
+	if s.Tokens >= 1 {
+		s.Tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = seconds((1 - s.Tokens) / rate)
+	}
+	d.Remaining = int(s.Tokens)
+	d.Reset = seconds((capacity - s.Tokens) / rate)
+	return s, d
+}
+
+// スライディングウィンドウ(カウンタによる近似)
+// 直前の窓の件数を、現在の窓と重なる割合で按分して現在の窓の件数に加える
+func slidingWindow(s domain_ratelimit.State, p domain_ratelimit.Policy, now time.Time) (domain_ratelimit.State, domain_ratelimit.Decision) {
+	start := now.Truncate(p.Window)
+	if !s.Stamp.Equal(start) {
+		if s.Stamp.Equal(start.Add(-p.Window)) {
+			s.PrevCount = s.Count
+		} else {
+			s.PrevCount = 0
+		}
+		s.Count = 0
+		s.Stamp = start
+	}
+
+	elapsed := now.Sub(start)
+	weight := 1 - float64(elapsed)/float64(p.Window)
+	estimated := float64(s.PrevCount)*weight + float64(s.Count)
+
+	d := domain_ratelimit.Decision{Limit: p.Limit, Reset: start.Add(p.Window).Sub(now)}
+	if estimated+1 <= float64(p.Limit) {
+		s.Count++
+		estimated++
+		d.Allowed = true
+	} else {
+		d.RetryAfter = slidingRetryAfter(s, p, elapsed)
+	}
+	d.Remaining = max(0, int(math.Floor(float64(p.Limit)-estimated)))
+	return s, d
+}
+
+// スライディングウィンドウで次に許可されるまでの時間
+// 前の窓の按分が減って(または窓が切り替わって)、推定件数がLimit-1以下になる時刻を求める
+func slidingRetryAfter(s domain_ratelimit.State, p domain_ratelimit.Policy, elapsed time.Duration) time.Duration {
+	limit := float64(p.Limit - 1)
+	window := float64(p.Window)
+	if float64(s.Count) <= limit && s.PrevCount > 0 {
+		// 現在の窓の中で空く
+		weight := (limit - float64(s.Count)) / float64(s.PrevCount)
+		return time.Duration((1-weight)*window) - elapsed
+	}
+	// 次の窓で、現在の窓の件数の按分が減るまで待つ
+	weight := limit / float64(s.Count)
+	return time.Duration(window) - elapsed + time.Duration((1-weight)*window)
+}
+
+// 秒数を時間に変換する
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
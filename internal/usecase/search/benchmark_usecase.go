@@ -0,0 +1,410 @@
+package search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	"errors"
+	"math"
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ベンチマークの既定値
+const (
+	DefaultBenchmarkIterations  = 20
+	DefaultBenchmarkWarmup      = 3
+	DefaultBenchmarkTargetCount = 100
+)
+
+// ベンチマークユースケース(IF)
+type IBenchmarkUsecase interface {
+	// データセットを生成する
+	GenerateDataset(spec domain_search.DatasetSpec) ([]int, error)
+	// 探索アルゴリズムを比較する
+	Run(req domain_search.BenchmarkRequest) (domain_search.BenchmarkResult, error)
+}
+
+// ベンチマークユースケース(Impl)
+// 計測は同時に1つずつ行う(アロケーションの計測が他のベンチマークの分を含まないように)
+type BenchmarkUsecase struct {
+	Logger *pkg_logger.AppLogger
+	mu     sync.Mutex
+}
+
+// ベンチマークユースケースのインスタンス化
+func NewBenchmarkUsecase(l *pkg_logger.AppLogger) IBenchmarkUsecase {
+	return &BenchmarkUsecase{
+		Logger: l,
+	}
+}
+
+// 比較回数を数える探索関数
+type countingSearch func(arr []int, target int) (index int, comparisons int)
+
+// アルゴリズムごとの探索関数
+var countingSearches = map[domain_search.SearchAlgorithm]countingSearch{
+	domain_search.AlgorithmLinear:        linearSearchCount,
+	domain_search.AlgorithmBinary:        binarySearchCount,
+	domain_search.AlgorithmJump:          jumpSearchCount,
+	domain_search.AlgorithmInterpolation: interpolationSearchCount,
+	domain_search.AlgorithmExponential:   exponentialSearchCount,
+}
+
+// データセットを生成する
+// 乱数はseedのみから決まるため、同じ条件で何度でも再現できる
+func (u *BenchmarkUsecase) GenerateDataset(spec domain_search.DatasetSpec) ([]int, error) {
+	if spec.Size < 0 {
+		return nil, errors.New("size must not be negative")
+	}
+	lo, hi := spec.Min, spec.Max
+	if hi == 0 {
+		hi = lo + spec.Size*10
+	}
+	if hi < lo {
+		return nil, errors.New("max must be greater than or equal to min")
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+	data := make([]int, spec.Size)
+	switch spec.Distribution {
+	case domain_search.DistributionUniform, "":
+		for i := range data {
+			data[i] = randomIn(rng, lo, hi)
+		}
+	case domain_search.DistributionSequential:
+		for i := range data {
+			data[i] = lo + i
+		}
+		// 連番をシャッフルしておき、sortedの指定で並びを決める
+		rng.Shuffle(len(data), func(i, j int) { data[i], data[j] = data[j], data[i] })
+	case domain_search.DistributionNormal:
+		// intの範囲いっぱいでも桁あふれしないようfloat64で計算する
+		mean, stddev := float64(lo)/2+float64(hi)/2, (float64(hi)-float64(lo))/6
+		for i := range data {
+			v := math.Round(rng.NormFloat64()*stddev + mean)
+			switch {
+			case v <= float64(lo):
+				data[i] = lo
+			case v >= float64(hi):
+				data[i] = hi
+			default:
+				data[i] = int(v)
+			}
+		}
+	case domain_search.DistributionFewUnique:
+		pool := make([]int, 10)
+		for i := range pool {
+			pool[i] = randomIn(rng, lo, hi)
+		}
+		for i := range data {
+			data[i] = pool[rng.Intn(len(pool))]
+		}
+	default:
+		return nil, errors.New("unknown distribution")
+	}
+
+	if spec.Sorted {
+		sort.Ints(data)
+	}
+	return data, nil
+}
+
+// lo以上hi以下のランダムな値
+// 幅はuint64で求めるため、intの範囲いっぱいでも桁あふれしない。intに収まる幅では rng.Intn と同じ値になる
+func randomIn(rng *rand.Rand, lo, hi int) int {
+	span := uint64(hi) - uint64(lo)
+	if span < math.MaxInt {
+		return lo + rng.Intn(int(span)+1)
+	}
+	// 幅がintに収まらない場合は、範囲外の値を捨てて引き直す(棄却の確率は1/2未満)
+	for {
+		if v := rng.Uint64(); v <= span {
+			return lo + int(v)
+		}
+	}
+}
+
+// 探索するtargetを生成する
+// 半分はデータに含まれる値、残りは範囲内のランダムな値(含まれないこともある)
+func generateTargets(data []int, count int, seed int64) []int {
+	rng := rand.New(rand.NewSource(seed))
+	lo, hi := 0, 0
+	for i, v := range data {
+		if i == 0 || v < lo {
+			lo = v
+		}
+		if i == 0 || v > hi {
+			hi = v
+		}
+	}
+
+	targets := make([]int, count)
+	for i := range targets {
+		if i%2 == 0 && len(data) > 0 {
+			targets[i] = data[rng.Intn(len(data))]
+		} else {
+			targets[i] = randomIn(rng, lo, min(hi, math.MaxInt-1)+1)
+		}
+	}
+	return targets
+}
+
+// 探索アルゴリズムを比較する
+func (u *BenchmarkUsecase) Run(req domain_search.BenchmarkRequest) (domain_search.BenchmarkResult, error) {
+	u.Logger.InfoLog.Println("Benchmark called")
+
+	// データの準備
+	var data []int
+	var seed int64
+	switch {
+	case req.Dataset != nil && req.Data != nil:
+		return domain_search.BenchmarkResult{}, errors.New("either dataset or data must be specified, not both")
+	case req.Dataset != nil:
+		generated, err := u.GenerateDataset(*req.Dataset)
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to generate dataset: %v", err)
+			return domain_search.BenchmarkResult{}, err
+		}
+		data, seed = generated, req.Dataset.Seed
+	case req.Data != nil:
+		data = req.Data
+	default:
+		return domain_search.BenchmarkResult{}, errors.New("dataset or data is required")
+	}
+	sorted := sort.IntsAreSorted(data)
+
+	// アルゴリズムのチェック
+	if len(req.Algorithms) == 0 {
+		return domain_search.BenchmarkResult{}, errors.New("algorithms is empty")
+	}
+	for _, a := range req.Algorithms {
+		if _, ok := countingSearches[a]; !ok {
+			return domain_search.BenchmarkResult{}, errors.New("unknown algorithm: " + string(a))
+		}
+		if a.RequiresSorted() && !sorted {
+			return domain_search.BenchmarkResult{}, errors.New("algorithm requires sorted data: " + string(a))
+		}
+	}
+
+	// 既定値
+	iterations, warmup := req.Iterations, req.Warmup
+	if iterations <= 0 {
+		iterations = DefaultBenchmarkIterations
+	}
+	if warmup < 0 {
+		warmup = 0
+	}
+	if req.Warmup == 0 && req.Iterations == 0 {
+		warmup = DefaultBenchmarkWarmup
+	}
+	targets := req.Targets
+	if targets == nil {
+		count := req.TargetCount
+		if count <= 0 {
+			count = DefaultBenchmarkTargetCount
+		}
+		targets = generateTargets(data, count, seed)
+	}
+
+	result := domain_search.BenchmarkResult{
+		Size:       len(data),
+		Sorted:     sorted,
+		Seed:       seed,
+		Targets:    len(targets),
+		Iterations: iterations,
+		Warmup:     warmup,
+		Results:    []domain_search.AlgorithmStats{},
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, a := range req.Algorithms {
+		result.Results = append(result.Results, measure(a, countingSearches[a], data, targets, iterations, warmup))
+	}
+
+	u.Logger.InfoLog.Printf("Benchmark completed: size=%d targets=%d algorithms=%d", len(data), len(targets), len(req.Algorithms))
+	return result, nil
+}
+
+// 1つのアルゴリズムを計測する
+func measure(algorithm domain_search.SearchAlgorithm, search countingSearch, data, targets []int, iterations, warmup int) domain_search.AlgorithmStats {
+	// 1回の実行(全てのtargetを探索)
+	run := func() (comparisons int64, found int) {
+		for _, t := range targets {
+			index, c := search(data, t)
+			comparisons += int64(c)
+			if index >= 0 {
+				found++
+			}
+		}
+		return comparisons, found
+	}
+
+	// ウォームアップ
+	for i := 0; i < warmup; i++ {
+		run()
+	}
+
+	// 計測
+	durations := make([]int64, iterations)
+	var comparisons int64
+	var found int
+	// アロケーションはプロセス全体の値の差分のため、同時に処理中の他のリクエストの分も含む概算
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	for i := range durations {
+		start := time.Now()
+		comparisons, found = run()
+		durations[i] = time.Since(start).Nanoseconds()
+	}
+	runtime.ReadMemStats(&after)
+
+	// 集計
+	var total int64
+	for _, d := range durations {
+		total += d
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return domain_search.AlgorithmStats{
+		Algorithm:    algorithm,
+		Runs:         iterations,
+		MinNs:        durations[0],
+		MedianNs:     percentile(durations, 50),
+		P95Ns:        percentile(durations, 95),
+		MeanNs:       total / int64(iterations),
+		Comparisons:  comparisons,
+		ApproxAllocs: int64(after.Mallocs - before.Mallocs),
+		ApproxBytes:  int64(after.TotalAlloc - before.TotalAlloc),
+		Found:        found,
+	}
+}
+
+// パーセンタイル(最近傍順位法)
+// sortedは昇順にソート済みであること
+func percentile(sorted []int64, p int) int64 {
+	rank := int(math.Ceil(float64(p) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// 線形探索(比較回数付き)
+func linearSearchCount(arr []int, target int) (int, int) {
+	for i, num := range arr {
+		if num == target {
+			return i, i + 1
+		}
+	}
+	return -1, len(arr)
+}
+
+// 二分探索(比較回数付き)
+func binarySearchCount(arr []int, target int) (int, int) {
+	return binarySearchRange(arr, target, 0, len(arr)-1)
+}
+
+// 範囲を指定した二分探索(比較回数付き)
+func binarySearchRange(arr []int, target, low, high int) (int, int) {
+	comparisons := 0
+	for low <= high {
+		mid := low + (high-low)/2
+		comparisons++
+		if arr[mid] == target {
+			return mid, comparisons
+		}
+		if arr[mid] < target {
+			low = mid + 1
+		} else {
+			high = mid - 1
+		}
+	}
+	return -1, comparisons
+}
+
+// ジャンプ探索(比較回数付き)
+// √nずつ飛ばして範囲を絞り、その範囲を線形探索する
+func jumpSearchCount(arr []int, target int) (int, int) {
+	n := len(arr)
+	if n == 0 {
+		return -1, 0
+	}
+	step := int(math.Sqrt(float64(n)))
+	comparisons := 0
+	prev, curr := 0, step
+	for {
+		comparisons++
+		if arr[min(curr, n)-1] >= target {
+			break
+		}
+		prev, curr = curr, curr+step
+		if prev >= n {
+			return -1, comparisons
+		}
+	}
+	for i := prev; i < min(curr, n); i++ {
+		comparisons++
+		if arr[i] == target {
+			return i, comparisons
+		}
+	}
+	return -1, comparisons
+}
+
+// 補間探索(比較回数付き)
+// 値の大きさから位置を推定する。一様分布のデータで効果が高い
+func interpolationSearchCount(arr []int, target int) (int, int) {
+	low, high := 0, len(arr)-1
+	comparisons := 0
+	for low <= high && target >= arr[low] && target <= arr[high] {
+		if arr[high] == arr[low] {
+			comparisons++
+			if arr[low] == target {
+				return low, comparisons
+			}
+			return -1, comparisons
+		}
+		// 値の差は桁あふれしないようfloat64で求め、丸めで範囲外になった位置は端に寄せる
+		pos := low
+		if span := float64(arr[high]) - float64(arr[low]); span > 0 {
+			pos += int((float64(target) - float64(arr[low])) * float64(high-low) / span)
+		}
+		pos = min(max(pos, low), high)
+		comparisons++
+		switch {
+		case arr[pos] == target:
+			return pos, comparisons
+		case arr[pos] < target:
+			low = pos + 1
+		default:
+			high = pos - 1
+		}
+	}
+	return -1, comparisons
+}
+
+// 指数探索(比較回数付き)
+// 1, 2, 4, 8...と範囲を広げてから二分探索する。先頭付近の値に強い
+func exponentialSearchCount(arr []int, target int) (int, int) {
+	n := len(arr)
+	if n == 0 {
+		return -1, 0
+	}
+	comparisons := 1
+	if arr[0] == target {
+		return 0, comparisons
+	}
+	bound := 1
+	for bound < n {
+		comparisons++
+		if arr[bound] >= target {
+			break
+		}
+		bound *= 2
+	}
+	index, c := binarySearchRange(arr, target, bound/2, min(bound, n-1))
+	return index, comparisons + c
+}
@@ -0,0 +1,417 @@
+package search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	"container/heap"
+	"errors"
+	"time"
+)
+
+// グラフ探索ユースケース(IF)
+type IGraphUsecase interface {
+	// 幅優先探索の訪問順
+	BFS(input domain_search.GraphInput, start string) (domain_search.GraphResult, error)
+	// 深さ優先探索の訪問順
+	DFS(input domain_search.GraphInput, start string) (domain_search.GraphResult, error)
+	// 連結成分
+	ConnectedComponents(input domain_search.GraphInput) (domain_search.GraphResult, error)
+	// 閉路検出
+	DetectCycle(input domain_search.GraphInput) (domain_search.GraphResult, error)
+	// トポロジカルソート
+	TopologicalSort(input domain_search.GraphInput) (domain_search.GraphResult, error)
+	// 強連結成分
+	StronglyConnectedComponents(input domain_search.GraphInput) (domain_search.GraphResult, error)
+}
+
+// グラフ探索ユースケース(Impl)
+type GraphUsecase struct {
+	Logger *pkg_logger.AppLogger
+}
+
+// グラフ探索ユースケースのインスタンス化
+func NewGraphUsecase(l *pkg_logger.AppLogger) IGraphUsecase {
+	return &GraphUsecase{
+		Logger: l,
+	}
+}
+
+// グラフを作成し、アルゴリズムを実行して実行時間を計測する
+func (u *GraphUsecase) run(algorithm string, input domain_search.GraphInput, fn func(g *domain_search.Graph, result *domain_search.GraphResult) error) (domain_search.GraphResult, error) {
+	u.Logger.InfoLog.Printf("%s called", algorithm)
+
+	g, err := domain_search.NewGraph(input)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Invalid graph: %v", err)
+		return domain_search.GraphResult{}, err
+	}
+
+	result := domain_search.GraphResult{
+		Algorithm: algorithm,
+		Nodes:     len(g.Nodes),
+		Edges:     g.EdgeCount(),
+	}
+	start := time.Now()
+	if err := fn(g, &result); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to run %s: %v", algorithm, err)
+		return domain_search.GraphResult{}, err
+	}
+	result.ElapsedNs = time.Since(start).Nanoseconds()
+
+	u.Logger.InfoLog.Printf("%s completed: nodes=%d edges=%d elapsed=%dns", algorithm, result.Nodes, result.Edges, result.ElapsedNs)
+	return result, nil
+}
+
+// 探索の開始ノードを決める
+// 指定が無い場合は全ノードを順に起点とする(森全体を探索する)
+func startNodes(g *domain_search.Graph, start string) ([]int, error) {
+	if start == "" {
+		nodes := make([]int, len(g.Nodes))
+		for i := range nodes {
+			nodes[i] = i
+		}
+		return nodes, nil
+	}
+	i, ok := g.Index(start)
+	if !ok {
+		return nil, errors.New("start node not found")
+	}
+	return []int{i}, nil
+}
+
+// BFS（幅優先探索）
+// [採用パターン]
+// - 最短経路（非加重）
+// - レベル順の走査
+func (u *GraphUsecase) BFS(input domain_search.GraphInput, start string) (domain_search.GraphResult, error) {
+	return u.run("bfs", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		starts, err := startNodes(g, start)
+		if err != nil {
+			return err
+		}
+
+		visited := make([]bool, len(g.Nodes))
+		order := []int{}
+		for _, s := range starts {
+			if visited[s] {
+				continue
+			}
+			visited[s] = true
+			queue := []int{s}
+			for len(queue) > 0 {
+				n := queue[0]
+				queue = queue[1:]
+				order = append(order, n)
+				for _, e := range g.Adj[n] {
+					if !visited[e.To] {
+						visited[e.To] = true
+						queue = append(queue, e.To)
+					}
+				}
+			}
+		}
+
+		result.Order = g.IDs(order)
+		result.Visited = len(order)
+		return nil
+	})
+}
+
+// DFS（深さ優先探索）
+// 明示的なスタックで実装し、隣接リストの順に訪問する(行きがけ順)
+// [採用パターン]
+// - 到達可能性の確認
+// - 閉路検出やトポロジカルソートの基礎
+func (u *GraphUsecase) DFS(input domain_search.GraphInput, start string) (domain_search.GraphResult, error) {
+	return u.run("dfs", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		starts, err := startNodes(g, start)
+		if err != nil {
+			return err
+		}
+
+		visited := make([]bool, len(g.Nodes))
+		order := []int{}
+		for _, s := range starts {
+			stack := []int{s}
+			for len(stack) > 0 {
+				n := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				if visited[n] {
+					continue
+				}
+				visited[n] = true
+				order = append(order, n)
+				// 隣接リストの先頭から訪問するため、逆順に積む
+				for i := len(g.Adj[n]) - 1; i >= 0; i-- {
+					if to := g.Adj[n][i].To; !visited[to] {
+						stack = append(stack, to)
+					}
+				}
+			}
+		}
+
+		result.Order = g.IDs(order)
+		result.Visited = len(order)
+		return nil
+	})
+}
+
+// 連結成分
+// 有向グラフの場合は辺の向きを無視した弱連結成分を求める
+func (u *GraphUsecase) ConnectedComponents(input domain_search.GraphInput) (domain_search.GraphResult, error) {
+	return u.run("components", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		// 向きを無視した隣接リスト
+		undirected := g.Adj
+		if g.Directed {
+			undirected = make([][]domain_search.GraphEdge, len(g.Nodes))
+			for from, edges := range g.Adj {
+				for _, e := range edges {
+					undirected[from] = append(undirected[from], e)
+					undirected[e.To] = append(undirected[e.To], domain_search.GraphEdge{To: from, Weight: e.Weight})
+				}
+			}
+		}
+
+		visited := make([]bool, len(g.Nodes))
+		order := []int{}
+		components := [][]string{}
+		for s := range g.Nodes {
+			if visited[s] {
+				continue
+			}
+			visited[s] = true
+			component := []int{}
+			queue := []int{s}
+			for len(queue) > 0 {
+				n := queue[0]
+				queue = queue[1:]
+				component = append(component, n)
+				for _, e := range undirected[n] {
+					if !visited[e.To] {
+						visited[e.To] = true
+						queue = append(queue, e.To)
+					}
+				}
+			}
+			order = append(order, component...)
+			components = append(components, g.IDs(component))
+		}
+
+		result.Order = g.IDs(order)
+		result.Components = components
+		result.Visited = len(order)
+		return nil
+	})
+}
+
+// 閉路検出
+// 有向グラフは訪問中(灰色)のノードへの辺、無向グラフは親以外の訪問済みノードへの辺で閉路を検出する
+func (u *GraphUsecase) DetectCycle(input domain_search.GraphInput) (domain_search.GraphResult, error) {
+	return u.run("cycle", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		const (
+			white = iota // 未訪問
+			gray         // 訪問中
+			black        // 訪問済み
+		)
+
+		// スタックの要素(ノードと次に調べる辺の位置)
+		type frame struct {
+			node, next int
+			// 無向グラフで親へ戻る辺を1本だけ読み飛ばすためのフラグ
+			parent        int
+			skippedParent bool
+		}
+
+		color := make([]int, len(g.Nodes))
+		visited := 0
+		var cycle []int
+		for s := range g.Nodes {
+			if color[s] != white || cycle != nil {
+				continue
+			}
+			color[s] = gray
+			visited++
+			stack := []*frame{{node: s, parent: -1}}
+			for len(stack) > 0 && cycle == nil {
+				f := stack[len(stack)-1]
+				if f.next >= len(g.Adj[f.node]) {
+					// 全ての辺を調べ終えた
+					color[f.node] = black
+					stack = stack[:len(stack)-1]
+					continue
+				}
+				to := g.Adj[f.node][f.next].To
+				f.next++
+
+				if !g.Directed && to == f.parent && !f.skippedParent {
+					f.skippedParent = true
+					continue
+				}
+
+				switch {
+				case color[to] == white:
+					color[to] = gray
+					visited++
+					stack = append(stack, &frame{node: to, parent: f.node})
+				case color[to] == gray:
+					// スタック上のtoから現在のノードまでが閉路
+					for i, fr := range stack {
+						if fr.node == to {
+							for _, c := range stack[i:] {
+								cycle = append(cycle, c.node)
+							}
+							break
+						}
+					}
+					cycle = append(cycle, to)
+				}
+			}
+		}
+
+		hasCycle := cycle != nil
+		result.HasCycle = &hasCycle
+		result.Cycle = g.IDs(cycle)
+		result.Visited = visited
+		return nil
+	})
+}
+
+// トポロジカルソート(Kahnのアルゴリズム)
+// 入次数0のノードが複数ある場合はノードの順序を優先し、結果を決定的にする
+func (u *GraphUsecase) TopologicalSort(input domain_search.GraphInput) (domain_search.GraphResult, error) {
+	return u.run("toposort", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		if !g.Directed {
+			return errors.New("graph must be directed")
+		}
+
+		indegree := make([]int, len(g.Nodes))
+		for _, edges := range g.Adj {
+			for _, e := range edges {
+				indegree[e.To]++
+			}
+		}
+
+		// 入次数0のノード(番号の小さい順に取り出す)
+		ready := &intMinHeap{}
+		for n, d := range indegree {
+			if d == 0 {
+				heap.Push(ready, n)
+			}
+		}
+
+		order := []int{}
+		for ready.Len() > 0 {
+			n := heap.Pop(ready).(int)
+			order = append(order, n)
+			for _, e := range g.Adj[n] {
+				indegree[e.To]--
+				if indegree[e.To] == 0 {
+					heap.Push(ready, e.To)
+				}
+			}
+		}
+
+		if len(order) != len(g.Nodes) {
+			return errors.New("graph has a cycle")
+		}
+
+		hasCycle := false
+		result.HasCycle = &hasCycle
+		result.Order = g.IDs(order)
+		result.Visited = len(order)
+		return nil
+	})
+}
+
+// 強連結成分(Tarjanのアルゴリズム)
+// 無向グラフの場合は連結成分と同じ結果になる
+func (u *GraphUsecase) StronglyConnectedComponents(input domain_search.GraphInput) (domain_search.GraphResult, error) {
+	return u.run("scc", input, func(g *domain_search.Graph, result *domain_search.GraphResult) error {
+		n := len(g.Nodes)
+		index := make([]int, n)
+		low := make([]int, n)
+		onStack := make([]bool, n)
+		for i := range index {
+			index[i] = -1
+		}
+
+		counter := 0
+		stack := []int{}
+		order := []int{}
+		components := [][]string{}
+
+		// 再帰の代わりに明示的なスタックを使う
+		type frame struct{ node, next int }
+		for s := 0; s < n; s++ {
+			if index[s] >= 0 {
+				continue
+			}
+			callStack := []frame{{node: s}}
+			index[s], low[s] = counter, counter
+			counter++
+			stack = append(stack, s)
+			onStack[s] = true
+			order = append(order, s)
+
+			for len(callStack) > 0 {
+				f := &callStack[len(callStack)-1]
+				v := f.node
+				if f.next < len(g.Adj[v]) {
+					w := g.Adj[v][f.next].To
+					f.next++
+					if index[w] < 0 {
+						index[w], low[w] = counter, counter
+						counter++
+						stack = append(stack, w)
+						onStack[w] = true
+						order = append(order, w)
+						callStack = append(callStack, frame{node: w})
+					} else if onStack[w] {
+						low[v] = min(low[v], index[w])
+					}
+					continue
+				}
+
+				// vの探索が終わった
+				callStack = callStack[:len(callStack)-1]
+				if len(callStack) > 0 {
+					parent := callStack[len(callStack)-1].node
+					low[parent] = min(low[parent], low[v])
+				}
+				if low[v] == index[v] {
+					// vを根とする強連結成分を取り出す
+					component := []int{}
+					for {
+						w := stack[len(stack)-1]
+						stack = stack[:len(stack)-1]
+						onStack[w] = false
+						component = append(component, w)
+						if w == v {
+							break
+						}
+					}
+					components = append(components, g.IDs(component))
+				}
+			}
+		}
+
+		result.Order = g.IDs(order)
+		result.Components = components
+		result.Visited = len(order)
+		return nil
+	})
+}
+
+// 最小値を取り出すヒープ(トポロジカルソート用)
+type intMinHeap []int
+
+func (h intMinHeap) Len() int           { return len(h) }
+func (h intMinHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h intMinHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *intMinHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *intMinHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,270 @@
+package search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	pkg_logger "backend/internal/pkg/logger"
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// 迷路ユースケース(IF)
+type IMazeUsecase interface {
+	// 迷路を生成する
+	Generate(spec domain_search.MazeSpec) (domain_search.MazeResult, error)
+	// テキストを迷路に変換する(エラーの場合は行番号を返す)
+	Import(text string) ([][]int, int, error)
+	// 迷路をテキストに変換する
+	Export(grid [][]int, format domain_search.MazeFormat) (string, error)
+}
+
+// 迷路ユースケース(Impl)
+type MazeUsecase struct {
+	Logger *pkg_logger.AppLogger
+}
+
+// 迷路ユースケースのインスタンス化
+func NewMazeUsecase(l *pkg_logger.AppLogger) IMazeUsecase {
+	return &MazeUsecase{
+		Logger: l,
+	}
+}
+
+// 迷路を生成する
+// 乱数はseedのみから決まるため、同じ条件からは常に同じ迷路が生成される
+func (u *MazeUsecase) Generate(spec domain_search.MazeSpec) (domain_search.MazeResult, error) {
+	u.Logger.InfoLog.Printf("Maze generate called: %dx%d algorithm=%s seed=%d", spec.Rows, spec.Cols, spec.Algorithm, spec.Seed)
+
+	if spec.Rows < 1 || spec.Cols < 1 || spec.Rows%2 == 0 || spec.Cols%2 == 0 {
+		return domain_search.MazeResult{}, errors.New("rows and cols must be odd")
+	}
+	density := 1.0
+	if spec.Density != nil {
+		density = *spec.Density
+	}
+	if density < 0 || density > 1 {
+		return domain_search.MazeResult{}, errors.New("density must be between 0 and 1")
+	}
+	if spec.Algorithm == "" {
+		spec.Algorithm = domain_search.MazeBacktracker
+	}
+
+	m := newMaze(spec.Rows, spec.Cols, rand.New(rand.NewSource(spec.Seed)))
+	switch spec.Algorithm {
+	case domain_search.MazeBacktracker:
+		m.backtracker()
+	case domain_search.MazePrim:
+		m.prim()
+	case domain_search.MazeKruskal:
+		m.kruskal()
+	default:
+		return domain_search.MazeResult{}, errors.New("unknown maze algorithm")
+	}
+	m.braid(1 - density)
+
+	text, _ := domain_search.FormatMaze(m.grid, domain_search.MazeFormatASCII)
+	return domain_search.MazeResult{
+		Rows:      spec.Rows,
+		Cols:      spec.Cols,
+		Algorithm: spec.Algorithm,
+		Seed:      spec.Seed,
+		Grid:      m.grid,
+		Text:      text,
+	}, nil
+}
+
+// テキストを迷路に変換する
+func (u *MazeUsecase) Import(text string) ([][]int, int, error) {
+	u.Logger.InfoLog.Println("Maze import called")
+
+	grid, line, err := domain_search.ParseMaze(text)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to parse maze at line %d: %v", line, err)
+		return nil, line, err
+	}
+	return grid, 0, nil
+}
+
+// 迷路をテキストに変換する
+func (u *MazeUsecase) Export(grid [][]int, format domain_search.MazeFormat) (string, error) {
+	u.Logger.InfoLog.Println("Maze export called")
+
+	text, err := domain_search.FormatMaze(grid, format)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to format maze: %v", err)
+		return "", err
+	}
+	return text, nil
+}
+
+// 生成中の迷路
+// 部屋は偶数の座標にあり、部屋同士の間のマスを通路にすることで部屋をつなぐ
+type maze struct {
+	grid       [][]int
+	rows, cols int // 部屋の行数・列数
+	rng        *rand.Rand
+}
+
+// 全てのマスを壁にし、部屋だけを通路にした迷路を作成する
+func newMaze(height, width int, rng *rand.Rand) *maze {
+	grid := make([][]int, height)
+	for i := range grid {
+		grid[i] = make([]int, width)
+		for j := range grid[i] {
+			if i%2 == 1 || j%2 == 1 {
+				grid[i][j] = domain_search.MazeWall
+			}
+		}
+	}
+	return &maze{grid: grid, rows: (height + 1) / 2, cols: (width + 1) / 2, rng: rng}
+}
+
+// 部屋の番号
+func (m *maze) id(r, c int) int {
+	return r*m.cols + c
+}
+
+// 隣接する部屋(上下左右の順)
+func (m *maze) neighbors(id int) []int {
+	r, c := id/m.cols, id%m.cols
+	result := make([]int, 0, 4)
+	if r > 0 {
+		result = append(result, m.id(r-1, c))
+	}
+	if r < m.rows-1 {
+		result = append(result, m.id(r+1, c))
+	}
+	if c > 0 {
+		result = append(result, m.id(r, c-1))
+	}
+	if c < m.cols-1 {
+		result = append(result, m.id(r, c+1))
+	}
+	return result
+}
+
+// 2つの部屋の間の壁を取り除く
+func (m *maze) connect(a, b int) {
+	ar, ac := a/m.cols, a%m.cols
+	br, bc := b/m.cols, b%m.cols
+	m.grid[ar+br][ac+bc] = domain_search.MazePath
+}
+
+// 再帰的バックトラッキング
+// 大きな迷路でもスタックが溢れないよう、明示的なスタックで実装する
+func (m *maze) backtracker() {
+	visited := make([]bool, m.rows*m.cols)
+	stack := []int{0}
+	visited[0] = true
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+		candidates := []int{}
+		for _, n := range m.neighbors(cur) {
+			if !visited[n] {
+				candidates = append(candidates, n)
+			}
+		}
+		if len(candidates) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		next := candidates[m.rng.Intn(len(candidates))]
+		m.connect(cur, next)
+		visited[next] = true
+		stack = append(stack, next)
+	}
+}
+
+// プリム法
+// 迷路に含まれる部屋に隣接する部屋(フロンティア)からランダムに1つ選び、迷路につなげる
+func (m *maze) prim() {
+	inMaze := make([]bool, m.rows*m.cols)
+	inFrontier := make([]bool, m.rows*m.cols)
+	frontier := []int{}
+	add := func(id int) {
+		inMaze[id] = true
+		for _, n := range m.neighbors(id) {
+			if !inMaze[n] && !inFrontier[n] {
+				inFrontier[n] = true
+				frontier = append(frontier, n)
+			}
+		}
+	}
+
+	add(0)
+	for len(frontier) > 0 {
+		i := m.rng.Intn(len(frontier))
+		cur := frontier[i]
+		frontier[i] = frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+
+		connected := []int{}
+		for _, n := range m.neighbors(cur) {
+			if inMaze[n] {
+				connected = append(connected, n)
+			}
+		}
+		m.connect(cur, connected[m.rng.Intn(len(connected))])
+		add(cur)
+	}
+}
+
+// クラスカル法
+// 部屋の間の壁をランダムな順に見て、別々のグループに属する部屋をつなぐ
+func (m *maze) kruskal() {
+	type edge struct{ a, b int }
+	edges := []edge{}
+	for r := 0; r < m.rows; r++ {
+		for c := 0; c < m.cols; c++ {
+			if r < m.rows-1 {
+				edges = append(edges, edge{m.id(r, c), m.id(r+1, c)})
+			}
+			if c < m.cols-1 {
+				edges = append(edges, edge{m.id(r, c), m.id(r, c+1)})
+			}
+		}
+	}
+	m.rng.Shuffle(len(edges), func(i, j int) { edges[i], edges[j] = edges[j], edges[i] })
+
+	// Union-Find
+	parent := make([]int, m.rows*m.cols)
+	for i := range parent {
+		parent[i] = i
+	}
+	find := func(x int) int {
+		for parent[x] != x {
+			parent[x] = parent[parent[x]]
+			x = parent[x]
+		}
+		return x
+	}
+
+	for _, e := range edges {
+		ra, rb := find(e.a), find(e.b)
+		if ra != rb {
+			parent[ra] = rb
+			m.connect(e.a, e.b)
+		}
+	}
+}
+
+// 部屋の間に残っている壁のうち、ratioの割合を取り除いてループを作る
+func (m *maze) braid(ratio float64) {
+	if ratio <= 0 {
+		return
+	}
+	walls := [][2]int{}
+	for i, row := range m.grid {
+		for j, cell := range row {
+			// 部屋の間の壁(柱ではないもの)
+			if cell == domain_search.MazeWall && (i%2 == 0) != (j%2 == 0) {
+				walls = append(walls, [2]int{i, j})
+			}
+		}
+	}
+	m.rng.Shuffle(len(walls), func(i, j int) { walls[i], walls[j] = walls[j], walls[i] })
+	n := int(math.Round(ratio * float64(len(walls))))
+	for _, w := range walls[:n] {
+		m.grid[w[0]][w[1]] = domain_search.MazePath
+	}
+}
@@ -1,6 +1,7 @@
 package search_usecase
 
 import (
+	domain_search "backend/internal/domain/search"
 	pkg_logger "backend/internal/pkg/logger"
 )
 
@@ -9,7 +10,17 @@ type ISearchUsecase interface {
 	LinearSearch(arr []int, target int) int
 	BinarySearch(arr []int, target int) int
 	BFS(graph [][]int) int
+	BFSPath(graph [][]int) (int, []domain_search.Point)
 	DFS(graph [][]int) bool
+	DFSPath(graph [][]int) (bool, []domain_search.Point)
+	Dijkstra(grid [][]int, costs [][]int, start, goal domain_search.Point, diagonal bool) (domain_search.PathResult, error)
+	AStar(grid [][]int, costs [][]int, start, goal domain_search.Point, diagonal bool, heuristic domain_search.Heuristic) (domain_search.PathResult, error)
+
+	// 途中経過を記録する版(tがnilの場合は記録しない)
+	LinearSearchTrace(arr []int, target int, t domain_search.Tracer) int
+	BinarySearchTrace(arr []int, target int, t domain_search.Tracer) int
+	BFSTrace(graph [][]int, t domain_search.Tracer) (int, []domain_search.Point)
+	DFSTrace(graph [][]int, t domain_search.Tracer) (bool, []domain_search.Point)
 }
 
 // Searchユースケース(Impl)
@@ -31,15 +42,29 @@ func NewSearchUsecase(l *pkg_logger.AppLogger) ISearchUsecase {
 // - 未ソート
 // - 部分一致検索など
 func (u *SearchUsecase) LinearSearch(arr []int, target int) int {
+	return u.LinearSearchTrace(arr, target, nil)
+}
+
+// 線形探索の途中経過付き版
+func (u *SearchUsecase) LinearSearchTrace(arr []int, target int, t domain_search.Tracer) int {
 	index := -1
 
 	for i, num := range arr {
+		if t != nil {
+			t(domain_search.TraceEvent{Type: domain_search.TraceCompare, Args: []int{i, num}})
+		}
 		if num == target {
 			index = i
 			break
 		}
 	}
 
+	if t != nil {
+		if index >= 0 {
+			t(domain_search.TraceEvent{Type: domain_search.TraceFound, Args: []int{index}})
+		}
+		t(domain_search.TraceEvent{Type: domain_search.TraceDone})
+	}
 	return index
 }
 
@@ -49,12 +74,23 @@ func (u *SearchUsecase) LinearSearch(arr []int, target int) int {
 // - ソート済み
 // - 単調増加/減少の条件での最大/最小探索
 func (u *SearchUsecase) BinarySearch(arr []int, target int) int {
+	return u.BinarySearchTrace(arr, target, nil)
+}
+
+// 二分探索の途中経過付き版
+func (u *SearchUsecase) BinarySearchTrace(arr []int, target int, t domain_search.Tracer) int {
 	low, high := 0, len(arr)-1
+	index := -1
 
 	for low <= high {
 		mid := low + (high-low)/2
+		if t != nil {
+			t(domain_search.TraceEvent{Type: domain_search.TraceBounds, Args: []int{low, high, mid}})
+			t(domain_search.TraceEvent{Type: domain_search.TraceCompare, Args: []int{mid, arr[mid]}})
+		}
 		if arr[mid] == target {
-			return mid
+			index = mid
+			break
 		}
 		if arr[mid] < target {
 			low = mid + 1
@@ -63,7 +99,13 @@ func (u *SearchUsecase) BinarySearch(arr []int, target int) int {
 		}
 	}
 
-	return -1
+	if t != nil {
+		if index >= 0 {
+			t(domain_search.TraceEvent{Type: domain_search.TraceFound, Args: []int{index}})
+		}
+		t(domain_search.TraceEvent{Type: domain_search.TraceDone})
+	}
+	return index
 }
 
 // BFS（幅優先探索）
@@ -73,6 +115,28 @@ func (u *SearchUsecase) BinarySearch(arr []int, target int) int {
 // - 迷路/レベル探索
 // - 伝播問題
 func (u *SearchUsecase) BFS(graph [][]int) int {
+	steps, _ := u.BFSPath(graph)
+	return steps
+}
+
+// BFS（幅優先探索）の経路付き版
+// 距離に加えて、スタートからゴールまでの経路を返す
+func (u *SearchUsecase) BFSPath(graph [][]int) (int, []domain_search.Point) {
+	return u.BFSTrace(graph, nil)
+}
+
+// BFS（幅優先探索）の途中経過付き版
+// 深さが変わるたびにキューの中身(フロンティア)を記録する
+func (u *SearchUsecase) BFSTrace(graph [][]int, t domain_search.Tracer) (int, []domain_search.Point) {
+	if t != nil {
+		defer t(domain_search.TraceEvent{Type: domain_search.TraceDone})
+	}
+
+	// 空のグリッドは到達不可
+	if len(graph) == 0 || len(graph[0]) == 0 {
+		return -1, nil
+	}
+
 	// 座標と距離
 	type Point struct {
 		X, Y, Dist int
@@ -87,21 +151,44 @@ func (u *SearchUsecase) BFS(graph [][]int) int {
 
 	// 初期化
 	visited := make([][]bool, H)
+	// 経路復元用の親
+	parent := make([][]domain_search.Point, H)
 	for i := range visited {
 		visited[i] = make([]bool, W)
+		parent[i] = make([]domain_search.Point, W)
 	}
 	// 訪問済み
 	visited[0][0] = true
+	if t != nil {
+		t(domain_search.TraceEvent{Type: domain_search.TraceVisit, Args: []int{0, 0}})
+		t(domain_search.TraceEvent{Type: domain_search.TraceEnqueue, Args: []int{0, 0, 1}})
+	}
+	// 記録済みのフロンティアの深さ
+	depth := 0
 
 	// キューが空になるまでループ
 	for len(queue) > 0 {
+		if t != nil && queue[0].Dist > depth {
+			depth = queue[0].Dist
+			cells := make([]domain_search.Point, len(queue))
+			for i, q := range queue {
+				cells[i] = domain_search.Point{Row: q.X, Col: q.Y}
+			}
+			t(domain_search.TraceEvent{Type: domain_search.TraceFrontier, Args: []int{depth}, Cells: cells})
+		}
 
 		p := queue[0]
 		queue = queue[1:]
+		if t != nil {
+			t(domain_search.TraceEvent{Type: domain_search.TraceDequeue, Args: []int{p.X, p.Y, len(queue)}})
+		}
 
 		if p.X == H-1 && p.Y == W-1 {
-			// ゴールに到達したら距離を返す
-			return p.Dist
+			// ゴールに到達したら距離と経路を返す
+			if t != nil {
+				t(domain_search.TraceEvent{Type: domain_search.TraceFound, Args: []int{p.X, p.Y}})
+			}
+			return p.Dist, buildPath(parent, domain_search.Point{Row: 0, Col: 0}, domain_search.Point{Row: p.X, Col: p.Y})
 		}
 
 		// 上下左右を探索
@@ -110,14 +197,33 @@ func (u *SearchUsecase) BFS(graph [][]int) int {
 			if nx >= 0 && ny >= 0 && nx < H && ny < W && !visited[nx][ny] && graph[nx][ny] == 0 {
 				// 訪問済みにする
 				visited[nx][ny] = true
+				parent[nx][ny] = domain_search.Point{Row: p.X, Col: p.Y}
 				// キューに追加
 				queue = append(queue, Point{nx, ny, p.Dist + 1})
+				if t != nil {
+					t(domain_search.TraceEvent{Type: domain_search.TraceVisit, Args: []int{nx, ny}})
+					t(domain_search.TraceEvent{Type: domain_search.TraceEnqueue, Args: []int{nx, ny, len(queue)}})
+				}
 			}
 		}
 	}
 
 	// ゴールに到達できなかったら-1を返す
-	return -1
+	return -1, nil
+}
+
+// 親の情報からstartからgoalまでの経路を復元する
+func buildPath(parent [][]domain_search.Point, start, goal domain_search.Point) []domain_search.Point {
+	path := []domain_search.Point{goal}
+	for p := goal; p != start; {
+		p = parent[p.Row][p.Col]
+		path = append(path, p)
+	}
+	// 逆順にする
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
 }
 
 // DFS（深さ優先探索）
@@ -127,36 +233,87 @@ func (u *SearchUsecase) BFS(graph [][]int) int {
 // - 組み合わせ列挙
 // - 到達可能性の確認
 func (u *SearchUsecase) DFS(graph [][]int) bool {
+	result, _ := u.DFSPath(graph)
+	return result
+}
+
+// DFS（深さ優先探索）の経路付き版
+// 到達可否に加えて、見つかった経路を返す(最短とは限らない)
+// 大きな迷路でもスタックが溢れないよう、再帰ではなく明示的なスタックで探索する
+func (u *SearchUsecase) DFSPath(graph [][]int) (bool, []domain_search.Point) {
+	return u.DFSTrace(graph, nil)
+}
+
+// DFS（深さ優先探索）の途中経過付き版
+func (u *SearchUsecase) DFSTrace(graph [][]int, t domain_search.Tracer) (bool, []domain_search.Point) {
+	if t != nil {
+		defer t(domain_search.TraceEvent{Type: domain_search.TraceDone})
+	}
+
+	// 空のグリッド、スタート地点が壁の場合は到達不可
+	if len(graph) == 0 || len(graph[0]) == 0 || graph[0][0] == 1 {
+		return false, nil
+	}
+
 	H := len(graph)
 	W := len(graph[0])
 
 	visited := make([][]bool, H)
+	// 経路復元用の親
+	parent := make([][]domain_search.Point, H)
 	for i := range visited {
 		visited[i] = make([]bool, W)
+		parent[i] = make([]domain_search.Point, W)
+	}
+	// 下、上、右、左の順に探索する(再帰版と同じ順序)
+	dirs := [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+	// スタック
+	start := domain_search.Point{Row: 0, Col: 0}
+	stack := []domain_search.Point{start}
+	if t != nil {
+		t(domain_search.TraceEvent{Type: domain_search.TracePush, Args: []int{0, 0, 1}})
 	}
 
-	var dfs func(x, y int) bool
-	dfs = func(x, y int) bool {
-		// 境界外チェック
-		if x < 0 || y < 0 || x >= H || y >= W {
-			return false
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if t != nil {
+			t(domain_search.TraceEvent{Type: domain_search.TracePop, Args: []int{p.Row, p.Col, len(stack)}})
 		}
-		// 壁チェック、訪問済みチェック
-		if graph[x][y] == 1 || visited[x][y] {
-			return false
+		if visited[p.Row][p.Col] {
+			continue
 		}
+
 		// ゴール到達チェック
-		if x == H-1 && y == W-1 {
-			return true
+		if p.Row == H-1 && p.Col == W-1 {
+			if t != nil {
+				t(domain_search.TraceEvent{Type: domain_search.TraceFound, Args: []int{p.Row, p.Col}})
+			}
+			return true, buildPath(parent, start, p)
 		}
 
 		// 訪問済みにする
-		visited[x][y] = true
+		visited[p.Row][p.Col] = true
+		if t != nil {
+			t(domain_search.TraceEvent{Type: domain_search.TraceVisit, Args: []int{p.Row, p.Col}})
+		}
 
-		// 上下左右を再帰的に探索
-		return dfs(x+1, y) || dfs(x-1, y) || dfs(x, y+1) || dfs(x, y-1)
+		// 先に探索する方向が最後に積まれるよう、逆順に積む
+		for i := len(dirs) - 1; i >= 0; i-- {
+			nx, ny := p.Row+dirs[i][0], p.Col+dirs[i][1]
+			// 境界外チェック、壁チェック、訪問済みチェック
+			if nx < 0 || ny < 0 || nx >= H || ny >= W || graph[nx][ny] == 1 || visited[nx][ny] {
+				continue
+			}
+			parent[nx][ny] = p
+			stack = append(stack, domain_search.Point{Row: nx, Col: ny})
+			if t != nil {
+				t(domain_search.TraceEvent{Type: domain_search.TracePush, Args: []int{nx, ny, len(stack)}})
+			}
+		}
 	}
 
-	// スタート地点から探索
-	return dfs(0, 0)
+	// ゴールに到達できなかった
+	return false, nil
 }
@@ -0,0 +1,281 @@
+package search_usecase
+
+import (
+	domain_search "backend/internal/domain/search"
+	"container/heap"
+	"errors"
+	"math"
+)
+
+// 加重グリッドの経路探索
+// グリッドの値はBFS/DFS・迷路と同じく0が通路、1が壁
+// マスに入るコストはcostsで指定する(nilの場合は全て1)
+
+// 上下左右
+var straightDirs = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}}
+
+// 斜め
+var diagonalDirs = [][2]int{{1, 1}, {1, -1}, {-1, 1}, {-1, -1}}
+
+// 優先度付きキューの要素
+type pathNode struct {
+	point    domain_search.Point
+	cost     float64 // スタートからの実コスト
+	priority float64 // cost + ヒューリスティック
+	seq      int     // 同じ優先度の場合の順序(結果を決定的にする)
+}
+
+// 優先度付きキュー(container/heap)
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int { return len(q) }
+func (q pathQueue) Less(i, j int) bool {
+	if q[i].priority != q[j].priority {
+		return q[i].priority < q[j].priority
+	}
+	return q[i].seq < q[j].seq
+}
+func (q pathQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x any)   { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// ダイクストラ法
+// 加重グリッド上の最短経路
+// [採用パターン]
+// - 最短経路（加重、負の辺なし）
+// - ゴールの方向に関する情報が無い場合
+func (u *SearchUsecase) Dijkstra(grid [][]int, costs [][]int, start, goal domain_search.Point, diagonal bool) (domain_search.PathResult, error) {
+	if err := validatePathGrid(grid, costs, start, goal); err != nil {
+		return domain_search.PathResult{}, err
+	}
+
+	return shortestPath(grid, costs, start, goal, diagonal, func(domain_search.Point) float64 { return 0 }), nil
+}
+
+// A*（エースター）
+// ヒューリスティックでゴールに近いノードから展開する最短経路探索
+// [採用パターン]
+// - 最短経路（加重）でゴールの位置が分かっている場合
+// - ゲームのパスファインディング
+func (u *SearchUsecase) AStar(grid [][]int, costs [][]int, start, goal domain_search.Point, diagonal bool, heuristic domain_search.Heuristic) (domain_search.PathResult, error) {
+	if err := validatePathGrid(grid, costs, start, goal); err != nil {
+		return domain_search.PathResult{}, err
+	}
+	// マンハッタン距離は斜め移動では過大評価になり、最短経路を返せない
+	if heuristic == domain_search.HeuristicManhattan && diagonal {
+		return domain_search.PathResult{}, errors.New("manhattan heuristic does not support diagonal moves")
+	}
+
+	// ヒューリスティックの既定値
+	if heuristic == "" {
+		if diagonal {
+			heuristic = domain_search.HeuristicOctile
+		} else {
+			heuristic = domain_search.HeuristicManhattan
+		}
+	}
+
+	// 最小の移動コストを掛けることで、コストが1以外のグリッドでも過大評価しないようにする
+	minCost := minPathCost(grid, costs)
+	var h func(p domain_search.Point) float64
+	switch heuristic {
+	case domain_search.HeuristicManhattan:
+		h = func(p domain_search.Point) float64 {
+			return minCost * float64(abs(p.Row-goal.Row)+abs(p.Col-goal.Col))
+		}
+	case domain_search.HeuristicEuclidean:
+		h = func(p domain_search.Point) float64 {
+			return minCost * math.Hypot(float64(p.Row-goal.Row), float64(p.Col-goal.Col))
+		}
+	case domain_search.HeuristicChebyshev:
+		h = func(p domain_search.Point) float64 {
+			return minCost * float64(max(abs(p.Row-goal.Row), abs(p.Col-goal.Col)))
+		}
+	case domain_search.HeuristicOctile:
+		h = func(p domain_search.Point) float64 {
+			dx, dy := abs(p.Row-goal.Row), abs(p.Col-goal.Col)
+			return minCost * (float64(max(dx, dy)) + (math.Sqrt2-1)*float64(min(dx, dy)))
+		}
+	case domain_search.HeuristicZero:
+		h = func(domain_search.Point) float64 { return 0 }
+	default:
+		return domain_search.PathResult{}, errors.New("unknown heuristic")
+	}
+
+	return shortestPath(grid, costs, start, goal, diagonal, h), nil
+}
+
+// 経路探索の入力チェック
+func validatePathGrid(grid [][]int, costs [][]int, start, goal domain_search.Point) error {
+	if len(grid) == 0 || len(grid[0]) == 0 {
+		return errors.New("grid is empty")
+	}
+	for _, row := range grid {
+		for _, cell := range row {
+			if cell != domain_search.GridPath && cell != domain_search.GridWall {
+				return errors.New("grid must contain only 0 or 1")
+			}
+		}
+	}
+	if costs != nil {
+		if len(costs) != len(grid) {
+			return errors.New("costs must have the same shape as grid")
+		}
+		for i, row := range costs {
+			if len(row) != len(grid[i]) {
+				return errors.New("costs must have the same shape as grid")
+			}
+			for _, cost := range row {
+				if cost < 0 {
+					return errors.New("costs must not be negative")
+				}
+			}
+		}
+	}
+	if !inGrid(grid, start) {
+		return errors.New("start is out of range")
+	}
+	if !inGrid(grid, goal) {
+		return errors.New("goal is out of range")
+	}
+	if grid[start.Row][start.Col] == domain_search.GridWall {
+		return errors.New("start is a wall")
+	}
+	if grid[goal.Row][goal.Col] == domain_search.GridWall {
+		return errors.New("goal is a wall")
+	}
+	return nil
+}
+
+// 座標がグリッド内かどうか(行ごとの長さが異なる場合も考慮する)
+func inGrid(grid [][]int, p domain_search.Point) bool {
+	return p.Row >= 0 && p.Row < len(grid) && p.Col >= 0 && p.Col < len(grid[p.Row])
+}
+
+// 壁でないかどうか
+func passable(grid [][]int, p domain_search.Point) bool {
+	return grid[p.Row][p.Col] != domain_search.GridWall
+}
+
+// マスに入るコスト(costsがnilの場合は1)
+func cellCost(costs [][]int, p domain_search.Point) float64 {
+	if costs == nil {
+		return 1
+	}
+	return float64(costs[p.Row][p.Col])
+}
+
+// 壁以外のマスの最小コスト
+func minPathCost(grid [][]int, costs [][]int) float64 {
+	minCost := math.Inf(1)
+	for i, row := range grid {
+		for j := range row {
+			p := domain_search.Point{Row: i, Col: j}
+			if passable(grid, p) {
+				minCost = math.Min(minCost, cellCost(costs, p))
+			}
+		}
+	}
+	return minCost
+}
+
+// ダイクストラ法/A*の共通処理
+// hが常に0の場合はダイクストラ法になる
+func shortestPath(grid [][]int, costs [][]int, start, goal domain_search.Point, diagonal bool, h func(domain_search.Point) float64) domain_search.PathResult {
+	H := len(grid)
+
+	// 初期化
+	dist := make([][]float64, H)
+	closed := make([][]bool, H)
+	parent := make([][]domain_search.Point, H)
+	for i := range grid {
+		W := len(grid[i])
+		dist[i] = make([]float64, W)
+		closed[i] = make([]bool, W)
+		parent[i] = make([]domain_search.Point, W)
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+		}
+	}
+
+	// 移動方向
+	dirs := straightDirs
+	if diagonal {
+		dirs = append(append([][2]int{}, straightDirs...), diagonalDirs...)
+	}
+
+	// 優先度付きキュー
+	seq := 0
+	queue := &pathQueue{{point: start, cost: 0, priority: h(start), seq: seq}}
+	dist[start.Row][start.Col] = 0
+	expanded := 0
+
+	for queue.Len() > 0 {
+		n := heap.Pop(queue).(pathNode)
+		p := n.point
+		// 既に確定済みのノードは読み飛ばす
+		if closed[p.Row][p.Col] {
+			continue
+		}
+		closed[p.Row][p.Col] = true
+		expanded++
+
+		// ゴールに到達したら経路を返す
+		if p == goal {
+			return domain_search.PathResult{
+				Found:    true,
+				Cost:     n.cost,
+				Path:     buildPath(parent, start, goal),
+				Expanded: expanded,
+			}
+		}
+
+		for _, d := range dirs {
+			next := domain_search.Point{Row: p.Row + d[0], Col: p.Col + d[1]}
+			if !inGrid(grid, next) || !passable(grid, next) || closed[next.Row][next.Col] {
+				continue
+			}
+
+			// 斜め移動は角をすり抜けられないようにする
+			step := cellCost(costs, next)
+			if d[0] != 0 && d[1] != 0 {
+				a := domain_search.Point{Row: p.Row + d[0], Col: p.Col}
+				b := domain_search.Point{Row: p.Row, Col: p.Col + d[1]}
+				if !inGrid(grid, a) || !inGrid(grid, b) || !passable(grid, a) || !passable(grid, b) {
+					continue
+				}
+				step *= math.Sqrt2
+			}
+
+			// より短い経路が見つかった場合は更新
+			cost := n.cost + step
+			if cost < dist[next.Row][next.Col] {
+				dist[next.Row][next.Col] = cost
+				parent[next.Row][next.Col] = p
+				seq++
+				heap.Push(queue, pathNode{point: next, cost: cost, priority: cost + h(next), seq: seq})
+			}
+		}
+	}
+
+	// ゴールに到達できなかった
+	return domain_search.PathResult{
+		Found:    false,
+		Cost:     -1,
+		Path:     []domain_search.Point{},
+		Expanded: expanded,
+	}
+}
+
+// 絶対値
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,460 @@
+package sort_usecase
+
+import (
+	domain_sort "backend/internal/domain/sort"
+	pkg_logger "backend/internal/pkg/logger"
+	"errors"
+	"math/bits"
+	"time"
+)
+
+// ソートユースケース(IF)
+type ISortUsecase interface {
+	// 要素をその場でソートする
+	Sort(algorithm domain_sort.Algorithm, items []domain_sort.Item, opts domain_sort.SortOptions) (domain_sort.SortResult, error)
+}
+
+// ソートユースケース(Impl)
+type SortUsecase struct {
+	Logger *pkg_logger.AppLogger
+}
+
+// ソートユースケースのインスタンス化
+func NewSortUsecase(l *pkg_logger.AppLogger) ISortUsecase {
+	return &SortUsecase{
+		Logger: l,
+	}
+}
+
+// アルゴリズムごとのソート関数
+var sorters = map[domain_sort.Algorithm]func(s *sorter){
+	domain_sort.AlgorithmQuick:     quickSort,
+	domain_sort.AlgorithmMerge:     mergeSort,
+	domain_sort.AlgorithmHeap:      heapSort,
+	domain_sort.AlgorithmInsertion: insertionSort,
+	domain_sort.AlgorithmRadix:     radixSort,
+	domain_sort.AlgorithmTim:       timSort,
+}
+
+// 要素をその場でソートする
+func (u *SortUsecase) Sort(algorithm domain_sort.Algorithm, items []domain_sort.Item, opts domain_sort.SortOptions) (domain_sort.SortResult, error) {
+	u.Logger.InfoLog.Printf("Sort called: algorithm=%s count=%d", algorithm, len(items))
+
+	fn, ok := sorters[algorithm]
+	if !ok {
+		u.Logger.ErrorLog.Printf("Unknown algorithm: %s", algorithm)
+		return domain_sort.SortResult{}, errors.New("unknown algorithm")
+	}
+	if algorithm == domain_sort.AlgorithmRadix && !domain_sort.AllInts(items) {
+		u.Logger.ErrorLog.Println("Radix sort supports integers only")
+		return domain_sort.SortResult{}, errors.New("radix sort supports integers only")
+	}
+
+	s := &sorter{
+		items:     items,
+		desc:      opts.Desc,
+		tiebreak:  opts.Stable && !algorithm.IsStable(),
+		maxSteps:  opts.MaxTraceSteps,
+		recording: opts.Trace,
+	}
+	start := time.Now()
+	fn(s)
+	elapsed := time.Since(start).Nanoseconds()
+
+	u.Logger.InfoLog.Printf("Sort completed: comparisons=%d elapsed=%dns", s.comparisons, elapsed)
+	return domain_sort.SortResult{
+		Algorithm:   algorithm,
+		Count:       len(items),
+		Stable:      algorithm.IsStable() || s.tiebreak,
+		Desc:        opts.Desc,
+		Comparisons: s.comparisons,
+		ElapsedNs:   elapsed,
+		Steps:       s.steps,
+		Truncated:   s.truncated,
+	}, nil
+}
+
+// ソートの状態(比較回数と途中経過を記録する)
+type sorter struct {
+	items       []domain_sort.Item
+	desc        bool
+	tiebreak    bool // 等しい要素を元の位置で比較する(不安定なソートを安定にする)
+	comparisons int64
+
+	recording bool
+	maxSteps  int
+	steps     []domain_sort.Step
+	truncated bool
+}
+
+// 途中経過を記録する
+func (s *sorter) record(op domain_sort.StepOp, i, j int) {
+	if !s.recording {
+		return
+	}
+	if len(s.steps) >= s.maxSteps {
+		s.recording, s.truncated = false, true
+		return
+	}
+	s.steps = append(s.steps, domain_sort.Step{Op: op, I: i, J: j})
+}
+
+// 要素の比較(a < b: 負、a == b: 0、a > b: 正)
+func (s *sorter) compare(a, b domain_sort.Item) int {
+	s.comparisons++
+	c := a.Key.Compare(b.Key)
+	if s.desc {
+		c = -c
+	}
+	if c == 0 && s.tiebreak {
+		c = a.Index - b.Index
+	}
+	return c
+}
+
+// i番目 < j番目 か
+func (s *sorter) less(i, j int) bool {
+	s.record(domain_sort.StepCompare, i, j)
+	return s.compare(s.items[i], s.items[j]) < 0
+}
+
+// i番目とj番目を交換する
+func (s *sorter) swap(i, j int) {
+	s.record(domain_sort.StepSwap, i, j)
+	s.items[i], s.items[j] = s.items[j], s.items[i]
+}
+
+// i番目に要素を書き込む
+func (s *sorter) set(i int, it domain_sort.Item) {
+	s.record(domain_sort.StepSet, i, it.Index)
+	s.items[i] = it
+}
+
+// 挿入ソート
+func insertionSort(s *sorter) {
+	insertionSortRange(s, 0, len(s.items))
+}
+
+// [lo, hi) の挿入ソート
+func insertionSortRange(s *sorter, lo, hi int) {
+	for i := lo + 1; i < hi; i++ {
+		for j := i; j > lo && s.less(j, j-1); j-- {
+			s.swap(j, j-1)
+		}
+	}
+}
+
+// クイックソート(イントロソート: 3-way分割、nintherピボット)
+// 小さい方の区間のみ再帰するため、再帰の深さはO(log n)に収まる
+// 分割が2·log₂n回を超えた区間はヒープソートに切り替え、意図的な入力でも最悪O(n log n)にする
+func quickSort(s *sorter) {
+	quickSortRange(s, 0, len(s.items)-1, 2*bits.Len(uint(len(s.items))))
+}
+
+// nintherでピボットを選ぶ区間の長さ(これより短い区間は3つの中央値)
+const nintherThreshold = 40
+
+// [lo, hi] のクイックソート(depthは残りの分割回数)
+func quickSortRange(s *sorter, lo, hi, depth int) {
+	for lo < hi {
+		if depth == 0 {
+			heapSortRange(s, lo, hi+1)
+			return
+		}
+		depth--
+
+		// ピボットをloに置く
+		s.swap(lo, choosePivot(s, lo, hi))
+
+		// [lo, lt) < pivot, [lt, i) == pivot, (gt, hi] > pivot
+		pivot := s.items[lo]
+		lt, i, gt := lo, lo+1, hi
+		for i <= gt {
+			s.record(domain_sort.StepCompare, i, lt)
+			c := s.compare(s.items[i], pivot)
+			switch {
+			case c < 0:
+				s.swap(lt, i)
+				lt++
+				i++
+			case c > 0:
+				s.swap(i, gt)
+				gt--
+			default:
+				i++
+			}
+		}
+
+		if lt-lo < hi-gt {
+			quickSortRange(s, lo, lt-1, depth)
+			lo = gt + 1
+		} else {
+			quickSortRange(s, gt+1, hi, depth)
+			hi = lt - 1
+		}
+	}
+}
+
+// ピボットの位置を選ぶ
+// 長い区間はninther(3つずつの中央値の中央値)、短い区間は両端と中央の中央値
+func choosePivot(s *sorter, lo, hi int) int {
+	mid := lo + (hi-lo)/2
+	if hi-lo+1 < nintherThreshold {
+		return median3(s, lo, mid, hi)
+	}
+	d := (hi - lo + 1) / 8
+	return median3(s,
+		median3(s, lo, lo+d, lo+2*d),
+		median3(s, mid-d, mid, mid+d),
+		median3(s, hi-2*d, hi-d, hi),
+	)
+}
+
+// 3つの位置のうち、中央値の要素の位置(要素は動かさない)
+func median3(s *sorter, a, b, c int) int {
+	if s.less(b, a) {
+		a, b = b, a
+	}
+	if !s.less(c, b) {
+		return b
+	}
+	if s.less(c, a) {
+		return a
+	}
+	return c
+}
+
+// マージソート(トップダウン)
+func mergeSort(s *sorter) {
+	buf := make([]domain_sort.Item, len(s.items))
+	mergeSortRange(s, buf, 0, len(s.items))
+}
+
+// [lo, hi) のマージソート
+func mergeSortRange(s *sorter, buf []domain_sort.Item, lo, hi int) {
+	if hi-lo < 2 {
+		return
+	}
+	mid := lo + (hi-lo)/2
+	mergeSortRange(s, buf, lo, mid)
+	mergeSortRange(s, buf, mid, hi)
+	merge(s, buf, lo, mid, hi)
+}
+
+// ソート済みの [lo, mid) と [mid, hi) をマージする
+func merge(s *sorter, buf []domain_sort.Item, lo, mid, hi int) {
+	copy(buf[lo:hi], s.items[lo:hi])
+	i, j := lo, mid
+	for k := lo; k < hi; k++ {
+		switch {
+		case i >= mid:
+			s.set(k, buf[j])
+			j++
+		case j >= hi:
+			s.set(k, buf[i])
+			i++
+		default:
+			s.record(domain_sort.StepCompare, j, i)
+			// 等しい場合は左側を先にして安定にする
+			if s.compare(buf[j], buf[i]) < 0 {
+				s.set(k, buf[j])
+				j++
+			} else {
+				s.set(k, buf[i])
+				i++
+			}
+		}
+	}
+}
+
+// ヒープソート
+func heapSort(s *sorter) {
+	heapSortRange(s, 0, len(s.items))
+}
+
+// [lo, hi) のヒープソート
+func heapSortRange(s *sorter, lo, hi int) {
+	n := hi - lo
+	for i := n/2 - 1; i >= 0; i-- {
+		siftDown(s, lo, i, n)
+	}
+	for end := n - 1; end > 0; end-- {
+		s.swap(lo, lo+end)
+		siftDown(s, lo, 0, end)
+	}
+}
+
+// lo番目から始まる最大ヒープの[0, n)でi番目を下ろす
+func siftDown(s *sorter, lo, i, n int) {
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && s.less(lo+child, lo+child+1) {
+			child++
+		}
+		if !s.less(lo+i, lo+child) {
+			return
+		}
+		s.swap(lo+i, lo+child)
+		i = child
+	}
+}
+
+// 基数ソート(LSD、8ビットずつ8パス)
+// 整数のみ対象。符号ビットを反転して負の数も正しく並べる
+func radixSort(s *sorter) {
+	n := len(s.items)
+	if n < 2 {
+		return
+	}
+	keys := make([]uint64, n)
+	for i, it := range s.items {
+		keys[i] = radixKey(it.Key.Int, s.desc)
+	}
+	buf := make([]domain_sort.Item, n)
+	bufKeys := make([]uint64, n)
+
+	for shift := uint(0); shift < 64; shift += 8 {
+		var count [257]int
+		for _, k := range keys {
+			count[(k>>shift)&0xff+1]++
+		}
+		// 全ての要素が同じバケットのパスは省略する
+		if count[(keys[0]>>shift)&0xff+1] == n {
+			continue
+		}
+		for i := 1; i < len(count); i++ {
+			count[i] += count[i-1]
+		}
+		for i, k := range keys {
+			b := (k >> shift) & 0xff
+			buf[count[b]], bufKeys[count[b]] = s.items[i], k
+			count[b]++
+		}
+		for i := range buf {
+			s.set(i, buf[i])
+		}
+		keys, bufKeys = bufKeys, keys
+	}
+}
+
+// 基数ソート用のキー(符号なしで比較できるようにする)
+func radixKey(v int64, desc bool) uint64 {
+	k := uint64(v) ^ (1 << 63)
+	if desc {
+		k = ^k
+	}
+	return k
+}
+
+// ティムソートで挿入ソートする最小の長さの目安
+const timMinMerge = 32
+
+// ティムソート(簡易版、ギャロップモードなし)
+// 既存の昇順・降順の並び(ラン)を活かし、短いランは挿入ソートで伸ばしてからマージする
+func timSort(s *sorter) {
+	n := len(s.items)
+	if n < 2 {
+		return
+	}
+	minRun := timMinRun(n)
+	buf := make([]domain_sort.Item, n)
+
+	// ランのスタック(開始位置と長さ)
+	type run struct{ start, length int }
+	runs := []run{}
+
+	// スタックの不変条件を満たすまでマージする
+	mergeAt := func(i int) {
+		a, b := runs[i], runs[i+1]
+		merge(s, buf, a.start, b.start, b.start+b.length)
+		runs[i] = run{a.start, a.length + b.length}
+		runs = append(runs[:i+1], runs[i+2:]...)
+	}
+	collapse := func() {
+		for len(runs) > 1 {
+			i := len(runs) - 2
+			if i > 0 && runs[i-1].length <= runs[i].length+runs[i+1].length {
+				if runs[i-1].length < runs[i+1].length {
+					i--
+				}
+				mergeAt(i)
+			} else if runs[i].length <= runs[i+1].length {
+				mergeAt(i)
+			} else {
+				return
+			}
+		}
+	}
+
+	for lo := 0; lo < n; {
+		length := countRun(s, lo, n)
+		if length < minRun {
+			force := min(minRun, n-lo)
+			binaryInsertionSort(s, lo, lo+force, lo+length)
+			length = force
+		}
+		runs = append(runs, run{lo, length})
+		collapse()
+		lo += length
+	}
+	for len(runs) > 1 {
+		mergeAt(len(runs) - 2)
+	}
+}
+
+// ランの最小の長さ
+func timMinRun(n int) int {
+	r := 0
+	for n >= timMinMerge {
+		r |= n & 1
+		n >>= 1
+	}
+	return n + r
+}
+
+// loから始まるランの長さを返す
+// 狭義の降順のランは反転して昇順にする(等しい要素を含めないため安定性は保たれる)
+func countRun(s *sorter, lo, hi int) int {
+	end := lo + 1
+	if end == hi {
+		return 1
+	}
+	if s.less(end, lo) {
+		for end++; end < hi && s.less(end, end-1); end++ {
+		}
+		for i, j := lo, end-1; i < j; i, j = i+1, j-1 {
+			s.swap(i, j)
+		}
+	} else {
+		for end++; end < hi && !s.less(end, end-1); end++ {
+		}
+	}
+	return end - lo
+}
+
+// [lo, hi) の二分挿入ソート([lo, sorted) はソート済み)
+func binaryInsertionSort(s *sorter, lo, hi, sorted int) {
+	for i := sorted; i < hi; i++ {
+		pivot := s.items[i]
+		// pivotより大きい最初の位置(等しい要素の後ろに入れて安定にする)
+		left, right := lo, i
+		for left < right {
+			mid := left + (right-left)/2
+			s.record(domain_sort.StepCompare, i, mid)
+			if s.compare(pivot, s.items[mid]) < 0 {
+				right = mid
+			} else {
+				left = mid + 1
+			}
+		}
+		for j := i; j > left; j-- {
+			s.set(j, s.items[j-1])
+		}
+		if left != i {
+			s.set(left, pivot)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package usecase_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_rrule "backend/internal/pkg/rrule"
+	repository_todo "backend/internal/repository/todo"
+	"errors"
+	"time"
+)
+
+// 繰り返しのTodoユースケースの設定
+type RecurrenceOptions struct {
+	Horizon   time.Duration // 予定のTodoを先に作成しておく期間
+	BatchSize int           // スケジューラが1回に取得する繰り返しの数
+}
+
+// 繰り返しのTodoユースケース(IF)
+type ITodoRecurrenceUsecase interface {
+	// 繰り返しを作成し、先の期間の予定のTodoを作成する
+	CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, []domain_todo.Todo, error)
+	// 繰り返しを取得
+	GetRecurrence(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error)
+	// 繰り返しの予定を変更し、変更・作成したTodoを返す(変更した予定が先頭)
+	EditOccurrence(scope domain_todo.Scope, id string, edit domain_todo.OccurrenceEdit) ([]domain_todo.Todo, error)
+	// 予定のTodoの作成が遅れている繰り返しについて、now+Horizonより前の予定を作成し、作成した数を返す(スケジューラ用)
+	MaterializeDue(now time.Time) (int, error)
+}
+
+// 繰り返しのTodoユースケース(Impl)
+type TodoRecurrenceUsecase struct {
+	Logger               *pkg_logger.AppLogger
+	todoRepository       repository_todo.ITodoRepository
+	recurrenceRepository repository_todo.ITodoRecurrenceRepository
+	options              RecurrenceOptions
+}
+
+// 繰り返しのTodoユースケースのインスタンス化
+func NewTodoRecurrenceUsecase(l *pkg_logger.AppLogger, tr repository_todo.ITodoRepository, rr repository_todo.ITodoRecurrenceRepository, opts RecurrenceOptions) ITodoRecurrenceUsecase {
+	return &TodoRecurrenceUsecase{
+		Logger:               l,
+		todoRepository:       tr,
+		recurrenceRepository: rr,
+		options:              opts,
+	}
+}
+
+// 繰り返しを作成
+// 作成したユーザーの繰り返しになり、作成先の指定が無ければ操作中のワークスペースに作成する
+func (u *TodoRecurrenceUsecase) CreateRecurrence(scope domain_todo.Scope, recurrence domain_todo.Recurrence) (domain_todo.Recurrence, []domain_todo.Todo, error) {
+	u.Logger.InfoLog.Println("CreateRecurrence called")
+
+	// バリデーション
+	if recurrence.Description == "" {
+		u.Logger.ErrorLog.Println("description is empty")
+		return domain_todo.Recurrence{}, nil, errors.New("description is empty")
+	}
+	if recurrence.StartAt.IsZero() {
+		u.Logger.ErrorLog.Println("start_at is empty")
+		return domain_todo.Recurrence{}, nil, errors.New("start_at is empty")
+	}
+	// 予定は秒単位にする
+	recurrence.StartAt = recurrence.StartAt.Truncate(time.Second)
+	if _, err := u.schedule(recurrence); err != nil {
+		return domain_todo.Recurrence{}, nil, err
+	}
+
+	recurrence.UserId = scope.UserID
+	if recurrence.WorkspaceID == "" {
+		recurrence.WorkspaceID = scope.WorkspaceID
+	}
+
+	// 繰り返しのTodoリポジトリから繰り返しを作成(repository層)
+	created, err := u.recurrenceRepository.CreateRecurrence(scope, recurrence)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to create recurrence: %v", err)
+		return domain_todo.Recurrence{}, nil, err
+	}
+
+	// 最初の予定を含めて、先の期間の予定のTodoを作成する
+	// 開始日時が過去の場合は、作成済みの日時(現在)以降の予定のみ作成する
+	schedule, err := u.schedule(created)
+	if err != nil {
+		return domain_todo.Recurrence{}, nil, err
+	}
+	todos, err := materialize(u.recurrenceRepository, schedule, created, u.horizon(schedule, created.MaterializedUntil.Add(-time.Second)))
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to materialize occurrences: %v", err)
+		return domain_todo.Recurrence{}, nil, err
+	}
+
+	u.Logger.InfoLog.Printf("Created recurrence: %v (%d occurrences)", created.ID, len(todos))
+	return created, todos, nil
+}
+
+// 繰り返しを取得
+func (u *TodoRecurrenceUsecase) GetRecurrence(scope domain_todo.Scope, id string) (domain_todo.Recurrence, error) {
+	u.Logger.InfoLog.Println("GetRecurrence called")
+
+	// バリデーション
+	if id == "" {
+		u.Logger.ErrorLog.Println("id is empty")
+		return domain_todo.Recurrence{}, errors.New("id is empty")
+	}
+
+	// 繰り返しのTodoリポジトリから繰り返しを取得(repository層)
+	recurrence, err := u.recurrenceRepository.GetRecurrenceById(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get recurrence: %v", err)
+		return domain_todo.Recurrence{}, err
+	}
+
+	u.Logger.InfoLog.Printf("Fetched recurrence: %v", recurrence.ID)
+	return recurrence, nil
+}
+
+// 繰り返しの予定を変更
+// "this"はこの予定のTodoのみ、"future"は繰り返しをこの予定で分け、以降の予定を新しい繰り返しで作り直す
+func (u *TodoRecurrenceUsecase) EditOccurrence(scope domain_todo.Scope, id string, edit domain_todo.OccurrenceEdit) ([]domain_todo.Todo, error) {
+	u.Logger.InfoLog.Println("EditOccurrence called")
+
+	// バリデーション
+	if id == "" {
+		u.Logger.ErrorLog.Println("id is empty")
+		return nil, errors.New("id is empty")
+	}
+	if edit.Scope != domain_todo.OccurrenceScopeThis && edit.Scope != domain_todo.OccurrenceScopeFuture {
+		u.Logger.ErrorLog.Printf("invalid scope: %v", edit.Scope)
+		return nil, errors.New("invalid scope")
+	}
+
+	// Todoリポジトリから予定のTodoを取得(repository層)
+	todo, err := u.todoRepository.GetTodoById(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get todo: %v", err)
+		return nil, err
+	}
+	if todo.RecurrenceID == "" || todo.OccurrenceAt == nil {
+		u.Logger.ErrorLog.Println("todo is not recurring")
+		return nil, errors.New("todo is not recurring")
+	}
+
+	if edit.Scope == domain_todo.OccurrenceScopeThis {
+		if edit.Description != "" {
+			todo.Description = edit.Description
+		}
+		if edit.DueAt != nil {
+			todo.DueAt = edit.DueAt
+		}
+		updated, err := u.recurrenceRepository.UpdateOccurrence(scope, todo)
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to update occurrence: %v", err)
+			return nil, err
+		}
+		u.Logger.InfoLog.Printf("Updated occurrence: %v", updated)
+		return []domain_todo.Todo{updated}, nil
+	}
+
+	// 以降の予定の繰り返し(指定が無い項目は元の繰り返しと同じ)
+	recurrence, err := u.recurrenceRepository.GetRecurrenceById(scope, todo.RecurrenceID)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get recurrence: %v", err)
+		return nil, err
+	}
+	next := domain_todo.Recurrence{
+		UserId:      recurrence.UserId,
+		Description: recurrence.Description,
+		RRule:       recurrence.RRule,
+		Timezone:    recurrence.Timezone,
+		StartAt:     *todo.OccurrenceAt,
+	}
+	if edit.Description != "" {
+		next.Description = edit.Description
+	}
+	if edit.RRule != "" {
+		next.RRule = edit.RRule
+	}
+	if edit.Timezone != "" {
+		next.Timezone = edit.Timezone
+	}
+	if edit.DueAt != nil {
+		next.StartAt = edit.DueAt.Truncate(time.Second)
+	}
+	if _, err := u.schedule(next); err != nil {
+		return nil, err
+	}
+
+	// 繰り返しのTodoリポジトリから繰り返しを分ける(repository層)
+	created, moved, err := u.recurrenceRepository.SplitRecurrence(scope, id, next)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to split recurrence: %v", err)
+		return nil, err
+	}
+
+	// 移した予定の次から、先の期間の予定のTodoを作成する(過去の予定は作成しない)
+	schedule, err := u.schedule(created)
+	if err != nil {
+		return nil, err
+	}
+	after := created.MaterializedUntil.Add(-time.Second)
+	if after.Before(created.StartAt) {
+		after = created.StartAt
+	}
+	todos, err := materialize(u.recurrenceRepository, schedule, created, u.horizon(schedule, after))
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to materialize occurrences: %v", err)
+		return nil, err
+	}
+
+	u.Logger.InfoLog.Printf("Split recurrence: %v (%d occurrences)", created.ID, len(todos))
+	return append([]domain_todo.Todo{moved}, todos...), nil
+}
+
+// 予定のTodoの作成が遅れている繰り返しの予定を作成
+// 1つの繰り返しで失敗しても、ログに残して他の繰り返しを続ける
+func (u *TodoRecurrenceUsecase) MaterializeDue(now time.Time) (int, error) {
+	u.Logger.InfoLog.Println("MaterializeDue called")
+
+	until := now.Add(u.options.Horizon)
+	limit := u.options.BatchSize
+	if limit < 1 {
+		limit = 1
+	}
+
+	// 前のページの最後の繰り返しより後を取得する(失敗した繰り返しも同じ実行で繰り返さない)
+	var last domain_todo.Recurrence
+	total, processed := 0, 0
+	for {
+		recurrences, err := u.recurrenceRepository.GetPendingRecurrences(until, last, limit)
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to get pending recurrences: %v", err)
+			return total, err
+		}
+
+		for _, recurrence := range recurrences {
+			processed++
+
+			schedule, err := u.schedule(recurrence)
+			if err != nil {
+				continue
+			}
+			todos, err := materialize(u.recurrenceRepository, schedule, recurrence, until)
+			if err != nil {
+				u.Logger.ErrorLog.Printf("Failed to materialize recurrence %v: %v", recurrence.ID, err)
+				continue
+			}
+			total += len(todos)
+		}
+		if len(recurrences) < limit {
+			break
+		}
+		last = recurrences[len(recurrences)-1]
+	}
+
+	u.Logger.InfoLog.Printf("Materialized %d occurrences of %d recurrences", total, processed)
+	return total, nil
+}
+
+// 繰り返しの予定(ルール・タイムゾーンが不正な場合は"invalid rrule"・"invalid timezone")
+func (u *TodoRecurrenceUsecase) schedule(recurrence domain_todo.Recurrence) (*pkg_rrule.Schedule, error) {
+	schedule, err := recurrenceSchedule(recurrence)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Invalid recurrence %q (%s): %v", recurrence.RRule, recurrence.Timezone, err)
+	}
+	return schedule, err
+}
+
+// 予定のTodoを作成する期限(先の期間まで)
+// 期間内に予定が無い場合も、afterより後の最初の予定は作成する
+func (u *TodoRecurrenceUsecase) horizon(schedule *pkg_rrule.Schedule, after time.Time) time.Time {
+	until := time.Now().Add(u.options.Horizon)
+	if next, ok := schedule.Next(after); ok && !next.Before(until) {
+		// 予定は秒単位のため、1秒後までにすれば次の予定を含む
+		until = next.Add(time.Second)
+	}
+	return until
+}
+
+// 繰り返しの予定
+func recurrenceSchedule(recurrence domain_todo.Recurrence) (*pkg_rrule.Schedule, error) {
+	rule, err := pkg_rrule.Parse(recurrence.RRule)
+	if err != nil {
+		return nil, errors.New("invalid rrule")
+	}
+	schedule, err := pkg_rrule.NewSchedule(rule, recurrence.StartAt, recurrence.Timezone)
+	if err != nil {
+		return nil, errors.New("invalid timezone")
+	}
+	return schedule, nil
+}
+
+// 作成済みの日時からuntilより前の予定のTodoを作成する(終了日時より前の予定のみ)
+func materialize(rr repository_todo.ITodoRecurrenceRepository, schedule *pkg_rrule.Schedule, recurrence domain_todo.Recurrence, until time.Time) ([]domain_todo.Todo, error) {
+	if !until.After(recurrence.MaterializedUntil) {
+		return []domain_todo.Todo{}, nil
+	}
+	end := until
+	if recurrence.EndAt != nil && recurrence.EndAt.Before(end) {
+		end = *recurrence.EndAt
+	}
+	return rr.MaterializeOccurrences(recurrence, schedule.Between(recurrence.MaterializedUntil, end), until)
+}
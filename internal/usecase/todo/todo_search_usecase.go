@@ -0,0 +1,68 @@
+package usecase_todo
+
+import (
+	domain_todo "backend/internal/domain/todo"
+	pkg_logger "backend/internal/pkg/logger"
+	repository_todo "backend/internal/repository/todo"
+	"errors"
+)
+
+// 検索結果の件数
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// Todo検索ユースケース(IF)
+type ITodoSearchUsecase interface {
+	// ユーザーが参照できるTodoを全文検索
+	SearchTodos(scope domain_todo.Scope, q string, limit int) ([]domain_todo.TodoSearchResult, error)
+}
+
+// Todo検索ユースケース(Impl)
+type TodoSearchUsecase struct {
+	Logger               *pkg_logger.AppLogger
+	todoSearchRepository repository_todo.ITodoSearchRepository
+}
+
+// Todo検索ユースケースのインスタンス化
+func NewTodoSearchUsecase(l *pkg_logger.AppLogger, tsr repository_todo.ITodoSearchRepository) ITodoSearchUsecase {
+	return &TodoSearchUsecase{
+		Logger:               l,
+		todoSearchRepository: tsr,
+	}
+}
+
+// ユーザーが参照できるTodoを全文検索
+func (u *TodoSearchUsecase) SearchTodos(scope domain_todo.Scope, q string, limit int) ([]domain_todo.TodoSearchResult, error) {
+	u.Logger.InfoLog.Println("SearchTodos called")
+
+	// バリデーション
+	if scope.UserID == "" {
+		u.Logger.ErrorLog.Println("user_id is empty")
+		return nil, errors.New("user_id is empty")
+	}
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	// 検索クエリのパース
+	query, err := domain_todo.ParseTodoSearchQuery(q, limit)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to parse query: %v", err)
+		return nil, err
+	}
+
+	// Todo検索リポジトリから検索(repository層)
+	results, err := u.todoSearchRepository.SearchTodos(scope, query)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to search todos: %v", err)
+		return nil, err
+	}
+
+	u.Logger.InfoLog.Printf("Found %d todos", len(results))
+	return results, nil
+}
@@ -5,44 +5,54 @@ import (
 	pkg_logger "backend/internal/pkg/logger"
 	repository_todo "backend/internal/repository/todo"
 	"errors"
+	"time"
 )
 
 // Todoユースケース(IF)
+// scopeのユーザーが所属するワークスペースのTodoのみ操作できる
 type ITodoUsecase interface {
 	// 全てのTodoを取得
-	GetAllTodos() ([]domain_todo.Todo, error)
+	GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error)
 	// idを指定してTodoを取得
-	GetTodoById(id string) (domain_todo.Todo, error)
+	GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error)
 	// 特定のユーザーのTodoを取得
-	GetTodoByUserId(userId string) ([]domain_todo.Todo, error)
-	// 新しいTodoを作成
-	CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error)
+	GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error)
+	// 新しいTodoを作成(作成者はリクエストのユーザー)
+	CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error)
 	// Todoを更新
-	UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error)
+	UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error)
 	// Todoを削除
-	DeleteTodo(id string) error
+	DeleteTodo(scope domain_todo.Scope, id string) error
+	// Todoと子孫のTodoを階層で取得
+	GetTodoTree(scope domain_todo.Scope, id string) (*domain_todo.TodoNode, error)
+	// Todoの親と兄弟の中での位置を変更
+	MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error)
+	// Todoの完了状態を変更し、変更したTodo(と作成した繰り返しの次の予定)を返す
+	CompleteTodo(scope domain_todo.Scope, id string, completion domain_todo.TodoCompletion) ([]domain_todo.Todo, error)
 }
 
 // Todoユースケース(Impl)
 type TodoUsecase struct {
-	Logger         *pkg_logger.AppLogger
-	todoRepository repository_todo.ITodoRepository
+	Logger               *pkg_logger.AppLogger
+	todoRepository       repository_todo.ITodoRepository
+	recurrenceRepository repository_todo.ITodoRecurrenceRepository
 }
 
 // Todoユースケースのインスタンス化
-func NewTodoUsecase(l *pkg_logger.AppLogger, tr repository_todo.ITodoRepository) ITodoUsecase {
+func NewTodoUsecase(l *pkg_logger.AppLogger, tr repository_todo.ITodoRepository, rr repository_todo.ITodoRecurrenceRepository) ITodoUsecase {
 	return &TodoUsecase{
-		Logger:         l,
-		todoRepository: tr,
+		Logger:               l,
+		todoRepository:       tr,
+		recurrenceRepository: rr,
 	}
 }
 
 // 全てのTodoを取得
-func (u *TodoUsecase) GetAllTodos() ([]domain_todo.Todo, error) {
+func (u *TodoUsecase) GetAllTodos(scope domain_todo.Scope) ([]domain_todo.Todo, error) {
 	u.Logger.InfoLog.Println("GetAllTodos called")
 
 	// Todoリポジトリから全てのTodoを取得(repository層)
-	todos, err := u.todoRepository.GetAllTodos()
+	todos, err := u.todoRepository.GetAllTodos(scope)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to get all todos: %v", err)
 		return nil, err
@@ -53,7 +63,7 @@ func (u *TodoUsecase) GetAllTodos() ([]domain_todo.Todo, error) {
 }
 
 // idを指定してTodoを取得
-func (u *TodoUsecase) GetTodoById(id string) (domain_todo.Todo, error) {
+func (u *TodoUsecase) GetTodoById(scope domain_todo.Scope, id string) (domain_todo.Todo, error) {
 	u.Logger.InfoLog.Println("GetTodoById called")
 
 	// バリデーション
@@ -63,7 +73,7 @@ func (u *TodoUsecase) GetTodoById(id string) (domain_todo.Todo, error) {
 	}
 
 	// Todoリポジトリから指定されたidのTodoを取得(repository層)
-	todo, err := u.todoRepository.GetTodoById(id)
+	todo, err := u.todoRepository.GetTodoById(scope, id)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to get todo by id: %v", err)
 		return domain_todo.Todo{}, err
@@ -74,7 +84,7 @@ func (u *TodoUsecase) GetTodoById(id string) (domain_todo.Todo, error) {
 }
 
 // 特定のユーザーのTodoを取得
-func (u *TodoUsecase) GetTodoByUserId(userId string) ([]domain_todo.Todo, error) {
+func (u *TodoUsecase) GetTodoByUserId(scope domain_todo.Scope, userId string) ([]domain_todo.Todo, error) {
 	u.Logger.InfoLog.Println("GetTodoByUserId called")
 
 	// バリデーション
@@ -84,7 +94,7 @@ func (u *TodoUsecase) GetTodoByUserId(userId string) ([]domain_todo.Todo, error)
 	}
 
 	// Todoリポジトリから特定のユーザーのTodoを取得(repository層)
-	todos, err := u.todoRepository.GetTodoByUserId(userId)
+	todos, err := u.todoRepository.GetTodoByUserId(scope, userId)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to get todo by user_id: %v", err)
 		return nil, err
@@ -95,7 +105,8 @@ func (u *TodoUsecase) GetTodoByUserId(userId string) ([]domain_todo.Todo, error)
 }
 
 // 新しいTodoを作成
-func (u *TodoUsecase) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
+// 作成者はリクエストボディのuser_idではなく、リクエストのユーザーにする
+func (u *TodoUsecase) CreateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
 	u.Logger.InfoLog.Println("CreateTodo called")
 
 	// バリデーション
@@ -103,13 +114,32 @@ func (u *TodoUsecase) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error
 		u.Logger.ErrorLog.Println("description is empty")
 		return domain_todo.Todo{}, errors.New("description is empty")
 	}
+	todo.UserId = scope.UserID
 	if todo.UserId == "" {
 		u.Logger.ErrorLog.Println("user_id is empty")
 		return domain_todo.Todo{}, errors.New("user_id is empty")
 	}
 
+	// サブタスクは親と同じワークスペースに作成する
+	if todo.ParentID != "" {
+		parent, err := u.parentTodo(scope, todo.ParentID, 1)
+		if err != nil {
+			return domain_todo.Todo{}, err
+		}
+		if todo.WorkspaceID != "" && todo.WorkspaceID != parent.WorkspaceID {
+			u.Logger.ErrorLog.Println("parent not found")
+			return domain_todo.Todo{}, errors.New("parent not found")
+		}
+		todo.WorkspaceID = parent.WorkspaceID
+	}
+
+	// 作成先の指定が無ければ操作中のワークスペースに作成する
+	if todo.WorkspaceID == "" {
+		todo.WorkspaceID = scope.WorkspaceID
+	}
+
 	// Todoリポジトリから新しいTodoを作成(repository層)
-	createdTodo, err := u.todoRepository.CreateTodo(todo)
+	createdTodo, err := u.todoRepository.CreateTodo(scope, todo)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to create todo: %v", err)
 		return domain_todo.Todo{}, err
@@ -120,7 +150,8 @@ func (u *TodoUsecase) CreateTodo(todo domain_todo.Todo) (domain_todo.Todo, error
 }
 
 // Todoを更新
-func (u *TodoUsecase) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error) {
+// 繰り返しの予定を完了にした場合は、次の予定のTodoを作成する
+func (u *TodoUsecase) UpdateTodo(scope domain_todo.Scope, todo domain_todo.Todo) (domain_todo.Todo, error) {
 	u.Logger.InfoLog.Println("UpdateTodo called")
 
 	// バリデーション
@@ -138,18 +169,19 @@ func (u *TodoUsecase) UpdateTodo(todo domain_todo.Todo) (domain_todo.Todo, error
 	}
 
 	// Todoリポジトリから指定されたidのTodoを更新(repository層)
-	updatedTodo, err := u.todoRepository.UpdateTodo(todo)
+	updatedTodo, err := u.todoRepository.UpdateTodo(scope, todo)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to update todo: %v", err)
 		return domain_todo.Todo{}, err
 	}
+	u.materializeNext(scope, updatedTodo)
 
 	u.Logger.InfoLog.Printf("Updated todo: %v", updatedTodo)
 	return updatedTodo, nil
 }
 
 // Todoを削除
-func (u *TodoUsecase) DeleteTodo(id string) error {
+func (u *TodoUsecase) DeleteTodo(scope domain_todo.Scope, id string) error {
 	u.Logger.InfoLog.Println("DeleteTodo called")
 
 	// バリデーション
@@ -159,7 +191,7 @@ func (u *TodoUsecase) DeleteTodo(id string) error {
 	}
 
 	// Todoリポジトリから指定されたidのTodoを削除(repository層)
-	err := u.todoRepository.DeleteTodo(id)
+	err := u.todoRepository.DeleteTodo(scope, id)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to delete todo: %v", err)
 		return err
@@ -168,3 +200,266 @@ func (u *TodoUsecase) DeleteTodo(id string) error {
 	u.Logger.InfoLog.Printf("Deleted todo: %v", id)
 	return nil
 }
+
+// Todoと子孫のTodoを階層で取得
+func (u *TodoUsecase) GetTodoTree(scope domain_todo.Scope, id string) (*domain_todo.TodoNode, error) {
+	u.Logger.InfoLog.Println("GetTodoTree called")
+
+	// バリデーション
+	if id == "" {
+		u.Logger.ErrorLog.Println("id is empty")
+		return nil, errors.New("id is empty")
+	}
+
+	// Todoリポジトリから子孫のTodoを取得(repository層)
+	tree, err := u.todoTree(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+		return nil, err
+	}
+
+	u.Logger.InfoLog.Printf("Fetched todo tree: %v", tree.ID)
+	return tree, nil
+}
+
+// Todoの親と兄弟の中での位置を変更
+// 隣のTodoは移動先の親の子に限る("neighbour not found")
+// 自身・子孫の下には移動できず("cycle detected")、移動後の階層はMaxTodoDepthまで("max depth exceeded")
+func (u *TodoUsecase) MoveTodo(scope domain_todo.Scope, id string, move domain_todo.TodoMove) (domain_todo.Todo, error) {
+	u.Logger.InfoLog.Println("MoveTodo called")
+
+	// バリデーション
+	if id == "" {
+		u.Logger.ErrorLog.Println("id is empty")
+		return domain_todo.Todo{}, errors.New("id is empty")
+	}
+	if move.AfterID == id || move.BeforeID == id {
+		u.Logger.ErrorLog.Println("neighbour not found")
+		return domain_todo.Todo{}, errors.New("neighbour not found")
+	}
+
+	// 隣のTodoを指定した場合は、隣のTodoの親に移動する
+	for _, neighbourId := range []string{move.AfterID, move.BeforeID} {
+		if neighbourId == "" {
+			continue
+		}
+		neighbour, err := u.todoRepository.GetTodoById(scope, neighbourId)
+		if err != nil {
+			u.Logger.ErrorLog.Printf("Failed to get neighbour todo: %v", err)
+			if err.Error() == "todo not found" {
+				return domain_todo.Todo{}, errors.New("neighbour not found")
+			}
+			return domain_todo.Todo{}, err
+		}
+		if move.ParentID != "" && move.ParentID != neighbour.ParentID {
+			u.Logger.ErrorLog.Println("neighbour not found")
+			return domain_todo.Todo{}, errors.New("neighbour not found")
+		}
+		move.ParentID = neighbour.ParentID
+	}
+
+	if move.ParentID == id {
+		u.Logger.ErrorLog.Println("cycle detected")
+		return domain_todo.Todo{}, errors.New("cycle detected")
+	}
+
+	// 移動するTodoと子孫のTodoを取得(repository層)
+	tree, err := u.todoTree(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+		return domain_todo.Todo{}, err
+	}
+
+	if move.ParentID != "" {
+		for _, descendantId := range tree.DescendantIDs() {
+			if descendantId == move.ParentID {
+				u.Logger.ErrorLog.Println("cycle detected")
+				return domain_todo.Todo{}, errors.New("cycle detected")
+			}
+		}
+		if _, err := u.parentTodo(scope, move.ParentID, tree.Height()); err != nil {
+			return domain_todo.Todo{}, err
+		}
+	}
+
+	// Todoリポジトリから指定されたidのTodoを移動(repository層)
+	moved, err := u.todoRepository.MoveTodo(scope, id, move)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to move todo: %v", err)
+		return domain_todo.Todo{}, err
+	}
+
+	u.Logger.InfoLog.Printf("Moved todo: %v", moved)
+	return moved, nil
+}
+
+// Todoの完了状態を変更
+// completionの指定に応じて子孫・祖先のTodoにも反映する
+// 繰り返しの予定を完了にした場合は、次の予定のTodoを作成して返すTodoに加える
+func (u *TodoUsecase) CompleteTodo(scope domain_todo.Scope, id string, completion domain_todo.TodoCompletion) ([]domain_todo.Todo, error) {
+	u.Logger.InfoLog.Println("CompleteTodo called")
+
+	// バリデーション
+	if id == "" {
+		u.Logger.ErrorLog.Println("id is empty")
+		return nil, errors.New("id is empty")
+	}
+
+	// 変更するTodoと子孫のTodoを取得(repository層)
+	tree, err := u.todoTree(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+		return nil, err
+	}
+	ids := []string{id}
+	if completion.Descendants {
+		ids = append(ids, tree.DescendantIDs()...)
+	}
+
+	if completion.Ancestors {
+		ancestorIds, err := u.cascadeAncestors(scope, id, ids, completion.Completed)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorIds...)
+	}
+
+	// Todoリポジトリから完了状態を変更(repository層)
+	todos, err := u.todoRepository.SetTodosCompleted(scope, ids, completion.Completed)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to complete todo: %v", err)
+		return nil, err
+	}
+	next := []domain_todo.Todo{}
+	for _, todo := range todos {
+		next = append(next, u.materializeNext(scope, todo)...)
+	}
+	todos = append(todos, next...)
+
+	u.Logger.InfoLog.Printf("Completed %d todos", len(todos))
+	return todos, nil
+}
+
+// 完了した繰り返しの予定について、次の予定のTodoを作成する(作成済み・終了した場合は何もしない)
+// 完了の変更は確定しているため、失敗してもエラーにせずログに残す(次の予定はスケジューラも作成する)
+func (u *TodoUsecase) materializeNext(scope domain_todo.Scope, todo domain_todo.Todo) []domain_todo.Todo {
+	if !todo.Completed || todo.RecurrenceID == "" || todo.OccurrenceAt == nil {
+		return nil
+	}
+
+	recurrence, err := u.recurrenceRepository.GetRecurrenceById(scope, todo.RecurrenceID)
+	if err != nil {
+		u.Logger.WarnLog.Printf("Failed to get recurrence: %v", err)
+		return nil
+	}
+	schedule, err := recurrenceSchedule(recurrence)
+	if err != nil {
+		u.Logger.WarnLog.Printf("Invalid recurrence %v: %v", recurrence.ID, err)
+		return nil
+	}
+	next, ok := schedule.Next(*todo.OccurrenceAt)
+	if !ok || next.Before(recurrence.MaterializedUntil) || (recurrence.EndAt != nil && !next.Before(*recurrence.EndAt)) {
+		return nil
+	}
+
+	// 予定は秒単位のため、1秒後までにすれば次の予定を含む
+	created, err := materialize(u.recurrenceRepository, schedule, recurrence, next.Add(time.Second))
+	if err != nil {
+		u.Logger.WarnLog.Printf("Failed to materialize next occurrence: %v", err)
+		return nil
+	}
+	u.Logger.InfoLog.Printf("Materialized %d occurrences of recurrence: %v", len(created), recurrence.ID)
+	return created
+}
+
+// Todoと子孫のTodoを階層にする
+func (u *TodoUsecase) todoTree(scope domain_todo.Scope, id string) (*domain_todo.TodoNode, error) {
+	todos, err := u.todoRepository.GetTodoSubtree(scope, id)
+	if err != nil {
+		return nil, err
+	}
+	return domain_todo.BuildTodoTree(todos, id)
+}
+
+// 親のTodoを取得し、高さheightの階層を下に追加できるか確認する
+func (u *TodoUsecase) parentTodo(scope domain_todo.Scope, parentId string, height int) (domain_todo.Todo, error) {
+	parent, err := u.todoRepository.GetTodoById(scope, parentId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get parent todo: %v", err)
+		if err.Error() == "todo not found" {
+			return domain_todo.Todo{}, errors.New("parent not found")
+		}
+		return domain_todo.Todo{}, err
+	}
+
+	ancestors, err := u.todoRepository.GetTodoAncestors(scope, parentId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get ancestors: %v", err)
+		return domain_todo.Todo{}, err
+	}
+	// 親の深さ(ルートは1) + 追加する階層の高さ
+	if len(ancestors)+1+height > domain_todo.MaxTodoDepth {
+		u.Logger.ErrorLog.Println("max depth exceeded")
+		return domain_todo.Todo{}, errors.New("max depth exceeded")
+	}
+	return parent, nil
+}
+
+// 完了状態を反映する祖先のTodoのIDを返す
+// 未完了にする場合は全ての祖先、完了にする場合は全ての子が完了になる祖先(近い順にたどり、未完了の子が残れば止める)
+func (u *TodoUsecase) cascadeAncestors(scope domain_todo.Scope, id string, changed []string, completed bool) ([]string, error) {
+	ancestors, err := u.todoRepository.GetTodoAncestors(scope, id)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get ancestors: %v", err)
+		return nil, err
+	}
+	ids := []string{}
+	if len(ancestors) == 0 {
+		return ids, nil
+	}
+	if !completed {
+		for _, ancestor := range ancestors {
+			ids = append(ids, ancestor.ID)
+		}
+		return ids, nil
+	}
+
+	// ルートのTodoからの階層で、変更後の完了状態を確認する
+	rootId := ancestors[len(ancestors)-1].ID
+	root, err := u.todoTree(scope, rootId)
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to get todo tree: %v", err)
+		return nil, err
+	}
+	done := map[string]bool{}
+	for _, changedId := range changed {
+		done[changedId] = true
+	}
+	nodes := map[string]*domain_todo.TodoNode{}
+	var index func(n *domain_todo.TodoNode)
+	index = func(n *domain_todo.TodoNode) {
+		nodes[n.ID] = n
+		if n.Completed {
+			done[n.ID] = true
+		}
+		for _, child := range n.Children {
+			index(child)
+		}
+	}
+	index(root)
+
+	for _, ancestor := range ancestors {
+		node, ok := nodes[ancestor.ID]
+		if !ok {
+			break
+		}
+		for _, child := range node.Children {
+			if !done[child.ID] {
+				return ids, nil
+			}
+		}
+		done[ancestor.ID] = true
+		ids = append(ids, ancestor.ID)
+	}
+	return ids, nil
+}
@@ -8,8 +8,8 @@ import (
 
 // ユーザーユースケース(IF)
 type IUserUsecase interface {
-	// 全てのユーザーを取得
-	GetAllUsers() ([]domain_user.Users, error)
+	// 参照できる全てのユーザーを取得(自分と、同じワークスペースのメンバー)
+	GetAllUsers(userId string) ([]domain_user.Users, error)
 }
 
 // ユーザーユースケース(Impl)
@@ -27,11 +27,11 @@ func NewUserUsecase(l *pkg_logger.AppLogger, u repository_user.IUserRepository)
 }
 
 // 全てのユーザーを取得
-func (u *UserUsecase) GetAllUsers() ([]domain_user.Users, error) {
+func (u *UserUsecase) GetAllUsers(userId string) ([]domain_user.Users, error) {
 	u.Logger.InfoLog.Println("GetAllUsers called")
 
 	// ユーザーリポジトリから全てのユーザーを取得(repository層)
-	users, err := u.userRepository.GetAllUsers()
+	users, err := u.userRepository.GetAllUsers(userId)
 	if err != nil {
 		u.Logger.ErrorLog.Printf("Failed to get all users: %v", err)
 		return nil, err
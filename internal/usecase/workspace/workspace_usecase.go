@@ -0,0 +1,291 @@
+package usecase_workspace
+
+import (
+	domain_workspace "backend/internal/domain/workspace"
+	pkg_logger "backend/internal/pkg/logger"
+	pkg_mail "backend/internal/pkg/mail"
+	repository_auth "backend/internal/repository/auth"
+	repository_workspace "backend/internal/repository/workspace"
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ワークスペースユースケースの設定
+type WorkspaceOptions struct {
+	InvitationTTL time.Duration // 招待の有効期間
+	LinkBaseURL   string        // メールのリンク先(フロントエンド)のURL
+}
+
+// ワークスペースユースケース(IF)
+type IWorkspaceUsecase interface {
+	// ワークスペースを作成(作成したユーザーがオーナーになる)
+	CreateWorkspace(userId string, name string) (domain_workspace.Workspace, error)
+	// 所属するワークスペースを取得
+	GetWorkspaces(userId string) ([]domain_workspace.Membership, error)
+	// ワークスペースでの役割を取得(所属していない場合は"workspace not found")
+	Authorize(userId string, workspaceId string) (domain_workspace.Role, error)
+	// ワークスペースのメンバーを取得
+	GetMembers(userId string, workspaceId string) ([]domain_workspace.Member, error)
+	// メンバーの役割を変更(オーナーのみ)
+	UpdateMemberRole(actorId string, workspaceId string, userId string, role domain_workspace.Role) error
+	// メンバーを削除(オーナー、または本人の脱退)
+	RemoveMember(actorId string, workspaceId string, userId string) error
+	// メールで招待する(オーナーのみ)
+	Invite(ctx context.Context, actorId string, workspaceId string, email string, role domain_workspace.Role, lang string) (domain_workspace.Invitation, error)
+	// 招待を受けてメンバーになる(招待されたメールアドレスを確認済みのユーザーのみ)
+	AcceptInvitation(userId string, token string) (domain_workspace.Membership, error)
+}
+
+// ワークスペースユースケース(Impl)
+type WorkspaceUsecase struct {
+	Logger              *pkg_logger.AppLogger
+	workspaceRepository repository_workspace.IWorkspaceRepository
+	accountRepository   repository_auth.IAccountRepository
+	mailer              pkg_mail.Mailer
+	templates           *pkg_mail.Templates
+	options             WorkspaceOptions
+}
+
+// ワークスペースユースケースのインスタンス化
+func NewWorkspaceUsecase(l *pkg_logger.AppLogger, wr repository_workspace.IWorkspaceRepository, ar repository_auth.IAccountRepository, mailer pkg_mail.Mailer, templates *pkg_mail.Templates, opts WorkspaceOptions) IWorkspaceUsecase {
+	return &WorkspaceUsecase{
+		Logger:              l,
+		workspaceRepository: wr,
+		accountRepository:   ar,
+		mailer:              mailer,
+		templates:           templates,
+		options:             opts,
+	}
+}
+
+// 招待のメールの本文に渡す値
+type invitationMail struct {
+	Workspace string
+	Email     string
+	Role      domain_workspace.Role
+	Link      string
+	ExpiresAt time.Time
+}
+
+// ワークスペースを作成
+func (u *WorkspaceUsecase) CreateWorkspace(userId string, name string) (domain_workspace.Workspace, error) {
+	u.Logger.InfoLog.Println("CreateWorkspace called")
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain_workspace.Workspace{}, errors.New("name is empty")
+	}
+	if len([]rune(name)) > domain_workspace.MaxNameLength {
+		return domain_workspace.Workspace{}, errors.New("name is too long")
+	}
+
+	workspace, err := u.workspaceRepository.CreateWorkspace(domain_workspace.Workspace{Name: name}, userId)
+	if err != nil {
+		return domain_workspace.Workspace{}, errors.New("failed to create workspace")
+	}
+	return workspace, nil
+}
+
+// 所属するワークスペースを取得
+func (u *WorkspaceUsecase) GetWorkspaces(userId string) ([]domain_workspace.Membership, error) {
+	u.Logger.InfoLog.Println("GetWorkspaces called")
+
+	memberships, err := u.workspaceRepository.GetMemberships(userId)
+	if err != nil {
+		return nil, errors.New("failed to get workspaces")
+	}
+	return memberships, nil
+}
+
+// ワークスペースでの役割を取得
+// 所属していないワークスペースは、存在を明かさないよう"workspace not found"とする
+func (u *WorkspaceUsecase) Authorize(userId string, workspaceId string) (domain_workspace.Role, error) {
+	member, err := u.workspaceRepository.GetMember(workspaceId, userId)
+	if err != nil {
+		if err.Error() == "member not found" {
+			return "", errors.New("workspace not found")
+		}
+		return "", errors.New("failed to get workspace")
+	}
+	return member.Role, nil
+}
+
+// ワークスペースのメンバーを取得
+func (u *WorkspaceUsecase) GetMembers(userId string, workspaceId string) ([]domain_workspace.Member, error) {
+	u.Logger.InfoLog.Println("GetMembers called")
+
+	if _, err := u.Authorize(userId, workspaceId); err != nil {
+		return nil, err
+	}
+	members, err := u.workspaceRepository.GetMembers(workspaceId)
+	if err != nil {
+		return nil, errors.New("failed to get members")
+	}
+	return members, nil
+}
+
+// メンバーの役割を変更
+// 最後のオーナーの役割は変更できない
+func (u *WorkspaceUsecase) UpdateMemberRole(actorId string, workspaceId string, userId string, role domain_workspace.Role) error {
+	u.Logger.InfoLog.Println("UpdateMemberRole called")
+
+	if !role.Valid() {
+		return errors.New("invalid role")
+	}
+	if err := u.requireOwner(actorId, workspaceId); err != nil {
+		return err
+	}
+
+	if err := u.workspaceRepository.UpdateMemberRole(workspaceId, userId, role); err != nil {
+		if err.Error() == "member not found" || err.Error() == "workspace must have an owner" {
+			return err
+		}
+		return errors.New("failed to update member")
+	}
+
+	u.Logger.InfoLog.Printf("Changed role of %s in workspace %s to %s", userId, workspaceId, role)
+	return nil
+}
+
+// メンバーを削除
+// オーナーは誰でも、それ以外は自分のみ削除できる(脱退)。最後のオーナーは削除できない
+func (u *WorkspaceUsecase) RemoveMember(actorId string, workspaceId string, userId string) error {
+	u.Logger.InfoLog.Println("RemoveMember called")
+
+	if actorId == userId {
+		if _, err := u.Authorize(actorId, workspaceId); err != nil {
+			return err
+		}
+	} else if err := u.requireOwner(actorId, workspaceId); err != nil {
+		return err
+	}
+
+	if err := u.workspaceRepository.RemoveMember(workspaceId, userId); err != nil {
+		if err.Error() == "member not found" || err.Error() == "workspace must have an owner" {
+			return err
+		}
+		return errors.New("failed to remove member")
+	}
+
+	u.Logger.InfoLog.Printf("Removed %s from workspace %s", userId, workspaceId)
+	return nil
+}
+
+// メールで招待する
+func (u *WorkspaceUsecase) Invite(ctx context.Context, actorId string, workspaceId string, email string, role domain_workspace.Role, lang string) (domain_workspace.Invitation, error) {
+	u.Logger.InfoLog.Println("Invite called")
+
+	matched, err := regexp.MatchString(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`, email)
+	if err != nil || !matched {
+		return domain_workspace.Invitation{}, errors.New("invalid email format")
+	}
+	if !role.Valid() {
+		return domain_workspace.Invitation{}, errors.New("invalid role")
+	}
+	if err := u.requireOwner(actorId, workspaceId); err != nil {
+		return domain_workspace.Invitation{}, err
+	}
+	workspace, err := u.workspaceRepository.GetWorkspace(workspaceId)
+	if err != nil {
+		return domain_workspace.Invitation{}, errors.New("failed to invite")
+	}
+
+	token, hash, err := domain_workspace.GenerateInvitationToken()
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to generate invitation token: %v", err)
+		return domain_workspace.Invitation{}, errors.New("failed to invite")
+	}
+	invitation, err := u.workspaceRepository.CreateInvitation(domain_workspace.Invitation{
+		WorkspaceID: workspaceId,
+		Email:       strings.ToLower(email),
+		Role:        role,
+		InvitedBy:   actorId,
+		TokenHash:   hash,
+		ExpiresAt:   time.Now().Add(u.options.InvitationTTL),
+	})
+	if err != nil {
+		return domain_workspace.Invitation{}, errors.New("failed to invite")
+	}
+
+	link := strings.TrimSuffix(u.options.LinkBaseURL, "/") + "/workspaces/join?token=" + url.QueryEscape(token)
+	subject, body, err := u.templates.Render("workspace_invitation", lang, invitationMail{
+		Workspace: workspace.Name,
+		Email:     invitation.Email,
+		Role:      role,
+		Link:      link,
+		ExpiresAt: invitation.ExpiresAt,
+	})
+	if err != nil {
+		u.Logger.ErrorLog.Printf("Failed to render invitation mail: %v", err)
+		return domain_workspace.Invitation{}, errors.New("failed to send email")
+	}
+	if err := u.mailer.Send(ctx, pkg_mail.Message{To: invitation.Email, Subject: subject, Body: body}); err != nil {
+		u.Logger.ErrorLog.Printf("Failed to send invitation mail: %v", err)
+		return domain_workspace.Invitation{}, errors.New("failed to send email")
+	}
+
+	u.Logger.InfoLog.Printf("Invited %s to workspace %s as %s", invitation.Email, workspaceId, role)
+	return invitation, nil
+}
+
+// 招待を受けてメンバーになる
+// ログイン中のユーザーのメールアドレスが招待先と一致し、確認済みの場合のみ招待された役割で追加する
+// 転送・漏洩したリンクでは参加できず、招待も使用済みにしない
+func (u *WorkspaceUsecase) AcceptInvitation(userId string, token string) (domain_workspace.Membership, error) {
+	u.Logger.InfoLog.Println("AcceptInvitation called")
+
+	hash := domain_workspace.HashInvitationToken(token)
+	invitation, err := u.workspaceRepository.GetInvitation(hash, time.Now())
+	if err != nil {
+		if err.Error() == "invitation not found" {
+			return domain_workspace.Membership{}, errors.New("invalid or expired invitation")
+		}
+		return domain_workspace.Membership{}, errors.New("failed to accept invitation")
+	}
+	account, err := u.accountRepository.GetAccountById(userId)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return domain_workspace.Membership{}, errors.New("permission denied")
+		}
+		return domain_workspace.Membership{}, errors.New("failed to accept invitation")
+	}
+	if account.EmailVerifiedAt == nil || !strings.EqualFold(account.Email, invitation.Email) {
+		u.Logger.WarnLog.Printf("Invitation %s is not for user %s", invitation.ID, userId)
+		return domain_workspace.Membership{}, errors.New("permission denied")
+	}
+
+	// 招待の使用とメンバーの追加は同じトランザクションで行う
+	member, err := u.workspaceRepository.AcceptInvitation(hash, userId, time.Now())
+	if err != nil {
+		switch err.Error() {
+		case "invitation not found":
+			return domain_workspace.Membership{}, errors.New("invalid or expired invitation")
+		case "already a member":
+			return domain_workspace.Membership{}, err
+		}
+		return domain_workspace.Membership{}, errors.New("failed to accept invitation")
+	}
+	workspace, err := u.workspaceRepository.GetWorkspace(member.WorkspaceID)
+	if err != nil {
+		return domain_workspace.Membership{}, errors.New("failed to accept invitation")
+	}
+
+	u.Logger.InfoLog.Printf("Joined workspace %s: user %s", workspace.ID, userId)
+	return domain_workspace.Membership{Workspace: workspace, Role: member.Role}, nil
+}
+
+// オーナーであることを確認する
+func (u *WorkspaceUsecase) requireOwner(userId string, workspaceId string) error {
+	role, err := u.Authorize(userId, workspaceId)
+	if err != nil {
+		return err
+	}
+	if role != domain_workspace.RoleOwner {
+		return errors.New("permission denied")
+	}
+	return nil
+}
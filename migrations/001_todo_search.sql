@@ -0,0 +1,9 @@
+-- Todoの全文検索
+-- descriptionからtsvectorを生成し、GINインデックスを張る
+-- (title/tagsカラムを追加した場合はここに重み付きで追加する)
+ALTER TABLE todos
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple', coalesce(description, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS todos_search_vector_idx
+    ON todos USING GIN (search_vector);
@@ -0,0 +1,14 @@
+-- レート制限の状態
+-- 複数のインスタンスで共有するためのテーブル。失っても制限が緩むだけなのでUNLOGGEDにする
+-- 期限切れの行は定期的に削除する: DELETE FROM rate_limits WHERE expires_at < now();
+CREATE UNLOGGED TABLE IF NOT EXISTS rate_limits (
+    key        text PRIMARY KEY,
+    tokens     double precision NOT NULL DEFAULT 0,
+    count      integer NOT NULL DEFAULT 0,
+    prev_count integer NOT NULL DEFAULT 0,
+    stamp      timestamptz,
+    expires_at timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_expires_at_idx
+    ON rate_limits (expires_at);
@@ -0,0 +1,22 @@
+-- ログインの失敗の記録
+-- keyは "email:<メールアドレス>" または "ip:<IPアドレス>"。存在しないメールアドレスも記録する
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key            text PRIMARY KEY,
+    count          integer NOT NULL DEFAULT 0,
+    last_failed_at timestamptz,
+    locked_until   timestamptz
+);
+
+-- 監査ログ
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    action     text NOT NULL,
+    actor_id   uuid,
+    email      text NOT NULL DEFAULT '',
+    ip         text NOT NULL DEFAULT '',
+    detail     text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_created_at_idx
+    ON audit_logs (created_at DESC);
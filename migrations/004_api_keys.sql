@@ -0,0 +1,18 @@
+-- APIキー
+-- キーそのものは保存せず、識別用のプレフィックスとSHA-256のハッシュを保存する
+CREATE TABLE IF NOT EXISTS api_keys (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    name         text NOT NULL,
+    kind         text NOT NULL CHECK (kind IN ('personal', 'service')),
+    prefix       text NOT NULL UNIQUE,
+    hash         text NOT NULL,
+    scopes       text[] NOT NULL DEFAULT '{}',
+    expires_at   timestamptz NOT NULL,
+    last_used_at timestamptz,
+    revoked_at   timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS api_keys_user_id_idx
+    ON api_keys (user_id, created_at DESC);
@@ -0,0 +1,16 @@
+-- 外部のIDプロバイダ(OIDC)のアカウントとユーザーの紐付け
+-- 確認済みのメールアドレスが一致するユーザーに、初回ログイン時に紐付ける
+CREATE TABLE IF NOT EXISTS user_identities (
+    provider   text NOT NULL,
+    subject    text NOT NULL,
+    user_id    uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    email      text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS user_identities_user_id_idx
+    ON user_identities (user_id);
+
+CREATE INDEX IF NOT EXISTS users_lower_email_idx
+    ON users (lower(email));
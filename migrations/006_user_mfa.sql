@@ -0,0 +1,11 @@
+-- 2要素認証(TOTP)
+-- confirmed_at が NULL の間は登録途中で、ログイン時には要求しない
+-- 回復コードはSHA-256のハッシュのみを保存し、使用したものは配列から取り除く
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id        uuid PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         text NOT NULL,
+    confirmed_at   timestamptz,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    recovery_codes text[] NOT NULL DEFAULT '{}',
+    created_at     timestamptz NOT NULL DEFAULT now()
+);
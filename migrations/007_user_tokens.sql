@@ -0,0 +1,19 @@
+-- メールアドレスの確認日時
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz;
+
+-- パスワードの再設定・メールアドレスの確認のトークン
+-- トークンそのものは保存せず、SHA-256のハッシュを保存する。使用済みのものは used_at を設定する
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id         uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    purpose    text NOT NULL CHECK (purpose IN ('password_reset', 'email_verification')),
+    hash       text NOT NULL UNIQUE,
+    email      text NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS user_tokens_user_id_idx
+    ON user_tokens (user_id, purpose)
+    WHERE used_at IS NULL;
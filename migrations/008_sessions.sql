@@ -0,0 +1,16 @@
+-- ログインのセッション
+-- アクセストークンにIDを含め、認証のたびに失効していないかを確認する
+CREATE TABLE IF NOT EXISTS sessions (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    device       text NOT NULL,
+    ip           text NOT NULL DEFAULT '',
+    user_agent   text NOT NULL DEFAULT '',
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    last_seen_at timestamptz NOT NULL DEFAULT now(),
+    revoked_at   timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx
+    ON sessions (user_id, last_seen_at DESC)
+    WHERE revoked_at IS NULL;
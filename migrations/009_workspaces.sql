@@ -0,0 +1,79 @@
+-- ワークスペース
+-- 個人用のワークスペース(personal_user_id を設定)はユーザーごとに1つ作成する
+CREATE TABLE IF NOT EXISTS workspaces (
+    id               uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    name             text NOT NULL,
+    personal_user_id uuid UNIQUE REFERENCES users (id) ON DELETE CASCADE,
+    created_at       timestamptz NOT NULL DEFAULT now()
+);
+
+-- ワークスペースのメンバーと役割
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id      uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role         text NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at   timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS workspace_members_user_id_idx
+    ON workspace_members (user_id);
+
+-- メールでの招待
+-- トークンそのものは保存せず、SHA-256のハッシュを保存する。受け入れたものは accepted_at を設定する
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    email        text NOT NULL,
+    role         text NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by   uuid REFERENCES users (id) ON DELETE SET NULL,
+    token_hash   text NOT NULL UNIQUE,
+    expires_at   timestamptz NOT NULL,
+    accepted_at  timestamptz,
+    created_at   timestamptz NOT NULL DEFAULT now()
+);
+
+-- 既存のユーザーの個人用のワークスペース
+INSERT INTO workspaces (name, personal_user_id)
+SELECT 'Personal', u.id
+FROM users u
+ON CONFLICT (personal_user_id) DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, w.personal_user_id, 'owner'
+FROM workspaces w
+WHERE w.personal_user_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+-- ユーザーの作成時に個人用のワークスペースを作成する
+CREATE OR REPLACE FUNCTION create_personal_workspace() RETURNS trigger AS $$
+DECLARE
+    workspace_id uuid;
+BEGIN
+    INSERT INTO workspaces (name, personal_user_id)
+    VALUES ('Personal', NEW.id)
+    RETURNING id INTO workspace_id;
+
+    INSERT INTO workspace_members (workspace_id, user_id, role)
+    VALUES (workspace_id, NEW.id, 'owner');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_personal_workspace ON users;
+CREATE TRIGGER users_personal_workspace
+    AFTER INSERT ON users
+    FOR EACH ROW EXECUTE FUNCTION create_personal_workspace();
+
+-- 既存のTodoは作成したユーザーの個人用のワークスペースに移す
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS workspace_id uuid REFERENCES workspaces (id) ON DELETE CASCADE;
+
+UPDATE todos t
+SET workspace_id = w.id
+FROM workspaces w
+WHERE t.workspace_id IS NULL AND w.personal_user_id = t.user_id;
+
+ALTER TABLE todos ALTER COLUMN workspace_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS todos_workspace_id_idx
+    ON todos (workspace_id);
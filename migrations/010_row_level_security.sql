@@ -0,0 +1,107 @@
+-- 行レベルセキュリティ(RLS)
+-- Todo・ユーザーのリポジトリは、リクエストのユーザーごとのトランザクションで request.jwt.claims と
+-- ロール(authenticated)を設定する(pkg_supabase.SupabaseClient.AsUser)。
+-- Go側の条件が漏れても、所属していないワークスペースのTodoや他のユーザーは参照・変更できない。
+-- 接続プールのロール(テーブルの所有者)にはポリシーを適用しない(ログインなど認証前の処理で使う)
+
+-- Supabase以外のPostgresでも適用できるようにロールを作成する
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'authenticated') THEN
+        CREATE ROLE authenticated NOLOGIN;
+    END IF;
+END
+$$;
+
+-- 接続プールのロールから切り替えられるようにする
+GRANT authenticated TO CURRENT_USER;
+
+GRANT USAGE ON SCHEMA public TO authenticated;
+GRANT SELECT, INSERT, UPDATE, DELETE ON todos TO authenticated;
+GRANT SELECT ON workspaces, workspace_members TO authenticated;
+-- パスワードなどの列は参照させない
+REVOKE ALL ON users FROM authenticated;
+GRANT SELECT (id, username, email, created_at, updated_at) ON users TO authenticated;
+
+-- リクエストのユーザーID(request.jwt.claims の sub)
+-- 未設定の場合(トランザクションの外では空文字になる)はNULL
+CREATE OR REPLACE FUNCTION app_user_id() RETURNS uuid
+    LANGUAGE sql STABLE
+AS $$
+    SELECT (NULLIF(current_setting('request.jwt.claims', true), '')::json ->> 'sub')::uuid
+$$;
+
+-- リクエストのユーザーが所属するワークスペース
+-- workspace_members のポリシーから参照しても再帰しないよう、SECURITY DEFINER でRLSを適用せずに読む
+CREATE OR REPLACE FUNCTION app_workspace_ids(roles text[] DEFAULT ARRAY['owner', 'editor', 'viewer']) RETURNS SETOF uuid
+    LANGUAGE sql STABLE SECURITY DEFINER
+    SET search_path = public
+AS $$
+    SELECT workspace_id FROM workspace_members WHERE user_id = app_user_id() AND role = ANY (roles)
+$$;
+
+REVOKE ALL ON FUNCTION app_workspace_ids(text[]) FROM PUBLIC;
+GRANT EXECUTE ON FUNCTION app_user_id(), app_workspace_ids(text[]) TO authenticated;
+
+-- Todo: 所属するワークスペースのものを参照でき、オーナー・編集者は作成・更新・削除できる
+-- 作成者(user_id)は自分のみで、後から変更できない
+ALTER TABLE todos ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS todos_select ON todos;
+CREATE POLICY todos_select ON todos FOR SELECT TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids()));
+
+DROP POLICY IF EXISTS todos_insert ON todos;
+CREATE POLICY todos_insert ON todos FOR INSERT TO authenticated
+    WITH CHECK (
+        user_id = app_user_id()
+        AND workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor']))
+    );
+
+DROP POLICY IF EXISTS todos_update ON todos;
+CREATE POLICY todos_update ON todos FOR UPDATE TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])))
+    WITH CHECK (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])));
+
+-- ポリシーでは変更前の値と比べられないため、作成者の変更はトリガーで拒否する
+CREATE OR REPLACE FUNCTION todos_keep_user_id() RETURNS trigger
+    LANGUAGE plpgsql
+AS $$
+BEGIN
+    IF NEW.user_id IS DISTINCT FROM OLD.user_id AND current_user = 'authenticated' THEN
+        RAISE EXCEPTION 'user_id of a todo cannot be changed' USING ERRCODE = 'insufficient_privilege';
+    END IF;
+    RETURN NEW;
+END
+$$;
+
+DROP TRIGGER IF EXISTS todos_keep_user_id ON todos;
+CREATE TRIGGER todos_keep_user_id BEFORE UPDATE OF user_id ON todos
+    FOR EACH ROW EXECUTE FUNCTION todos_keep_user_id();
+
+DROP POLICY IF EXISTS todos_delete ON todos;
+CREATE POLICY todos_delete ON todos FOR DELETE TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])));
+
+-- ワークスペース・メンバー: 所属するワークスペースのもののみ参照できる(変更は接続プールのロールで行う)
+ALTER TABLE workspaces ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS workspaces_select ON workspaces;
+CREATE POLICY workspaces_select ON workspaces FOR SELECT TO authenticated
+    USING (id IN (SELECT app_workspace_ids()));
+
+ALTER TABLE workspace_members ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS workspace_members_select ON workspace_members;
+CREATE POLICY workspace_members_select ON workspace_members FOR SELECT TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids()));
+
+-- ユーザー: 自分と、同じワークスペースのメンバーのみ参照できる
+ALTER TABLE users ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS users_select ON users;
+CREATE POLICY users_select ON users FOR SELECT TO authenticated
+    USING (
+        id = app_user_id()
+        OR id IN (SELECT user_id FROM workspace_members WHERE workspace_id IN (SELECT app_workspace_ids()))
+    );
@@ -0,0 +1,48 @@
+-- Todoのサブタスク
+-- parent_id が NULL のものがルート。親を削除すると子孫も削除する
+-- position は同じ親(ルートの場合はワークスペース)の兄弟の中での並び順
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS parent_id uuid REFERENCES todos (id) ON DELETE CASCADE;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS position integer NOT NULL DEFAULT 0;
+
+-- 既存のTodoは作成日時順に並べる
+UPDATE todos t
+SET position = o.position
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY workspace_id, parent_id ORDER BY created_at, id) - 1 AS position
+    FROM todos
+) o
+WHERE t.id = o.id;
+
+CREATE INDEX IF NOT EXISTS todos_parent_id_idx
+    ON todos (workspace_id, parent_id, position);
+
+-- 親は同じワークスペースのTodoに限り、循環させない
+-- 階層の深さの上限はアプリケーション側で確認する(domain_todo.MaxTodoDepth)
+CREATE OR REPLACE FUNCTION check_todo_parent() RETURNS trigger AS $$
+BEGIN
+    IF NEW.parent_id IS NULL THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (SELECT 1 FROM todos WHERE id = NEW.parent_id AND workspace_id = NEW.workspace_id) THEN
+        RAISE EXCEPTION 'parent not found' USING ERRCODE = 'foreign_key_violation';
+    END IF;
+
+    IF EXISTS (
+        WITH RECURSIVE up AS (
+            SELECT id, parent_id FROM todos WHERE id = NEW.parent_id
+            UNION
+            SELECT p.id, p.parent_id FROM todos p JOIN up ON p.id = up.parent_id
+        )
+        SELECT 1 FROM up WHERE id = NEW.id
+    ) THEN
+        RAISE EXCEPTION 'cycle detected' USING ERRCODE = 'check_violation';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS todos_check_parent ON todos;
+CREATE TRIGGER todos_check_parent
+    BEFORE INSERT OR UPDATE OF parent_id, workspace_id ON todos
+    FOR EACH ROW EXECUTE FUNCTION check_todo_parent();
@@ -0,0 +1,50 @@
+-- Todoの並び順をフラクショナルインデックス(pkg_rank)のキーにする
+-- キーはバイト順に比較するため COLLATE "C" にする。移動したTodoのキーだけを更新する
+-- 同じ親の兄弟でキーを重複させない(並び直しの間は SET CONSTRAINTS で確認を遅らせる)
+
+-- 62進数でwidth桁に符号化し、末尾の"0"を除く(pkg_rank.Spread と同じ)
+CREATE OR REPLACE FUNCTION pg_temp.rank_key(v bigint, width integer) RETURNS text AS $$
+DECLARE
+    digits constant text := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+    key text := '';
+BEGIN
+    FOR i IN 1..width LOOP
+        key := substr(digits, (v % 62)::integer + 1, 1) || key;
+        v := v / 62;
+    END LOOP;
+    RETURN rtrim(key, '0');
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+DO $$
+BEGIN
+    -- 011 の整数の position を、同じ順で等間隔のキーに置き換える(5桁: 兄弟は 62^5 未満)
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'todos' AND column_name = 'position' AND data_type = 'integer'
+    ) THEN
+        DROP INDEX IF EXISTS todos_parent_id_idx;
+        ALTER TABLE todos ADD COLUMN position_key text COLLATE "C";
+
+        UPDATE todos t
+        SET position_key = pg_temp.rank_key(o.rn * 916132832 / (o.cnt + 1), 5)
+        FROM (
+            SELECT id,
+                row_number() OVER (PARTITION BY workspace_id, parent_id ORDER BY position, created_at, id) AS rn,
+                count(*) OVER (PARTITION BY workspace_id, parent_id) AS cnt
+            FROM todos
+        ) o
+        WHERE t.id = o.id;
+
+        ALTER TABLE todos DROP COLUMN position;
+        ALTER TABLE todos RENAME COLUMN position_key TO position;
+        ALTER TABLE todos ALTER COLUMN position SET NOT NULL;
+    END IF;
+END
+$$;
+
+-- 一意制約のインデックスを兄弟の並びの取得にも使う
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_position_unique;
+ALTER TABLE todos ADD CONSTRAINT todos_position_unique
+    UNIQUE NULLS NOT DISTINCT (workspace_id, parent_id, position)
+    DEFERRABLE INITIALLY IMMEDIATE;
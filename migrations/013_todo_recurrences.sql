@@ -0,0 +1,56 @@
+-- 繰り返しのTodo
+-- 繰り返し(シリーズ)は iCalendar の RRULE とタイムゾーンを持ち、予定ごとのTodoを先の期間まで作成しておく
+-- materialized_until より前の予定は作成済み(削除された予定も作り直さない)
+-- 「以降の全て」を変更した場合は end_at で終了し、新しい繰り返しを作成する
+CREATE TABLE IF NOT EXISTS todo_recurrences (
+    id                 uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id       uuid NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id            uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    description        text NOT NULL,
+    rrule              text NOT NULL,
+    timezone           text NOT NULL,
+    start_at           timestamptz NOT NULL,
+    end_at             timestamptz,
+    materialized_until timestamptz NOT NULL,
+    created_at         timestamptz NOT NULL DEFAULT now(),
+    updated_at         timestamptz NOT NULL DEFAULT now()
+);
+
+-- スケジューラが予定の作成の遅れている繰り返しを探す
+CREATE INDEX IF NOT EXISTS todo_recurrences_materialized_until_idx
+    ON todo_recurrences (materialized_until);
+
+CREATE INDEX IF NOT EXISTS todo_recurrences_workspace_id_idx
+    ON todo_recurrences (workspace_id);
+
+-- 予定のTodo
+-- occurrence_at は繰り返しの中での予定日時(期日 due_at を変更しても変わらない)
+-- 繰り返しを削除しても作成済みのTodoは残す
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS due_at timestamptz;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS recurrence_id uuid REFERENCES todo_recurrences (id) ON DELETE SET NULL;
+ALTER TABLE todos ADD COLUMN IF NOT EXISTS occurrence_at timestamptz;
+
+-- 同じ予定を2回作成しない(繰り返しでないTodoはNULLのため対象外)
+ALTER TABLE todos DROP CONSTRAINT IF EXISTS todos_occurrence_unique;
+ALTER TABLE todos ADD CONSTRAINT todos_occurrence_unique
+    UNIQUE (recurrence_id, occurrence_at);
+
+-- 行レベルセキュリティ(migrations/010_row_level_security.sql と同じ方針)
+-- 所属するワークスペースのものを参照でき、オーナー・編集者は作成・更新できる
+-- スケジューラは接続プールのロール(ポリシーを適用しない)で予定のTodoを作成する
+GRANT SELECT, INSERT, UPDATE ON todo_recurrences TO authenticated;
+
+ALTER TABLE todo_recurrences ENABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS todo_recurrences_select ON todo_recurrences;
+CREATE POLICY todo_recurrences_select ON todo_recurrences FOR SELECT TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids()));
+
+DROP POLICY IF EXISTS todo_recurrences_insert ON todo_recurrences;
+CREATE POLICY todo_recurrences_insert ON todo_recurrences FOR INSERT TO authenticated
+    WITH CHECK (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])));
+
+DROP POLICY IF EXISTS todo_recurrences_update ON todo_recurrences;
+CREATE POLICY todo_recurrences_update ON todo_recurrences FOR UPDATE TO authenticated
+    USING (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])))
+    WITH CHECK (workspace_id IN (SELECT app_workspace_ids(ARRAY['owner', 'editor'])));
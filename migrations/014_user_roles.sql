@@ -0,0 +1,6 @@
+-- ユーザーのロール
+-- アクセストークンのroleはログイン時にここから取得する(管理者への変更はSQLで行う)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role text NOT NULL DEFAULT 'user';
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'admin'));